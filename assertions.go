@@ -0,0 +1,78 @@
+package gptschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Assertion is a check on a response derived from a schema by GenerateAssertions, applying
+// to every value at its path: "[]" stands for all array items and "{}" for all map values.
+type Assertion = internal.Assertion
+
+// AssertionKind classifies an Assertion.
+type AssertionKind = internal.AssertionKind
+
+// Kinds of assertions.
+const (
+	// AssertExists checks that a required property is present in its object
+	AssertExists = internal.AssertExists
+	// AssertType checks the JSON type of a value, null included for nullable values
+	AssertType = internal.AssertType
+	// AssertEnum checks that a value is one of the enum values, or the const
+	AssertEnum = internal.AssertEnum
+	// AssertRange checks the minimum and maximum bounds of a number
+	AssertRange = internal.AssertRange
+)
+
+// GenerateAssertions converts a schema into the assertions its responses must pass: every
+// required property exists, and every value has its type, one of its enum values and a
+// number within its bounds. They are meant for eval harnesses, to derive output-quality
+// checks from the response contract and report them one by one, where Validate stops at
+// whether the response is valid. $refs are not followed.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Ticket{})
+//	for _, assertion := range GenerateAssertions(schema) {
+//	    fmt.Println(assertion.Kind, assertion) // enum status is one of "open", "closed"
+//	}
+func GenerateAssertions(schema *internal.Schema) []Assertion {
+	if schema == nil {
+		return nil
+	}
+	return internal.SchemaAssertions(*schema)
+}
+
+// CheckAssertions runs assertions against a JSON response and returns an error for each
+// failed assertion, in the order of the assertions, e.g. to score a model response as the
+// share of passed assertions. An error is only returned for invalid JSON.
+//
+// Example:
+//
+//	assertions := GenerateAssertions(schema)
+//	failures, err := CheckAssertions(assertions, []byte(content))
+//	if err != nil {
+//	    return err
+//	}
+//	score := 1 - float64(len(failures))/float64(len(assertions))
+//	for _, failure := range failures {
+//	    log.Println(failure) // status: "pending" is not one of "open", "closed"
+//	}
+func CheckAssertions(assertions []Assertion, response []byte) ([]error, error) {
+	decoder := json.NewDecoder(bytes.NewReader(response))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid JSON response: %w", err)
+	}
+	var failures []error
+	for _, assertion := range assertions {
+		if err := assertion.Check(document); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return failures, nil
+}
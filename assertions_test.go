@@ -0,0 +1,65 @@
+package gptschema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestGenerateAssertions(t *testing.T) {
+	schema := internal.ArticleSchema
+	assertions := GenerateAssertions(&schema)
+	kinds := make(map[AssertionKind]int)
+	for _, assertion := range assertions {
+		kinds[assertion.Kind]++
+	}
+	expected := map[AssertionKind]int{AssertType: 6, AssertExists: 4, AssertEnum: 3}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("expected %v, got %v", expected, kinds)
+	}
+	if GenerateAssertions(nil) != nil {
+		t.Errorf("expected no assertions for a nil schema")
+	}
+}
+
+func TestCheckAssertions(t *testing.T) {
+	schema := internal.ArticleSchema
+	assertions := GenerateAssertions(&schema)
+	tests := []struct {
+		name     string
+		response string
+		expected []string
+	}{
+		{name: "valid", response: `{"title":"Go","status":"draft","history":["draft"],"next":null}`},
+		{
+			name:     "failures",
+			response: `{"status":"deleted","history":["draft","gone"]}`,
+			expected: []string{
+				`response: missing required property "title"`,
+				`status: "deleted" is not one of "draft", "published", "archived"`,
+				`history[1]: "gone" is not one of "draft", "published", "archived"`,
+				`response: missing required property "next"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures, err := CheckAssertions(assertions, []byte(tt.response))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var messages []string
+			for _, failure := range failures {
+				messages = append(messages, failure.Error())
+			}
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, messages)
+			}
+		})
+	}
+
+	if _, err := CheckAssertions(assertions, []byte(`{"title":`)); err == nil {
+		t.Errorf("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,276 @@
+package gptschema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// ErrInvalidSchema is returned by SchemaBuilder.Build and the composition helpers for inconsistent schemas.
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// SchemaBuilder constructs a schema without a Go struct, for shapes only known at runtime
+// such as user-configured extraction fields. Builders are created with Object, String,
+// Integer, Number, Boolean and Array, configured with chained calls and turned into the
+// same Schema type as GenerateSchema with Build. Mistakes such as a constraint applied to
+// the wrong type are reported by Build, not by the chained calls.
+//
+// Objects set additionalProperties to false. OpenAI strict mode requires every property
+// to be listed in Required, use Nullable for properties that may be absent.
+//
+// Example:
+//
+//	schema, err := Object().
+//	    Prop("name", String().MinLength(1)).
+//	    Prop("tags", Array(String())).
+//	    Required("name", "tags").
+//	    Build()
+type SchemaBuilder struct {
+	jsonType    string
+	keywords    internal.Schema
+	props       []string
+	propertyMap map[string]*SchemaBuilder
+	required    []string
+	items       *SchemaBuilder
+	not         *SchemaBuilder
+	nullable    bool
+	errs        []error
+}
+
+func newBuilder(jsonType string) *SchemaBuilder {
+	return &SchemaBuilder{jsonType: jsonType, keywords: make(internal.Schema)}
+}
+
+// Object starts an object schema.
+func Object() *SchemaBuilder {
+	b := newBuilder("object")
+	b.propertyMap = make(map[string]*SchemaBuilder)
+	return b
+}
+
+// String starts a string schema.
+func String() *SchemaBuilder { return newBuilder("string") }
+
+// Integer starts an integer schema.
+func Integer() *SchemaBuilder { return newBuilder("integer") }
+
+// Number starts a number schema.
+func Number() *SchemaBuilder { return newBuilder("number") }
+
+// Boolean starts a boolean schema.
+func Boolean() *SchemaBuilder { return newBuilder("boolean") }
+
+// Array starts an array schema whose items are described by items.
+func Array(items *SchemaBuilder) *SchemaBuilder {
+	b := newBuilder("array")
+	b.items = items
+	if items == nil {
+		b.addError("array items must not be nil")
+	}
+	return b
+}
+
+func (b *SchemaBuilder) addError(format string, args ...interface{}) {
+	b.errs = append(b.errs, fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidSchema}, args...)...))
+}
+
+// set records a keyword allowed for the given types only
+func (b *SchemaBuilder) set(keyword string, value interface{}, types ...string) *SchemaBuilder {
+	for _, t := range types {
+		if b.jsonType == t {
+			b.keywords[keyword] = value
+			return b
+		}
+	}
+	b.addError("%s does not apply to %s schemas", keyword, b.jsonType)
+	return b
+}
+
+// Prop adds a property to an object schema.
+func (b *SchemaBuilder) Prop(name string, prop *SchemaBuilder) *SchemaBuilder {
+	switch {
+	case b.jsonType != "object":
+		b.addError("property %q added to a %s schema", name, b.jsonType)
+	case prop == nil:
+		b.addError("property %q must not be nil", name)
+	case b.propertyMap[name] != nil:
+		b.addError("property %q added twice", name)
+	default:
+		b.props = append(b.props, name)
+		b.propertyMap[name] = prop
+	}
+	return b
+}
+
+// Required lists the properties of an object schema that must be present.
+func (b *SchemaBuilder) Required(names ...string) *SchemaBuilder {
+	if b.jsonType != "object" {
+		b.addError("required does not apply to %s schemas", b.jsonType)
+		return b
+	}
+	b.required = append(b.required, names...)
+	return b
+}
+
+// Description documents the schema for the model.
+func (b *SchemaBuilder) Description(description string) *SchemaBuilder {
+	b.keywords["description"] = description
+	return b
+}
+
+// Enum restricts the schema to the given values.
+func (b *SchemaBuilder) Enum(values ...interface{}) *SchemaBuilder {
+	return b.set("enum", values, "string", "integer", "number", "boolean")
+}
+
+// Nullable also accepts null, the way optional fields are described in strict mode.
+func (b *SchemaBuilder) Nullable() *SchemaBuilder {
+	b.nullable = true
+	return b
+}
+
+// Not rejects the values matching schema, e.g. String().Not(String().MaxLength(0)) forbids
+// empty strings. OpenAI strict mode rejects the keyword, StripUnsupported removes it from the
+// transmitted schema while Validate keeps checking it against the built schema.
+func (b *SchemaBuilder) Not(schema *SchemaBuilder) *SchemaBuilder {
+	switch {
+	case schema == nil:
+		b.addError("not schema must not be nil")
+	case b.not != nil:
+		b.addError("not set twice")
+	default:
+		b.not = schema
+	}
+	return b
+}
+
+// Exclude rejects the given values, e.g. String().Exclude("n/a", "unknown"). It is a Not of an enum.
+func (b *SchemaBuilder) Exclude(values ...interface{}) *SchemaBuilder {
+	return b.Not(newBuilder(b.jsonType).Enum(values...))
+}
+
+// MinLength sets the minimum length of a string schema.
+func (b *SchemaBuilder) MinLength(n int) *SchemaBuilder { return b.set("minLength", n, "string") }
+
+// MaxLength sets the maximum length of a string schema.
+func (b *SchemaBuilder) MaxLength(n int) *SchemaBuilder { return b.set("maxLength", n, "string") }
+
+// Pattern sets the regular expression a string schema must match.
+func (b *SchemaBuilder) Pattern(pattern string) *SchemaBuilder {
+	if _, err := regexp.Compile(pattern); err != nil {
+		b.addError("pattern %q: %v", pattern, err)
+		return b
+	}
+	return b.set("pattern", pattern, "string")
+}
+
+// Format sets the format of a string schema, such as "email" or "date-time".
+func (b *SchemaBuilder) Format(format string) *SchemaBuilder {
+	return b.set("format", format, "string")
+}
+
+// Minimum sets the inclusive minimum of a numeric schema.
+func (b *SchemaBuilder) Minimum(min float64) *SchemaBuilder {
+	return b.set("minimum", min, "integer", "number")
+}
+
+// Maximum sets the inclusive maximum of a numeric schema.
+func (b *SchemaBuilder) Maximum(max float64) *SchemaBuilder {
+	return b.set("maximum", max, "integer", "number")
+}
+
+// MinItems sets the minimum number of items of an array schema.
+func (b *SchemaBuilder) MinItems(n int) *SchemaBuilder { return b.set("minItems", n, "array") }
+
+// MaxItems sets the maximum number of items of an array schema.
+func (b *SchemaBuilder) MaxItems(n int) *SchemaBuilder { return b.set("maxItems", n, "array") }
+
+// Build returns the schema, or ErrInvalidSchema errors for every mistake found in the builder
+// and its nested builders.
+func (b *SchemaBuilder) Build() (*internal.Schema, error) {
+	schema, err := b.build("")
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (b *SchemaBuilder) build(path string) (internal.Schema, error) {
+	errs := append([]error(nil), b.errs...)
+	for i, err := range errs {
+		if path != "" {
+			err = fmt.Errorf("%s: %w", path, err)
+		}
+		errs[i] = &internal.SchemaError{Code: InvalidSchema, Path: path, Err: err}
+	}
+	schema := make(internal.Schema, len(b.keywords)+4)
+	for k, v := range b.keywords {
+		schema[k] = v
+	}
+	schema["type"] = b.jsonType
+	switch b.jsonType {
+	case "object":
+		props := make(internal.Schema, len(b.props))
+		for _, name := range b.props {
+			prop, err := b.propertyMap[name].build(joinSchemaPath(path, name))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			props[name] = prop
+		}
+		for _, name := range b.required {
+			if b.propertyMap[name] == nil {
+				errs = append(errs, &internal.SchemaError{
+					Code: InvalidSchema, Path: path,
+					Err: fmt.Errorf("%w: required property %q is not defined", ErrInvalidSchema, name),
+				})
+			}
+		}
+		schema["properties"] = props
+		schema["additionalProperties"] = false
+		if len(b.required) > 0 {
+			schema["required"] = append([]string(nil), b.required...)
+		}
+	case "array":
+		if b.items != nil {
+			items, err := b.items.build(path + "[]")
+			if err != nil {
+				errs = append(errs, err)
+			}
+			schema["items"] = items
+		}
+	}
+	if b.not != nil {
+		not, err := b.not.build(path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		schema["not"] = not
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if !b.nullable {
+		return schema, nil
+	}
+	// primitives use a type array and other schemas anyOf, like optional fields
+	if b.jsonType != "object" && b.jsonType != "array" {
+		schema["type"] = []string{b.jsonType, "null"}
+		if values, ok := schema["enum"].([]interface{}); ok {
+			schema["enum"] = append(values[:len(values):len(values)], nil)
+		}
+		return schema, nil
+	}
+	return internal.Schema{"anyOf": []internal.Schema{schema, {"type": "null"}}}, nil
+}
+
+// joinSchemaPath joins property names the way Lint reports paths
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,144 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestSchemaBuilder(t *testing.T) {
+	schema, err := Object().
+		Prop("name", String().MinLength(1).Description("display name")).
+		Prop("tags", Array(String()).MaxItems(5)).
+		Prop("score", Number().Minimum(0).Maximum(1).Nullable()).
+		Prop("status", String().Enum("open", "closed")).
+		Prop("address", Object().Prop("city", String()).Required("city").Nullable()).
+		Required("name", "tags", "score", "status", "address").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := internal.Schema{
+		"type": "object",
+		"properties": internal.Schema{
+			"name": internal.Schema{"type": "string", "minLength": 1, "description": "display name"},
+			"tags": internal.Schema{"type": "array", "items": internal.Schema{"type": "string"}, "maxItems": 5},
+			"score": internal.Schema{
+				"type":    []string{"number", "null"},
+				"minimum": float64(0),
+				"maximum": float64(1),
+			},
+			"status": internal.Schema{"type": "string", "enum": []interface{}{"open", "closed"}},
+			"address": internal.Schema{"anyOf": []internal.Schema{
+				{
+					"type":                 "object",
+					"properties":           internal.Schema{"city": internal.Schema{"type": "string"}},
+					"required":             []string{"city"},
+					"additionalProperties": false,
+				},
+				{"type": "null"},
+			}},
+		},
+		"required":             []string{"name", "tags", "score", "status", "address"},
+		"additionalProperties": false,
+	}
+	if !reflect.DeepEqual(*schema, expected) {
+		t.Errorf("expected %+v, got %+v", expected, *schema)
+	}
+	issues, err := Lint(schema)
+	if err != nil || len(issues) != 0 {
+		t.Errorf("expected built schema to pass lint, got %v %v", issues, err)
+	}
+}
+
+func TestSchemaBuilder_Not(t *testing.T) {
+	schema, err := Object().
+		Prop("name", String().Not(String().MaxLength(0))).
+		Prop("status", String().Enum("open", "closed", "unknown").Exclude("unknown").Nullable()).
+		Required("name", "status").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props := (*schema)["properties"].(internal.Schema)
+	expectedName := internal.Schema{"type": "string", "not": internal.Schema{"type": "string", "maxLength": 0}}
+	if !reflect.DeepEqual(props["name"], expectedName) {
+		t.Errorf("expected %+v, got %+v", expectedName, props["name"])
+	}
+	expectedStatus := internal.Schema{
+		"type": []string{"string", "null"},
+		"enum": []interface{}{"open", "closed", "unknown", nil},
+		"not":  internal.Schema{"type": "string", "enum": []interface{}{"unknown"}},
+	}
+	if !reflect.DeepEqual(props["status"], expectedStatus) {
+		t.Errorf("expected %+v, got %+v", expectedStatus, props["status"])
+	}
+
+	mismatches, err := Validate(schema, []byte(`{"name":"","status":"unknown"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mismatches) != 2 || mismatches[0].Path != "name" || mismatches[0].Keyword != "not" || mismatches[1].Path != "status" {
+		t.Errorf("unexpected mismatches %v", mismatches)
+	}
+	if mismatches, _ := Validate(schema, []byte(`{"name":"a","status":null}`)); len(mismatches) != 0 {
+		t.Errorf("expected a valid response, got %v", mismatches)
+	}
+}
+
+func TestSchemaBuilder_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *SchemaBuilder
+		errorMsg string
+	}{
+		{
+			name:     "constraint on the wrong type",
+			builder:  Object().Prop("age", Integer().MinLength(1)),
+			errorMsg: "age: invalid schema: minLength does not apply to integer schemas",
+		},
+		{
+			name:     "undefined required property",
+			builder:  Object().Prop("name", String()).Required("id"),
+			errorMsg: `invalid schema: required property "id" is not defined`,
+		},
+		{
+			name:     "property on a primitive",
+			builder:  String().Prop("name", String()),
+			errorMsg: `invalid schema: property "name" added to a string schema`,
+		},
+		{
+			name:     "duplicate property",
+			builder:  Object().Prop("name", String()).Prop("name", Integer()),
+			errorMsg: `invalid schema: property "name" added twice`,
+		},
+		{
+			name:     "not set twice",
+			builder:  String().Exclude("a").Not(String().MaxLength(0)),
+			errorMsg: "invalid schema: not set twice",
+		},
+		{
+			name:     "excluded values on an object",
+			builder:  Object().Prop("address", Object().Exclude("x")),
+			errorMsg: "address: invalid schema: enum does not apply to object schemas",
+		},
+		{
+			name:     "invalid pattern",
+			builder:  Object().Prop("tags", Array(String().Pattern("("))),
+			errorMsg: "tags[]: invalid schema: pattern \"(\": error parsing regexp: missing closing ): `(`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if !errors.Is(err, ErrInvalidSchema) {
+				t.Fatalf("expected ErrInvalidSchema, got %v", err)
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("mismatch error message, expect=%s, got=%s", tt.errorMsg, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// checkConfig holds the flags of the check command
+type checkConfig struct {
+	Strict   bool
+	MaxDepth int
+	Live     bool
+	BaseURL  string
+	Model    string
+}
+
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gptschema check [flags] [packages]\n\n"+
+			"Lints the schema of every type marked with a %s directive, including the rules\n"+
+			"registered with gptschema.RegisterLintRule by the packages of the types, and runs it\n"+
+			"through the meta-schema and example checks of gptschema.CheckConformance.\n"+
+			"With -live, each schema is also submitted to an OpenAI-compatible endpoint in a minimal\n"+
+			"request (max_tokens=1) using the OPENAI_API_KEY environment variable, and the provider's\n"+
+			"verdict is reported alongside the local findings.\n\n", generateDirective)
+		fs.PrintDefaults()
+	}
+	config := checkConfig{}
+	fs.BoolVar(&config.Strict, "strict", true, "check OpenAI strict mode compatible schemas")
+	fs.IntVar(&config.MaxDepth, "max-depth", 50, "maximum depth for nested struct traversal")
+	fs.BoolVar(&config.Live, "live", false, "submit each schema to the provider in a minimal request")
+	fs.StringVar(&config.BaseURL, "base-url", "https://api.openai.com/v1", "base URL of the OpenAI-compatible API")
+	fs.StringVar(&config.Model, "model", "gpt-4o-mini", "model used for live checks")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	types, err := discoverPatterns(fs.Args())
+	if err != nil {
+		return err
+	}
+	data, err := newProgramData(types, config)
+	if err != nil {
+		return err
+	}
+	src, err := renderProgram(checkTemplate, data)
+	if err != nil {
+		return err
+	}
+	return runGenerator("check", src, os.Stdout)
+}
+
+// program run with "go run" to lint and optionally submit the schemas
+var checkTemplate = template.Must(template.New("check").Parse(`// Code generated by gptschema check. DO NOT EDIT.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/akane9506/gptschema"
+{{- range $i, $path := .Imports}}
+	p{{$i}} {{printf "%q" $path}}
+{{- end}}
+)
+
+func main() {
+	opts := []gptschema.Option{
+		gptschema.WithStrict({{.Config.Strict}}),
+		gptschema.WithMaxDepth({{.Config.MaxDepth}}),
+	}
+	live := gptschema.LiveCheckConfig{
+		BaseURL: {{printf "%q" .Config.BaseURL}},
+		Model:   {{printf "%q" .Config.Model}},
+	}
+	targets := []struct {
+		name  string
+		value interface{}
+	}{
+{{- range .Targets}}
+		{ {{- printf "%q" .Name}}, p{{.Import}}.{{.Type}}{}},
+{{- end}}
+	}
+	failed := false
+	for _, target := range targets {
+		schema, err := gptschema.GenerateSchema(target.value, opts...)
+		if err != nil {
+			fmt.Printf("%s: %v\n", target.name, err)
+			failed = true
+			continue
+		}
+		issues, err := gptschema.Lint(schema, opts...)
+		if err != nil {
+			fmt.Printf("%s: %v\n", target.name, err)
+			failed = true
+			continue
+		}
+		conformance, err := gptschema.CheckConformance(schema, opts...)
+		if err != nil {
+			fmt.Printf("%s: %v\n", target.name, err)
+			failed = true
+			continue
+		}
+		issues = append(issues, conformance...)
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", target.name, issue)
+			failed = true
+		}
+{{- if .Config.Live}}
+		result, err := gptschema.CheckLive(context.Background(), live, target.name, target.value, opts...)
+		if err != nil {
+			fmt.Printf("%s: live check failed: %v\n", target.name, err)
+			failed = true
+			continue
+		}
+		if !result.Accepted {
+			fmt.Printf("%s: rejected by provider: %s\n", target.name, result.ProviderMessage)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s: accepted by provider\n", target.name)
+{{- else}}
+		_ = live
+		_ = context.Background
+{{- end}}
+		if len(issues) == 0 {
+			fmt.Printf("%s: ok\n", target.name)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+`))
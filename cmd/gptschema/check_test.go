@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestCheckSource(t *testing.T) {
+	types := []discoveredType{
+		{ImportPath: "example.com/models", Package: "models", Name: "Address"},
+		{ImportPath: "example.com/billing", Package: "billing", Name: "Invoice"},
+	}
+	tests := []struct {
+		name    string
+		config  checkConfig
+		present []string
+		absent  []string
+	}{
+		{
+			name:   "lint only",
+			config: checkConfig{Strict: true, MaxDepth: 20, BaseURL: "https://api.openai.com/v1", Model: "gpt-4o-mini"},
+			present: []string{
+				`{"models_Address", p0.Address{}}`,
+				`{"billing_Invoice", p1.Invoice{}}`,
+				`gptschema.WithStrict(true)`,
+				`gptschema.WithMaxDepth(20)`,
+				`gptschema.Lint(schema, opts...)`,
+				`gptschema.CheckConformance(schema, opts...)`,
+			},
+			absent: []string{"gptschema.CheckLive"},
+		},
+		{
+			name:   "live",
+			config: checkConfig{Strict: false, MaxDepth: 50, Live: true, BaseURL: "http://localhost:8080/v1", Model: "local"},
+			present: []string{
+				`gptschema.WithStrict(false)`,
+				`BaseURL: "http://localhost:8080/v1"`,
+				`Model:   "local"`,
+				`gptschema.CheckLive(context.Background(), live, target.name, target.value, opts...)`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := newProgramData(types, tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			src, err := renderProgram(checkTemplate, data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := format.Source(src); err != nil {
+				t.Fatalf("generated source is invalid: %v\n%s", err, src)
+			}
+			for _, part := range tt.present {
+				if !bytes.Contains(src, []byte(part)) {
+					t.Errorf("generated source missing %s\n%s", part, src)
+				}
+			}
+			for _, part := range tt.absent {
+				if bytes.Contains(src, []byte(part)) {
+					t.Errorf("generated source unexpectedly contains %s\n%s", part, src)
+				}
+			}
+		})
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	if err := runCheck([]string{"./testdata/models"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := runCheck([]string{"-strict=false", "./testdata/invalid"})
+	if err == nil || !strings.Contains(err.Error(), "must be exported") {
+		t.Errorf("expected error for an unexported type, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// name of the optional file holding the generation options of a conformance case
+const caseOptionsFile = "options.json"
+
+// conformConfig holds the flags of the conform command
+type conformConfig struct {
+	Update bool
+}
+
+// conformCase is a directory of the corpus: a Go package whose marked types are converted
+// and compared with the <Type>.schema.json files next to them
+type conformCase struct {
+	Name    string
+	Dir     string
+	Options schemaOptions
+}
+
+func runConform(args []string) error {
+	fs := flag.NewFlagSet("conform", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gptschema conform [flags] [corpus]\n\n"+
+			"Runs the converter over a conformance corpus, testdata/corpus by default. Each\n"+
+			"subdirectory of the corpus is a case: a Go package of the current module whose types\n"+
+			"marked with a %s directive are compared with the <Type>.schema.json files\n"+
+			"next to them. An optional %s file sets the options of a case, e.g.\n"+
+			"{\"strict\": false, \"max_depth\": 10}.\n\n", generateDirective, caseOptionsFile)
+		fs.PrintDefaults()
+	}
+	config := conformConfig{}
+	fs.BoolVar(&config.Update, "update", false, "write the generated schemas instead of comparing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	corpus := filepath.Join("testdata", "corpus")
+	if fs.NArg() > 0 {
+		corpus = fs.Arg(0)
+	}
+	cases, err := loadCorpus(corpus)
+	if err != nil {
+		return err
+	}
+	patterns := make([]string, len(cases))
+	for i, c := range cases {
+		patterns[i] = packagePattern(c.Dir)
+	}
+	types, err := discoverPatterns(patterns)
+	if err != nil {
+		return err
+	}
+	groups, options, err := conformGroups(cases, types)
+	if err != nil {
+		return err
+	}
+	results, err := generateSchemas("conform", types, groups, options)
+	if err != nil {
+		return err
+	}
+	return checkResults(cases, results, config, os.Stdout)
+}
+
+// loadCorpus lists the cases of a corpus in name order with their options
+func loadCorpus(corpus string) ([]conformCase, error) {
+	entries, err := os.ReadDir(corpus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corpus: %w", err)
+	}
+	var cases []conformCase
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		c := conformCase{
+			Name:    entry.Name(),
+			Dir:     filepath.Join(corpus, entry.Name()),
+			Options: defaultSchemaOptions(),
+		}
+		data, err := os.ReadFile(filepath.Join(c.Dir, caseOptionsFile))
+		if err == nil {
+			if err := decodeStrict(data, &c.Options); err != nil {
+				return nil, fmt.Errorf("case %s: invalid %s: %w", c.Name, caseOptionsFile, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("case %s: %w", c.Name, err)
+		}
+		cases = append(cases, c)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no cases found in corpus %s", corpus)
+	}
+	return cases, nil
+}
+
+// packagePattern turns a directory into a pattern of the go command, which reads
+// relative paths without a ./ prefix as import paths
+func packagePattern(dir string) string {
+	if filepath.IsAbs(dir) || strings.HasPrefix(dir, ".") {
+		return dir
+	}
+	return "." + string(filepath.Separator) + dir
+}
+
+// conformGroups returns the case and the options of each discovered type
+func conformGroups(cases []conformCase, types []discoveredType) ([]string, []schemaOptions, error) {
+	byDir := make(map[string]conformCase, len(cases))
+	for _, c := range cases {
+		abs, err := filepath.Abs(c.Dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		byDir[abs] = c
+	}
+	groups := make([]string, len(types))
+	options := make([]schemaOptions, len(types))
+	for i, t := range types {
+		c, ok := byDir[t.Dir]
+		if !ok {
+			return nil, nil, fmt.Errorf("type %s.%s is outside the corpus", t.Package, t.Name)
+		}
+		groups[i], options[i] = c.Name, c.Options
+	}
+	return groups, options, nil
+}
+
+// checkResults compares the generated schemas with the expected files, or writes them with
+// -update, and fails when a schema differs, is missing or could not be generated
+func checkResults(cases []conformCase, results []schemaResult, config conformConfig, w io.Writer) error {
+	dirs := make(map[string]string, len(cases))
+	for _, c := range cases {
+		dirs[c.Name] = c.Dir
+	}
+	results = append([]schemaResult(nil), results...)
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Group != results[j].Group {
+			return results[i].Group < results[j].Group
+		}
+		return results[i].Type < results[j].Type
+	})
+	failed := 0
+	for _, result := range results {
+		name := result.Group + "/" + result.Type
+		if result.Error != "" {
+			failed++
+			fmt.Fprintf(w, "FAIL %s: %s\n", name, result.Error)
+			continue
+		}
+		got, err := indentJSON(result.Schema)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		path := filepath.Join(dirs[result.Group], result.Type+".schema.json")
+		if config.Update {
+			if err := os.WriteFile(path, got, 0o644); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "updated %s\n", path)
+			continue
+		}
+		expected, err := os.ReadFile(path)
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "FAIL %s: missing %s, run with -update to create it\n", name, path)
+			continue
+		}
+		if diff, err := compareSchemas(expected, got); err != nil {
+			failed++
+			fmt.Fprintf(w, "FAIL %s: invalid %s: %v\n", name, path, err)
+		} else if diff != "" {
+			failed++
+			fmt.Fprintf(w, "FAIL %s: schema differs from %s\n%s", name, path, diff)
+		} else {
+			fmt.Fprintf(w, "ok   %s\n", name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d conformance cases failed", failed, len(results))
+	}
+	return nil
+}
+
+// indentJSON formats a schema like the expected files, keys sorted and indented by two spaces
+func indentJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	indented, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(indented, '\n'), nil
+}
+
+// compareSchemas returns the first differing line of two schemas in their canonical form,
+// empty when they are equal regardless of key order and whitespace
+func compareSchemas(expected, got []byte) (string, error) {
+	want, err := indentJSON(expected)
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(want, got) {
+		return "", nil
+	}
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; ; i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("\tline %d\n\texpected: %s\n\tgot:      %s\n", i+1, strings.TrimSpace(w), strings.TrimSpace(g)), nil
+		}
+	}
+}
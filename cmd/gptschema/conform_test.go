@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadCorpus(t *testing.T) {
+	corpus := t.TempDir()
+	for _, name := range []string{"plain", "relaxed"} {
+		if err := os.Mkdir(filepath.Join(corpus, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(corpus, "relaxed", caseOptionsFile), []byte(`{"strict": false, "max_depth": 5}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cases, err := loadCorpus(corpus)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []conformCase{
+		{Name: "plain", Dir: filepath.Join(corpus, "plain"), Options: schemaOptions{Strict: true, MaxDepth: 50}},
+		{Name: "relaxed", Dir: filepath.Join(corpus, "relaxed"), Options: schemaOptions{Strict: false, MaxDepth: 5}},
+	}
+	if !reflect.DeepEqual(cases, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cases)
+	}
+
+	if err := os.WriteFile(filepath.Join(corpus, "relaxed", caseOptionsFile), []byte(`{"strcit": false}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadCorpus(corpus); err == nil || !strings.Contains(err.Error(), "case relaxed: invalid options.json") {
+		t.Errorf("expected an error for an unknown option, got %v", err)
+	}
+	if _, err := loadCorpus(t.TempDir()); err == nil {
+		t.Errorf("expected an error for an empty corpus")
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	got, err := indentJSON([]byte(`{"type":"object","required":["id"]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff, err := compareSchemas([]byte(`{"required": ["id"], "type": "object"}`), got); err != nil || diff != "" {
+		t.Errorf("expected equal schemas regardless of key order, got %q, %v", diff, err)
+	}
+	diff, err := compareSchemas([]byte(`{"required": ["name"], "type": "object"}`), got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "\tline 3\n\texpected: \"name\"\n\tgot:      \"id\"\n"
+	if diff != expected {
+		t.Errorf("expected %q, got %q", expected, diff)
+	}
+	if _, err := compareSchemas([]byte(`{`), got); err == nil {
+		t.Errorf("expected an error for an invalid expected file")
+	}
+}
+
+func TestCheckResults(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Match.schema.json"), []byte(`{"type": "string"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Differ.schema.json"), []byte(`{"type": "integer"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cases := []conformCase{{Name: "case", Dir: dir}}
+	results := []schemaResult{
+		{Group: "case", Type: "Match", Schema: []byte(`{"type":"string"}`)},
+		{Group: "case", Type: "Differ", Schema: []byte(`{"type":"string"}`)},
+		{Group: "case", Type: "Missing", Schema: []byte(`{"type":"string"}`)},
+		{Group: "case", Type: "Broken", Error: "unsupported type: chan int"},
+	}
+	var out bytes.Buffer
+	err := checkResults(cases, results, conformConfig{}, &out)
+	if err == nil || err.Error() != "3 of 4 conformance cases failed" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, line := range []string{
+		"FAIL case/Broken: unsupported type: chan int",
+		"FAIL case/Differ: schema differs from",
+		"ok   case/Match",
+		"FAIL case/Missing: missing",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output missing %q\n%s", line, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := checkResults(cases, results[:3], conformConfig{Update: true}, &out); err != nil {
+		t.Fatalf("unexpected error with -update: %v", err)
+	}
+	if err := checkResults(cases, results[:3], conformConfig{}, &out); err != nil {
+		t.Errorf("expected the updated files to match, got %v", err)
+	}
+}
+
+func TestRunConform(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	if err := runConform([]string{filepath.Join("..", "..", "testdata", "corpus")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// directive marking the types to generate a schema for
+const generateDirective = "//gptschema:generate"
+
+// name of the files registering the generated schemas in their packages
+const registryFile = "gptschema_generated.go"
+
+// listedPackage holds the fields of "go list -json" used for discovery
+type listedPackage struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	GoFiles    []string
+}
+
+// discoveredType is a type marked with the generate directive
+type discoveredType struct {
+	ImportPath string
+	Package    string
+	Name       string
+	// Dir is the directory of the package
+	Dir string
+}
+
+// generateConfig holds the flags of the generate command
+type generateConfig struct {
+	Out      string
+	Strict   bool
+	MaxDepth int
+	Registry bool
+}
+
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gptschema generate [flags] [packages]\n\n"+
+			"Writes one schema file per type marked with a %s directive.\n"+
+			"With -registry, a %s file registering the schemas is also written to each\n"+
+			"package, so GenerateSchema returns them without reflection.\n"+
+			"Packages default to the current directory, e.g. ./... for the whole module.\n\n", generateDirective, registryFile)
+		fs.PrintDefaults()
+	}
+	config := generateConfig{}
+	fs.StringVar(&config.Out, "out", "schemas", "directory the schema files are written to")
+	fs.BoolVar(&config.Strict, "strict", true, "generate OpenAI strict mode compatible schemas")
+	fs.IntVar(&config.MaxDepth, "max-depth", 50, "maximum depth for nested struct traversal")
+	fs.BoolVar(&config.Registry, "registry", false, "also write Go files registering the schemas in their packages")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	types, err := discoverPatterns(fs.Args())
+	if err != nil {
+		return err
+	}
+	src, err := generatorSource(types, config)
+	if err != nil {
+		return err
+	}
+	return runGenerator("generate", src, os.Stdout)
+}
+
+// discoverPatterns lists the packages matching the patterns (the current directory by default)
+// and returns the types marked with the directive, failing when there are none
+func discoverPatterns(patterns []string) ([]discoveredType, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	pkgs, err := listPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+	types, err := discoverTypes(pkgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no types marked with %s found in %s", generateDirective, strings.Join(patterns, " "))
+	}
+	return types, nil
+}
+
+// listPackages resolves package patterns with the go command
+func listPackages(patterns []string) ([]listedPackage, error) {
+	cmd := exec.Command("go", append([]string{"list", "-json"}, patterns...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list failed: %v\n%s", err, stderr.String())
+	}
+	var pkgs []listedPackage
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	for decoder.More() {
+		var pkg listedPackage
+		if err := decoder.Decode(&pkg); err != nil {
+			return nil, fmt.Errorf("failed to decode go list output: %w", err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// hasDirective reports whether a doc comment carries the generate directive
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.TrimSpace(comment.Text) == generateDirective {
+			return true
+		}
+	}
+	return false
+}
+
+// discoverTypes parses the packages and returns the types marked with the directive,
+// the directive may be placed on the type declaration or on a spec of a grouped declaration
+func discoverTypes(pkgs []listedPackage) ([]discoveredType, error) {
+	var types []discoveredType
+	fset := token.NewFileSet()
+	for _, pkg := range pkgs {
+		for _, name := range pkg.GoFiles {
+			file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					if !hasDirective(typeSpec.Doc) && !(len(gen.Specs) == 1 && hasDirective(gen.Doc)) {
+						continue
+					}
+					position := fset.Position(typeSpec.Pos())
+					switch {
+					case pkg.Name == "main":
+						return nil, fmt.Errorf("%s: type %s is in package main, which cannot be imported", position, typeSpec.Name.Name)
+					case !typeSpec.Name.IsExported():
+						return nil, fmt.Errorf("%s: type %s must be exported", position, typeSpec.Name.Name)
+					case typeSpec.TypeParams != nil:
+						return nil, fmt.Errorf("%s: generic type %s cannot be generated", position, typeSpec.Name.Name)
+					}
+					types = append(types, discoveredType{
+						ImportPath: pkg.ImportPath,
+						Package:    pkg.Name,
+						Name:       typeSpec.Name.Name,
+						Dir:        pkg.Dir,
+					})
+				}
+			}
+		}
+	}
+	return types, nil
+}
+
+// program run with "go run" to generate the schemas through reflection
+var generatorTemplate = template.Must(template.New("generator").Parse(`// Code generated by gptschema generate. DO NOT EDIT.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+{{- if .Config.Registry}}
+	"go/format"
+{{- end}}
+	"os"
+	"path/filepath"
+
+	"github.com/akane9506/gptschema"
+{{- range $i, $path := .Imports}}
+	p{{$i}} {{printf "%q" $path}}
+{{- end}}
+)
+
+func main() {
+	opts := []gptschema.Option{
+		gptschema.WithStrict({{.Config.Strict}}),
+		gptschema.WithMaxDepth({{.Config.MaxDepth}}),
+		// previously registered schemas may be out of date
+		gptschema.WithGeneratedSchemas(false),
+	}
+	targets := []struct {
+		file  string
+		value interface{}
+	}{
+{{- range .Targets}}
+		{ {{- printf "%q" .File}}, p{{.Import}}.{{.Type}}{}},
+{{- end}}
+	}
+{{- if .Config.Registry}}
+	// package directory, package name and type of each target
+	registry := [][3]string{
+{{- range .Targets}}
+		{ {{- printf "%q" .Dir}}, {{printf "%q" .Package}}, {{printf "%q" .Type}}},
+{{- end}}
+	}
+	registrations := make(map[string][]string)
+	var dirs []string
+{{- end}}
+	out := {{printf "%q" .Config.Out}}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for {{if .Config.Registry}}i{{else}}_{{end}}, target := range targets {
+		schema, err := gptschema.GenerateSchemaBytes(target.value, opts...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", target.file, err)
+			os.Exit(1)
+		}
+{{- if .Config.Registry}}
+		dir := registry[i][0]
+		if _, ok := registrations[dir]; !ok {
+			dirs = append(dirs, dir)
+			registrations[dir] = []string{"package " + registry[i][1]}
+		}
+		registrations[dir] = append(registrations[dir], fmt.Sprintf("\tregister(gptschema.RegisterGeneratedSchema[%s](%q, opts...))", registry[i][2], schema))
+{{- end}}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, schema, "", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", target.file, err)
+			os.Exit(1)
+		}
+		indented.WriteByte('\n')
+		path := filepath.Join(out, target.file)
+		if err := os.WriteFile(path, indented.Bytes(), 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+	}
+{{- if .Config.Registry}}
+	for _, dir := range dirs {
+		var src bytes.Buffer
+		src.WriteString("// Code generated by gptschema generate. DO NOT EDIT.\n\n")
+		src.WriteString(registrations[dir][0] + "\n\n")
+		src.WriteString("import \"github.com/akane9506/gptschema\"\n\n")
+		src.WriteString("func init() {\n")
+		src.WriteString("\topts := []gptschema.Option{gptschema.WithStrict({{.Config.Strict}}), gptschema.WithMaxDepth({{.Config.MaxDepth}})}\n")
+		src.WriteString("\tregister := func(err error) {\n\t\tif err != nil {\n\t\t\tpanic(err)\n\t\t}\n\t}\n")
+		for _, line := range registrations[dir][1:] {
+			src.WriteString(line + "\n")
+		}
+		src.WriteString("}\n")
+		formatted, err := format.Source(src.Bytes())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		path := filepath.Join(dir, {{printf "%q" .RegistryFile}})
+		if err := os.WriteFile(path, formatted, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+	}
+{{- end}}
+}
+`))
+
+type generatorTarget struct {
+	// Name identifies the type as "package_Type", a valid response format name
+	Name    string
+	File    string
+	Import  int
+	Type    string
+	Package string
+	Dir     string
+}
+
+// programData is the input of the generated programs
+type programData struct {
+	Imports      []string
+	Targets      []generatorTarget
+	Config       interface{}
+	RegistryFile string
+}
+
+// newProgramData assigns an import alias to every package of the types. Types of
+// distinct packages sharing a package and type name would share their schema file and
+// response format name, so they are rejected.
+func newProgramData(types []discoveredType, config interface{}) (programData, error) {
+	data := programData{Config: config, RegistryFile: registryFile}
+	importIndex := make(map[string]int)
+	files := make(map[string]discoveredType)
+	for _, t := range types {
+		file := t.Package + "." + t.Name + ".schema.json"
+		if other, ok := files[file]; ok {
+			return programData{}, fmt.Errorf("types %s.%s and %s.%s would both be written to %s, rename one of them",
+				other.ImportPath, other.Name, t.ImportPath, t.Name, file)
+		}
+		files[file] = t
+		index, ok := importIndex[t.ImportPath]
+		if !ok {
+			index = len(data.Imports)
+			importIndex[t.ImportPath] = index
+			data.Imports = append(data.Imports, t.ImportPath)
+		}
+		data.Targets = append(data.Targets, generatorTarget{
+			Name:    t.Package + "_" + t.Name,
+			File:    file,
+			Import:  index,
+			Type:    t.Name,
+			Package: t.Package,
+			Dir:     t.Dir,
+		})
+	}
+	return data, nil
+}
+
+// renderProgram executes a program template
+func renderProgram(tmpl *template.Template, data programData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// generatorSource renders the program generating the schema files of the types
+func generatorSource(types []discoveredType, config generateConfig) ([]byte, error) {
+	data, err := newProgramData(types, config)
+	if err != nil {
+		return nil, err
+	}
+	return renderProgram(generatorTemplate, data)
+}
+
+// runGenerator runs the generated program from a temporary directory inside the current
+// module, so the packages of the discovered types resolve like in a regular build
+func runGenerator(command string, src []byte, stdout io.Writer) error {
+	gomod, err := exec.Command("go", "env", "GOMOD").Output()
+	if err != nil {
+		return fmt.Errorf("go env failed: %w", err)
+	}
+	root := filepath.Dir(strings.TrimSpace(string(gomod)))
+	if root == "." || strings.TrimSpace(string(gomod)) == os.DevNull {
+		return fmt.Errorf("gptschema %s must run inside a Go module", command)
+	}
+	dir, err := os.MkdirTemp(root, ".gptschema-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	main := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(main, src, 0o644); err != nil {
+		return err
+	}
+	cmd := exec.Command("go", "run", main)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("gptschema %s failed: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiscoverTypes(t *testing.T) {
+	pkgs := []listedPackage{{
+		ImportPath: "example.com/models",
+		Name:       "models",
+		Dir:        filepath.Join("testdata", "models"),
+		GoFiles:    []string{"models.go"},
+	}}
+	types, err := discoverTypes(pkgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := filepath.Join("testdata", "models")
+	expected := []discoveredType{
+		{ImportPath: "example.com/models", Package: "models", Name: "Address", Dir: dir},
+		{ImportPath: "example.com/models", Package: "models", Name: "Order", Dir: dir},
+	}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("expected %+v, got %+v", expected, types)
+	}
+}
+
+func TestDiscoverTypes_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkg      listedPackage
+		errorMsg string
+	}{
+		{
+			name: "unexported type",
+			pkg: listedPackage{
+				ImportPath: "example.com/invalid",
+				Name:       "invalid",
+				Dir:        filepath.Join("testdata", "invalid"),
+				GoFiles:    []string{"invalid.go"},
+			},
+			errorMsg: "type hidden must be exported",
+		},
+		{
+			name: "package main",
+			pkg: listedPackage{
+				ImportPath: "example.com/models",
+				Name:       "main",
+				Dir:        filepath.Join("testdata", "models"),
+				GoFiles:    []string{"models.go"},
+			},
+			errorMsg: "type Address is in package main, which cannot be imported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := discoverTypes([]listedPackage{tt.pkg})
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestGeneratorSource(t *testing.T) {
+	types := []discoveredType{
+		{ImportPath: "example.com/models", Package: "models", Name: "Address"},
+		{ImportPath: "example.com/billing", Package: "models", Name: "Invoice"},
+		{ImportPath: "example.com/models", Package: "models", Name: "Order"},
+	}
+	src, err := generatorSource(types, generateConfig{Out: "schemas", Strict: true, MaxDepth: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := format.Source(src); err != nil {
+		t.Fatalf("generated source is invalid: %v\n%s", err, src)
+	}
+	for _, part := range []string{
+		`p0 "example.com/models"`,
+		`p1 "example.com/billing"`,
+		`{"models.Address.schema.json", p0.Address{}}`,
+		`{"models.Invoice.schema.json", p1.Invoice{}}`,
+		`gptschema.WithStrict(true)`,
+		`gptschema.WithMaxDepth(20)`,
+	} {
+		if !bytes.Contains(src, []byte(part)) {
+			t.Errorf("generated source missing %s\n%s", part, src)
+		}
+	}
+}
+
+func TestGeneratorSource_DuplicateFiles(t *testing.T) {
+	types := []discoveredType{
+		{ImportPath: "example.com/shop/models", Package: "models", Name: "Order"},
+		{ImportPath: "example.com/legacy/models", Package: "models", Name: "Order"},
+	}
+	_, err := generatorSource(types, generateConfig{Out: "schemas", Strict: true, MaxDepth: 20})
+	expected := "types example.com/shop/models.Order and example.com/legacy/models.Order would both be written to models.Order.schema.json"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error containing %q, got %v", expected, err)
+	}
+}
+
+func TestGeneratorSource_Registry(t *testing.T) {
+	types := []discoveredType{
+		{ImportPath: "example.com/models", Package: "models", Name: "Address", Dir: "/src/models"},
+	}
+	src, err := generatorSource(types, generateConfig{Out: "schemas", Strict: true, MaxDepth: 20, Registry: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := format.Source(src); err != nil {
+		t.Fatalf("generated source is invalid: %v\n%s", err, src)
+	}
+	for _, part := range []string{
+		`{"/src/models", "models", "Address"}`,
+		`gptschema.WithGeneratedSchemas(false)`,
+		`"gptschema_generated.go"`,
+	} {
+		if !bytes.Contains(src, []byte(part)) {
+			t.Errorf("generated source missing %s\n%s", part, src)
+		}
+	}
+	plain, _ := generatorSource(types, generateConfig{Out: "schemas", Strict: true, MaxDepth: 20})
+	if bytes.Contains(plain, []byte("RegisterGeneratedSchema")) {
+		t.Errorf("expected no registry without the flag\n%s", plain)
+	}
+}
+
+func TestRunGenerate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	out := t.TempDir()
+	if err := runGenerate([]string{"-out", out, "./testdata/models"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"models.Address.schema.json", "models.Order.schema.json"} {
+		data, err := os.ReadFile(filepath.Join(out, name))
+		if err != nil {
+			t.Fatalf("expected schema file %s: %v", name, err)
+		}
+		if !bytes.Contains(data, []byte(`"additionalProperties": false`)) {
+			t.Errorf("unexpected schema in %s:\n%s", name, data)
+		}
+	}
+	if err := runGenerate([]string{"-out", out, "./testdata/invalid"}); err == nil {
+		t.Errorf("expected error for an unexported type")
+	}
+}
+
+func TestRunGenerate_Registry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	path := filepath.Join("testdata", "models", registryFile)
+	t.Cleanup(func() { os.Remove(path) })
+	if err := runGenerate([]string{"-out", t.TempDir(), "-registry", "./testdata/models"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected registry file: %v", err)
+	}
+	for _, part := range []string{
+		"package models",
+		"register(gptschema.RegisterGeneratedSchema[Address](",
+		"register(gptschema.RegisterGeneratedSchema[Order](",
+	} {
+		if !bytes.Contains(data, []byte(part)) {
+			t.Errorf("registry file missing %s\n%s", part, data)
+		}
+	}
+	// the package builds with the registry and the schemas are still generated from the types
+	if err := runGenerate([]string{"-out", t.TempDir(), "-registry", "./testdata/models"}); err != nil {
+		t.Fatalf("unexpected error with an existing registry: %v", err)
+	}
+}
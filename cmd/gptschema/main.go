@@ -0,0 +1,64 @@
+// Command gptschema generates JSON schemas for Go types from the command line.
+//
+// Usage:
+//
+//	gptschema <command> [flags] [packages]
+//
+// The commands are:
+//
+//	generate    write one schema file per type marked with a //gptschema:generate directive
+//	check       lint the schemas of the marked types, optionally against a live endpoint
+//	conform     compare the schemas of a conformance corpus with their expected files
+//	pipe        read the types to generate on stdin and write a bundle of schemas to stdout
+//
+// Run "gptschema <command> -h" for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `gptschema generates JSON schemas for Go types.
+
+Usage:
+
+	gptschema <command> [flags] [packages]
+
+The commands are:
+
+	generate    write one schema file per type marked with a //gptschema:generate directive
+	check       lint the schemas of the marked types, optionally against a live endpoint
+	conform     compare the schemas of a conformance corpus with their expected files
+	pipe        read the types to generate on stdin and write a bundle of schemas to stdout
+
+Run "gptschema <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	case "check":
+		err = runCheck(os.Args[2:])
+	case "conform":
+		err = runConform(os.Args[2:])
+	case "pipe":
+		err = runPipe(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "gptschema: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gptschema:", err)
+		os.Exit(1)
+	}
+}
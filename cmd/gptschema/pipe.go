@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// pipeRequest is a set of types read by the pipe command
+type pipeRequest struct {
+	// Package is an import path or a directory of the current module, e.g. ./models
+	Package string          `json:"package"`
+	Types   []string        `json:"types"`
+	Options json.RawMessage `json:"options"`
+}
+
+// pipeSchema is an entry of the bundle written by the pipe command
+type pipeSchema struct {
+	// Package is the import path of the type
+	Package string `json:"package"`
+	Type    string `json:"type"`
+	// Name is "package_Type", a valid response format name
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+// pipeBundle is the output of the pipe command
+type pipeBundle struct {
+	Schemas []pipeSchema `json:"schemas"`
+}
+
+func runPipe(args []string) error {
+	fs := flag.NewFlagSet("pipe", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), "Usage: gptschema pipe < request.json > bundle.json\n\n"+
+			"Reads the types to generate on stdin and writes a JSON bundle of their schemas to stdout,\n"+
+			"for build scripts outside Go. The request is a set, or an array of sets, of the form\n"+
+			"{\"package\": \"./models\", \"types\": [\"Address\", \"Order\"], \"options\": {\"strict\": true, \"max_depth\": 50}},\n"+
+			"where package is an import path or a directory of the current module and options is optional.\n"+
+			"The bundle is {\"schemas\": [{\"package\": ..., \"type\": ..., \"name\": ..., \"schema\": {...}}]},\n"+
+			"in the order of the request.\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("pipe reads its request on stdin, unexpected argument %q", fs.Arg(0))
+	}
+	return pipe(os.Stdin, os.Stdout)
+}
+
+// pipe generates the schemas requested on r and writes the bundle to w
+func pipe(r io.Reader, w io.Writer) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	requests, err := parsePipeRequests(data)
+	if err != nil {
+		return err
+	}
+	var types []discoveredType
+	var groups []string
+	var options []schemaOptions
+	for _, request := range requests {
+		found, err := resolvePipeRequest(request)
+		if err != nil {
+			return err
+		}
+		opts := defaultSchemaOptions()
+		if len(request.Options) > 0 {
+			if err := decodeStrict(request.Options, &opts); err != nil {
+				return fmt.Errorf("package %s: invalid options: %w", request.Package, err)
+			}
+		}
+		for _, t := range found {
+			types = append(types, t)
+			groups = append(groups, request.Package)
+			options = append(options, opts)
+		}
+	}
+	results, err := generateSchemas("pipe", types, groups, options)
+	if err != nil {
+		return err
+	}
+	bundle := pipeBundle{Schemas: make([]pipeSchema, 0, len(results))}
+	for i, result := range results {
+		if result.Error != "" {
+			return fmt.Errorf("%s.%s: %s", types[i].ImportPath, result.Type, result.Error)
+		}
+		bundle.Schemas = append(bundle.Schemas, pipeSchema{
+			Package: types[i].ImportPath,
+			Type:    types[i].Name,
+			Name:    types[i].Package + "_" + types[i].Name,
+			Schema:  result.Schema,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundle)
+}
+
+// parsePipeRequests decodes a request set or an array of sets
+func parsePipeRequests(data []byte) ([]pipeRequest, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty request, expected a JSON description of the types on stdin")
+	}
+	var requests []pipeRequest
+	if data[0] == '{' {
+		var request pipeRequest
+		if err := decodeStrict(data, &request); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+		requests = append(requests, request)
+	} else if err := decodeStrict(data, &requests); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	for i, request := range requests {
+		if request.Package == "" {
+			return nil, fmt.Errorf("invalid request: set %d has no package", i)
+		}
+		if len(request.Types) == 0 {
+			return nil, fmt.Errorf("invalid request: package %s has no types", request.Package)
+		}
+	}
+	return requests, nil
+}
+
+// decodeStrict decodes JSON rejecting unknown fields, so typos in requests are reported
+func decodeStrict(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// resolvePipeRequest lists the package of a request and checks that its types can be generated
+func resolvePipeRequest(request pipeRequest) ([]discoveredType, error) {
+	pkgs, err := listPackages([]string{request.Package})
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("package %s matches %d packages, expected one", request.Package, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if pkg.Name == "main" {
+		return nil, fmt.Errorf("package %s is package main, which cannot be imported", request.Package)
+	}
+	declared, err := declaredTypes(pkg)
+	if err != nil {
+		return nil, err
+	}
+	types := make([]discoveredType, len(request.Types))
+	for i, name := range request.Types {
+		spec, ok := declared[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("package %s has no type %s", request.Package, name)
+		case !spec.Name.IsExported():
+			return nil, fmt.Errorf("package %s: type %s must be exported", request.Package, name)
+		case spec.TypeParams != nil:
+			return nil, fmt.Errorf("package %s: generic type %s cannot be generated", request.Package, name)
+		}
+		types[i] = discoveredType{ImportPath: pkg.ImportPath, Package: pkg.Name, Name: name, Dir: pkg.Dir}
+	}
+	return types, nil
+}
+
+// declaredTypes returns the type declarations of a package by name
+func declaredTypes(pkg listedPackage) (map[string]*ast.TypeSpec, error) {
+	declared := make(map[string]*ast.TypeSpec)
+	fset := token.NewFileSet()
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+				for _, spec := range gen.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					declared[typeSpec.Name.Name] = typeSpec
+				}
+			}
+		}
+	}
+	return declared, nil
+}
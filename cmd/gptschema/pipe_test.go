@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParsePipeRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		errorMsg string
+	}{
+		{name: "single set", input: `{"package": "./models", "types": ["Address"]}`, expected: 1},
+		{
+			name:     "array of sets",
+			input:    `[{"package": "./models", "types": ["Address"]}, {"package": "./billing", "types": ["Invoice"], "options": {"strict": false}}]`,
+			expected: 2,
+		},
+		{name: "empty", input: " \n", errorMsg: "empty request"},
+		{name: "unknown field", input: `{"pkg": "./models", "types": ["Address"]}`, errorMsg: `unknown field "pkg"`},
+		{name: "no package", input: `[{"types": ["Address"]}]`, errorMsg: "set 0 has no package"},
+		{name: "no types", input: `{"package": "./models"}`, errorMsg: "package ./models has no types"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, err := parsePipeRequests([]byte(tt.input))
+			if tt.errorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(requests) != tt.expected {
+				t.Errorf("expected %d sets, got %+v", tt.expected, requests)
+			}
+		})
+	}
+}
+
+func TestPipe(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	request := `[
+		{"package": "./testdata/models", "types": ["Order", "Ignored"]},
+		{"package": "./testdata/models", "types": ["Address"], "options": {"strict": false}}
+	]`
+	var out bytes.Buffer
+	if err := pipe(strings.NewReader(request), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var bundle pipeBundle
+	if err := json.Unmarshal(out.Bytes(), &bundle); err != nil {
+		t.Fatalf("invalid bundle: %v\n%s", err, out.String())
+	}
+	var names []string
+	for _, schema := range bundle.Schemas {
+		names = append(names, schema.Name)
+		if schema.Package != "github.com/akane9506/gptschema/cmd/gptschema/testdata/models" {
+			t.Errorf("unexpected package %q", schema.Package)
+		}
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(schema.Schema, &decoded); err != nil || decoded["type"] != "object" {
+			t.Errorf("unexpected schema for %s: %s", schema.Name, schema.Schema)
+		}
+	}
+	if got := strings.Join(names, ","); got != "models_Order,models_Ignored,models_Address" {
+		t.Errorf("expected the schemas in request order, got %s", got)
+	}
+
+	for input, errorMsg := range map[string]string{
+		`{"package": "./testdata/models", "types": ["Missing"]}`:                         "package ./testdata/models has no type Missing",
+		`{"package": "./testdata/invalid", "types": ["hidden"]}`:                         "type hidden must be exported",
+		`{"package": "./testdata/models", "types": ["Order"], "options": {"strcit": 1}}`: `invalid options: json: unknown field "strcit"`,
+	} {
+		if err := pipe(strings.NewReader(input), &out); err == nil || !strings.Contains(err.Error(), errorMsg) {
+			t.Errorf("expected error containing %q, got %v", errorMsg, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// schemaOptions are the generation options of a group of types, read from JSON
+type schemaOptions struct {
+	Strict   bool `json:"strict"`
+	MaxDepth int  `json:"max_depth"`
+}
+
+// defaultSchemaOptions match the defaults of the command flags
+func defaultSchemaOptions() schemaOptions {
+	return schemaOptions{Strict: true, MaxDepth: 50}
+}
+
+// schemaTarget is a type converted with its own options
+type schemaTarget struct {
+	generatorTarget
+	// Group identifies the types converted together, e.g. a conformance case
+	Group    string
+	Strict   bool
+	MaxDepth int
+}
+
+// schemasData is the input of the schemas program
+type schemasData struct {
+	Imports []string
+	Targets []schemaTarget
+}
+
+// schemaResult is a line printed by the schemas program
+type schemaResult struct {
+	Group  string          `json:"group"`
+	Type   string          `json:"type"`
+	Schema json.RawMessage `json:"schema,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// program run with "go run" to print the schema of every type, one JSON line each
+var schemasTemplate = template.Must(template.New("schemas").Parse(`// Code generated by gptschema. DO NOT EDIT.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/akane9506/gptschema"
+{{- range $i, $path := .Imports}}
+	p{{$i}} {{printf "%q" $path}}
+{{- end}}
+)
+
+func main() {
+	targets := []struct {
+		group    string
+		typeName string
+		value    interface{}
+		opts     []gptschema.Option
+	}{
+{{- range .Targets}}
+		{ {{- printf "%q" .Group}}, {{printf "%q" .Type}}, p{{.Import}}.{{.Type}}{}, []gptschema.Option{gptschema.WithStrict({{.Strict}}), gptschema.WithMaxDepth({{.MaxDepth}})}},
+{{- end}}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	for _, target := range targets {
+		result := map[string]interface{}{"group": target.group, "type": target.typeName}
+		opts := append(target.opts, gptschema.WithGeneratedSchemas(false))
+		if schema, err := gptschema.GenerateSchemaBytes(target.value, opts...); err != nil {
+			result["error"] = err.Error()
+		} else {
+			result["schema"] = json.RawMessage(schema)
+		}
+		if err := encoder.Encode(result); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+`))
+
+// generateSchemas converts the types with the options of their group in a single program,
+// and returns the results in the order of the types
+func generateSchemas(command string, types []discoveredType, groups []string, options []schemaOptions) ([]schemaResult, error) {
+	program, err := newProgramData(types, nil)
+	if err != nil {
+		return nil, err
+	}
+	data := schemasData{Imports: program.Imports}
+	for i, target := range program.Targets {
+		data.Targets = append(data.Targets, schemaTarget{
+			generatorTarget: target,
+			Group:           groups[i],
+			Strict:          options[i].Strict,
+			MaxDepth:        options[i].MaxDepth,
+		})
+	}
+	var src bytes.Buffer
+	if err := schemasTemplate.Execute(&src, data); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := runGenerator(command, src.Bytes(), &out); err != nil {
+		return nil, err
+	}
+	return parseSchemaResults(&out)
+}
+
+// parseSchemaResults reads the lines printed by the schemas program
+func parseSchemaResults(r io.Reader) ([]schemaResult, error) {
+	var results []schemaResult
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		var result schemaResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("failed to decode program output: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, scanner.Err()
+}
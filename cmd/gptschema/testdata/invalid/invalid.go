@@ -0,0 +1,6 @@
+package invalid
+
+//gptschema:generate
+type hidden struct {
+	Value string `json:"value"`
+}
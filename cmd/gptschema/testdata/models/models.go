@@ -0,0 +1,24 @@
+package models
+
+// Address is generated through the directive on its declaration.
+//
+//gptschema:generate
+type Address struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type (
+	// Order is generated through the directive on its spec.
+	//
+	//gptschema:generate
+	Order struct {
+		ID      string  `json:"id"`
+		Address Address `json:"address"`
+	}
+
+	// Ignored has no directive.
+	Ignored struct {
+		Value string `json:"value"`
+	}
+)
@@ -0,0 +1,378 @@
+package gptschema
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Merge combines two object schemas, e.g. a base response generated from one struct and a
+// per-feature extension generated from another. The result has the properties of both and
+// requires the properties required by either. A property defined by both schemas must be
+// identical, otherwise ErrInvalidSchema is returned. additionalProperties is false if
+// either schema sets it to false. The inputs are not modified, the result shares their
+// property schemas.
+//
+// Example:
+//
+//	base, _ := GenerateSchema(BaseResponse{})
+//	extension, _ := GenerateSchema(SentimentFields{})
+//	schema, err := Merge(base, extension)
+func Merge(a, b *internal.Schema) (*internal.Schema, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("%w: cannot merge a nil schema", ErrInvalidSchema)
+	}
+	if (*a)["type"] != "object" || (*b)["type"] != "object" {
+		return nil, fmt.Errorf("%w: only object schemas can be merged", ErrInvalidSchema)
+	}
+	merged := make(internal.Schema, len(*a))
+	for k, v := range *a {
+		merged[k] = v
+	}
+	props := make(internal.Schema)
+	aProps, _ := (*a)["properties"].(internal.Schema)
+	bProps, _ := (*b)["properties"].(internal.Schema)
+	for name, prop := range aProps {
+		props[name] = prop
+	}
+	for name, prop := range bProps {
+		if existing, ok := props[name]; ok && !reflect.DeepEqual(existing, prop) {
+			return nil, fmt.Errorf("%w: property %q is defined differently in both schemas", ErrInvalidSchema, name)
+		}
+		props[name] = prop
+	}
+	merged["properties"] = props
+	var required []string
+	seen := make(map[string]bool)
+	for _, s := range []*internal.Schema{a, b} {
+		names, _ := (*s)["required"].([]string)
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				required = append(required, name)
+			}
+		}
+	}
+	delete(merged, "required")
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+	if (*a)["additionalProperties"] == false || (*b)["additionalProperties"] == false {
+		merged["additionalProperties"] = false
+	}
+	return &merged, nil
+}
+
+// Subtract returns the properties of object schema a that b does not define, e.g. to ask
+// a follow-up request for only the fields added since a first one. Objects defined by both
+// keep the nested properties b lacks and are dropped once nothing is left; other properties
+// defined by both are dropped even when they differ. Nullable objects, such as pointer
+// fields, are compared by their object variant and stay nullable. The result keeps the
+// keywords of a and requires the kept properties a required. The inputs are not modified.
+//
+// Example:
+//
+//	v1, _ := GenerateSchema(InvoiceV1{})
+//	v2, _ := GenerateSchema(InvoiceV2{})
+//	followUp, err := Subtract(v2, v1) // only the fields added in v2
+func Subtract(a, b *internal.Schema) (*internal.Schema, error) {
+	if err := checkObjectOperands("subtract", a, b); err != nil {
+		return nil, err
+	}
+	result := subtractProperties(*a, *b)
+	return &result, nil
+}
+
+// Intersect returns the properties both object schemas define identically, e.g. to compare
+// what the contracts of two teams share. Objects defined by both keep their shared nested
+// properties and are dropped when they share none, and stay nullable when both are; other
+// properties defined differently are dropped. The result keeps the keywords of a and
+// requires the kept properties both schemas require. The inputs are not modified.
+//
+// Example:
+//
+//	billing, _ := GenerateSchema(BillingCustomer{})
+//	support, _ := GenerateSchema(SupportCustomer{})
+//	shared, err := Intersect(billing, support)
+func Intersect(a, b *internal.Schema) (*internal.Schema, error) {
+	if err := checkObjectOperands("intersect", a, b); err != nil {
+		return nil, err
+	}
+	result := intersectProperties(*a, *b)
+	return &result, nil
+}
+
+// checkObjectOperands reports the operands of Subtract and Intersect that are not object
+// schemas
+func checkObjectOperands(operation string, a, b *internal.Schema) error {
+	if a == nil || b == nil {
+		return fmt.Errorf("%w: cannot %s a nil schema", ErrInvalidSchema, operation)
+	}
+	if (*a)["type"] != "object" || (*b)["type"] != "object" {
+		return fmt.Errorf("%w: only object schemas can be used to %s", ErrInvalidSchema, operation)
+	}
+	return nil
+}
+
+// subtractProperties returns a copy of a without the properties of b
+func subtractProperties(a, b internal.Schema) internal.Schema {
+	aProps, _ := a["properties"].(internal.Schema)
+	bProps, _ := b["properties"].(internal.Schema)
+	props := make(internal.Schema)
+	for name, prop := range aProps {
+		other, ok := bProps[name]
+		switch {
+		case !ok:
+			props[name] = prop
+		default:
+			object, wrap, _, ok := objectSchema(prop)
+			otherObject, _, _, otherOk := objectSchema(other)
+			if !ok || !otherOk {
+				continue
+			}
+			if rest := subtractProperties(object, otherObject); len(rest["properties"].(internal.Schema)) > 0 {
+				props[name] = wrap(rest)
+			}
+		}
+	}
+	required, _ := a["required"].([]string)
+	return withProperties(a, props, required)
+}
+
+// intersectProperties returns a copy of a with the properties b defines identically
+func intersectProperties(a, b internal.Schema) internal.Schema {
+	aProps, _ := a["properties"].(internal.Schema)
+	bProps, _ := b["properties"].(internal.Schema)
+	props := make(internal.Schema)
+	for name, prop := range aProps {
+		other, ok := bProps[name]
+		switch {
+		case !ok:
+		case reflect.DeepEqual(prop, other):
+			props[name] = prop
+		default:
+			object, wrap, nullable, ok := objectSchema(prop)
+			otherObject, _, otherNullable, otherOk := objectSchema(other)
+			if !ok || !otherOk {
+				continue
+			}
+			shared := intersectProperties(object, otherObject)
+			if len(shared["properties"].(internal.Schema)) == 0 {
+				continue
+			}
+			// the shared object is only nullable when both are
+			if nullable && otherNullable {
+				shared = wrap(shared)
+			}
+			props[name] = shared
+		}
+	}
+	bRequired, _ := b["required"].([]string)
+	requiredByB := make(map[string]bool, len(bRequired))
+	for _, name := range bRequired {
+		requiredByB[name] = true
+	}
+	aRequired, _ := a["required"].([]string)
+	var required []string
+	for _, name := range aRequired {
+		if requiredByB[name] {
+			required = append(required, name)
+		}
+	}
+	return withProperties(a, props, required)
+}
+
+// objectSchema returns the object schema of a property with properties, unwrapping the
+// anyOf of nullable objects, with a function putting a rewritten object back in the
+// wrapper next to its null variant
+func objectSchema(prop interface{}) (object internal.Schema, wrap func(internal.Schema) internal.Schema, nullable, ok bool) {
+	s, ok := prop.(internal.Schema)
+	if !ok {
+		return nil, nil, false, false
+	}
+	if hasObjectType(s) {
+		types, _ := s["type"].([]string)
+		return s, func(o internal.Schema) internal.Schema { return o }, containsType(types, "null"), true
+	}
+	variants, _ := s["anyOf"].([]internal.Schema)
+	if len(variants) != 2 {
+		return nil, nil, false, false
+	}
+	for i, variant := range variants {
+		null := variants[1-i]
+		if null["type"] != "null" || !hasObjectType(variant) {
+			continue
+		}
+		return variant, func(o internal.Schema) internal.Schema {
+			wrapped := make(internal.Schema, len(s))
+			for k, v := range s {
+				wrapped[k] = v
+			}
+			rewritten := make([]internal.Schema, 2)
+			rewritten[i], rewritten[1-i] = o, null
+			wrapped["anyOf"] = rewritten
+			return wrapped
+		}, true, true
+	}
+	return nil, nil, false, false
+}
+
+// hasObjectType reports whether a schema is an object with properties, nullable type
+// arrays included
+func hasObjectType(s internal.Schema) bool {
+	if _, ok := s["properties"].(internal.Schema); !ok {
+		return false
+	}
+	types, _ := s["type"].([]string)
+	return s["type"] == "object" || containsType(types, "object")
+}
+
+func containsType(types []string, t string) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+// withProperties returns a copy of s with the properties props, requiring the names of
+// required among them; dependentRequired only keeps the kept properties
+func withProperties(s, props internal.Schema, required []string) internal.Schema {
+	out := make(internal.Schema, len(s))
+	for k, v := range s {
+		out[k] = v
+	}
+	out["properties"] = props
+	var kept []string
+	for _, name := range required {
+		if _, ok := props[name]; ok {
+			kept = append(kept, name)
+		}
+	}
+	delete(out, "required")
+	if len(kept) > 0 {
+		out["required"] = kept
+	}
+	if dependents, ok := s["dependentRequired"].(internal.Schema); ok {
+		delete(out, "dependentRequired")
+		keptDependents := make(internal.Schema)
+		for name, v := range dependents {
+			names, _ := v.([]string)
+			var keptNames []string
+			for _, dependent := range names {
+				if _, ok := props[dependent]; ok {
+					keptNames = append(keptNames, dependent)
+				}
+			}
+			if _, ok := props[name]; ok && len(keptNames) > 0 {
+				keptDependents[name] = keptNames
+			}
+		}
+		if len(keptDependents) > 0 {
+			out["dependentRequired"] = keptDependents
+		}
+	}
+	return out
+}
+
+// AllOf composes schemas that must all be satisfied. Object schemas defining the same
+// property differently can never be satisfied together and are reported with ErrInvalidSchema.
+// OpenAI strict mode does not support allOf, use Merge to combine object schemas instead.
+func AllOf(schemas ...*internal.Schema) (*internal.Schema, error) {
+	composed, err := compose("allOf", schemas)
+	if err != nil {
+		return nil, err
+	}
+	props := make(internal.Schema)
+	for _, s := range schemas {
+		sProps, _ := (*s)["properties"].(internal.Schema)
+		for name, prop := range sProps {
+			if existing, ok := props[name]; ok && !reflect.DeepEqual(existing, prop) {
+				return nil, fmt.Errorf("%w: property %q is defined differently in allOf schemas", ErrInvalidSchema, name)
+			}
+			props[name] = prop
+		}
+	}
+	return composed, nil
+}
+
+// AnyOf composes schemas of which at least one must be satisfied. anyOf is supported by
+// OpenAI strict mode for properties, but the root schema must remain an object.
+//
+// Example:
+//
+//	card, _ := GenerateSchema(CardPayment{})
+//	transfer, _ := GenerateSchema(BankTransfer{})
+//	payment, err := AnyOf(card, transfer)
+func AnyOf(schemas ...*internal.Schema) (*internal.Schema, error) {
+	return compose("anyOf", schemas)
+}
+
+// OneOf composes schemas of which exactly one must be satisfied.
+// OpenAI strict mode does not support oneOf, AnyOf is the closest supported composition.
+func OneOf(schemas ...*internal.Schema) (*internal.Schema, error) {
+	return compose("oneOf", schemas)
+}
+
+// compose wraps the schemas under a composition keyword
+func compose(keyword string, schemas []*internal.Schema) (*internal.Schema, error) {
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("%w: %s requires at least one schema", ErrInvalidSchema, keyword)
+	}
+	variants := make([]internal.Schema, len(schemas))
+	for i, s := range schemas {
+		if s == nil {
+			return nil, fmt.Errorf("%w: %s schema %d is nil", ErrInvalidSchema, keyword, i)
+		}
+		variants[i] = *s
+	}
+	return &internal.Schema{keyword: variants}, nil
+}
+
+// Normalize flattens allOf compositions, including nested ones produced by AllOf or by
+// patches, into a single schema wherever the members combine, since OpenAI strict mode
+// does not accept allOf. Members defining the same property differently are reported with
+// ErrInvalidSchema; allOf with other conflicting keywords are left in place for Lint to report.
+//
+// Example:
+//
+//	composed, _ := AllOf(base, extension)
+//	schema, err := Normalize(composed)
+func Normalize(schema *internal.Schema) (*internal.Schema, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("%w: cannot normalize a nil schema", ErrInvalidSchema)
+	}
+	normalized, err := internal.FlattenAllOf(*schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return &normalized, nil
+}
+
+// Select returns a copy of an object schema keeping only the properties at paths, so one
+// canonical struct can back lighter extraction requests asking for a few of its fields.
+// Paths use the syntax of Schema.At: "lines[].qty" keeps the lines array with items
+// holding only qty, "address" keeps the whole address object. The objects on the way keep
+// their keywords and require the kept properties they required before, nullable ones stay
+// nullable. $defs reached through $refs, e.g. with WithDefs, are pruned by every path
+// reaching them. A path matching no property returns ErrInvalidSchema.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Invoice{})
+//	totals, err := Select(schema, "number", "total", "lines[].amount")
+func Select(schema *internal.Schema, paths ...string) (*internal.Schema, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("%w: cannot select from a nil schema", ErrInvalidSchema)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: no paths to select", ErrInvalidSchema)
+	}
+	selected, err := internal.SelectPaths(*schema, paths)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return &selected, nil
+}
@@ -0,0 +1,431 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestMerge(t *testing.T) {
+	base := &internal.Schema{
+		"type": "object",
+		"properties": internal.Schema{
+			"id":   internal.Schema{"type": "string"},
+			"note": internal.Schema{"type": "string"},
+		},
+		"required":             []string{"id", "note"},
+		"additionalProperties": false,
+	}
+	extension := &internal.Schema{
+		"type": "object",
+		"properties": internal.Schema{
+			"id":        internal.Schema{"type": "string"},
+			"sentiment": internal.Schema{"type": "string", "enum": []interface{}{"positive", "negative"}},
+		},
+		"required":             []string{"sentiment", "id"},
+		"additionalProperties": false,
+	}
+	merged, err := Merge(base, extension)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := internal.Schema{
+		"type": "object",
+		"properties": internal.Schema{
+			"id":        internal.Schema{"type": "string"},
+			"note":      internal.Schema{"type": "string"},
+			"sentiment": internal.Schema{"type": "string", "enum": []interface{}{"positive", "negative"}},
+		},
+		"required":             []string{"id", "note", "sentiment"},
+		"additionalProperties": false,
+	}
+	if !reflect.DeepEqual(*merged, expected) {
+		t.Errorf("expected %+v, got %+v", expected, *merged)
+	}
+	if props := (*base)["properties"].(internal.Schema); len(props) != 2 {
+		t.Errorf("expected base schema to be unchanged, got %v", props)
+	}
+	issues, err := Lint(merged)
+	if err != nil || len(issues) != 0 {
+		t.Errorf("expected merged schema to pass lint, got %v %v", issues, err)
+	}
+}
+
+func TestMerge_Invalid(t *testing.T) {
+	object := &internal.Schema{"type": "object", "properties": internal.Schema{"id": internal.Schema{"type": "string"}}}
+	tests := []struct {
+		name     string
+		a, b     *internal.Schema
+		errorMsg string
+	}{
+		{
+			name:     "conflicting property",
+			a:        object,
+			b:        &internal.Schema{"type": "object", "properties": internal.Schema{"id": internal.Schema{"type": "integer"}}},
+			errorMsg: `invalid schema: property "id" is defined differently in both schemas`,
+		},
+		{
+			name:     "non-object schema",
+			a:        object,
+			b:        &internal.Schema{"type": "string"},
+			errorMsg: "invalid schema: only object schemas can be merged",
+		},
+		{
+			name:     "nil schema",
+			a:        object,
+			errorMsg: "invalid schema: cannot merge a nil schema",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Merge(tt.a, tt.b)
+			if !errors.Is(err, ErrInvalidSchema) {
+				t.Fatalf("expected ErrInvalidSchema, got %v", err)
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("expected error %q, got %q", tt.errorMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestComposition(t *testing.T) {
+	card := &internal.Schema{"type": "object", "properties": internal.Schema{"number": internal.Schema{"type": "string"}}}
+	transfer := &internal.Schema{"type": "object", "properties": internal.Schema{"iban": internal.Schema{"type": "string"}}}
+	tests := []struct {
+		name    string
+		compose func(...*internal.Schema) (*internal.Schema, error)
+		keyword string
+	}{
+		{name: "allOf", compose: AllOf, keyword: "allOf"},
+		{name: "anyOf", compose: AnyOf, keyword: "anyOf"},
+		{name: "oneOf", compose: OneOf, keyword: "oneOf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := tt.compose(card, transfer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := internal.Schema{tt.keyword: []internal.Schema{*card, *transfer}}
+			if !reflect.DeepEqual(*schema, expected) {
+				t.Errorf("expected %+v, got %+v", expected, *schema)
+			}
+			if _, err := tt.compose(); !errors.Is(err, ErrInvalidSchema) {
+				t.Errorf("expected ErrInvalidSchema without schemas, got %v", err)
+			}
+			if _, err := tt.compose(card, nil); !errors.Is(err, ErrInvalidSchema) {
+				t.Errorf("expected ErrInvalidSchema for a nil schema, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAllOf_Conflict(t *testing.T) {
+	a := &internal.Schema{"type": "object", "properties": internal.Schema{"id": internal.Schema{"type": "string"}}}
+	b := &internal.Schema{"type": "object", "properties": internal.Schema{"id": internal.Schema{"type": "integer"}}}
+	_, err := AllOf(a, b)
+	if !errors.Is(err, ErrInvalidSchema) {
+		t.Fatalf("expected ErrInvalidSchema, got %v", err)
+	}
+	if _, err := AnyOf(a, b); err != nil {
+		t.Errorf("expected anyOf alternatives to allow differing properties, got %v", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	base := &internal.Schema{
+		"type":                 "object",
+		"properties":           internal.Schema{"id": internal.Schema{"type": "string"}},
+		"required":             []string{"id"},
+		"additionalProperties": false,
+	}
+	extension := &internal.Schema{
+		"type":                 "object",
+		"properties":           internal.Schema{"score": internal.Schema{"type": "number"}},
+		"required":             []string{"score"},
+		"additionalProperties": false,
+	}
+	composed, err := AllOf(base, extension)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	normalized, err := Normalize(composed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	merged, _ := Merge(base, extension)
+	if !reflect.DeepEqual(normalized, merged) {
+		t.Errorf("expected normalized allOf to match Merge, got %+v", *normalized)
+	}
+	if _, ok := (*composed)["allOf"]; !ok {
+		t.Errorf("expected the input schema to be left unchanged")
+	}
+	if _, err := Normalize(nil); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for a nil schema, got %v", err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	type Line struct {
+		SKU    string  `json:"sku"`
+		Amount float64 `json:"amount"`
+	}
+	type Invoice struct {
+		Number string  `json:"number"`
+		Notes  *string `json:"notes"`
+		Lines  []Line  `json:"lines"`
+	}
+	schema, err := GenerateSchema(Invoice{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	selected, err := Select(schema, "notes", "lines[].amount")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if required := (*selected)["required"]; !reflect.DeepEqual(required, []string{"notes", "lines"}) {
+		t.Errorf("expected the selected properties to stay required, got %v", required)
+	}
+	notes, _ := selected.At("notes")
+	if !reflect.DeepEqual(notes, (*schema)["properties"].(internal.Schema)["notes"]) {
+		t.Errorf("expected notes to stay nullable, got %+v", notes)
+	}
+	if _, ok := selected.At("lines[].sku"); ok {
+		t.Errorf("expected lines[].sku to be pruned")
+	}
+	if _, ok := selected.At("number"); ok {
+		t.Errorf("expected number to be pruned")
+	}
+	issues, err := Lint(selected)
+	if err != nil || len(issues) != 0 {
+		t.Errorf("expected the selected schema to pass lint, got %v %v", issues, err)
+	}
+	if _, ok := schema.At("lines[].sku"); !ok {
+		t.Errorf("expected the input schema to be left unchanged")
+	}
+	for _, paths := range [][]string{nil, {"total"}} {
+		if _, err := Select(schema, paths...); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("expected ErrInvalidSchema for %v, got %v", paths, err)
+		}
+	}
+}
+
+func TestSubtractAndIntersect(t *testing.T) {
+	a := &internal.Schema{
+		"type": "object",
+		"properties": internal.Schema{
+			"id":    internal.Schema{"type": "string"},
+			"score": internal.Schema{"type": "number"},
+			"total": internal.Schema{"type": "integer"},
+			"address": internal.Schema{
+				"type": "object",
+				"properties": internal.Schema{
+					"city": internal.Schema{"type": "string"},
+					"zip":  internal.Schema{"type": "string"},
+				},
+				"required":             []string{"city", "zip"},
+				"additionalProperties": false,
+			},
+		},
+		"required":             []string{"id", "score", "total", "address"},
+		"additionalProperties": false,
+	}
+	b := &internal.Schema{
+		"type": "object",
+		"properties": internal.Schema{
+			"id":    internal.Schema{"type": "string"},
+			"total": internal.Schema{"type": "number"},
+			"address": internal.Schema{
+				"type":                 "object",
+				"properties":           internal.Schema{"city": internal.Schema{"type": "string"}},
+				"required":             []string{"city"},
+				"additionalProperties": false,
+			},
+		},
+		"required":             []string{"total", "address"},
+		"additionalProperties": false,
+	}
+	tests := []struct {
+		name     string
+		apply    func(a, b *internal.Schema) (*internal.Schema, error)
+		expected internal.Schema
+	}{
+		{
+			name:  "subtract",
+			apply: Subtract,
+			expected: internal.Schema{
+				"type": "object",
+				"properties": internal.Schema{
+					"score": internal.Schema{"type": "number"},
+					"address": internal.Schema{
+						"type":                 "object",
+						"properties":           internal.Schema{"zip": internal.Schema{"type": "string"}},
+						"required":             []string{"zip"},
+						"additionalProperties": false,
+					},
+				},
+				"required":             []string{"score", "address"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name:  "intersect",
+			apply: Intersect,
+			expected: internal.Schema{
+				"type": "object",
+				"properties": internal.Schema{
+					"id": internal.Schema{"type": "string"},
+					"address": internal.Schema{
+						"type":                 "object",
+						"properties":           internal.Schema{"city": internal.Schema{"type": "string"}},
+						"required":             []string{"city"},
+						"additionalProperties": false,
+					},
+				},
+				"required":             []string{"address"},
+				"additionalProperties": false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.apply(a, b)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, *result)
+			}
+			if _, err := tt.apply(a, nil); !errors.Is(err, ErrInvalidSchema) {
+				t.Errorf("expected ErrInvalidSchema for a nil schema, got %v", err)
+			}
+			if _, err := tt.apply(a, &internal.Schema{"type": "string"}); !errors.Is(err, ErrInvalidSchema) {
+				t.Errorf("expected ErrInvalidSchema for a non-object schema, got %v", err)
+			}
+		})
+	}
+	if props := (*a)["properties"].(internal.Schema); len(props) != 4 {
+		t.Errorf("expected the input schema to be left unchanged, got %v", props)
+	}
+	if empty, _ := Subtract(a, a); len((*empty)["properties"].(internal.Schema)) != 0 {
+		t.Errorf("expected subtracting a schema from itself to leave no properties, got %+v", *empty)
+	}
+}
+
+func TestSubtractAndIntersect_NullableObjects(t *testing.T) {
+	type AddressV1 struct {
+		Street string `json:"street"`
+	}
+	type AddressV2 struct {
+		Street string `json:"street"`
+		Zip    string `json:"zip"`
+	}
+	type CompanyV1 struct {
+		HQ *AddressV1 `json:"hq,omitempty"`
+	}
+	type CompanyV2 struct {
+		HQ *AddressV2 `json:"hq,omitempty"`
+	}
+	type ProfileV1 struct {
+		Work    *AddressV1 `json:"work,omitempty"`
+		Company CompanyV1  `json:"company"`
+	}
+	type ProfileV2 struct {
+		Work    *AddressV2 `json:"work,omitempty"`
+		Company CompanyV2  `json:"company"`
+	}
+	v1, err := GenerateSchema(ProfileV1{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v2, err := GenerateSchema(ProfileV2{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nullableAddress := func(names ...string) internal.Schema {
+		props := make(internal.Schema)
+		for _, name := range names {
+			props[name] = internal.Schema{"type": "string"}
+		}
+		return internal.Schema{"anyOf": []internal.Schema{
+			{"type": "object", "properties": props, "required": names, "additionalProperties": false},
+			{"type": "null"},
+		}}
+	}
+	tests := []struct {
+		name     string
+		apply    func(a, b *internal.Schema) (*internal.Schema, error)
+		expected map[string]internal.Schema
+	}{
+		{
+			name:  "subtract keeps the added nested fields",
+			apply: Subtract,
+			expected: map[string]internal.Schema{
+				"work":       nullableAddress("zip"),
+				"company.hq": nullableAddress("zip"),
+			},
+		},
+		{
+			name:  "intersect keeps the shared nested fields",
+			apply: Intersect,
+			expected: map[string]internal.Schema{
+				"work":       nullableAddress("street"),
+				"company.hq": nullableAddress("street"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.apply(v2, v1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for path, expected := range tt.expected {
+				got, _ := result.At(path)
+				if !reflect.DeepEqual(got, expected) {
+					t.Errorf("%s: expected %+v, got %+v", path, expected, got)
+				}
+			}
+			issues, err := Lint(result)
+			if err != nil || len(issues) != 0 {
+				t.Errorf("expected the result to pass lint, got %v %v", issues, err)
+			}
+		})
+	}
+}
+
+func TestSelect_WithDefs(t *testing.T) {
+	type Line struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type Invoice struct {
+		Number  string `json:"number"`
+		Lines   []Line `json:"lines"`
+		Returns []Line `json:"returns"`
+	}
+	schema, err := GenerateSchema(Invoice{}, WithDefs(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	selected, err := Select(schema, "lines[].qty")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	qty, ok := selected.At("lines[].qty")
+	if !ok || qty["type"] != "integer" {
+		t.Errorf("expected lines[].qty to be kept through $defs, got %+v", *selected)
+	}
+	if _, ok := selected.At("lines[].sku"); ok {
+		t.Errorf("expected lines[].sku to be pruned")
+	}
+	if mismatches, err := Validate(selected, []byte(`{"lines":[{"qty":2}]}`)); err != nil || len(mismatches) != 0 {
+		t.Errorf("expected a pruned response to match, got %v %v", mismatches, err)
+	}
+	if _, ok := schema.At("returns[].sku"); !ok {
+		t.Errorf("expected the input schema to be left unchanged")
+	}
+}
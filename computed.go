@@ -0,0 +1,33 @@
+package gptschema
+
+import (
+	"reflect"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// RegisterComputed exposes the result of a method of T as a schema property, so schemas can
+// describe derived fields the model should produce even though no struct field exists.
+// The method must take no arguments and return a single value, optionally followed by an error.
+// Its return type is converted like a regular field and the property is always required.
+//
+// Computed properties are not part of the struct, so encoding/json ignores them when the
+// response is unmarshaled. Registration is global and safe for concurrent use.
+//
+// Example:
+//
+//	type User struct {
+//	    First string `json:"first"`
+//	    Last  string `json:"last"`
+//	}
+//
+//	func (u User) DisplayName() string { return u.First + " " + u.Last }
+//
+//	func init() {
+//	    if err := gptschema.RegisterComputed[User]("displayName", "DisplayName"); err != nil {
+//	        panic(err)
+//	    }
+//	}
+func RegisterComputed[T any](name, method string) error {
+	return internal.RegisterComputed(reflect.TypeOf((*T)(nil)).Elem(), name, method)
+}
@@ -0,0 +1,36 @@
+package gptschema
+
+import (
+	"strings"
+	"testing"
+)
+
+type testUser struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+}
+
+func (u testUser) DisplayName() string {
+	return u.First + " " + u.Last
+}
+
+func TestRegisterComputed(t *testing.T) {
+	if err := RegisterComputed[testUser]("displayName", "DisplayName"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := RegisterComputed[testUser]("fullName", "FullName"); err == nil {
+		t.Errorf("expected error for a missing method")
+	}
+	result, err := GenerateSchemaJSON(testUser{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		`"displayName":{"type":"string"}`,
+		`"required":["first","last","displayName"]`,
+	} {
+		if !strings.Contains(result, part) {
+			t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", part, result)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package gptschema
+
+import (
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// CheckConformance runs a schema through the checks a standard JSON Schema 2020-12
+// validator performs before accepting it, to catch drift between the schemas this package
+// emits and what validators accept:
+//
+//   - meta-schema: the value of a keyword does not match the bundled 2020-12 meta-schema,
+//     e.g. a string minimum or an unknown type
+//   - unknown-keyword: a keyword outside the 2020-12 vocabularies, rejected by validators in
+//     strict mode, e.g. the nullable keyword of OpenAPI 3.0
+//   - pattern-syntax: a pattern that does not compile with RE2, or uses syntax of Go regular
+//     expressions that ECMA-262 does not accept, such as named groups or \z
+//   - invalid-example, invalid-default: a value of examples or default that does not
+//     validate against its own schema
+//
+// Issue paths are the path of the keyword in the schema document for meta-schema issues,
+// and the property path for the others. An error is only returned for invalid options and
+// schemas that cannot be evaluated, such as unresolved $refs.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Order{})
+//	issues, err := CheckConformance(schema)
+//	for _, issue := range issues {
+//	    t.Error(issue) // qty: example 0 does not match the schema: qty: expected integer, got string ("2") (invalid-example)
+//	}
+func CheckConformance(schema *internal.Schema, opts ...Option) ([]LintIssue, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, ErrInvalidSchema
+	}
+	return internal.CheckConformance(*schema, options)
+}
+
+// CheckRegisteredConformance runs CheckConformance over every schema of a registry, including
+// each version registered with RegisterVersion, and every schema recorded with
+// RegisterGeneratedSchema. The result maps the schemas with issues to them: registry
+// entries by name ("invoice v2" for versions), generated schemas by Go type, followed by
+// their generation options when they differ from the defaults. Pass a nil registry to only
+// check the generated schemas. It is meant for tests guarding the schemas of an application.
+//
+// Example:
+//
+//	func TestSchemasConform(t *testing.T) {
+//	    failures, err := gptschema.CheckRegisteredConformance(registry)
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    for name, issues := range failures {
+//	        t.Errorf("%s: %v", name, issues)
+//	    }
+//	}
+func CheckRegisteredConformance(r *Registry, opts ...Option) (map[string][]LintIssue, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	failures := make(map[string][]LintIssue)
+	check := func(name string, schema internal.Schema) error {
+		issues, err := internal.CheckConformance(schema, options)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if len(issues) > 0 {
+			failures[name] = issues
+		}
+		return nil
+	}
+	if r != nil {
+		for _, name := range r.Names() {
+			versions := r.Versions(name)
+			if len(versions) == 0 {
+				entry, _ := r.Get(name)
+				if err := check(name, *entry.Schema); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			for _, version := range versions {
+				entry, _ := r.GetVersion(name, version)
+				if err := check(fmt.Sprintf("%s v%d", name, version), *entry.Schema); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, generated := range internal.GeneratedSchemas() {
+		name := generated.Type.String()
+		if generated.Options != "" {
+			name += " (" + generated.Options + ")"
+		}
+		if err := check(name, generated.Schema); err != nil {
+			return nil, err
+		}
+	}
+	return failures, nil
+}
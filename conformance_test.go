@@ -0,0 +1,98 @@
+package gptschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestCheckConformance(t *testing.T) {
+	type Line struct {
+		SKU string `json:"sku" description:"Stock keeping unit"`
+		Qty int    `json:"qty"`
+	}
+	type Order struct {
+		ID    string            `json:"id"`
+		Lines []Line            `json:"lines"`
+		Note  *string           `json:"note,omitempty"`
+		Tags  map[string]string `json:"tags" keys:"^[a-z]+$"`
+	}
+	for name, generate := range map[string]func() (*internal.Schema, error){
+		"strict":     func() (*internal.Schema, error) { return GenerateSchema(internal.Employee{}) },
+		"non-strict": func() (*internal.Schema, error) { return GenerateSchema(Order{}, WithStrict(false)) },
+	} {
+		schema, err := generate()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		issues, err := CheckConformance(schema)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(issues) != 0 {
+			t.Errorf("%s: expected generated schemas to conform, got %+v", name, issues)
+		}
+	}
+
+	schema := &internal.Schema{"type": "object", "properties": internal.Schema{
+		"qty": internal.Schema{"type": "integer", "examples": []interface{}{"2"}},
+	}}
+	issues, err := CheckConformance(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Rule != "invalid-example" || issues[0].Path != "qty" {
+		t.Errorf("expected an invalid-example issue, got %+v", issues)
+	}
+	if _, err := CheckConformance(nil); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for a nil schema, got %v", err)
+	}
+}
+
+type conformanceDrift struct {
+	Value string `json:"value"`
+}
+
+func TestCheckRegisteredConformance(t *testing.T) {
+	type Invoice struct {
+		Total float64 `json:"total"`
+	}
+	registry := NewRegistry()
+	if err := registry.Register("address", internal.Address{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterVersion("invoice", 1, Invoice{}); err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := registry.GetVersion("invoice", 1)
+	(*entry.Schema)["properties"].(internal.Schema)["total"].(internal.Schema)["minimum"] = "0"
+
+	err := RegisterGeneratedSchema[conformanceDrift](`{"type":"object","properties":{"value":{"type":"string","nullable":true}}}`, WithStrict(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures, err := CheckRegisteredConformance(registry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := failures["address"]; ok {
+		t.Errorf("expected address to conform, got %+v", failures["address"])
+	}
+	if issues := failures["invoice v1"]; len(issues) != 1 || issues[0].Rule != "meta-schema" {
+		t.Errorf("expected a meta-schema issue for invoice v1, got %+v", issues)
+	}
+	name := "gptschema.conformanceDrift (strict=false)"
+	if issues := failures[name]; len(issues) != 1 || !strings.Contains(issues[0].Message, `"nullable"`) {
+		t.Errorf("expected an unknown-keyword issue for %s, got %+v", name, failures)
+	}
+
+	generatedOnly, err := CheckRegisteredConformance(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := generatedOnly["invoice v1"]; ok || len(generatedOnly[name]) != 1 {
+		t.Errorf("expected only the generated schemas to be checked, got %+v", generatedOnly)
+	}
+}
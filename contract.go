@@ -0,0 +1,234 @@
+package gptschema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// ErrContractChanged is returned by VerifyFingerprints when a registered schema no longer
+// matches its recorded fingerprint.
+var ErrContractChanged = errors.New("schema contract changed")
+
+// ContractError lists the registered schemas whose fingerprint differs from the recorded one.
+// It wraps ErrContractChanged.
+type ContractError struct {
+	// Changed are the schemas whose fingerprint changed
+	Changed []string
+	// Missing are the recorded schemas that are no longer registered
+	Missing []string
+	// Added are the registered schemas without a recorded fingerprint
+	Added []string
+	// Changes are the differences of the changed schemas, set by VerifyContracts
+	Changes map[string][]SchemaChange
+
+	// hint tells how to resolve the error, the contract test hint when empty
+	hint string
+}
+
+func (e *ContractError) Error() string {
+	var parts []string
+	if len(e.Changed) > 0 {
+		parts = append(parts, "changed: "+strings.Join(e.Changed, ", "))
+	}
+	if len(e.Missing) > 0 {
+		parts = append(parts, "no longer registered: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Added) > 0 {
+		parts = append(parts, "not recorded: "+strings.Join(e.Added, ", "))
+	}
+	hint := e.hint
+	if hint == "" {
+		hint = "regenerate the contract test if the change is intended"
+	}
+	msg := fmt.Sprintf("%s (%s), %s", ErrContractChanged, strings.Join(parts, "; "), hint)
+	for _, name := range e.Changed {
+		for _, change := range e.Changes[name] {
+			msg += fmt.Sprintf("\n  %s: %s", name, change)
+		}
+	}
+	return msg
+}
+
+func (e *ContractError) Unwrap() error {
+	return ErrContractChanged
+}
+
+// Fingerprint returns the SHA-256 of the canonical JSON encoding of a schema, with sorted
+// keys, as "sha256:<hex>". Schemas describing the same contract have the same fingerprint.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(AddressItem{})
+//	fingerprint, _ := Fingerprint(schema) // sha256:3f1c...
+func Fingerprint(schema *internal.Schema) (string, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal schema to JSON: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// Fingerprints returns the fingerprint of every registered schema, keyed by name.
+// Versions registered with RegisterVersion are keyed as "name@v2".
+func (r *Registry) Fingerprints() (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fingerprints := make(map[string]string)
+	for _, name := range r.names {
+		entries := []*RegistryEntry{r.entries[name]}
+		if versions, ok := r.versions[name]; ok {
+			entries = entries[:0]
+			for _, entry := range versions {
+				entries = append(entries, entry)
+			}
+		}
+		for _, entry := range entries {
+			fingerprint, err := Fingerprint(entry.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("fingerprint %q: %w", name, err)
+			}
+			fingerprints[contractKey(entry)] = fingerprint
+		}
+	}
+	return fingerprints, nil
+}
+
+// contractKey names an entry in the recorded fingerprints
+func contractKey(entry *RegistryEntry) string {
+	if entry.Version > 0 {
+		return fmt.Sprintf("%s@v%d", entry.Name, entry.Version)
+	}
+	return entry.Name
+}
+
+// VerifyFingerprints compares the schemas of a registry with recorded fingerprints and
+// returns a *ContractError listing the changed, removed and unrecorded schemas, so any
+// struct change that alters a contract fails until the recording is consciously updated.
+// It is called by the test files generated with GenerateContractTest.
+//
+// Example:
+//
+//	err := VerifyFingerprints(registry, map[string]string{"address_item": "sha256:3f1c..."})
+func VerifyFingerprints(registry *Registry, golden map[string]string) error {
+	current, err := registry.Fingerprints()
+	if err != nil {
+		return err
+	}
+	contractErr := &ContractError{}
+	for name, fingerprint := range current {
+		recorded, ok := golden[name]
+		switch {
+		case !ok:
+			contractErr.Added = append(contractErr.Added, name)
+		case recorded != fingerprint:
+			contractErr.Changed = append(contractErr.Changed, name)
+		}
+	}
+	for name := range golden {
+		if _, ok := current[name]; !ok {
+			contractErr.Missing = append(contractErr.Missing, name)
+		}
+	}
+	if len(contractErr.Changed)+len(contractErr.Missing)+len(contractErr.Added) == 0 {
+		return nil
+	}
+	sort.Strings(contractErr.Changed)
+	sort.Strings(contractErr.Missing)
+	sort.Strings(contractErr.Added)
+	return contractErr
+}
+
+// ContractTestConfig configures the test file generated by GenerateContractTest.
+type ContractTestConfig struct {
+	// Package of the generated file
+	Package string
+	// Registry is the Go expression returning the registry in the test, e.g. "Contracts()"
+	Registry string
+	// TestName defaults to TestSchemaContracts
+	TestName string
+}
+
+// source template of the generated contract tests
+var contractTestTemplate = template.Must(template.New("contract").Parse(`// Code generated by gptschema. DO NOT EDIT.
+// Regenerate this file after an intended contract change.
+
+package {{.Package}}
+
+import (
+	"testing"
+
+	"github.com/akane9506/gptschema"
+)
+
+// contractFingerprints are the fingerprints of the registered schemas when this file was generated
+var contractFingerprints = map[string]string{
+{{- range .Fingerprints}}
+	{{printf "%q" .Name}}: {{printf "%q" .Fingerprint}},
+{{- end}}
+}
+
+func {{.TestName}}(t *testing.T) {
+	if err := gptschema.VerifyFingerprints({{.Registry}}, contractFingerprints); err != nil {
+		t.Fatal(err)
+	}
+}
+`))
+
+// GenerateContractTest emits the source of a Go test file asserting the current fingerprint
+// of each schema of the registry, so that a struct change altering a contract fails the tests
+// until the file is regenerated. Check the file in next to the registry and regenerate it,
+// e.g. from a go:generate directive, when a contract change is intended.
+//
+// Example:
+//
+//	src, err := GenerateContractTest(Contracts(), ContractTestConfig{Package: "models", Registry: "Contracts()"})
+//	_ = os.WriteFile("contracts_test.go", src, 0o644)
+func GenerateContractTest(registry *Registry, config ContractTestConfig) ([]byte, error) {
+	if config.Package == "" || config.Registry == "" {
+		return nil, fmt.Errorf("%w: contract test requires a package and a registry expression", ErrInvalidOption)
+	}
+	if config.TestName == "" {
+		config.TestName = "TestSchemaContracts"
+	}
+	fingerprints, err := registry.Fingerprints()
+	if err != nil {
+		return nil, err
+	}
+	type recorded struct{ Name, Fingerprint string }
+	data := struct {
+		ContractTestConfig
+		Fingerprints []recorded
+	}{ContractTestConfig: config}
+	for _, name := range sortedNames(fingerprints) {
+		data.Fingerprints = append(data.Fingerprints, recorded{Name: name, Fingerprint: fingerprints[name]})
+	}
+	var buf bytes.Buffer
+	if err := contractTestTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid contract test source: %w", ErrInvalidOption, err)
+	}
+	return src, nil
+}
+
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,99 @@
+package gptschema
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestFingerprint(t *testing.T) {
+	schema, _ := GenerateSchema(internal.Employee{})
+	fingerprint, err := Fingerprint(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(fingerprint, "sha256:") || len(fingerprint) != len("sha256:")+64 {
+		t.Errorf("unexpected fingerprint %q", fingerprint)
+	}
+	again, _ := GenerateSchema(&internal.Employee{})
+	if other, _ := Fingerprint(again); other != fingerprint {
+		t.Errorf("expected the same fingerprint for the same contract, got %q and %q", fingerprint, other)
+	}
+	changed, _ := GenerateSchema(InvoiceV1{})
+	if other, _ := Fingerprint(changed); other == fingerprint {
+		t.Errorf("expected a different fingerprint for a different contract")
+	}
+}
+
+func contractRegistry(t *testing.T) *Registry {
+	registry := NewRegistry()
+	if err := registry.Register("employee", internal.Employee{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = registry.RegisterVersion("invoice", 1, InvoiceV1{})
+	_ = registry.RegisterVersion("invoice", 2, InvoiceV2{})
+	return registry
+}
+
+func TestVerifyFingerprints(t *testing.T) {
+	registry := contractRegistry(t)
+	golden, err := registry.Fingerprints()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := sortedNames(golden); !reflect.DeepEqual(names, []string{"employee", "invoice@v1", "invoice@v2"}) {
+		t.Errorf("unexpected fingerprint names %v", names)
+	}
+	if err := VerifyFingerprints(registry, golden); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	stale := map[string]string{
+		"employee":   "sha256:0",
+		"invoice@v1": golden["invoice@v1"],
+		"contact":    golden["employee"],
+	}
+	err = VerifyFingerprints(registry, stale)
+	var contractErr *ContractError
+	if !errors.As(err, &contractErr) || !errors.Is(err, ErrContractChanged) {
+		t.Fatalf("expected a ContractError, got %v", err)
+	}
+	expected := &ContractError{Changed: []string{"employee"}, Missing: []string{"contact"}, Added: []string{"invoice@v2"}}
+	if !reflect.DeepEqual(contractErr, expected) {
+		t.Errorf("expected %+v, got %+v", expected, contractErr)
+	}
+	if !strings.Contains(err.Error(), "changed: employee; no longer registered: contact; not recorded: invoice@v2") {
+		t.Errorf("unexpected message %q", err)
+	}
+}
+
+func TestGenerateContractTest(t *testing.T) {
+	registry := contractRegistry(t)
+	golden, _ := registry.Fingerprints()
+	src, err := GenerateContractTest(registry, ContractTestConfig{Package: "models", Registry: "Contracts()"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		"package models",
+		`"employee":   "` + golden["employee"] + `",`,
+		`"invoice@v2": "` + golden["invoice@v2"] + `",`,
+		"func TestSchemaContracts(t *testing.T) {",
+		"gptschema.VerifyFingerprints(Contracts(), contractFingerprints)",
+	} {
+		if !bytes.Contains(src, []byte(part)) {
+			t.Errorf("generated source missing %s\n%s", part, src)
+		}
+	}
+
+	if _, err := GenerateContractTest(registry, ContractTestConfig{Package: "models"}); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption without a registry expression, got %v", err)
+	}
+	if _, err := GenerateContractTest(registry, ContractTestConfig{Package: "models", Registry: "Contracts(", TestName: "TestX"}); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption for invalid source, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package gptschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Unmarshal parses a model response into v, undoing the encodings applied by the
+// options used to generate the schema. Pass the same options given to GenerateSchema.
+//
+// For example, with WithInt64AsString the model emits 64-bit integers as numeric strings,
+// which Unmarshal converts back so they decode into int64/uint64 fields without losing precision.
+// Fields tagged with `json:",string"` are decoded by encoding/json directly.
+// WithLenientDecode also accepts scalars of a compatible type, such as "42" for an integer.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Order{}, WithInt64AsString(true))
+//	// ... send the schema to the model ...
+//	var order Order
+//	err := Unmarshal([]byte(content), &order, WithInt64AsString(true))
+func Unmarshal(data []byte, v interface{}, opts ...Option) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("unmarshal target must be a non-nil pointer, got %T", v)
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+	return decodeValue(raw, v, options)
+}
+
+// decodeValue stores a value decoded with UseNumber into the pointer v, undoing the
+// encodings applied by the options
+func decodeValue(raw interface{}, v interface{}, options *internal.Options) error {
+	coerced, err := json.Marshal(internal.CoerceValue(reflect.TypeOf(v), raw, options))
+	if err != nil {
+		return fmt.Errorf("failed to re-encode coerced value: %w", err)
+	}
+	return json.Unmarshal(coerced, v)
+}
+
+// WithLenientDecode makes Unmarshal convert scalars the model emitted with a compatible
+// type to the type of their field instead of failing: numeric strings such as "42" or
+// "2.5" for numbers, "true"/"false" strings and 1/0 for booleans, integral numbers such
+// as 42.0 for integers, and numbers or booleans for strings. Every conversion is reported
+// to the handler set with WithWarningHandler, with the path of the value, e.g.
+// `items[2].qty: coerced string "3" to integer`. Values that cannot be converted losslessly
+// are left to encoding/json, which reports them as errors.
+//
+// Example:
+//
+//	var order Order
+//	err := Unmarshal([]byte(`{"qty":"3","gift":1}`), &order,
+//	    WithLenientDecode(true),
+//	    WithWarningHandler(func(w Warning) { log.Println(w) }))
+func WithLenientDecode(enabled bool) Option {
+	return func(opts *internal.Options) {
+		opts.LenientDecode = enabled
+	}
+}
@@ -0,0 +1,185 @@
+package gptschema
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnmarshal_Int64AsString(t *testing.T) {
+	type Order struct {
+		ID    int64   `json:"id"`
+		Items []int64 `json:"items"`
+		Total float64 `json:"total,string"`
+	}
+	data := []byte(`{"id":"9007199254740993","items":["1","2"],"total":"12.5"}`)
+	var order Order
+	if err := Unmarshal(data, &order, WithInt64AsString(true)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.ID != 9007199254740993 {
+		t.Errorf("expected id 9007199254740993, got %d", order.ID)
+	}
+	if len(order.Items) != 2 || order.Items[0] != 1 || order.Items[1] != 2 {
+		t.Errorf("expected items [1 2], got %v", order.Items)
+	}
+	if order.Total != 12.5 {
+		t.Errorf("expected total 12.5, got %v", order.Total)
+	}
+}
+
+func TestUnmarshal_InvalidInputs(t *testing.T) {
+	type Order struct {
+		ID int64 `json:"id"`
+	}
+	tests := []struct {
+		name   string
+		data   []byte
+		target interface{}
+	}{
+		{name: "non-pointer target", data: []byte(`{"id":1}`), target: Order{}},
+		{name: "nil pointer target", data: []byte(`{"id":1}`), target: (*Order)(nil)},
+		{name: "malformed json", data: []byte(`{"id":`), target: &Order{}},
+		{name: "string without the option", data: []byte(`{"id":"1"}`), target: &Order{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Unmarshal(tt.data, tt.target); err == nil {
+				t.Errorf("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestUnmarshal_TimeFormat(t *testing.T) {
+	type Event struct {
+		StartsAt time.Time  `json:"startsAt"`
+		EndsAt   *time.Time `json:"endsAt"`
+	}
+	expected := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		format TimeFormat
+		data   string
+	}{
+		{name: "rfc3339", format: TimeRFC3339, data: `{"startsAt":"2024-03-01T00:00:00Z","endsAt":"2024-03-01T00:00:00Z"}`},
+		{name: "date", format: TimeDate, data: `{"startsAt":"2024-03-01","endsAt":"2024-03-01"}`},
+		{name: "unix seconds", format: TimeUnixSeconds, data: `{"startsAt":1709251200,"endsAt":1709251200}`},
+		{name: "unix millis", format: TimeUnixMillis, data: `{"startsAt":1709251200000,"endsAt":1709251200000}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event Event
+			if err := Unmarshal([]byte(tt.data), &event, WithTimeFormat(tt.format)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !event.StartsAt.Equal(expected) || event.EndsAt == nil || !event.EndsAt.Equal(expected) {
+				t.Errorf("expected %v, got %v and %v", expected, event.StartsAt, event.EndsAt)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_NamingConvention(t *testing.T) {
+	type User struct {
+		UserID    string
+		FirstName string `json:",omitempty"`
+		Email     string `json:"email"`
+	}
+	opts := []Option{WithNamingConvention(SnakeCase)}
+	schema, err := GenerateSchemaJSON(User{}, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(schema, `"required":["user_id","first_name","email"]`) {
+		t.Errorf("unexpected schema %s", schema)
+	}
+	var user User
+	data := []byte(`{"user_id":"u1","first_name":"Ada","email":"ada@example.com"}`)
+	if err := Unmarshal(data, &user, opts...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := User{UserID: "u1", FirstName: "Ada", Email: "ada@example.com"}
+	if user != expected {
+		t.Errorf("expected %+v, got %+v", expected, user)
+	}
+}
+
+func TestUnmarshal_NamingConventionShadowing(t *testing.T) {
+	type Base struct {
+		Name string
+	}
+	type Outer struct {
+		Base
+		Name string `json:"Name"`
+	}
+	opts := []Option{WithNamingConvention(SnakeCase)}
+	schema, err := GenerateSchemaJSON(Outer{}, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// like encoding/json, Outer.Name hides Base.Name before the naming convention applies
+	if !strings.Contains(schema, `"required":["Name"]`) || strings.Contains(schema, `"name"`) {
+		t.Errorf("unexpected schema %s", schema)
+	}
+	var outer Outer
+	if err := Unmarshal([]byte(`{"Name":"outer","name":"base"}`), &outer, opts...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outer.Name != "outer" || outer.Base.Name != "" {
+		t.Errorf("expected only Outer.Name to be set, got %+v", outer)
+	}
+}
+
+func TestUnmarshal_Inline(t *testing.T) {
+	type Address struct {
+		Street string `json:"street"`
+		City   string `json:"city"`
+	}
+	type Customer struct {
+		Name    string  `json:"name"`
+		Address Address `json:",inline"`
+	}
+	var customer Customer
+	data := []byte(`{"name":"Ada","street":"1 Main St","city":"London"}`)
+	if err := Unmarshal(data, &customer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Customer{Name: "Ada", Address: Address{Street: "1 Main St", City: "London"}}
+	if customer != expected {
+		t.Errorf("expected %+v, got %+v", expected, customer)
+	}
+}
+
+func TestUnmarshal_LenientDecode(t *testing.T) {
+	type Line struct {
+		Qty  int    `json:"qty"`
+		Gift bool   `json:"gift"`
+		Note string `json:"note"`
+	}
+	type Order struct {
+		Lines []Line `json:"lines"`
+	}
+	data := []byte(`{"lines":[{"qty":"3","gift":1,"note":42}]}`)
+	var order Order
+	if err := Unmarshal(data, &order); err == nil {
+		t.Fatalf("expected an error without lenient decoding")
+	}
+	var warnings []Warning
+	order = Order{}
+	err := Unmarshal(data, &order,
+		WithLenientDecode(true),
+		WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(order.Lines) != 1 || order.Lines[0] != (Line{Qty: 3, Gift: true, Note: "42"}) {
+		t.Errorf("expected coerced line, got %+v", order.Lines)
+	}
+	if len(warnings) != 3 {
+		t.Errorf("expected a warning per coercion, got %v", warnings)
+	}
+	if err := Unmarshal([]byte(`{"lines":[{"qty":"three"}]}`), &order, WithLenientDecode(true)); err == nil {
+		t.Errorf("expected an error for a value that cannot be coerced")
+	}
+}
@@ -0,0 +1,45 @@
+package gptschema
+
+import (
+	"os"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// WithDescriptionsFile describes fields with the descriptions of a sidecar file, so prompt
+// engineers can maintain them outside Go source. The file maps field paths as listed by
+// Paths to descriptions, the empty path describing the schema itself, in JSON (.json) or
+// YAML (.yaml, .yml). YAML files must be a flat mapping of strings, with dotted paths as
+// keys; long descriptions can use literal (|) or folded (>) blocks.
+//
+// A description from the file replaces the whole description of its field. Fields already
+// described differently, e.g. by a description tag, are reported to the warning handler,
+// as are the paths matching no field. The file is read when the option is applied, and an
+// unreadable or malformed file is reported with ErrInvalidOption. WithLocale translations
+// take precedence over the file.
+//
+// Example:
+//
+//	// descriptions.yaml:
+//	//   title: Title of the article, at most 10 words
+//	//   items[].price: >
+//	//     Unit price in cents,
+//	//     before discounts
+//	schema, err := GenerateSchema(Article{}, WithDescriptionsFile("descriptions.yaml"),
+//	    WithWarningHandler(func(w Warning) { log.Printf("gptschema: %s", w) }))
+func WithDescriptionsFile(name string) Option {
+	return func(opts *internal.Options) {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			opts.AddError("reading descriptions file: %v", err)
+			return
+		}
+		descriptions, err := internal.ParseDescriptionsFile(name, data)
+		if err != nil {
+			opts.AddError("%v", err)
+			return
+		}
+		opts.DescriptionsFile = name
+		opts.FileDescriptions = descriptions
+	}
+}
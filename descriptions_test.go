@@ -0,0 +1,56 @@
+package gptschema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithDescriptionsFile(t *testing.T) {
+	type Article struct {
+		Title string   `json:"title" description:"Title of the article"`
+		Tags  []string `json:"tags"`
+	}
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	yamlFile := write("descriptions.yaml", "title: Title, at most 10 words\ntags[]: >\n  A topic,\n  lowercase\n")
+
+	var warnings []string
+	schema, err := GenerateSchema(Article{}, WithDescriptionsFile(yamlFile),
+		WithWarningHandler(func(w Warning) { warnings = append(warnings, w.Path) }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for path, expected := range map[string]string{"title": "Title, at most 10 words", "tags[]": "A topic, lowercase"} {
+		field, _ := schema.At(path)
+		if field["description"] != expected {
+			t.Errorf("%s: expected %q, got %v", path, expected, field["description"])
+		}
+	}
+	if len(warnings) != 1 || warnings[0] != "title" {
+		t.Errorf("expected a conflict warning for title, got %v", warnings)
+	}
+
+	// translations take precedence over the file
+	translations := Translations{"fr": {"title": "Titre"}}
+	schema, err = GenerateSchema(Article{}, WithDescriptionsFile(yamlFile), WithLocale(translations, "fr"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if title, _ := schema.At("title"); title["description"] != "Titre" {
+		t.Errorf("expected the translation, got %v", title["description"])
+	}
+
+	for _, name := range []string{filepath.Join(dir, "missing.yaml"), write("bad.yaml", "customer:\n  name: Name\n")} {
+		if _, err := GenerateSchema(Article{}, WithDescriptionsFile(name)); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%s: expected ErrInvalidOption, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package gptschema
+
+import (
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Dialect is a JSON Schema dialect accepted by ConvertDialect.
+type Dialect = internal.Dialect
+
+// Dialects accepted by ConvertDialect.
+const (
+	// DialectDraft202012 is JSON Schema 2020-12, used by generated schemas and OpenAI
+	DialectDraft202012 = internal.DialectDraft202012
+	// DialectDraft07 is JSON Schema draft-07, still the default of many validators
+	DialectDraft07 = internal.DialectDraft07
+	// DialectOpenAPI30 is the OpenAPI 3.0 schema object
+	DialectOpenAPI30 = internal.DialectOpenAPI30
+)
+
+// ConvertDialect rewrites a schema for another JSON Schema dialect, so that one generation
+// pass can feed OpenAI, an OpenAPI document and a standard validator:
+//   - null types and null anyOf variants become nullable: true for OpenAPI 3.0, and back
+//   - $defs become definitions for draft-07, and back, rewriting the $refs
+//   - prefixItems become an items array for draft-07 tuples, and back
+//   - dependentRequired becomes dependencies for draft-07, and back
+//
+// OpenAPI 3.0 schemas cannot hold definitions, so $refs are inlined as by Inline, and
+// tuples are reported with ErrInvalidSchema. contains and dependentRequired are removed
+// with a warning. The input is not modified.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Order{})
+//	openapi, err := ConvertDialect(schema, DialectOpenAPI30) // "type":"string","nullable":true
+func ConvertDialect(schema *internal.Schema, dialect Dialect, opts ...Option) (*internal.Schema, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if !dialect.Valid() {
+		return nil, fmt.Errorf("%w: unknown dialect %q", ErrInvalidOption, dialect)
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("%w: cannot convert a nil schema", ErrInvalidSchema)
+	}
+	converted, err := internal.ConvertDialect(*schema, dialect, options)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	return &converted, nil
+}
@@ -0,0 +1,72 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestConvertDialect(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Order struct {
+		ID       string   `json:"id"`
+		Note     *string  `json:"note,omitempty"`
+		Billing  Address  `json:"billing"`
+		Shipping *Address `json:"shipping,omitempty"`
+	}
+	schema, err := GenerateSchema(Order{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	openapi, err := ConvertDialect(schema, DialectOpenAPI30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props := (*openapi)["properties"].(internal.Schema)
+	if note := props["note"]; !reflect.DeepEqual(note, internal.Schema{"type": "string", "nullable": true}) {
+		t.Errorf("expected a nullable string, got %+v", note)
+	}
+	if shipping := props["shipping"].(internal.Schema); shipping["type"] != "object" || shipping["nullable"] != true {
+		t.Errorf("expected a nullable object, got %+v", shipping)
+	}
+	back, err := ConvertDialect(openapi, DialectDraft202012)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(back, schema) {
+		t.Errorf("expected the round trip to restore %+v, got %+v", *schema, *back)
+	}
+
+	bundled, err := Bundle(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	draft07, err := ConvertDialect(bundled, DialectDraft07)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := (*draft07)["definitions"]; !ok {
+		t.Errorf("expected $defs to become definitions, got %+v", *draft07)
+	}
+	if billing := (*draft07)["properties"].(internal.Schema)["billing"]; !reflect.DeepEqual(billing, internal.Schema{"$ref": "#/definitions/Billing"}) {
+		t.Errorf("expected the $ref to be rewritten, got %+v", billing)
+	}
+}
+
+func TestConvertDialect_Invalid(t *testing.T) {
+	schema := &internal.Schema{"type": "string"}
+	if _, err := ConvertDialect(schema, Dialect("draft-04")); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+	tuple := &internal.Schema{"type": "array", "prefixItems": []internal.Schema{{"type": "string"}}}
+	if _, err := ConvertDialect(tuple, DialectOpenAPI30); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, got %v", err)
+	}
+	if _, err := ConvertDialect(nil, DialectDraft07); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for a nil schema, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package gptschema
+
+import (
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// SchemaChange is a difference between two versions of a schema: an added or removed
+// property, or a keyword of a property that changed. Breaking is set when a response
+// valid under the old schema may be rejected by the new one.
+type SchemaChange = internal.SchemaChange
+
+// ChangeKind classifies a SchemaChange.
+type ChangeKind = internal.ChangeKind
+
+// Kinds of schema changes.
+const (
+	// ChangeAdded is a property that only exists in the new schema
+	ChangeAdded = internal.ChangeAdded
+	// ChangeRemoved is a property that only exists in the old schema
+	ChangeRemoved = internal.ChangeRemoved
+	// ChangeModified is a keyword of a property that differs between the schemas
+	ChangeModified = internal.ChangeModified
+)
+
+// Diff compares two versions of a schema property by property and returns the changes
+// sorted by path, e.g. to review what a struct change does to a response contract.
+// Nullable properties are compared without their null variant, a change of nullability
+// is reported with the "nullable" keyword.
+//
+// Example:
+//
+//	oldSchema, _ := GenerateSchema(InvoiceV1{})
+//	newSchema, _ := GenerateSchema(InvoiceV2{})
+//	for _, change := range Diff(oldSchema, newSchema) {
+//	    log.Println(change) // currency: added required property (string)
+//	}
+func Diff(oldSchema, newSchema *internal.Schema) []SchemaChange {
+	return internal.DiffSchemas(*oldSchema, *newSchema)
+}
+
+// Changelog renders the changes between two schemas as a markdown changelog, with breaking
+// changes listed first, for the release notes of prompt and contract changes.
+//
+// Example:
+//
+//	notes := Changelog("Invoice contract", oldSchema, newSchema)
+//	// # Invoice contract
+//	//
+//	// ## Breaking changes
+//	//
+//	// - `currency`: added required property (string)
+func Changelog(title string, oldSchema, newSchema *internal.Schema) string {
+	return internal.RenderChangelog(title, Diff(oldSchema, newSchema))
+}
+
+// Changelog renders the changes between two versions registered with RegisterVersion as a
+// markdown changelog titled "<name>: version <from> to <to>".
+//
+// Example:
+//
+//	notes, err := registry.Changelog("invoice", 1, 2)
+func (r *Registry) Changelog(name string, from, to int) (string, error) {
+	oldEntry, ok := r.GetVersion(name, from)
+	if !ok {
+		return "", fmt.Errorf("%w: version %d of %q is not registered", ErrUnknownSchemaVersion, from, name)
+	}
+	newEntry, ok := r.GetVersion(name, to)
+	if !ok {
+		return "", fmt.Errorf("%w: version %d of %q is not registered", ErrUnknownSchemaVersion, to, name)
+	}
+	title := fmt.Sprintf("%s: version %d to %d", name, from, to)
+	return Changelog(title, oldEntry.Schema, newEntry.Schema), nil
+}
@@ -0,0 +1,45 @@
+package gptschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestDiff(t *testing.T) {
+	oldSchema, _ := GenerateSchema(internal.OrderV1{})
+	newSchema, _ := GenerateSchema(internal.OrderV2{})
+	changes := Diff(oldSchema, newSchema)
+	if len(changes) != 5 || changes[0].Path != "currency" || changes[0].Kind != ChangeAdded || !changes[0].Breaking {
+		t.Errorf("unexpected changes %v", changes)
+	}
+	notes := Changelog("Order contract", oldSchema, newSchema)
+	if !strings.HasPrefix(notes, "# Order contract\n\n## Breaking changes\n\n- `currency`: added required property (string)\n") {
+		t.Errorf("unexpected changelog\n%s", notes)
+	}
+}
+
+func TestRegistry_Changelog(t *testing.T) {
+	registry := NewRegistry()
+	_ = registry.RegisterVersion("invoice", 1, InvoiceV1{})
+	_ = registry.RegisterVersion("invoice", 2, InvoiceV2{})
+
+	notes, err := registry.Changelog("invoice", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "# invoice: version 1 to 2\n" +
+		"\n" +
+		"## Breaking changes\n" +
+		"\n" +
+		"- `currency`: added required property (string)\n" +
+		"- `schema_version`: const changed from 1 to 2\n"
+	if notes != expected {
+		t.Errorf("Changelog() =\n%s\nwant\n%s", notes, expected)
+	}
+	if _, err := registry.Changelog("invoice", 1, 3); !errors.Is(err, ErrUnknownSchemaVersion) {
+		t.Errorf("expected ErrUnknownSchemaVersion, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package gptschema
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// DynamicSchema is a schema whose properties are added and removed at runtime, e.g. when
+// users toggle which fields the model should extract. It starts from a generated base
+// schema. Every change is applied to a copy, linted, and published atomically, so concurrent
+// requests always read a complete schema that passed Lint, and a change failing it is not
+// applied. A DynamicSchema is safe for concurrent use.
+type DynamicSchema struct {
+	// mu serializes the changes, reads only load current
+	mu      sync.Mutex
+	current atomic.Pointer[FrozenSchema]
+	options *internal.Options
+}
+
+// SchemaEdit adds and removes properties within DynamicSchema.Update. Paths use the
+// syntax of Schema.At, e.g. "notes" or "lines[].discount".
+type SchemaEdit struct {
+	schema internal.Schema
+}
+
+// Add adds a required property to the object at the parent of path, as strict mode
+// requires; use SchemaBuilder.Nullable for properties the model may leave empty. An
+// existing property or an invalid builder is rejected with ErrInvalidSchema.
+func (e *SchemaEdit) Add(path string, prop *SchemaBuilder) error {
+	if prop == nil {
+		return fmt.Errorf("%w: property %q must not be nil", ErrInvalidSchema, path)
+	}
+	built, err := prop.build(path)
+	if err != nil {
+		return err
+	}
+	if err := internal.AddProperty(e.schema, path, built); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return nil
+}
+
+// Remove removes the property at path. A missing property is rejected with ErrInvalidSchema.
+func (e *SchemaEdit) Remove(path string) error {
+	if err := internal.RemoveProperty(e.schema, path); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return nil
+}
+
+// NewDynamicSchema starts a dynamic schema from a copy of base. The options select the
+// checks of Lint run after each change, such as WithStrict or WithProvider. A base schema
+// failing them returns a *LintError.
+//
+// Example:
+//
+//	base, _ := GenerateSchema(Invoice{})
+//	extraction, err := NewDynamicSchema(base)
+//	// per user settings
+//	err = extraction.Add("po_number", String().Nullable())
+//	schema := extraction.Schema() // send with the request
+func NewDynamicSchema(base *internal.Schema, opts ...Option) (*DynamicSchema, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return nil, fmt.Errorf("%w: dynamic schema requires a base schema", ErrInvalidSchema)
+	}
+	frozen := Freeze(base)
+	if issues := lintSchema(frozen.schema, options); len(issues) > 0 {
+		return nil, &LintError{Issues: issues}
+	}
+	d := &DynamicSchema{options: options}
+	d.current.Store(frozen)
+	return d, nil
+}
+
+// Schema returns the current schema. It does not change when properties are added or
+// removed later, a request keeps using the schema it started with.
+func (d *DynamicSchema) Schema() *FrozenSchema {
+	return d.current.Load()
+}
+
+// Update applies several changes at once: edit works on a copy of the current schema, which
+// is linted and published when edit returns nil. When edit or Lint fails, the schema is left
+// unchanged and the error, or a *LintError, is returned.
+//
+// Example:
+//
+//	err := extraction.Update(func(edit *SchemaEdit) error {
+//	    if err := edit.Remove("notes"); err != nil {
+//	        return err
+//	    }
+//	    return edit.Add("lines[].discount", Number().Minimum(0))
+//	})
+func (d *DynamicSchema) Update(edit func(*SchemaEdit) error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e := &SchemaEdit{schema: d.current.Load().Clone()}
+	if err := edit(e); err != nil {
+		return err
+	}
+	if issues := lintSchema(e.schema, d.options); len(issues) > 0 {
+		return &LintError{Issues: issues}
+	}
+	d.current.Store(&FrozenSchema{schema: e.schema})
+	return nil
+}
+
+// Add adds a required property at path, see SchemaEdit.Add.
+func (d *DynamicSchema) Add(path string, prop *SchemaBuilder) error {
+	return d.Update(func(e *SchemaEdit) error { return e.Add(path, prop) })
+}
+
+// Remove removes the property at path, see SchemaEdit.Remove.
+func (d *DynamicSchema) Remove(path string) error {
+	return d.Update(func(e *SchemaEdit) error { return e.Remove(path) })
+}
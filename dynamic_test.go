@@ -0,0 +1,99 @@
+package gptschema
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestDynamicSchema(t *testing.T) {
+	base, err := GenerateSchema(internal.Address{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dynamic, err := NewDynamicSchema(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before := dynamic.Schema()
+	if err := dynamic.Add("country", String().Enum("FR", "JP").Nullable()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := dynamic.Schema().At("country"); !ok {
+		t.Errorf("expected country to be added")
+	}
+	if _, ok := before.At("country"); ok {
+		t.Errorf("expected the previous schema to stay unchanged")
+	}
+	if _, ok := (*base)["properties"].(internal.Schema)["country"]; ok {
+		t.Errorf("expected the base schema to stay unchanged")
+	}
+	current := dynamic.Schema().Clone()
+	if issues, _ := Lint(&current); len(issues) != 0 {
+		t.Errorf("expected the schema to pass lint, got %v", issues)
+	}
+
+	// a failing update leaves the schema unchanged
+	err = dynamic.Update(func(edit *SchemaEdit) error {
+		if err := edit.Remove("city"); err != nil {
+			return err
+		}
+		return edit.Remove("missing")
+	})
+	if !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, got %v", err)
+	}
+	if _, ok := dynamic.Schema().At("city"); !ok {
+		t.Errorf("expected city to be kept after the failed update")
+	}
+	// a change failing lint is not applied
+	var lintErr *LintError
+	if err := dynamic.Add("meta", String().Exclude("n/a")); !errors.As(err, &lintErr) {
+		t.Errorf("expected a LintError, got %v", err)
+	} else if _, ok := dynamic.Schema().At("meta"); ok {
+		t.Errorf("expected meta not to be added")
+	}
+	if err := dynamic.Add("zip", Integer().MinLength(1)); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for an invalid builder, got %v", err)
+	}
+
+	if _, err := NewDynamicSchema(&internal.Schema{"type": "string"}); !errors.As(err, &lintErr) {
+		t.Errorf("expected a LintError for a base failing lint, got %v", err)
+	}
+	if _, err := NewDynamicSchema(nil); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, got %v", err)
+	}
+}
+
+func TestDynamicSchema_Concurrent(t *testing.T) {
+	base, _ := GenerateSchema(internal.Address{})
+	dynamic, err := NewDynamicSchema(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("field_%d", i)
+			if err := dynamic.Add(name, String()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			schema := dynamic.Schema().Clone()
+			if issues, _ := Lint(&schema); len(issues) != 0 {
+				t.Errorf("expected every published schema to pass lint, got %v", issues)
+			}
+		}()
+	}
+	wg.Wait()
+	if paths := dynamic.Schema().Paths(); len(paths) != 3+8 {
+		t.Errorf("expected 11 properties, got %d", len(paths))
+	}
+}
@@ -0,0 +1,55 @@
+package gptschema
+
+import (
+	"reflect"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// EnumValue is the set of types whose values can be registered as an enum.
+type EnumValue interface {
+	~string |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// RegisterEnum records the allowed values of a named type, so the enum definition
+// can live next to the type's constants. Registration is global and safe for concurrent use,
+// registering the same type again replaces its values.
+//
+// Every field of type T (or *T, []T...) emits the registered values as an enum without
+// repeating them in tags. Registered enums are also used as the property names of maps
+// keyed by T when WithExpandEnumMaps is enabled.
+//
+// Example:
+//
+//	type Weekday string
+//
+//	const (
+//	    Monday  Weekday = "mon"
+//	    Tuesday Weekday = "tue"
+//	)
+//
+//	func init() {
+//	    gptschema.RegisterEnum(Monday, Tuesday)
+//	}
+//
+//	type Shift struct {
+//	    Day Weekday `json:"day"` // {"type":"string","enum":["mon","tue"]}
+//	}
+func RegisterEnum[T EnumValue](values ...T) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	underlying := make([]interface{}, len(values))
+	for i, v := range values {
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.String:
+			underlying[i] = rv.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			underlying[i] = rv.Int()
+		default:
+			underlying[i] = rv.Uint()
+		}
+	}
+	internal.RegisterEnum(t, underlying)
+}
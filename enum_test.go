@@ -0,0 +1,108 @@
+package gptschema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type testWeekday string
+
+const (
+	testMonday  testWeekday = "mon"
+	testTuesday testWeekday = "tue"
+)
+
+func TestRegisterEnum_ExpandEnumMaps(t *testing.T) {
+	RegisterEnum(testMonday, testTuesday)
+	type Schedule struct {
+		Hours map[testWeekday]int `json:"hours"`
+	}
+	result, err := GenerateSchemaJSON(Schedule{}, WithExpandEnumMaps(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		`"mon":{"type":["integer","null"]}`,
+		`"tue":{"type":["integer","null"]}`,
+		`"required":["mon","tue"]`,
+	} {
+		if !strings.Contains(result, part) {
+			t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", part, result)
+		}
+	}
+}
+
+func TestRegisterEnum_ExpandEnumMapsRoundTrip(t *testing.T) {
+	RegisterEnum(testMonday, testTuesday)
+	type Schedule struct {
+		Hours map[testWeekday]int `json:"hours"`
+	}
+	schema, err := GenerateSchema(Schedule{}, WithExpandEnumMaps(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// strict mode requires every key, the model emits null for the missing ones
+	response := []byte(`{"hours":{"mon":8,"tue":null}}`)
+	if mismatches, err := Validate(schema, response); err != nil || len(mismatches) != 0 {
+		t.Fatalf("expected the response to match the schema, got %v %v", mismatches, err)
+	}
+	var schedule Schedule
+	if err := Unmarshal(response, &schedule, WithExpandEnumMaps(true)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(schedule.Hours, map[testWeekday]int{testMonday: 8}) {
+		t.Errorf("expected the null entry to be dropped, got %v", schedule.Hours)
+	}
+}
+
+type testStatus string
+
+func TestRegisterEnum_Fields(t *testing.T) {
+	RegisterEnum[testStatus]("draft", "published", "archived")
+	type Post struct {
+		Status testStatus `json:"status"`
+	}
+	result, err := GenerateSchemaJSON(Post{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `"status":{"enum":["draft","published","archived"],"type":"string"}`
+	if !strings.Contains(result, expected) {
+		t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", expected, result)
+	}
+}
+
+type testLevel int
+
+func (l testLevel) String() string {
+	switch l {
+	case 0:
+		return "Low"
+	case 1:
+		return "High"
+	default:
+		return "testLevel(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+func TestWithEnumDetection(t *testing.T) {
+	type Alert struct {
+		Level testLevel `json:"level"`
+	}
+	result, err := GenerateSchemaJSON(Alert{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `"level":{"enum":[0,1],"type":"integer"}`; !strings.Contains(result, expected) {
+		t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", expected, result)
+	}
+	result, err = GenerateSchemaJSON(Alert{}, WithEnumDetection(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `"level":{"type":"integer"}`; !strings.Contains(result, expected) {
+		t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", expected, result)
+	}
+}
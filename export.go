@@ -0,0 +1,207 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// schemaTitle returns the name of the Go type used as the title of exported documents
+func schemaTitle(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Name() == "" {
+		return "Schema"
+	}
+	return t.Name()
+}
+
+// ExportMarkdown generates the schema of v and renders it as a markdown field table
+// (name, type, required, nullable, description, constraints), so response contracts can be
+// pasted into design docs and runbooks. Nested fields are listed with their full path,
+// e.g. "companies[].address.city". Descriptions come from the `description` struct tag.
+//
+// Example:
+//
+//	doc, err := ExportMarkdown(Employee{})
+//	// # Employee
+//	//
+//	// | Field | Type | Required | Nullable | Description | Constraints |
+//	// | --- | --- | --- | --- | --- | --- |
+//	// | `name` | string | yes | no |  |  |
+//	// ...
+func ExportMarkdown(v interface{}, opts ...Option) (string, error) {
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return "", err
+	}
+	return internal.RenderMarkdown(schemaTitle(v), *schema), nil
+}
+
+// ExportHTML renders registered schemas into a small static HTML page, with a table of
+// contents, an anchor per schema and per field, and collapsible nested objects, for teams
+// publishing their LLM output contracts internally. All schemas of the registry are
+// rendered in registration order unless names are given.
+//
+// Example:
+//
+//	registry := NewRegistry()
+//	_ = registry.Register("address_item", AddressItem{})
+//	page, err := ExportHTML(registry, "LLM output contracts")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	_ = os.WriteFile("contracts.html", []byte(page), 0o644)
+func ExportHTML(registry *Registry, title string, names ...string) (string, error) {
+	if len(names) == 0 {
+		names = registry.Names()
+	}
+	docs := make([]internal.HTMLDocument, len(names))
+	for i, name := range names {
+		entry, ok := registry.Get(name)
+		if !ok {
+			return "", fmt.Errorf("schema %q is not registered", name)
+		}
+		docs[i] = internal.HTMLDocument{Name: name, Schema: *entry.Schema}
+	}
+	return internal.RenderHTML(title, docs)
+}
+
+// ExportMermaid generates the schema of v and renders its object graph as a Mermaid class
+// diagram, useful for reviewing deeply nested extraction schemas. Objects become classes
+// with their scalar fields as members, and object fields become edges labeled with the field
+// name ("[]" for arrays, "{}" for maps) and the multiplicity ("1", "0..1" when nullable,
+// "0..*" for arrays and maps). Nullable scalar members are suffixed with "?".
+//
+// Example:
+//
+//	diagram, err := ExportMermaid(Employee{})
+//	// classDiagram
+//	//     class Employee["Employee"] {
+//	//         name: string
+//	//         tags: array of string?
+//	//     }
+//	//     ...
+//	//     Employee --> "0..*" Employee_companies : companies[]
+func ExportMermaid(v interface{}, opts ...Option) (string, error) {
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return "", err
+	}
+	return internal.RenderMermaid(schemaTitle(v), *schema), nil
+}
+
+// ExportJTD generates the schema of v and converts it to a JSON Type Definition (RFC 8927),
+// for validation and code generation stacks standardized on JTD. The schema is generated
+// in non-strict mode with numeric formats, so maps become the values form, fields excluded
+// with required:"false" become optionalProperties and numbers get their JTD width: int32
+// for integers up to 32 bits, float32 and float64 for floats. JTD has no 64-bit integers,
+// int64 and uint64 fields are float64 unless WithInt64AsString is set. time.Time fields
+// are timestamps and string enums are enums. Constraints JTD cannot express are dropped,
+// unions other than nullable accept any value and are reported to the warning handler.
+//
+// Example:
+//
+//	jtd, err := ExportJTD(Order{})
+//	// {"properties":{"id":{"type":"string"},"total":{"type":"float64"}}}
+func ExportJTD(v interface{}, opts ...Option) ([]byte, error) {
+	opts = append(opts[:len(opts):len(opts)], WithStrict(false), WithNumericFormats(true))
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(internal.RenderJTD(*schema, options))
+}
+
+// BigQueryField is a column of a BigQuery table schema, see ExportBigQuery.
+type BigQueryField = internal.BigQueryField
+
+// ExportBigQuery generates the schema of v and converts it to a BigQuery table schema, the
+// JSON array of fields accepted by "bq mk --schema" and the table APIs, so extraction
+// pipelines create their destination tables from the same Go structs. The schema is
+// generated in non-strict mode so maps are allowed. Nested structs become RECORD fields,
+// slices REPEATED fields, and pointer, omitempty and required:"false" fields NULLABLE
+// fields. time.Time fields are TIMESTAMP, or DATE with WithTimeFormat(TimeDate). Maps,
+// unions and slices of slices, which BigQuery cannot type, become JSON columns, and
+// property names that are not valid column names are rewritten; both are reported to the
+// warning handler.
+//
+// Example:
+//
+//	fields, err := ExportBigQuery(Invoice{})
+//	_ = os.WriteFile("invoice.json", fields, 0o644)
+//	// [{"name":"id","type":"STRING","mode":"REQUIRED"},
+//	//  {"name":"lines","type":"RECORD","mode":"REPEATED","fields":[...]}]
+func ExportBigQuery(v interface{}, opts ...Option) ([]byte, error) {
+	opts = append(opts[:len(opts):len(opts)], WithStrict(false))
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(internal.RenderBigQuery(*schema, options))
+}
+
+// ExportLarkGrammar generates the schema of v and converts it to a Lark grammar accepting
+// the compact JSON of the schema, for OpenAI custom tools constrained by a grammar rather
+// than a JSON schema. Properties appear in the order of the schema, required ones in
+// declaration order. Objects, arrays, scalars, enums and nullable values are supported;
+// optional properties are left out and constraints such as patterns or lengths are not
+// enforced, both are reported to the warning handler. Maps and unions cannot be expressed
+// and return ErrUnsupportedType.
+//
+// Example:
+//
+//	grammar, err := ExportLarkGrammar(Ticket{})
+//	// start: "{" "\"title\"" ":" STRING "," "\"priority\"" ":" ("\"low\"" | "\"high\"") "}"
+//	//
+//	// STRING: /"(?:[^"\\\x00-\x1f]|\\(?:["\\\/bfnrt]|u[0-9a-fA-F]{4}))*"/
+//	tool := map[string]any{"type": "custom", "name": "file_ticket",
+//	    "format": map[string]any{"type": "grammar", "syntax": "lark", "definition": grammar}}
+func ExportLarkGrammar(v interface{}, opts ...Option) (string, error) {
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return "", err
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return "", err
+	}
+	return internal.RenderLarkGrammar(*schema, options)
+}
+
+// ExportRegex generates the schema of v and converts it to a regular expression matching
+// its compact JSON, for providers that only constrain outputs with a regex. Only flat
+// structs are supported: their fields must be scalars, enums or constants, nullable ones
+// included; other fields return ErrUnsupportedType. Properties appear in the order of the
+// schema; optional properties are left out and constraints such as patterns or lengths are
+// not enforced, both are reported to the warning handler. The expression is not anchored,
+// providers match it against the whole output.
+//
+// Example:
+//
+//	pattern, err := ExportRegex(Verdict{})
+//	// \{"label":(?:"spam"|"ham"),"confidence":-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?\}
+//	check := regexp.MustCompile(`^(?:` + pattern + `)$`)
+func ExportRegex(v interface{}, opts ...Option) (string, error) {
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return "", err
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return "", err
+	}
+	return internal.RenderRegex(*schema, options)
+}
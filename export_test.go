@@ -0,0 +1,189 @@
+package gptschema
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestExportMarkdown(t *testing.T) {
+	doc, err := ExportMarkdown(&internal.Employee{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		"# Employee\n",
+		"| `companies` | array of object | yes | no |  |  |\n",
+		"| `companies[].address.zip_code` | string | yes | yes |  |  |\n",
+		"| `tags` | array of string | yes | yes |  |  |\n",
+	} {
+		if !strings.Contains(doc, part) {
+			t.Errorf("ExportMarkdown() result missing expected part %q\nGot:\n%s", part, doc)
+		}
+	}
+	if _, err := ExportMarkdown(nil); err == nil {
+		t.Errorf("ExportMarkdown() expected error but got none")
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("employee", internal.Employee{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Register("contact", internal.DescribedContact{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page, err := ExportHTML(registry, "Contracts")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(page, `<section id="schema-employee">`) || !strings.Contains(page, `<section id="schema-contact">`) {
+		t.Errorf("expected a section per schema\nGot:\n%s", page)
+	}
+	page, err = ExportHTML(registry, "Contracts", "contact")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(page, `<section id="schema-employee">`) {
+		t.Errorf("expected only the selected schema\nGot:\n%s", page)
+	}
+	if _, err := ExportHTML(registry, "Contracts", "missing"); err == nil {
+		t.Errorf("expected error for an unregistered name")
+	}
+}
+
+func TestExportMermaid(t *testing.T) {
+	diagram, err := ExportMermaid(internal.Employee{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		"classDiagram\n",
+		`Employee --> "0..*" Employee_companies : companies[]`,
+		"zip_code: string?",
+	} {
+		if !strings.Contains(diagram, part) {
+			t.Errorf("ExportMermaid() result missing expected part %q\nGot:\n%s", part, diagram)
+		}
+	}
+}
+
+func TestExportJTD(t *testing.T) {
+	type Order struct {
+		ID       string            `json:"id"`
+		Quantity int16             `json:"quantity"`
+		Total    float64           `json:"total"`
+		Created  time.Time         `json:"created"`
+		Labels   map[string]string `json:"labels"`
+		Note     *string           `json:"note,omitempty"`
+		Coupon   string            `json:"coupon" required:"false"`
+	}
+	jtd, err := ExportJTD(Order{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"optionalProperties":{"coupon":{"type":"string"}},"properties":{"created":{"type":"timestamp"},` +
+		`"id":{"type":"string"},"labels":{"values":{"type":"string"}},"note":{"nullable":true,"type":"string"},` +
+		`"quantity":{"type":"int32"},"total":{"type":"float64"}}}`
+	if string(jtd) != expected {
+		t.Errorf("expected %s, got %s", expected, jtd)
+	}
+	if _, err := ExportJTD(nil); err == nil {
+		t.Errorf("ExportJTD() expected error but got none")
+	}
+}
+
+func TestExportBigQuery(t *testing.T) {
+	type Line struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type Invoice struct {
+		ID      string            `json:"id" description:"Invoice number"`
+		Created time.Time         `json:"created"`
+		Lines   []Line            `json:"lines"`
+		Note    *string           `json:"note,omitempty"`
+		Labels  map[string]string `json:"labels"`
+	}
+	var warnings []Warning
+	fields, err := ExportBigQuery(Invoice{}, WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `[{"name":"id","type":"STRING","mode":"REQUIRED","description":"Invoice number"},` +
+		`{"name":"created","type":"TIMESTAMP","mode":"REQUIRED"},` +
+		`{"name":"lines","type":"RECORD","mode":"REPEATED","fields":[{"name":"sku","type":"STRING","mode":"REQUIRED"},{"name":"qty","type":"INTEGER","mode":"REQUIRED"}]},` +
+		`{"name":"note","type":"STRING","mode":"NULLABLE"},` +
+		`{"name":"labels","type":"JSON","mode":"REQUIRED"}]`
+	if string(fields) != expected {
+		t.Errorf("expected %s, got %s", expected, fields)
+	}
+	if len(warnings) != 1 || warnings[0].Path != "labels" {
+		t.Errorf("expected a warning for the map, got %v", warnings)
+	}
+	if _, err := ExportBigQuery(nil); err == nil {
+		t.Errorf("ExportBigQuery() expected error but got none")
+	}
+}
+
+func TestExportLarkGrammar(t *testing.T) {
+	type Line struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type Ticket struct {
+		Title string   `json:"title"`
+		Lines []Line   `json:"lines"`
+		Score *float64 `json:"score"`
+	}
+	grammar, err := ExportLarkGrammar(Ticket{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `start: "{" "\"title\"" ":" STRING "," "\"lines\"" ":" "[" (lines_item ("," lines_item)*)? "]" "," "\"score\"" ":" NUMBER "}"
+lines_item: "{" "\"sku\"" ":" STRING "," "\"qty\"" ":" INTEGER "}"
+
+STRING: /"(?:[^"\\\x00-\x1f]|\\(?:["\\\/bfnrt]|u[0-9a-fA-F]{4}))*"/
+INTEGER: /-?(?:0|[1-9][0-9]*)/
+NUMBER: /-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?/
+`
+	if grammar != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, grammar)
+	}
+	type Tagged struct {
+		Labels map[string]string `json:"labels"`
+	}
+	if _, err := ExportLarkGrammar(Tagged{}, WithStrict(false)); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType for a map, got %v", err)
+	}
+}
+
+func TestExportRegex(t *testing.T) {
+	type Verdict struct {
+		Spam       bool    `json:"spam"`
+		Confidence float64 `json:"confidence"`
+		Reason     *string `json:"reason"`
+	}
+	pattern, err := ExportRegex(Verdict{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	matcher := regexp.MustCompile(`^(?:` + pattern + `)$`)
+	if !matcher.MatchString(`{"spam":true,"confidence":0.93,"reason":"link farm"}`) {
+		t.Errorf("expected %s to match a serialized Verdict", pattern)
+	}
+	if matcher.MatchString(`{"spam":"yes","confidence":0.93,"reason":"link farm"}`) {
+		t.Errorf("expected %s to reject a string for a boolean", pattern)
+	}
+	type Nested struct {
+		Verdict Verdict `json:"verdict"`
+	}
+	if _, err := ExportRegex(Nested{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType for a nested struct, got %v", err)
+	}
+}
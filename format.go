@@ -0,0 +1,34 @@
+package gptschema
+
+import (
+	"reflect"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// RegisterFormatDetector adds a function mapping struct fields to a JSON Schema "format",
+// so ID, email or URL wrapper types can be annotated centrally without per-field tags.
+// The detector receives the field type with pointers removed and the struct field itself.
+// Detectors are consulted in registration order for every field and the first match wins.
+// Formats apply to scalar properties only, objects and arrays are left unchanged.
+// Registration is global and safe for concurrent use.
+//
+// OpenAI strict mode supports a fixed set of string formats such as "email", "uuid",
+// "date-time" or "hostname", see Lint for the other restrictions.
+//
+// Example:
+//
+//	type Email string
+//
+//	func init() {
+//	    gptschema.RegisterFormatDetector(func(t reflect.Type, _ reflect.StructField) (string, bool) {
+//	        return "email", t == reflect.TypeOf(Email(""))
+//	    })
+//	}
+//
+//	type Contact struct {
+//	    Email Email `json:"email"` // {"type":"string","format":"email"}
+//	}
+func RegisterFormatDetector(detector func(t reflect.Type, field reflect.StructField) (format string, ok bool)) {
+	internal.RegisterFormatDetector(detector)
+}
@@ -0,0 +1,34 @@
+package gptschema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testHomepage string
+
+type testSite struct {
+	Home testHomepage `json:"home"`
+	Docs string       `json:"docs" link:"true"`
+	Name string       `json:"name"`
+}
+
+func TestRegisterFormatDetector(t *testing.T) {
+	RegisterFormatDetector(func(t reflect.Type, field reflect.StructField) (string, bool) {
+		return "uri", t == reflect.TypeOf(testHomepage("")) || field.Tag.Get("link") == "true"
+	})
+	result, err := GenerateSchemaJSON(testSite{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		`"home":{"format":"uri","type":"string"}`,
+		`"docs":{"format":"uri","type":"string"}`,
+		`"name":{"type":"string"}`,
+	} {
+		if !strings.Contains(result, part) {
+			t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", part, result)
+		}
+	}
+}
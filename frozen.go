@@ -0,0 +1,67 @@
+package gptschema
+
+import (
+	"encoding/json"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// FrozenSchema is an immutable schema, safe to cache and share between concurrent requests.
+// Generated schemas are plain maps: a caller adding a description to the schema of one
+// request would otherwise change it for every request sharing it. A FrozenSchema holds a
+// private copy and only hands out copies, which callers may modify freely.
+type FrozenSchema struct {
+	schema internal.Schema
+}
+
+// Freeze returns an immutable copy of a schema, later changes to schema do not affect it.
+// Interned subschemas (see WithInternedSubschemas) are copied too.
+//
+// Example:
+//
+//	var invoiceSchema = Freeze(MustGenerateSchema(Invoice{}))
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	    schema := invoiceSchema.Clone()
+//	    schema["description"] = "Invoice of " + r.URL.Query().Get("customer") // only this request sees it
+//	    // ...
+//	}
+func Freeze(schema *internal.Schema) *FrozenSchema {
+	if schema == nil {
+		return &FrozenSchema{}
+	}
+	return &FrozenSchema{schema: schema.Clone()}
+}
+
+// Clone returns a copy of the schema that callers may modify.
+func (f *FrozenSchema) Clone() internal.Schema {
+	return f.schema.Clone()
+}
+
+// At returns a copy of the schema at a field path, see LeafPath.
+func (f *FrozenSchema) At(path string) (internal.Schema, bool) {
+	s, ok := f.schema.At(path)
+	if !ok {
+		return nil, false
+	}
+	return s.Clone(), true
+}
+
+// Paths returns the scalar leaves of the schema, see LeafPath.
+func (f *FrozenSchema) Paths() []LeafPath {
+	return f.schema.Paths()
+}
+
+// MarshalJSON marshals the schema without copying it.
+func (f *FrozenSchema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.schema)
+}
+
+// String returns the schema as JSON.
+func (f *FrozenSchema) String() string {
+	data, err := f.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,52 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestFreeze(t *testing.T) {
+	schema := MustGenerateSchema(internal.Employee{})
+	expected, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frozen := Freeze(schema)
+
+	// changes to the original schema and to the copies handed out are not seen by the frozen schema
+	(*schema)["description"] = "modified"
+	clone := frozen.Clone()
+	clone["properties"].(internal.Schema)["name"].(internal.Schema)["type"] = "integer"
+	address, ok := frozen.At("companies[].address")
+	if !ok {
+		t.Fatalf("expected a schema at companies[].address")
+	}
+	address["required"].([]string)[0] = "modified"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := json.Marshal(frozen)
+			if err != nil || string(data) != string(expected) {
+				t.Errorf("expected %s, got %s (%v)", expected, data, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if frozen.String() != string(expected) {
+		t.Errorf("expected %s, got %s", expected, frozen.String())
+	}
+	if !reflect.DeepEqual(frozen.Paths(), internal.EmployeeSchema.Paths()) {
+		t.Errorf("unexpected paths %v", frozen.Paths())
+	}
+	if _, ok := frozen.At("missing"); ok {
+		t.Errorf("expected no schema at an unknown path")
+	}
+}
@@ -0,0 +1,149 @@
+package gptschema
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// FuncSchema holds the definition of a tool function: the name and description declared
+// with the `tool` tag of its argument struct, the parameters schema sent in the tools array
+// and the schema of the result it returns.
+type FuncSchema struct {
+	Name        string
+	Description string
+	Parameters  *internal.Schema
+	// Output is nil when the result is an interface, such as any, whose shape is unknown
+	Output *internal.Schema
+}
+
+// SchemaForFunc reflects over the signature of a tool function and generates its parameters
+// schema from the argument struct and its output schema from the result type. It accepts
+// the same functions and Execute structs as ToolSet.Register, as well as functions with
+// positional parameters wrapped with NamedParams.
+//
+// Example:
+//
+//	schemas, err := SchemaForFunc(func(ctx context.Context, args WeatherArgs) (Weather, error) {
+//	    return weatherService.Lookup(ctx, args.City)
+//	})
+//	// schemas.Parameters: {"type":"object","properties":{"city":{...}},...}
+//	// schemas.Output: {"type":"object","properties":{"temperature":{...}},...}
+func SchemaForFunc(fn interface{}, opts ...Option) (*FuncSchema, error) {
+	tool, err := newTool(fn)
+	if err != nil {
+		return nil, err
+	}
+	parameters, err := GenerateSchema(reflect.New(tool.args).Interface(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("parameters: %w", err)
+	}
+	meta, err := internal.ParseToolTag(tool.args)
+	if err != nil {
+		return nil, err
+	}
+	schemas := &FuncSchema{Name: meta.Name, Description: meta.Description, Parameters: parameters}
+	if tool.result.Kind() == reflect.Interface {
+		return schemas, nil
+	}
+	schemas.Output, err = outputSchema(tool.result, opts)
+	if err != nil {
+		return nil, fmt.Errorf("output: %w", err)
+	}
+	return schemas, nil
+}
+
+// outputSchema generates the schema of a result type, which unlike response formats
+// may be a scalar, slice or map
+func outputSchema(t reflect.Type, opts []Option) (*internal.Schema, error) {
+	base := t
+	for base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+	if base.Kind() == reflect.Struct {
+		return GenerateSchema(reflect.New(t).Interface(), opts...)
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := internal.JsonTypeOf(t, make(map[reflect.Type]int), 0, options)
+	if err != nil {
+		return nil, err
+	}
+	switch s := result.(type) {
+	case string:
+		return &internal.Schema{"type": s}, nil
+	case internal.Schema:
+		return &s, nil
+	}
+	return nil, fmt.Errorf("unexpected schema type: expected internal.Schema, got %T", result)
+}
+
+// NamedParamsFunc is a function with positional parameters and their JSON names,
+// created with NamedParams.
+type NamedParamsFunc struct {
+	fn    interface{}
+	names []string
+}
+
+// NamedParams names the positional parameters of fn, since Go reflection does not expose
+// parameter names, so the function can be passed to SchemaForFunc and ToolSet.Register
+// without declaring an argument struct. The parameters object has one required property per
+// name, in order, and a leading context.Context parameter is not named. The signature is
+// checked when the function is registered.
+//
+// Example:
+//
+//	lookup := func(ctx context.Context, city string, days int) (Weather, error) { ... }
+//	err := tools.Register("lookup_weather", "Look up the weather of a city",
+//	    NamedParams(lookup, "city", "days"))
+func NamedParams(fn interface{}, names ...string) *NamedParamsFunc {
+	return &NamedParamsFunc{fn: fn, names: append([]string(nil), names...)}
+}
+
+// tool builds the argument struct of the named parameters and a tool spreading it back
+// into positional arguments
+func (f *NamedParamsFunc) tool() (*Tool, error) {
+	v := reflect.ValueOf(f.fn)
+	if !v.IsValid() || v.Kind() != reflect.Func {
+		return nil, fmt.Errorf("%w: NamedParams expects a function, got %T", ErrUnsupportedType, f.fn)
+	}
+	t := v.Type()
+	if err := checkResults(t); err != nil {
+		return nil, err
+	}
+	offset := 0
+	if t.NumIn() > 0 && t.In(0) == contextType {
+		offset = 1
+	}
+	if t.NumIn()-offset != len(f.names) {
+		return nil, fmt.Errorf("%w: function %s has %d parameters, got %d names", ErrInvalidTag, t, t.NumIn()-offset, len(f.names))
+	}
+	seen := make(map[string]bool, len(f.names))
+	fields := make([]reflect.StructField, len(f.names))
+	for i, name := range f.names {
+		if name == "" || name == "-" || seen[name] {
+			return nil, fmt.Errorf("%w: invalid or duplicate parameter name %q", ErrInvalidTag, name)
+		}
+		seen[name] = true
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("P%d", i),
+			Type: t.In(i + offset),
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:%q`, name)),
+		}
+	}
+	args := reflect.StructOf(fields)
+	return &Tool{args: args, result: t.Out(0), invoke: func(ctx context.Context, arg reflect.Value) (interface{}, error) {
+		in := make([]reflect.Value, 0, t.NumIn())
+		if offset == 1 {
+			in = append(in, reflect.ValueOf(ctx))
+		}
+		for i := range f.names {
+			in = append(in, arg.Elem().Field(i))
+		}
+		return results(v.Call(in))
+	}}, nil
+}
@@ -0,0 +1,107 @@
+package gptschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestSchemaForFunc(t *testing.T) {
+	lookup := func(ctx context.Context, args WeatherArgs) (Weather, error) { return Weather{}, nil }
+	parameters, _ := GenerateSchema(WeatherArgs{})
+	output, _ := GenerateSchema(Weather{})
+
+	tests := []struct {
+		name       string
+		fn         interface{}
+		parameters interface{}
+		output     interface{}
+		wantErr    error
+	}{
+		{name: "function", fn: lookup, parameters: parameters, output: output},
+		{name: "executor", fn: &ConvertCurrency{}, parameters: mustSchema(t, ConvertCurrency{}), output: map[string]interface{}{"type": "number"}},
+		{
+			name: "named parameters",
+			fn: NamedParams(func(ctx context.Context, city string, days int) (Weather, error) {
+				return Weather{}, nil
+			}, "city", "days"),
+			parameters: parameters,
+			output:     output,
+		},
+		{name: "interface result", fn: func(args WeatherArgs) (interface{}, error) { return nil, nil }, parameters: parameters},
+		{name: "not a function", fn: "lookup", wantErr: ErrUnsupportedType},
+		{name: "unsupported result", fn: func(args WeatherArgs) (chan int, error) { return nil, nil }, wantErr: ErrUnsupportedType},
+		{name: "named parameters count", fn: NamedParams(func(city string, days int) (Weather, error) { return Weather{}, nil }, "city"), wantErr: ErrInvalidTag},
+		{name: "duplicate parameter name", fn: NamedParams(func(a, b string) (Weather, error) { return Weather{}, nil }, "city", "city"), wantErr: ErrInvalidTag},
+		{name: "named parameters of a non-function", fn: NamedParams(WeatherArgs{}), wantErr: ErrUnsupportedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SchemaForFunc(tt.fn)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertSameJSON(t, "parameters", got.Parameters, tt.parameters)
+			if tt.output == nil {
+				if got.Output != nil {
+					t.Errorf("expected no output schema, got %v", got.Output)
+				}
+				return
+			}
+			assertSameJSON(t, "output", got.Output, tt.output)
+		})
+	}
+}
+
+func TestSchemaForFunc_ToolTag(t *testing.T) {
+	got, err := SchemaForFunc(func(args internal.WeatherToolArgs) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "lookup_weather" || got.Description != "Look up the weather, by city" {
+		t.Errorf("unexpected definition %q, %q", got.Name, got.Description)
+	}
+}
+
+func TestNamedParams_Dispatch(t *testing.T) {
+	tools, _ := NewToolSet()
+	err := tools.Register("lookup_weather", "", NamedParams(func(ctx context.Context, city string, days int) (Weather, error) {
+		return Weather{City: city, Temperature: float64(days)}, nil
+	}, "city", "days"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := tools.Dispatch(context.Background(), ToolCall{Name: "lookup_weather", Arguments: `{"city":"Oslo","days":3}`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != `{"city":"Oslo","temperature":3}` {
+		t.Errorf("unexpected result %s", got)
+	}
+}
+
+func mustSchema(t *testing.T, v interface{}) interface{} {
+	schema, err := GenerateSchema(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return schema
+}
+
+func assertSameJSON(t *testing.T, label string, got, want interface{}) {
+	t.Helper()
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("%s: expected %s, got %s", label, wantJSON, gotJSON)
+	}
+}
@@ -0,0 +1,53 @@
+package gptschema
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// RegisterGeneratedSchema records the schema generated ahead of time for T with the
+// options, so GenerateSchema returns it instead of reflecting T when called with options
+// converting types the same way. Options applied after the conversion, such as
+// WithMaxDescriptionLength, WithSchemaID or WithProvenanceComment, do not need to match and
+// are still applied. Types without a registered schema fall back to reflection, so
+// applications can adopt code generation type by type.
+//
+// It is called by the init functions written by "gptschema generate -registry", which must
+// be regenerated when the types, or the enums and format detectors they use, change.
+// Registration is global and safe for concurrent use. An error is returned for invalid
+// JSON or options.
+//
+// Example:
+//
+//	func init() {
+//	    if err := gptschema.RegisterGeneratedSchema[Address](`{"type":"object",...}`, gptschema.WithStrict(true)); err != nil {
+//	        panic(err)
+//	    }
+//	}
+func RegisterGeneratedSchema[T any](schemaJSON string, opts ...Option) error {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return err
+	}
+	schema, err := internal.ParseSchema([]byte(schemaJSON))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	internal.RegisterGenerated(reflect.TypeOf((*T)(nil)).Elem(), options, schema)
+	return nil
+}
+
+// WithGeneratedSchemas selects whether GenerateSchema returns the schemas registered with
+// RegisterGeneratedSchema (the default) or always reflects types, e.g. to check that the
+// generated code is up to date.
+//
+// Example:
+//
+//	reflected, err := GenerateSchema(Address{}, WithGeneratedSchemas(false))
+func WithGeneratedSchemas(enabled bool) Option {
+	return func(opts *internal.Options) {
+		opts.UseGenerated = enabled
+	}
+}
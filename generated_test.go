@@ -0,0 +1,75 @@
+package gptschema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+type generatedPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func TestRegisterGeneratedSchema(t *testing.T) {
+	// the description tells the registered schema apart from the reflected one
+	err := RegisterGeneratedSchema[generatedPoint](`{"type":"object","description":"A point on the grid",`+
+		`"properties":{"x":{"type":"integer"},"y":{"type":"integer"}},"required":["x","y"],"additionalProperties":false}`,
+		WithStrict(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema, err := GenerateSchema(&generatedPoint{}, WithProvenanceComment(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if (*schema)["description"] != "A point on the grid" || (*schema)["$comment"] == nil {
+		t.Errorf("expected the registered schema with the provenance comment, got %+v", *schema)
+	}
+	reflected, err := GenerateSchema(generatedPoint{}, WithGeneratedSchemas(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delete(*schema, "description")
+	delete(*schema, "$comment")
+	if !reflect.DeepEqual(*schema, *reflected) {
+		t.Errorf("expected the registered schema to match reflection, got %+v and %+v", *schema, *reflected)
+	}
+	if other, _ := GenerateSchema(generatedPoint{}, WithStrict(false)); (*other)["description"] != nil {
+		t.Errorf("expected reflection for options converting types differently, got %+v", *other)
+	}
+
+	localized, err := GenerateSchema(generatedPoint{}, WithStrict(true),
+		WithLocale(Translations{"fr": {"x": "Abscisse"}}, "fr"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	x := (*localized)["properties"].(internal.Schema)["x"].(internal.Schema)
+	if (*localized)["description"] != "A point on the grid" || x["description"] != "Abscisse" {
+		t.Errorf("expected the registered schema with its translations, got %+v", *localized)
+	}
+
+	file := filepath.Join(t.TempDir(), "descriptions.json")
+	if err := os.WriteFile(file, []byte(`{"y":"Ordinate"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	described, err := GenerateSchema(generatedPoint{}, WithStrict(true), WithDescriptionsFile(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	y := (*described)["properties"].(internal.Schema)["y"].(internal.Schema)
+	if (*described)["description"] != "A point on the grid" || y["description"] != "Ordinate" {
+		t.Errorf("expected the registered schema with the descriptions of the file, got %+v", *described)
+	}
+
+	if err := RegisterGeneratedSchema[generatedPoint](`{"type":`); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for invalid JSON, got %v", err)
+	}
+	if err := RegisterGeneratedSchema[internal.Employee](`{}`, WithMaxDepth(0)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
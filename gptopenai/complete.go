@@ -0,0 +1,241 @@
+// Package gptopenai runs the whole Structured Outputs flow of the official openai-go SDK in
+// one call: generating the schema of a Go type, sending it as the response format,
+// validating the response and decoding it into the type.
+//
+// It lives in its own module so the core gptschema module stays free of the SDK dependency.
+package gptopenai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/akane9506/gptschema"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// ErrRefusal is returned when the model refuses to answer instead of producing the JSON.
+var ErrRefusal = errors.New("model refused to respond")
+
+// MismatchError is returned when the response does not match the schema of the target type.
+type MismatchError struct {
+	// Content is the raw response of the model
+	Content string
+	// Mismatches are the values of the response that do not match the schema
+	Mismatches []gptschema.ValidationError
+}
+
+func (e *MismatchError) Error() string {
+	lines := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		lines[i] = m.Error()
+	}
+	return fmt.Sprintf("response does not match the schema: %s", strings.Join(lines, "; "))
+}
+
+// Option configures Complete.
+type Option func(*config)
+
+type config struct {
+	model         openai.ChatModel
+	name          string
+	description   string
+	system        string
+	responses     bool
+	schemaOptions []gptschema.Option
+}
+
+// names accepted by OpenAI for a json_schema response format
+var formatName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// WithModel sets the model of the request, gpt-4o-mini by default.
+func WithModel(model openai.ChatModel) Option {
+	return func(c *config) {
+		c.model = model
+	}
+}
+
+// WithName sets the name of the response format, the snake_case name of the target type
+// by default, e.g. "address_item" for AddressItem.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithDescription sets the description of the response format, which tells the model what
+// the response is for.
+func WithDescription(description string) Option {
+	return func(c *config) {
+		c.description = description
+	}
+}
+
+// WithSystemPrompt sends instructions before the prompt, as a system message for Chat
+// Completions and as instructions for the Responses API.
+func WithSystemPrompt(prompt string) Option {
+	return func(c *config) {
+		c.system = prompt
+	}
+}
+
+// WithResponsesAPI sends the request to the Responses API instead of Chat Completions.
+func WithResponsesAPI(enabled bool) Option {
+	return func(c *config) {
+		c.responses = enabled
+	}
+}
+
+// WithSchemaOptions sets the gptschema options used to generate the schema, validate the
+// response and decode it, such as gptschema.WithInt64AsString.
+func WithSchemaOptions(opts ...gptschema.Option) Option {
+	return func(c *config) {
+		c.schemaOptions = append(c.schemaOptions, opts...)
+	}
+}
+
+// Complete asks the model to answer the prompt with a value of type T and returns it. The
+// schema of T is generated with gptschema and sent as a strict json_schema response format,
+// then the response is checked with gptschema.Validate and decoded with gptschema.Unmarshal.
+// Keywords OpenAI rejects are stripped from the transmitted schema with the ProviderOpenAI
+// profile, the response is still validated against the full schema.
+//
+// A response that does not match the schema is returned as a *MismatchError, whose
+// mismatches can be turned into a retry message with gptschema.FeedbackPrompt. A refusal
+// of the model is reported with ErrRefusal.
+//
+// Example:
+//
+//	client := openai.NewClient()
+//	address, err := gptopenai.Complete[AddressItem](ctx, client,
+//	    "Generate a mock address for a historical russian writer",
+//	    gptopenai.WithModel(openai.ChatModelGPT5Nano))
+func Complete[T any](ctx context.Context, client openai.Client, prompt string, opts ...Option) (T, error) {
+	var result T
+	c := config{model: openai.ChatModelGPT4oMini}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.name == "" {
+		c.name = typeName(reflect.TypeOf(&result).Elem())
+	}
+	if !formatName.MatchString(c.name) {
+		return result, fmt.Errorf("%w: response format name %q must match %s", gptschema.ErrInvalidOption, c.name, formatName)
+	}
+	schemaOptions := append([]gptschema.Option{gptschema.WithStrict(true), gptschema.WithProvider(gptschema.ProviderOpenAI)}, c.schemaOptions...)
+	schema, err := gptschema.GenerateSchema(result, schemaOptions...)
+	if err != nil {
+		return result, err
+	}
+	transmitted, _, err := gptschema.ProviderSchema(schema, schemaOptions...)
+	if err != nil {
+		return result, err
+	}
+
+	var content string
+	if c.responses {
+		content, err = completeResponses(ctx, client, prompt, c, *transmitted)
+	} else {
+		content, err = completeChat(ctx, client, prompt, c, *transmitted)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	mismatches, err := gptschema.Validate(schema, []byte(content), schemaOptions...)
+	if err != nil {
+		return result, fmt.Errorf("failed to validate response: %w", err)
+	}
+	if len(mismatches) > 0 {
+		return result, &MismatchError{Content: content, Mismatches: mismatches}
+	}
+	if err := gptschema.Unmarshal([]byte(content), &result, schemaOptions...); err != nil {
+		return result, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result, nil
+}
+
+// completeChat queries the Chat Completions API and returns the content of the first choice
+func completeChat(ctx context.Context, client openai.Client, prompt string, c config, schema map[string]interface{}) (string, error) {
+	format := openai.ResponseFormatJSONSchemaJSONSchemaParam{
+		Name:   c.name,
+		Schema: schema,
+		Strict: openai.Bool(true),
+	}
+	if c.description != "" {
+		format.Description = openai.String(c.description)
+	}
+	var messages []openai.ChatCompletionMessageParamUnion
+	if c.system != "" {
+		messages = append(messages, openai.SystemMessage(c.system))
+	}
+	messages = append(messages, openai.UserMessage(prompt))
+	chat, err := client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+		Messages: messages,
+		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{JSONSchema: format},
+		},
+		Model: c.model,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(chat.Choices) == 0 {
+		return "", errors.New("response has no choices")
+	}
+	message := chat.Choices[0].Message
+	if message.Refusal != "" {
+		return "", fmt.Errorf("%w: %s", ErrRefusal, message.Refusal)
+	}
+	return message.Content, nil
+}
+
+// completeResponses queries the Responses API and returns its output text
+func completeResponses(ctx context.Context, client openai.Client, prompt string, c config, schema map[string]interface{}) (string, error) {
+	format := responses.ResponseFormatTextJSONSchemaConfigParam{
+		Name:   c.name,
+		Schema: schema,
+		Strict: openai.Bool(true),
+	}
+	if c.description != "" {
+		format.Description = openai.String(c.description)
+	}
+	params := responses.ResponseNewParams{
+		Input: responses.ResponseNewParamsInputUnion{OfString: openai.String(prompt)},
+		Text: responses.ResponseTextConfigParam{
+			Format: responses.ResponseFormatTextConfigUnionParam{OfJSONSchema: &format},
+		},
+		Model: c.model,
+	}
+	if c.system != "" {
+		params.Instructions = openai.String(c.system)
+	}
+	response, err := client.Responses.New(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	for _, output := range response.Output {
+		for _, part := range output.Content {
+			if part.Type == "refusal" {
+				return "", fmt.Errorf("%w: %s", ErrRefusal, part.Refusal)
+			}
+		}
+	}
+	return response.OutputText(), nil
+}
+
+// typeName derives the default response format name from the target type
+func typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	name := gptschema.SnakeCase.Apply(t.Name())
+	if !formatName.MatchString(name) {
+		return "response"
+	}
+	return name
+}
@@ -0,0 +1,211 @@
+package gptopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+)
+
+type AddressItem struct {
+	ID   int64    `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// chatResponse wraps content into a Chat Completions response
+func chatResponse(content string) string {
+	message, _ := json.Marshal(map[string]interface{}{
+		"id":      "chatcmpl-1",
+		"object":  "chat.completion",
+		"model":   "test-model",
+		"choices": []interface{}{map[string]interface{}{"index": 0, "finish_reason": "stop", "message": map[string]interface{}{"role": "assistant", "content": content}}},
+	})
+	return string(message)
+}
+
+// responsesResponse wraps content into a Responses API response
+func responsesResponse(content string) string {
+	message, _ := json.Marshal(map[string]interface{}{
+		"id":     "resp-1",
+		"object": "response",
+		"model":  "test-model",
+		"status": "completed",
+		"output": []interface{}{map[string]interface{}{
+			"type":    "message",
+			"id":      "msg-1",
+			"role":    "assistant",
+			"status":  "completed",
+			"content": []interface{}{map[string]interface{}{"type": "output_text", "text": content, "annotations": []interface{}{}}},
+		}},
+	})
+	return string(message)
+}
+
+func testClient(t *testing.T, path, response string, request *map[string]interface{}) openai.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			t.Errorf("unexpected path %s, want %s", r.URL.Path, path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return openai.NewClient(option.WithBaseURL(server.URL+"/v1/"), option.WithAPIKey("test-key"), option.WithMaxRetries(0))
+}
+
+func TestComplete_Chat(t *testing.T) {
+	var request map[string]interface{}
+	client := testClient(t, "/v1/chat/completions", chatResponse(`{"id":"9007199254740993","name":"Tolstoy","tags":["writer"]}`), &request)
+
+	address, err := Complete[AddressItem](context.Background(), client, "Generate an address",
+		WithModel("test-model"),
+		WithDescription("mock address"),
+		WithSystemPrompt("You generate mock data."),
+		WithSchemaOptions(gptschema.WithInt64AsString(true)))
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if address.ID != 9007199254740993 || address.Name != "Tolstoy" || len(address.Tags) != 1 {
+		t.Errorf("Complete() = %+v", address)
+	}
+
+	if request["model"] != "test-model" {
+		t.Errorf("model = %v, want test-model", request["model"])
+	}
+	messages, _ := request["messages"].([]interface{})
+	if len(messages) != 2 {
+		t.Fatalf("messages = %v, want system and user messages", messages)
+	}
+	format, _ := request["response_format"].(map[string]interface{})
+	spec, _ := format["json_schema"].(map[string]interface{})
+	if format["type"] != "json_schema" || spec["name"] != "address_item" || spec["description"] != "mock address" || spec["strict"] != true {
+		t.Errorf("response_format = %v", format)
+	}
+	schema, _ := spec["schema"].(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	id, _ := properties["id"].(map[string]interface{})
+	if id["type"] != "string" {
+		t.Errorf("schema options not applied, id = %v", id)
+	}
+}
+
+func TestComplete_Responses(t *testing.T) {
+	var request map[string]interface{}
+	client := testClient(t, "/v1/responses", responsesResponse(`{"id":7,"name":"Chekhov","tags":[]}`), &request)
+
+	address, err := Complete[AddressItem](context.Background(), client, "Generate an address",
+		WithResponsesAPI(true),
+		WithName("writer_address"),
+		WithSystemPrompt("You generate mock data."))
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if address.ID != 7 || address.Name != "Chekhov" {
+		t.Errorf("Complete() = %+v", address)
+	}
+
+	if request["input"] != "Generate an address" || request["instructions"] != "You generate mock data." {
+		t.Errorf("request = %v", request)
+	}
+	text, _ := request["text"].(map[string]interface{})
+	format, _ := text["format"].(map[string]interface{})
+	if format["type"] != "json_schema" || format["name"] != "writer_address" || format["strict"] != true || format["schema"] == nil {
+		t.Errorf("text.format = %v", format)
+	}
+}
+
+func TestComplete_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		opts     []Option
+		check    func(t *testing.T, err error)
+	}{
+		{
+			name:     "mismatch",
+			response: chatResponse(`{"id":"7","name":"Gogol"}`),
+			check: func(t *testing.T, err error) {
+				var mismatch *MismatchError
+				if !errors.As(err, &mismatch) {
+					t.Fatalf("error = %v, want *MismatchError", err)
+				}
+				if len(mismatch.Mismatches) != 2 || mismatch.Content != `{"id":"7","name":"Gogol"}` {
+					t.Errorf("mismatches = %v", mismatch.Mismatches)
+				}
+			},
+		},
+		{
+			name:     "refusal",
+			response: `{"choices":[{"index":0,"message":{"role":"assistant","content":"","refusal":"I cannot help with that."}}]}`,
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, ErrRefusal) {
+					t.Errorf("error = %v, want ErrRefusal", err)
+				}
+			},
+		},
+		{
+			name:     "no choices",
+			response: `{"choices":[]}`,
+			check: func(t *testing.T, err error) {
+				if err == nil {
+					t.Error("expected an error")
+				}
+			},
+		},
+		{
+			name:     "invalid name",
+			response: chatResponse(`{}`),
+			opts:     []Option{WithName("address item")},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, gptschema.ErrInvalidOption) {
+					t.Errorf("error = %v, want ErrInvalidOption", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&request)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+			client := openai.NewClient(option.WithBaseURL(server.URL+"/v1/"), option.WithAPIKey("test-key"), option.WithMaxRetries(0))
+
+			_, err := Complete[AddressItem](context.Background(), client, "Generate an address", tt.opts...)
+			tt.check(t, err)
+		})
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  interface{}
+		want string
+	}{
+		{name: "struct", typ: AddressItem{}, want: "address_item"},
+		{name: "pointer", typ: &AddressItem{}, want: "address_item"},
+		{name: "unnamed", typ: map[string]string{}, want: "response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := typeName(reflect.TypeOf(tt.typ)); got != tt.want {
+				t.Errorf("typeName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/akane9506/gptschema/gptopenai
+
+go 1.22
+
+require (
+	github.com/akane9506/gptschema v0.5.0
+	github.com/openai/openai-go/v3 v3.21.0
+)
+
+require (
+	github.com/tidwall/gjson v1.18.0 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.1 // indirect
+	github.com/tidwall/sjson v1.2.5 // indirect
+)
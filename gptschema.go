@@ -10,30 +10,697 @@
 package gptschema
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
+	"sync"
 
 	"github.com/akane9506/gptschema/internal"
 )
 
+// Errors returned by schema generation, match them with errors.Is.
+var (
+	ErrUnsupportedType = internal.ErrUnsupportedType
+	ErrCircularRef     = internal.ErrCircularRef
+	ErrInvalidTag      = internal.ErrInvalidTag
+	ErrInvalidOption   = internal.ErrInvalidOption
+	ErrSchemaTooLarge  = internal.ErrSchemaTooLarge
+)
+
+// SchemaError classifies a failure with a Code and locates it with the Path of the property,
+// e.g. "companies[].address", empty for the root, so programs can branch on the class of a
+// failure instead of matching messages. Its message is the one of the wrapped cause, which
+// errors.Is and errors.As keep matching. Every error of GenerateSchema is a *SchemaError.
+//
+// Example:
+//
+//	_, err := GenerateSchema(Config{})
+//	var schemaErr *SchemaError
+//	if errors.As(err, &schemaErr) && schemaErr.Code == UnsupportedType {
+//	    log.Printf("field %s cannot be described, add a json:\"-\" tag", schemaErr.Path)
+//	}
+type SchemaError = internal.SchemaError
+
+// ErrorCode is the class of a SchemaError.
+type ErrorCode = internal.ErrorCode
+
+// Error codes of SchemaError.
+const (
+	// UnsupportedType is a Go type without a JSON Schema representation, such as a channel
+	UnsupportedType = internal.UnsupportedType
+	// CircularRef is a type referencing itself, see WithRecursiveRefs and WithRecursionUnroll
+	CircularRef = internal.CircularRef
+	// MaxDepth is a type nested deeper than the maximum depth, see WithMaxDepth
+	MaxDepth = internal.MaxDepth
+	// LimitExceeded is a schema over the size budget or a provider limit
+	LimitExceeded = internal.LimitExceeded
+	// TagSyntax is an invalid or misplaced struct tag
+	TagSyntax = internal.TagSyntax
+	// InvalidOption is an invalid option value
+	InvalidOption = internal.InvalidOption
+	// LintFailed is a schema rejected by a lint check, such as a disallowed keyword
+	LintFailed = internal.LintFailed
+	// InvalidSchema is a schema built or modified at runtime that is not valid
+	InvalidSchema = internal.InvalidSchema
+	// Internal is any other failure
+	Internal = internal.Internal
+)
+
+// CircularRefError reports the chain of types and fields that formed a cycle
+// (e.g. "A.Children → B.Parent → A"), or the path that exceeded the maximum depth.
+// It wraps ErrCircularRef, use errors.As to inspect the path.
+type CircularRefError = internal.CircularRefError
+
+// SchemaSizeError reports a schema larger than the budget set with WithMaxSchemaBytes,
+// with the largest top-level properties. It wraps ErrSchemaTooLarge.
+type SchemaSizeError = internal.SchemaSizeError
+
+// SizeContributor is a top-level property listed by SchemaSizeError.
+type SizeContributor = internal.SizeContributor
+
+// Warning describes an adjustment made to a schema during generation, such as a
+// truncated description. Warnings are passed to the handler set with WithWarningHandler.
+type Warning = internal.Warning
+
 // Option is a function that modifies schema generation options.
 // Options can be passed to GenerateSchema to customize behavior.
 type Option func(*internal.Options)
 
 // WithMaxDepth sets the maximum depth for nested struct traversal.
 // This prevents infinite recursion in deeply nested or circular structures.
-// The default maximum depth is 50.
+// The depth counts nested objects, structs and maps, which is what provider nesting limits
+// constrain: a [][][]string field does not consume it. Arrays are bounded separately, a
+// value may be enclosed by at most as many arrays as the maximum depth.
+// The default maximum depth is 50, values below 1 are rejected with ErrInvalidOption.
 //
 // Example:
 //
 //	schema, err := GenerateSchema(MyStruct{}, WithMaxDepth(20))
 func WithMaxDepth(depth int) Option {
 	return func(opts *internal.Options) {
+		if depth < 1 {
+			opts.AddError("max depth must be at least 1, got %d", depth)
+			return
+		}
 		opts.MaxDepth = depth
 	}
 }
 
+// GenerationReport describes the shape of a generated schema, see WithGenerationReport.
+type GenerationReport = internal.GenerationReport
+
+// WithGenerationReport fills report with the deepest object and array nesting of the
+// generated schema, to compare against provider limits or tune WithMaxDepth. Only object
+// nesting counts toward the maximum depth and provider nesting limits. A nil report is
+// rejected with ErrInvalidOption.
+//
+// Example:
+//
+//	var report GenerationReport
+//	schema, err := GenerateSchema(Catalog{}, WithGenerationReport(&report))
+//	log.Printf("objects nested %d deep, arrays %d deep", report.ObjectDepth, report.ArrayDepth)
+func WithGenerationReport(report *GenerationReport) Option {
+	return func(opts *internal.Options) {
+		if report == nil {
+			opts.AddError("generation report must not be nil")
+			return
+		}
+		opts.Report = report
+	}
+}
+
+// WithStrict toggles OpenAI strict mode compatibility. Strict mode is enabled by default.
+// When disabled, map fields are allowed and emitted as objects whose additionalProperties
+// describe the map values. A `keys:"<regex>"` tag on a map field emits patternProperties
+// instead, constraining the keys for providers that accept it. Fields tagged with
+// `required:"false"` are left out of the required array, independently of omitempty.
+//
+// Example:
+//
+//	type Translations struct {
+//	    Labels map[string]string `json:"labels" keys:"^[a-z]{2}$"`
+//	}
+//	schema, err := GenerateSchema(Translations{}, WithStrict(false))
+func WithStrict(strict bool) Option {
+	return func(opts *internal.Options) {
+		opts.Strict = strict
+	}
+}
+
+// WithAdditionalProperties sets additionalProperties of the objects generated from structs,
+// false by default. OpenAI strict mode requires false on every object, allowing them is
+// meant for other providers or for a branch scoped with WithSubtreeOptions.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Config{}, WithStrict(false), WithAdditionalProperties(true))
+func WithAdditionalProperties(allow bool) Option {
+	return func(opts *internal.Options) {
+		opts.AllowAdditionalProperty = allow
+	}
+}
+
+// WithExpandEnumMaps expands maps keyed by a registered enum (see RegisterEnum) into objects
+// with one property per enum value. In strict mode every property is required and nullable,
+// otherwise the properties are simply optional. The expanded form is strict mode compatible.
+// Pass the option to Unmarshal too, it drops the null entries standing for missing keys.
+//
+// Example:
+//
+//	type Schedule struct {
+//	    Hours map[Weekday]int `json:"hours"`
+//	}
+//	schema, err := GenerateSchema(Schedule{}, WithExpandEnumMaps(true))
+func WithExpandEnumMaps(expand bool) Option {
+	return func(opts *internal.Options) {
+		opts.ExpandEnumMaps = expand
+	}
+}
+
+// WithEnumDetection toggles enum autodetection, which is enabled by default.
+// Types generated by enumer (exposing a Values() method) and integer types generated by
+// stringer (printing out of range values as "Type(N)") emit their values as an enum,
+// encoded the way encoding/json marshals them. Enums registered with RegisterEnum
+// are always used regardless of this option.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(MyStruct{}, WithEnumDetection(false))
+func WithEnumDetection(detect bool) Option {
+	return func(opts *internal.Options) {
+		opts.DetectEnums = detect
+	}
+}
+
+// WithInt64AsString maps int64 and uint64 fields to string schemas with a numeric pattern,
+// since large IDs lose precision when models emit them as JSON numbers.
+// Use Unmarshal with the same option to decode the strings back into the integer fields.
+// A single field can opt in with encoding/json's own `json:",string"` tag option instead.
+//
+// Example:
+//
+//	type Order struct {
+//	    ID int64 `json:"id"` // {"type":"string","pattern":"^-?[0-9]+$"}
+//	}
+//	schema, err := GenerateSchema(Order{}, WithInt64AsString(true))
+func WithInt64AsString(enabled bool) Option {
+	return func(opts *internal.Options) {
+		opts.Int64AsString = enabled
+	}
+}
+
+// WithNumericFormats annotates integer and number schemas with an OpenAPI format
+// (int32, int64, float or double) derived from the Go kind, for consumers such as codegen
+// tools relying on these hints. The formats are stripped automatically in strict mode,
+// since OpenAI rejects them, so this option only has an effect together with WithStrict(false).
+//
+// Example:
+//
+//	type Point struct {
+//	    X float32 `json:"x"` // {"type":"number","format":"float"}
+//	    Y int64   `json:"y"` // {"type":"integer","format":"int64"}
+//	}
+//	schema, err := GenerateSchema(Point{}, WithStrict(false), WithNumericFormats(true))
+func WithNumericFormats(enabled bool) Option {
+	return func(opts *internal.Options) {
+		opts.NumericFormats = enabled
+	}
+}
+
+// WithRecursionUnroll expands self-referencing types n levels deep instead of returning
+// ErrCircularRef, and terminates the innermost occurrence with a null leaf.
+// It is an alternative to $ref for providers that don't support references.
+// The maximum depth still applies to the unrolled schema.
+//
+// Example:
+//
+//	type Comment struct {
+//	    Text    string    `json:"text"`
+//	    Replies []Comment `json:"replies"`
+//	}
+//	// comments with up to two levels of replies
+//	schema, err := GenerateSchema(Comment{}, WithRecursionUnroll(2))
+func WithRecursionUnroll(n int) Option {
+	return func(opts *internal.Options) {
+		if n < 0 {
+			opts.AddError("recursion unroll must not be negative, got %d", n)
+			return
+		}
+		opts.RecursionUnroll = n
+	}
+}
+
+// WithRecursiveRefs describes recursive types with $ref instead of returning
+// ErrCircularRef. A type met again on the current path, directly (Node.Next → Node) or
+// through other types (Tree.Children → Branch.Parent → Tree), becomes a reference: "#"
+// for the root type, and "#/$defs/<Type>" for the others, whose schema is moved to the
+// $defs of the root. Types reused by sibling fields are not cycles and stay inlined.
+// Pointer fields closing a cycle are always nullable, even when required, since only null
+// can end the document. It takes precedence over WithRecursionUnroll.
+//
+// Example:
+//
+//	type Tree struct {
+//	    Children []Branch `json:"children"`
+//	}
+//	type Branch struct {
+//	    Name   string `json:"name"`
+//	    Parent *Tree  `json:"parent"`
+//	}
+//	schema, err := GenerateSchema(Tree{}, WithRecursiveRefs(true))
+//	// {"properties": {"children": {"items": {"properties": {"parent": {"anyOf": [{"$ref": "#"}, {"type": "null"}]}, ...
+func WithRecursiveRefs(enabled bool) Option {
+	return func(opts *internal.Options) {
+		opts.RecursiveRefs = enabled
+	}
+}
+
+// WithDefs moves every struct type to the $defs of the root and describes each occurrence
+// with a "$ref", so a type used by several fields is described once. Anonymous structs are
+// named after the field path of their first occurrence (line_items → LineItems), and
+// identical anonymous structs share a definition wherever they are inlined. Recursive types
+// are described as with WithRecursiveRefs.
+//
+// Example:
+//
+//	type Order struct {
+//	    Billing  struct{ Street string `json:"street"` } `json:"billing"`
+//	    Shipping struct{ Street string `json:"street"` } `json:"shipping"`
+//	}
+//	schema, err := GenerateSchema(Order{}, WithDefs(true))
+//	// {"properties": {"billing": {"$ref": "#/$defs/Billing"}, "shipping": {"$ref": "#/$defs/Billing"}}, "$defs": {"Billing": ...
+func WithDefs(enabled bool) Option {
+	return func(opts *internal.Options) {
+		opts.Defs = enabled
+	}
+}
+
+// DefNaming selects how the definitions in $defs are named.
+type DefNaming = internal.DefNaming
+
+// Naming strategies accepted by WithDefNaming.
+const (
+	// DefNameType names definitions after their Go type, anonymous structs after their field path
+	DefNameType = internal.DefNameType
+	// DefNamePath names every definition after the field path of its first occurrence
+	DefNamePath = internal.DefNamePath
+	// DefNameQualified prefixes the Go type name with its package name, e.g. ModelsAddress
+	DefNameQualified = internal.DefNameQualified
+)
+
+// WithDefNaming selects how the definitions of WithDefs and WithRecursiveRefs are named.
+// The default is DefNameType. Names taken by another type get a numeric suffix
+// (Address, Address2), e.g. types of the same name from different packages.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Order{}, WithDefs(true), WithDefNaming(DefNameQualified))
+//	// {"$defs": {"ModelsAddress": ...
+func WithDefNaming(naming DefNaming) Option {
+	return func(opts *internal.Options) {
+		if !naming.Valid() {
+			opts.AddError("unknown definition naming %q", naming)
+			return
+		}
+		opts.DefNaming = naming
+	}
+}
+
+// TimeFormat describes how the application serializes time.Time values.
+type TimeFormat = internal.TimeFormat
+
+// Time formats accepted by WithTimeFormat.
+const (
+	// TimeRFC3339 describes times as RFC 3339 strings, the encoding/json default
+	TimeRFC3339 = internal.TimeRFC3339
+	// TimeDate describes times as date-only strings such as 2006-01-02
+	TimeDate = internal.TimeDate
+	// TimeUnixSeconds describes times as integer seconds since the Unix epoch
+	TimeUnixSeconds = internal.TimeUnixSeconds
+	// TimeUnixMillis describes times as integer milliseconds since the Unix epoch
+	TimeUnixMillis = internal.TimeUnixMillis
+)
+
+// WithTimeFormat selects how time.Time fields are described, so the schema matches
+// however the application actually serializes timestamps. The default is TimeRFC3339
+// ({"type":"string","format":"date-time"}). Epoch formats are integers whose description
+// states the unit, unless the field has its own description tag.
+// Unmarshal converts date-only and epoch values back into time.Time.
+//
+// Example:
+//
+//	type Event struct {
+//	    StartsAt time.Time `json:"startsAt"` // {"type":"integer","description":"Unix timestamp in seconds"}
+//	}
+//	schema, err := GenerateSchema(Event{}, WithTimeFormat(TimeUnixSeconds))
+func WithTimeFormat(format TimeFormat) Option {
+	return func(opts *internal.Options) {
+		if !format.Valid() {
+			opts.AddError("unknown time format %q", format)
+			return
+		}
+		opts.TimeFormat = format
+	}
+}
+
+// NamingConvention converts the property names of fields without a json tag name.
+type NamingConvention = internal.NamingConvention
+
+// Naming conventions accepted by WithNamingConvention.
+const (
+	// GoNames keeps the Go field names, like encoding/json
+	GoNames = internal.GoNames
+	// SnakeCase converts UserID to user_id
+	SnakeCase = internal.SnakeCase
+	// CamelCase converts UserID to userId
+	CamelCase = internal.CamelCase
+	// PascalCase converts UserID to UserId
+	PascalCase = internal.PascalCase
+)
+
+// WithNamingConvention converts the names of fields lacking a json tag name, so structs
+// mixing tagged and untagged fields produce consistent property names. Tagged names are
+// never changed. Two fields mapping to the same property after conversion are reported
+// with ErrInvalidTag. Unmarshal accepts the same option and maps the converted names back
+// to the Go fields, which encoding/json alone would not match.
+//
+// Example:
+//
+//	type User struct {
+//	    UserID string                   // "user_id"
+//	    Email  string `json:"email"`    // "email"
+//	}
+//	schema, err := GenerateSchema(User{}, WithNamingConvention(SnakeCase))
+func WithNamingConvention(convention NamingConvention) Option {
+	return func(opts *internal.Options) {
+		if !convention.Valid() {
+			opts.AddError("unknown naming convention %q", convention)
+			return
+		}
+		opts.NamingConvention = convention
+	}
+}
+
+// EmbeddingPolicy resolves conflicts between the fields of a struct and the fields
+// of its embedded structs.
+type EmbeddingPolicy = internal.EmbeddingPolicy
+
+// Embedding policies accepted by WithEmbeddingPolicy.
+const (
+	// DepthBased follows encoding/json: the shallowest field wins
+	DepthBased = internal.DepthBased
+	// OuterWins lets fields declared on the struct itself win, conflicts
+	// between fields of embedded structs are errors
+	OuterWins = internal.OuterWins
+	// ErrorOnConflict reports every conflict
+	ErrorOnConflict = internal.ErrorOnConflict
+)
+
+// WithEmbeddingPolicy controls how a field conflicting with a field of an embedded struct
+// is resolved. The default, DepthBased, applies the depth rules of encoding/json so schemas
+// match real marshaling. Unresolved conflicts are reported with ErrInvalidTag, fields of the
+// same depth always conflict.
+//
+// Example:
+//
+//	type Base struct {
+//	    ID string `json:"id"`
+//	}
+//	type User struct {
+//	    Base
+//	    ID int `json:"id"` // hides Base.ID with DepthBased and OuterWins
+//	}
+//	schema, err := GenerateSchema(User{}, WithEmbeddingPolicy(ErrorOnConflict)) // fails
+func WithEmbeddingPolicy(policy EmbeddingPolicy) Option {
+	return func(opts *internal.Options) {
+		if !policy.Valid() {
+			opts.AddError("unknown embedding policy %q", policy)
+			return
+		}
+		opts.EmbeddingPolicy = policy
+	}
+}
+
+// RequiredOrder selects the order of the property names in required arrays.
+type RequiredOrder = internal.RequiredOrder
+
+// Required orders accepted by WithRequiredOrder.
+const (
+	// DeclarationOrder lists the properties in the order of their fields, fields promoted
+	// from embedded structs at the position of the embedded struct, like encoding/json
+	DeclarationOrder = internal.DeclarationOrder
+	// Alphabetical sorts the property names
+	Alphabetical = internal.Alphabetical
+)
+
+// WithRequiredOrder selects the order of the required arrays. The default, DeclarationOrder,
+// follows the fields of the struct and stays the same however embedded fields are merged or
+// hidden, so hashes, prompt caches and golden files are not perturbed by incidental ordering.
+// Alphabetical keeps the arrays stable when fields are moved around in the struct.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Account{}, WithRequiredOrder(Alphabetical))
+//	// "required":["aliases","backup","email","id","name"]
+func WithRequiredOrder(order RequiredOrder) Option {
+	return func(opts *internal.Options) {
+		if !order.Valid() {
+			opts.AddError("unknown required order %q", order)
+			return
+		}
+		opts.RequiredOrder = order
+	}
+}
+
+// WithJSONv2Semantics describes fields the way encoding/json/v2 marshals them, so schemas
+// stay accurate as projects migrate to the new encoder:
+//   - omitzero makes a field optional
+//   - omitempty only makes strings, slices, maps, pointers and interfaces optional,
+//     since v2 never omits numbers, booleans or structs under that option
+//   - the ",string" option only quotes numbers
+//   - Unmarshal matches property names case-sensitively
+//
+// The inline option is supported regardless of this setting.
+//
+// Example:
+//
+//	type Counter struct {
+//	    Hits int `json:"hits,omitempty"` // {"type":"integer"}, always present with v2
+//	}
+//	schema, err := GenerateSchema(Counter{}, WithJSONv2Semantics(true))
+func WithJSONv2Semantics(enabled bool) Option {
+	return func(opts *internal.Options) {
+		opts.JSONv2 = enabled
+	}
+}
+
+// NullableStyle selects how optional fields are made nullable.
+type NullableStyle = internal.NullableStyle
+
+// Nullable styles accepted by WithNullableStyle.
+const (
+	// NullableMixed uses a type array for primitives and anyOf for other schemas
+	NullableMixed = internal.NullableMixed
+	// NullableTypeArray adds null to the type of every schema with a single type
+	NullableTypeArray = internal.NullableTypeArray
+	// NullableAnyOf wraps every optional schema in anyOf with null
+	NullableAnyOf = internal.NullableAnyOf
+)
+
+// WithNullableStyle normalizes the encoding of optional fields, which some validators
+// and diff tools require. By default (NullableMixed) optional primitives use a type array
+// such as ["string","null"] while objects, arrays and enums use anyOf with null.
+// NullableTypeArray adds null to the type of every schema, enums also list null as a value.
+// NullableAnyOf wraps every optional schema, primitives included, in anyOf.
+//
+// Example:
+//
+//	type User struct {
+//	    Address *Address `json:"address,omitempty"` // {"type":["object","null"],"properties":{...},...}
+//	}
+//	schema, err := GenerateSchema(User{}, WithNullableStyle(NullableTypeArray))
+func WithNullableStyle(style NullableStyle) Option {
+	return func(opts *internal.Options) {
+		if !style.Valid() {
+			opts.AddError("unknown nullable style %q", style)
+			return
+		}
+		opts.NullableStyle = style
+	}
+}
+
+// WithMaxSchemaBytes fails generation with a *SchemaSizeError when the marshaled schema
+// exceeds n bytes, protecting services from oversized request payloads as structs grow.
+// The error reports the measured size and the largest top-level properties.
+// Values below 0 are rejected with ErrInvalidOption, 0 disables the check (the default).
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Report{}, WithMaxSchemaBytes(16<<10))
+//	var sizeErr *SchemaSizeError
+//	if errors.As(err, &sizeErr) {
+//	    log.Printf("schema is %d bytes, largest property: %s", sizeErr.Size, sizeErr.Contributors[0].Path)
+//	}
+func WithMaxSchemaBytes(n int) Option {
+	return func(opts *internal.Options) {
+		if n < 0 {
+			opts.AddError("max schema bytes must not be negative, got %d", n)
+			return
+		}
+		opts.MaxSchemaBytes = n
+	}
+}
+
+// WithWarningHandler sets the function receiving the adjustments made to a schema during
+// generation, such as truncated descriptions. Warnings are dropped without a handler.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Report{},
+//	    WithMaxDescriptionLength(512),
+//	    WithWarningHandler(func(w Warning) { log.Printf("gptschema: %s", w) }),
+//	)
+func WithWarningHandler(handler func(Warning)) Option {
+	return func(opts *internal.Options) {
+		opts.WarningHandler = handler
+	}
+}
+
+// WithHumanizedDescriptions describes the properties without a description tag with a text
+// derived from their name, "createdAt" becomes "Created at", as even trivial descriptions
+// improve how well models follow terse field names. A nil humanizer uses Humanize, a custom
+// one receives the JSON property name and may return "" to leave a property undescribed.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Order{}, WithHumanizedDescriptions(nil))
+//	// {"createdAt": {"type": "string", "format": "date-time", "description": "Created at"}}
+func WithHumanizedDescriptions(humanizer func(name string) string) Option {
+	return func(opts *internal.Options) {
+		if humanizer == nil {
+			humanizer = internal.Humanize
+		}
+		opts.Humanizer = humanizer
+	}
+}
+
+// Humanize derives a description from a property name: createdAt, created_at and
+// created-at all become "Created at", acronyms keep their case (userID is "User ID").
+func Humanize(name string) string {
+	return internal.Humanize(name)
+}
+
+// WithMaxDescriptionLength truncates descriptions longer than n characters, so schemas
+// assembled from long doc comments are not rejected wholesale by providers capping
+// description lengths. Truncated descriptions end with an ellipsis, counted in n, and a
+// warning is reported for each of them. Values below 0 are rejected with ErrInvalidOption,
+// 0 disables truncation (the default).
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Report{}, WithMaxDescriptionLength(512))
+func WithMaxDescriptionLength(n int) Option {
+	return func(opts *internal.Options) {
+		if n < 0 {
+			opts.AddError("max description length must not be negative, got %d", n)
+			return
+		}
+		opts.MaxDescriptionLength = n
+	}
+}
+
+// WithSchemaID stamps the root schema with an $id derived from base, the import path and
+// the name of the Go type, so stored or shipped schemas can be traced back to their source.
+// base must be an absolute URI, otherwise ErrInvalidOption is returned. Anonymous structs
+// have no $id.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(models.Invoice{}, WithSchemaID("https://schemas.example.com"))
+//	// {"$id":"https://schemas.example.com/github.com/acme/models/Invoice",...}
+func WithSchemaID(base string) Option {
+	return func(opts *internal.Options) {
+		u, err := url.Parse(base)
+		if err != nil || !u.IsAbs() {
+			opts.AddError("schema id base must be an absolute URI, got %q", base)
+			return
+		}
+		opts.SchemaIDBase = base
+	}
+}
+
+// WithProvenanceComment stamps the root schema with a $comment recording the Go type, the
+// versions of its module and of gptschema read from the build info, and the generation
+// options differing from the defaults. Versions are left out when the binary carries no
+// module information, e.g. in tests.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(models.Invoice{}, WithProvenanceComment(true), WithStrict(false))
+//	// {"$comment":"generated by gptschema v0.4.0 from models.Invoice (github.com/acme/models v1.2.0), options: strict=false",...}
+func WithProvenanceComment(enabled bool) Option {
+	return func(opts *internal.Options) {
+		opts.Provenance = enabled
+	}
+}
+
+// WithInternedSubschemas shares structurally identical subschemas, such as the thousands of
+// {"type":"string"} of a large model, so generated schemas use a fraction of the memory.
+// The schema is equal to the one generated without interning, but modifying a subschema
+// affects every property sharing it: treat it as immutable, or copy it before editing.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Catalog{}, WithInternedSubschemas(true))
+func WithInternedSubschemas(enabled bool) Option {
+	return func(opts *internal.Options) {
+		opts.Intern = enabled
+	}
+}
+
+// package level defaults applied before the options of each call
+var (
+	defaultsMu     sync.RWMutex
+	defaultOptions []Option
+)
+
+// SetDefaultOptions establishes options applied to every subsequent call before the
+// options passed to that call, so applications can configure the maximum depth,
+// strict mode and similar settings once at startup. Each call replaces the previous
+// defaults, and calling it without options restores the built-in defaults.
+// It is safe for concurrent use.
+//
+// Example:
+//
+//	func init() {
+//	    gptschema.SetDefaultOptions(gptschema.WithMaxDepth(20), gptschema.WithInt64AsString(true))
+//	}
+func SetDefaultOptions(opts ...Option) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaultOptions = append([]Option(nil), opts...)
+}
+
+// buildOptions applies the options on top of the defaults,
+// invalid option values are reported as a joined error
+func buildOptions(opts []Option) (*internal.Options, error) {
+	options := internal.DefaultOptions()
+	defaultsMu.RLock()
+	for _, opt := range defaultOptions {
+		opt(options)
+	}
+	defaultsMu.RUnlock()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if err := options.Validate(); err != nil {
+		return nil, internal.NewSchemaError(InvalidOption, err)
+	}
+	return options, nil
+}
+
 // GenerateSchema converts a Go type into a JSON Schema compatible with OpenAI's structured outputs.
 //
 // The function accepts any Go value and generates a JSON Schema representation that follows
@@ -54,15 +721,24 @@ func WithMaxDepth(depth int) Option {
 //   - Primitives: string, bool, int (all variants), uint (all variants), float32, float64
 //   - Complex: struct, slice, array, pointer.
 //   - Embedded structs are supported and their fields are merged into the parent
+//   - Named types registered with RegisterEnum emit their values as an enum
+//   - Types generated by enumer or stringer are detected as enums (see WithEnumDetection)
 //
 // Unsupported Types (IMPORTANT):
-//   - map: Not allowed per OpenAI's additionalProperties requirement
+//   - map: Not allowed per OpenAI's additionalProperties requirement (allowed with WithStrict(false))
 //   - chan, func, interface, complex types
 //
 // JSON Tags:
 //   - Use `json:"fieldName"` to specify the JSON property name
 //   - Use `json:",omitempty"` to mark fields as optional (generates union with null)
-//   - Use `json:"-"` to skip fields entirely
+//   - Use `json:"-"` to skip fields entirely (`json:"-,"` names the field "-", as in encoding/json)
+//   - Use `required:"false"` to leave a field out of the required array (non-strict mode only)
+//   - Use `required:"true"` to keep an omitempty field strictly typed, without the null union
+//   - Use `description:"..."` to attach a description to a field
+//   - Use `json:",string"` to describe scalars stored inside JSON strings, as encoding/json does
+//   - Use `keys:"<regex>"` on a map field to emit patternProperties (non-strict mode only)
+//   - Use `items:"nullable"` on a slice of pointers to let its elements be null
+//   - Use `json:",inline"` to merge the properties of a struct field into the parent
 //
 // Examples:
 //
@@ -106,35 +782,79 @@ func WithMaxDepth(depth int) Option {
 //
 // Error Conditions:
 //   - Returns ErrUnsupportedType if the type cannot be converted to JSON Schema
-//   - Returns ErrCircularRef if circular references are detected (depth > 50 by default)
+//   - Returns ErrCircularRef if circular references are detected (depth > 50 by default),
+//     wrapped in a *CircularRefError describing the cycle path
+//   - Returns ErrInvalidTag if a struct tag cannot be applied to its field, or if two fields
+//     of the same struct map to the same property name
+//   - Returns ErrInvalidOption if an option was given an invalid value
+//   - Returns ErrSchemaTooLarge, wrapped in a *SchemaSizeError, if the schema exceeds
+//     the budget set with WithMaxSchemaBytes or the MaxSchemaBytes limit
 //
 // Note: The generated schema sets additionalProperties to false by default,
 // which is required for OpenAI's strict mode structured outputs.
 func GenerateSchema(v interface{}, opts ...Option) (*internal.Schema, error) {
 	t := reflect.TypeOf(v)
 	if t == nil {
-		return nil, fmt.Errorf("cannot generate schema for nil value")
+		return nil, internal.NewSchemaError(UnsupportedType, fmt.Errorf("cannot generate schema for nil value"))
 	}
 	// Dereference if pointer
 	for t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
 	if t.Kind() != reflect.Struct {
-		return nil, fmt.Errorf("the schema is expected to be a Go struct")
+		return nil, internal.NewSchemaError(UnsupportedType, fmt.Errorf("the schema is expected to be a Go struct"))
 	}
-	options := internal.DefaultOptions()
-	for _, opt := range opts {
-		opt(options)
-	}
-	visited := make(map[reflect.Type]bool)
-	depth := 0
-	result, err := internal.JsonTypeOf(t, visited, depth, options)
+	options, err := buildOptions(opts)
 	if err != nil {
 		return nil, err
 	}
-	schema, ok := result.(internal.Schema)
+	// schemas registered by generated code replace the reflection of their type
+	schema, ok := internal.LookupGenerated(t, options)
 	if !ok {
-		return nil, fmt.Errorf("unexpected schema type: expected internal.Schema, got %T", result)
+		visited := internal.AcquireVisited()
+		depth := 0
+		result, err := internal.JsonTypeOf(t, visited, depth, options)
+		internal.ReleaseVisited(visited)
+		if err != nil {
+			return nil, internal.ClassifyError(err)
+		}
+		if schema, ok = result.(internal.Schema); !ok {
+			return nil, internal.NewSchemaError(Internal, fmt.Errorf("unexpected schema type: expected internal.Schema, got %T", result))
+		}
+	}
+	if options.FileDescriptions != nil {
+		schema = internal.MergeDescriptions(schema, options.FileDescriptions, options)
+	}
+	if options.LocalizedDescriptions != nil {
+		schema = internal.LocalizeDescriptions(schema, options.LocalizedDescriptions, options)
+	}
+	if options.MaxDescriptionLength > 0 {
+		schema = internal.TruncateDescriptions(schema, options.MaxDescriptionLength, options)
+	}
+	if options.SchemaIDBase != "" {
+		if id := internal.SchemaID(options.SchemaIDBase, t); id != "" {
+			schema["$id"] = id
+		}
+	}
+	if options.Provenance {
+		schema["$comment"] = internal.ProvenanceComment(t, options)
+	}
+	if err := checkLimits(schema, options); err != nil {
+		return nil, internal.NewSchemaError(LimitExceeded, err)
+	}
+	if err := checkKeywords(schema, options); err != nil {
+		return nil, internal.NewSchemaError(LintFailed, err)
+	}
+	if budget := options.SchemaBudget(); budget > 0 {
+		if err := internal.CheckSchemaSize(schema, budget); err != nil {
+			return nil, internal.ClassifyError(err)
+		}
+	}
+	if options.Report != nil {
+		*options.Report = internal.MeasureNesting(schema)
+	}
+	if options.Intern {
+		schema = internal.Intern(schema)
 	}
 	return &schema, nil
 }
@@ -187,13 +907,89 @@ func GenerateSchema(v interface{}, opts ...Option) (*internal.Schema, error) {
 //   - Returns error if JSON marshaling fails (rare, indicates internal schema structure issue)
 
 func GenerateSchemaJSON(v interface{}, opts ...Option) (string, error) {
-	schema, err := GenerateSchema(v, opts...)
+	parsedSchema, err := GenerateSchemaBytes(v, opts...)
 	if err != nil {
 		return "", err
 	}
-	parsedSchema, err := json.Marshal(schema)
+	return string(parsedSchema), nil
+}
+
+// MustGenerateSchema is like GenerateSchema but panics if the schema cannot be generated.
+// It simplifies the initialization of package-level variables holding static schemas,
+// like regexp.MustCompile.
+//
+// Example:
+//
+//	var addressSchema = MustGenerateSchema(Address{})
+func MustGenerateSchema(v interface{}, opts ...Option) *internal.Schema {
+	schema, err := GenerateSchema(v, opts...)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal schema to JSON: %w", err)
+		panic(fmt.Sprintf("gptschema: GenerateSchema(%T): %v", v, err))
 	}
-	return string(parsedSchema), nil
+	return schema
+}
+
+// MustGenerateSchemaJSON is like GenerateSchemaJSON but panics if the schema cannot be
+// generated.
+//
+// Example:
+//
+//	var addressSchemaJSON = MustGenerateSchemaJSON(Address{})
+func MustGenerateSchemaJSON(v interface{}, opts ...Option) string {
+	schema, err := GenerateSchemaJSON(v, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("gptschema: GenerateSchemaJSON(%T): %v", v, err))
+	}
+	return schema
+}
+
+// buffers reused across calls to GenerateSchemaBytes
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// withArena allocates the maps of the generated schema from arena
+func withArena(arena *internal.Arena) Option {
+	return func(opts *internal.Options) {
+		opts.Arena = arena
+	}
+}
+
+// GenerateSchemaBytes converts a Go type into a JSON Schema encoded as []byte.
+//
+// Most callers pass the schema straight to an HTTP client, so this variant skips the
+// string conversion of GenerateSchemaJSON. The schema is encoded with a json.Encoder into
+// a pooled buffer, and the returned slice is owned by the caller. As the schema is never
+// exposed, the maps and required lists generated for structs, arrays and maps are taken
+// from pools and returned once encoded, which reduces the garbage of services generating
+// schemas per request with dynamic options.
+//
+// This function follows the same type support, options and errors as GenerateSchemaJSON.
+//
+// Example:
+//
+//	body, err := GenerateSchemaBytes(MyResponseFormat{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+func GenerateSchemaBytes(v interface{}, opts ...Option) ([]byte, error) {
+	// the schema never leaves this call, its maps are recycled once encoded
+	arena := internal.NewArena()
+	defer arena.Release()
+	schema, err := GenerateSchema(v, append(opts[:len(opts):len(opts)], withArena(arena))...)
+	if err != nil {
+		return nil, err
+	}
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+	if err := json.NewEncoder(buf).Encode(schema); err != nil {
+		return nil, fmt.Errorf("failed to marshal schema to JSON: %w", err)
+	}
+	// the encoder terminates the value with a newline
+	encoded := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return append([]byte(nil), encoded...), nil
 }
@@ -1,8 +1,13 @@
 package gptschema
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/akane9506/gptschema/internal"
@@ -105,7 +110,7 @@ func TestGenerateSchema_WithMaxDepth(t *testing.T) {
 				if err == nil {
 					t.Errorf("expected error but got none")
 				}
-				if err != internal.ErrCircularRef {
+				if !errors.Is(err, internal.ErrCircularRef) {
 					t.Errorf("expected ErrCircularRef, got %v", err)
 				}
 				if result != nil {
@@ -123,6 +128,109 @@ func TestGenerateSchema_WithMaxDepth(t *testing.T) {
 	}
 }
 
+func TestGenerateSchema_WithGenerationReport(t *testing.T) {
+	type Grid struct {
+		Cells  [][][]string       `json:"cells"`
+		Owners []internal.Company `json:"owners"`
+	}
+	tests := []struct {
+		name     string
+		input    interface{}
+		maxDepth int
+		expected GenerationReport
+	}{
+		{name: "nested arrays", input: Grid{}, maxDepth: 3, expected: GenerationReport{ObjectDepth: 3, ArrayDepth: 3}},
+		{name: "objects in arrays", input: internal.Employee{}, maxDepth: 3, expected: GenerationReport{ObjectDepth: 3, ArrayDepth: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var report GenerationReport
+			if _, err := GenerateSchema(tt.input, WithMaxDepth(tt.maxDepth), WithGenerationReport(&report)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if report != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, report)
+			}
+		})
+	}
+	if _, err := GenerateSchema(internal.Employee{}, WithGenerationReport(nil)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestGenerateSchema_WithRecursiveRefs(t *testing.T) {
+	schema, err := GenerateSchema(internal.Forest{}, WithRecursiveRefs(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*schema, internal.ForestRecursiveSchema) {
+		t.Errorf("expected %+v, got %+v", internal.ForestRecursiveSchema, *schema)
+	}
+	response := `{"trees": [{"name": "oak", "children": [{"label": "branch", "parent": {"name": "oak", "children": [{"label": 1, "parent": null}]}}]}], "oldest": null}`
+	mismatches, err := Validate(schema, []byte(response))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mismatches) != 1 || mismatches[0].Path != "trees[0].children[0].parent.children[0].label" {
+		t.Errorf("expected a mismatch at the nested label, got %v", mismatches)
+	}
+
+	// without the option, the cycle is reported
+	if _, err := GenerateSchema(internal.Tree{}); !errors.Is(err, ErrCircularRef) {
+		t.Errorf("expected ErrCircularRef, got %v", err)
+	}
+}
+
+func TestGenerateSchema_WithDefs(t *testing.T) {
+	type Order struct {
+		Billing struct {
+			Street string `json:"street"`
+		} `json:"billing"`
+		Shipping struct {
+			Street string `json:"street"`
+		} `json:"shipping"`
+		Home internal.Address `json:"home"`
+	}
+	tests := []struct {
+		name  string
+		opts  []Option
+		home  string
+		names []string
+	}{
+		{name: "type names", opts: []Option{WithDefs(true)}, home: "Address", names: []string{"Address", "Billing"}},
+		{name: "path names", opts: []Option{WithDefs(true), WithDefNaming(DefNamePath)}, home: "Home", names: []string{"Billing", "Home"}},
+		{name: "qualified names", opts: []Option{WithDefs(true), WithDefNaming(DefNameQualified)}, home: "InternalAddress", names: []string{"Billing", "InternalAddress"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := GenerateSchema(Order{}, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defs, _ := (*schema)["$defs"].(internal.Schema)
+			names := make([]string, 0, len(defs))
+			for name := range defs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("expected definitions %v, got %v", tt.names, names)
+			}
+			props := (*schema)["properties"].(internal.Schema)
+			if ref := props["shipping"].(internal.Schema)["$ref"]; ref != "#/$defs/Billing" {
+				t.Errorf("expected shipping to share the billing definition, got %v", ref)
+			}
+			if ref := props["home"].(internal.Schema)["$ref"]; ref != "#/$defs/"+tt.home {
+				t.Errorf("expected home to refer to %s, got %v", tt.home, ref)
+			}
+		})
+	}
+
+	if _, err := GenerateSchema(Order{}, WithDefNaming("short")); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
 func TestGenerateSchema_MultipleOptions(t *testing.T) {
 	type Simple struct {
 		Name string `json:"name"`
@@ -191,3 +299,548 @@ func TestGenerateSchemaJSON_InvalidInputs(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSchema_WithStrict(t *testing.T) {
+	type Translations struct {
+		Labels map[string]string `json:"labels" keys:"^[a-z]{2}$"`
+	}
+	if _, err := GenerateSchema(Translations{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType in strict mode, got %v", err)
+	}
+	result, err := GenerateSchemaJSON(Translations{}, WithStrict(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `"labels":{"additionalProperties":false,"patternProperties":{"^[a-z]{2}$":{"type":"string"}},"type":"object"}`
+	if !strings.Contains(result, expected) {
+		t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", expected, result)
+	}
+}
+
+func TestGenerateSchema_WithNumericFormats(t *testing.T) {
+	type Point struct {
+		X float32 `json:"x"`
+		Y int64   `json:"y,omitempty"`
+	}
+	result, err := GenerateSchemaJSON(Point{}, WithStrict(false), WithNumericFormats(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		`"x":{"format":"float","type":"number"}`,
+		`"y":{"anyOf":[{"format":"int64","type":"integer"},{"type":"null"}]}`,
+	} {
+		if !strings.Contains(result, part) {
+			t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", part, result)
+		}
+	}
+}
+
+func TestGenerateSchema_CircularRefPath(t *testing.T) {
+	_, err := GenerateSchema(internal.Tree{})
+	var cycleErr *CircularRefError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected *CircularRefError, got %v", err)
+	}
+	if !errors.Is(err, ErrCircularRef) {
+		t.Errorf("expected error to wrap ErrCircularRef")
+	}
+	expected := "circular reference detected: Tree.Children → Branch.Parent → Tree"
+	if err.Error() != expected {
+		t.Errorf("mismatch error message, expect=%s, got=%s", expected, err.Error())
+	}
+}
+
+func TestGenerateSchema_WithRecursionUnroll(t *testing.T) {
+	result, err := GenerateSchemaJSON(internal.Node{}, WithRecursionUnroll(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `"next":{"type":"null"}`; !strings.Contains(result, expected) {
+		t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", expected, result)
+	}
+	if _, err := GenerateSchema(internal.Node{}); !errors.Is(err, ErrCircularRef) {
+		t.Errorf("expected ErrCircularRef without unrolling, got %v", err)
+	}
+}
+
+func TestSetDefaultOptions(t *testing.T) {
+	type Order struct {
+		ID int64 `json:"id"`
+	}
+	defer SetDefaultOptions()
+	SetDefaultOptions(WithInt64AsString(true))
+
+	result, err := GenerateSchemaJSON(Order{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `"id":{"pattern":"^-?[0-9]+$","type":"string"}`; !strings.Contains(result, expected) {
+		t.Errorf("expected default option to apply, got %s", result)
+	}
+
+	// options of a call override the defaults
+	result, err = GenerateSchemaJSON(Order{}, WithInt64AsString(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `"id":{"type":"integer"}`; !strings.Contains(result, expected) {
+		t.Errorf("expected call option to override default, got %s", result)
+	}
+
+	// calling without options restores the built-in defaults
+	SetDefaultOptions()
+	result, err = GenerateSchemaJSON(Order{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `"id":{"type":"integer"}`; !strings.Contains(result, expected) {
+		t.Errorf("expected built-in defaults after reset, got %s", result)
+	}
+}
+
+func TestSetDefaultOptions_Concurrent(t *testing.T) {
+	defer SetDefaultOptions()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(depth int) {
+			defer wg.Done()
+			SetDefaultOptions(WithMaxDepth(depth + 10))
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := GenerateSchema(internal.StructWithTags{}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGenerateSchema_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		errorMsg string
+	}{
+		{
+			name:     "negative max depth",
+			opts:     []Option{WithMaxDepth(-3)},
+			errorMsg: "invalid option: max depth must be at least 1, got -3",
+		},
+		{
+			name:     "zero max depth",
+			opts:     []Option{WithMaxDepth(0)},
+			errorMsg: "invalid option: max depth must be at least 1, got 0",
+		},
+		{
+			name:     "negative recursion unroll",
+			opts:     []Option{WithRecursionUnroll(-1)},
+			errorMsg: "invalid option: recursion unroll must not be negative, got -1",
+		},
+		{
+			name:     "unknown time format",
+			opts:     []Option{WithTimeFormat("iso8601")},
+			errorMsg: `invalid option: unknown time format "iso8601"`,
+		},
+		{
+			name:     "unknown naming convention",
+			opts:     []Option{WithNamingConvention("kebab-case")},
+			errorMsg: `invalid option: unknown naming convention "kebab-case"`,
+		},
+		{
+			name:     "unknown embedding policy",
+			opts:     []Option{WithEmbeddingPolicy("inner_wins")},
+			errorMsg: `invalid option: unknown embedding policy "inner_wins"`,
+		},
+		{
+			name:     "unknown nullable style",
+			opts:     []Option{WithNullableStyle("oneOf")},
+			errorMsg: `invalid option: unknown nullable style "oneOf"`,
+		},
+		{
+			name:     "negative max schema bytes",
+			opts:     []Option{WithMaxSchemaBytes(-1)},
+			errorMsg: "invalid option: max schema bytes must not be negative, got -1",
+		},
+		{
+			name:     "negative max description length",
+			opts:     []Option{WithMaxDescriptionLength(-5)},
+			errorMsg: "invalid option: max description length must not be negative, got -5",
+		},
+		{
+			name:     "unknown limit policy",
+			opts:     []Option{WithLimitPolicy("panic")},
+			errorMsg: `invalid option: unknown limit policy "panic"`,
+		},
+		{
+			name:     "errors are accumulated",
+			opts:     []Option{WithMaxDepth(-1), WithRecursionUnroll(-2)},
+			errorMsg: "invalid option: max depth must be at least 1, got -1\ninvalid option: recursion unroll must not be negative, got -2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateSchema(internal.StructWithTags{}, tt.opts...)
+			if !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("expected ErrInvalidOption, got %v", err)
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("mismatch error message, expect=%s, got=%s", tt.errorMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestGenerateSchemaBytes(t *testing.T) {
+	expected, err := json.Marshal(internal.StructWithTagsSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		result, err := GenerateSchemaBytes(internal.StructWithTags{})
+		if err != nil {
+			t.Fatalf("GenerateSchemaBytes() error = %v", err)
+		}
+		if !bytes.Equal(result, expected) {
+			t.Errorf("expected %s, got %s", expected, result)
+		}
+	}
+	if _, err := GenerateSchemaBytes(nil); err == nil {
+		t.Errorf("GenerateSchemaBytes() expected error but got none")
+	}
+}
+
+func TestGenerateSchemaBytes_Pooled(t *testing.T) {
+	// concurrent calls recycle the maps of each other's schemas
+	expected, err := json.Marshal(internal.EmployeeSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				result, err := GenerateSchemaBytes(internal.Employee{}, WithInternedSubschemas(j%2 == 0))
+				if err != nil || !bytes.Equal(result, expected) {
+					t.Errorf("expected %s, got %s (%v)", expected, result, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// schemas returned by GenerateSchema are never recycled
+	schema := MustGenerateSchema(internal.Employee{})
+	if _, err := GenerateSchemaBytes(internal.Employee{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*schema, internal.EmployeeSchema) {
+		t.Errorf("expected %+v, got %+v", internal.EmployeeSchema, *schema)
+	}
+}
+
+func TestGenerateSchema_EmbeddingPolicy(t *testing.T) {
+	_, err := GenerateSchema(internal.ShadowedTimestamps{}, WithEmbeddingPolicy(ErrorOnConflict))
+	if !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+	schema, err := GenerateSchema(internal.ShadowedTimestamps{}, WithEmbeddingPolicy(OuterWins))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*schema, internal.ShadowedTimestampsSchema) {
+		t.Errorf("expected %+v, got %+v", internal.ShadowedTimestampsSchema, *schema)
+	}
+}
+
+func TestGenerateSchema_JSONv2Semantics(t *testing.T) {
+	type Counter struct {
+		Hits  int    `json:"hits,omitempty"`
+		Label string `json:"label,omitzero"`
+	}
+	result, err := GenerateSchemaJSON(Counter{}, WithJSONv2Semantics(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		`"hits":{"type":"integer"}`,
+		`"label":{"type":["string","null"]}`,
+	} {
+		if !strings.Contains(result, part) {
+			t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", part, result)
+		}
+	}
+}
+
+func TestGenerateSchema_NullableStyle(t *testing.T) {
+	type Owner struct {
+		Name string `json:"name"`
+	}
+	type Pet struct {
+		Nick  *string `json:"nick,omitempty"`
+		Owner *Owner  `json:"owner,omitempty"`
+	}
+	result, err := GenerateSchemaJSON(Pet{}, WithNullableStyle(NullableAnyOf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		`"nick":{"anyOf":[{"type":"string"},{"type":"null"}]}`,
+		`"owner":{"anyOf":[{"additionalProperties":false`,
+	} {
+		if !strings.Contains(result, part) {
+			t.Errorf("GenerateSchemaJSON() result missing expected part %s\nGot: %s", part, result)
+		}
+	}
+}
+
+func TestGenerateSchema_MaxSchemaBytes(t *testing.T) {
+	encoded, err := GenerateSchemaBytes(internal.StructWithTags{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := GenerateSchema(internal.StructWithTags{}, WithMaxSchemaBytes(len(encoded))); err != nil {
+		t.Errorf("unexpected error at the exact size: %v", err)
+	}
+	_, err = GenerateSchemaBytes(internal.StructWithTags{}, WithMaxSchemaBytes(len(encoded)-1))
+	var sizeErr *SchemaSizeError
+	if !errors.As(err, &sizeErr) || !errors.Is(err, ErrSchemaTooLarge) {
+		t.Fatalf("expected *SchemaSizeError, got %v", err)
+	}
+	if sizeErr.Size != len(encoded) || sizeErr.Limit != len(encoded)-1 || len(sizeErr.Contributors) == 0 {
+		t.Errorf("unexpected size error %+v", sizeErr)
+	}
+}
+
+func TestGenerateSchema_MaxDescriptionLength(t *testing.T) {
+	var warnings []string
+	result, err := GenerateSchemaJSON(internal.DescribedContact{},
+		WithMaxDescriptionLength(10),
+		WithWarningHandler(func(w Warning) { warnings = append(warnings, w.String()) }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result, `"description":"Full name…"`) {
+		t.Errorf("expected truncated description, got %s", result)
+	}
+	sort.Strings(warnings)
+	expected := []string{
+		"name: description truncated from 24 to 10 characters",
+		"phone: description truncated from 31 to 10 characters",
+		"tags: description truncated from 16 to 10 characters",
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected warnings %v, got %v", expected, warnings)
+	}
+}
+
+func TestGenerateSchema_ErrorCodes(t *testing.T) {
+	type Stream struct {
+		Updates chan int `json:"updates"`
+	}
+	type Invalid struct {
+		Name string `json:"name" required:"maybe"`
+	}
+	tests := []struct {
+		name  string
+		input interface{}
+		opts  []Option
+		code  ErrorCode
+		path  string
+	}{
+		{name: "nil value", input: nil, code: UnsupportedType},
+		{name: "unsupported field", input: Stream{}, code: UnsupportedType, path: "updates"},
+		{name: "tag syntax", input: Invalid{}, code: TagSyntax, path: "name"},
+		{name: "circular reference", input: internal.Node{}, code: CircularRef, path: "next"},
+		{name: "max depth", input: internal.Employee{}, opts: []Option{WithMaxDepth(1)}, code: MaxDepth, path: "companies[].name"},
+		{name: "invalid option", input: internal.Employee{}, opts: []Option{WithMaxDepth(0)}, code: InvalidOption},
+		{name: "size budget", input: internal.Employee{}, opts: []Option{WithMaxSchemaBytes(10)}, code: LimitExceeded},
+		{name: "disallowed keyword", input: internal.Employee{}, opts: []Option{WithAllowedKeywords("type")}, code: LintFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateSchema(tt.input, tt.opts...)
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("expected a SchemaError, got %v", err)
+			}
+			if schemaErr.Code != tt.code || schemaErr.Path != tt.path {
+				t.Errorf("expected %s at %q, got %s at %q", tt.code, tt.path, schemaErr.Code, schemaErr.Path)
+			}
+		})
+	}
+
+	_, err := Object().Prop("age", Integer().MinLength(1)).Build()
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || schemaErr.Code != InvalidSchema || schemaErr.Path != "age" || !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected an InvalidSchema error at age, got %v", err)
+	}
+}
+
+func TestMustGenerateSchema(t *testing.T) {
+	if schema := MustGenerateSchema(internal.Employee{}); !reflect.DeepEqual(*schema, internal.EmployeeSchema) {
+		t.Errorf("expected %+v, got %+v", internal.EmployeeSchema, *schema)
+	}
+	expected, _ := GenerateSchemaJSON(internal.Employee{})
+	if result := MustGenerateSchemaJSON(internal.Employee{}); result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	tests := []struct {
+		name     string
+		generate func()
+		expected string
+	}{
+		{
+			name:     "schema",
+			generate: func() { MustGenerateSchema(internal.Node{}) },
+			expected: "gptschema: GenerateSchema(internal.Node): circular reference detected: Node.Next → Node",
+		},
+		{
+			name:     "json",
+			generate: func() { MustGenerateSchemaJSON(internal.Employee{}, WithMaxDepth(0)) },
+			expected: "gptschema: GenerateSchemaJSON(internal.Employee): invalid option: max depth must be at least 1, got 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.expected {
+					t.Errorf("expected panic %q, got %v", tt.expected, r)
+				}
+			}()
+			tt.generate()
+		})
+	}
+}
+
+func TestGenerateSchema_InternedSubschemas(t *testing.T) {
+	expected, err := GenerateSchema(internal.Employee{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema, err := GenerateSchema(internal.Employee{}, WithInternedSubschemas(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(schema, expected) {
+		t.Errorf("expected %+v, got %+v", expected, schema)
+	}
+	props := (*schema)["properties"].(internal.Schema)
+	name := reflect.ValueOf(props["name"]).Pointer()
+	items := reflect.ValueOf(props["tags"].(internal.Schema)["anyOf"].([]internal.Schema)[0]["items"]).Pointer()
+	if name != items {
+		t.Errorf("expected the string subschemas to be shared")
+	}
+}
+
+func TestGenerateSchema_HumanizedDescriptions(t *testing.T) {
+	type Event struct {
+		CreatedAt string `json:"createdAt"`
+		Title     string `json:"title" description:"Event title"`
+	}
+	result, err := GenerateSchemaJSON(Event{}, WithHumanizedDescriptions(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result, `"createdAt":{"description":"Created at","type":"string"}`) ||
+		!strings.Contains(result, `"description":"Event title"`) {
+		t.Errorf("expected humanized and tag descriptions, got %s", result)
+	}
+
+	result, err = GenerateSchemaJSON(Event{}, WithHumanizedDescriptions(strings.ToUpper))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result, `"description":"CREATEDAT"`) || strings.Contains(result, `"TITLE"`) {
+		t.Errorf("expected the custom humanizer on undescribed fields only, got %s", result)
+	}
+
+	result, err = GenerateSchemaJSON(Event{})
+	if err != nil || strings.Contains(result, "Created at") {
+		t.Errorf("expected no humanized descriptions by default, got %s %v", result, err)
+	}
+}
+
+func TestGenerateSchema_Provenance(t *testing.T) {
+	schema, err := GenerateSchema(&internal.Employee{},
+		WithSchemaID("https://schemas.example.com/"),
+		WithProvenanceComment(true),
+		WithStrict(false),
+		WithMaxDepth(20),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id := (*schema)["$id"]; id != "https://schemas.example.com/github.com/akane9506/gptschema/internal/Employee" {
+		t.Errorf("unexpected $id %v", id)
+	}
+	comment, _ := (*schema)["$comment"].(string)
+	if !strings.HasPrefix(comment, "generated by gptschema") ||
+		!strings.Contains(comment, " from internal.Employee (github.com/akane9506/gptschema/internal") ||
+		!strings.HasSuffix(comment, "options: strict=false, max_depth=20") {
+		t.Errorf("unexpected $comment %q", comment)
+	}
+
+	anonymous, err := GenerateSchema(struct {
+		Name string `json:"name"`
+	}{}, WithSchemaID("https://schemas.example.com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := (*anonymous)["$id"]; ok {
+		t.Errorf("expected no $id for an anonymous struct")
+	}
+	if plain, _ := GenerateSchema(internal.Employee{}); (*plain)["$id"] != nil || (*plain)["$comment"] != nil {
+		t.Errorf("expected no provenance by default")
+	}
+
+	for _, base := range []string{"", "schemas/models", "://invalid"} {
+		if _, err := GenerateSchema(internal.Employee{}, WithSchemaID(base)); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("expected ErrInvalidOption for base %q, got %v", base, err)
+		}
+	}
+}
+
+func TestGenerateSchema_RequiredOrder(t *testing.T) {
+	schema, err := GenerateSchema(internal.ReorderedTimestamps{}, WithRequiredOrder(Alphabetical))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if required := (*schema)["required"]; !reflect.DeepEqual(required, []string{"Created", "Note", "Updated"}) {
+		t.Errorf("unexpected required %v", required)
+	}
+	if _, err := GenerateSchema(internal.ReorderedTimestamps{}, WithRequiredOrder("random")); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestGenerateSchema_TypesTag(t *testing.T) {
+	schema, err := GenerateSchema(internal.Measurement{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issues, _ := Lint(schema); len(issues) != 0 {
+		t.Errorf("expected the schema to pass lint, got %v", issues)
+	}
+	data := []byte(`{"value":"12.5kg","unit":"kg","offset":3,"flag":null}`)
+	if mismatches, err := Validate(schema, data); err != nil || len(mismatches) != 0 {
+		t.Fatalf("expected a valid response, got %v %v", mismatches, err)
+	}
+	var m internal.Measurement
+	if err := Unmarshal(data, &m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Value != "12.5kg" || string(m.Unit) != `"kg"` || m.Offset != float64(3) {
+		t.Errorf("unexpected value %+v", m)
+	}
+	if mismatches, _ := Validate(schema, []byte(`{"value":true,"unit":"kg","offset":null,"flag":false}`)); len(mismatches) != 1 || mismatches[0].Path != "value" {
+		t.Errorf("expected a type mismatch on value, got %v", mismatches)
+	}
+}
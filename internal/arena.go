@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"reflect"
+	"sync"
+)
+
+// maxPooledEntries keeps unusually large maps and slices out of the pools, so one huge
+// schema does not pin its memory for the lifetime of the process
+const maxPooledEntries = 256
+
+var (
+	schemaPool  = sync.Pool{New: func() interface{} { return make(Schema, 4) }}
+	stringsPool = sync.Pool{New: func() interface{} { return new([]string) }}
+	visitedPool = sync.Pool{New: func() interface{} { return make(map[reflect.Type]int) }}
+)
+
+// Arena allocates the maps of a schema whose lifetime ends with a single call, such as a
+// schema generated only to be marshaled, and returns them to shared pools on Release.
+// Only the maps allocated by the arena are released, with the required lists stored in
+// them, so the schemas shared with the rest of the program, like registered enums, are
+// never recycled. A nil Arena allocates from the heap and releases nothing. An Arena is
+// not safe for concurrent use.
+type Arena struct {
+	schemas []Schema
+}
+
+// NewArena returns an empty arena
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+// schema returns an empty map owned by the arena
+func (a *Arena) schema() Schema {
+	if a == nil {
+		return make(Schema)
+	}
+	s := schemaPool.Get().(Schema)
+	a.schemas = append(a.schemas, s)
+	return s
+}
+
+// strings returns an empty slice, pooled when the arena is not nil
+func (a *Arena) strings() []string {
+	if a == nil {
+		return nil
+	}
+	return (*stringsPool.Get().(*[]string))[:0]
+}
+
+// Release clears the maps allocated by the arena and returns them to the pools, the
+// schemas built from them must not be used afterwards
+func (a *Arena) Release() {
+	if a == nil {
+		return
+	}
+	for _, s := range a.schemas {
+		if required, ok := s["required"].([]string); ok && cap(required) <= maxPooledEntries {
+			required = required[:0]
+			stringsPool.Put(&required)
+		}
+		if len(s) <= maxPooledEntries {
+			clear(s)
+			schemaPool.Put(s)
+		}
+	}
+	a.schemas = nil
+}
+
+// AcquireVisited returns an empty map tracking the types being converted, for JsonTypeOf
+func AcquireVisited() map[reflect.Type]int {
+	return visitedPool.Get().(map[reflect.Type]int)
+}
+
+// ReleaseVisited returns a map obtained from AcquireVisited to the pool
+func ReleaseVisited(visited map[reflect.Type]int) {
+	clear(visited)
+	visitedPool.Put(visited)
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArena(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected Schema
+	}{
+		{"nested structs", Employee{}, EmployeeSchema},
+		{"pointers", CollectionWithPointers{}, CollectionWithPointersSchema},
+		{"nested", NestedStruct{}, NestedStructSchema},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the second round reuses the maps released by the first one
+			for round := 0; round < 2; round++ {
+				opts, _, depth := getInputs()
+				opts.Arena = NewArena()
+				visited := AcquireVisited()
+				result, err := JsonTypeOf(reflect.TypeOf(tt.input), visited, depth, opts)
+				ReleaseVisited(visited)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(result, tt.expected) {
+					t.Fatalf("round %d: expected %v, got %v", round, tt.expected, result)
+				}
+				opts.Arena.Release()
+				if len(result.(Schema)) != 0 {
+					t.Errorf("expected the released schema to be cleared, got %v", result)
+				}
+			}
+		})
+	}
+}
+
+func TestArenaReleasesOwnMapsOnly(t *testing.T) {
+	shared := Schema{"type": "string", "enum": []interface{}{"a", "b"}}
+	arena := NewArena()
+	s := arena.schema()
+	s["type"] = "array"
+	s["items"] = shared
+	arena.Release()
+	if len(s) != 0 {
+		t.Errorf("expected the arena map to be cleared, got %v", s)
+	}
+	if len(shared) != 2 {
+		t.Errorf("expected the shared schema to be untouched, got %v", shared)
+	}
+
+	var nilArena *Arena
+	if m := nilArena.schema(); m == nil || len(m) != 0 {
+		t.Errorf("expected a heap allocated map, got %v", m)
+	}
+	if nilArena.strings() != nil {
+		t.Errorf("expected a nil slice")
+	}
+	nilArena.Release()
+}
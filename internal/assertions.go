@@ -0,0 +1,293 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AssertionKind classifies an Assertion
+type AssertionKind string
+
+const (
+	// AssertExists checks that a required property is present in its object
+	AssertExists AssertionKind = "exists"
+	// AssertType checks the JSON type of a value
+	AssertType AssertionKind = "type"
+	// AssertEnum checks that a value is one of the enum values, or the const
+	AssertEnum AssertionKind = "enum"
+	// AssertRange checks the minimum and maximum bounds of a number
+	AssertRange AssertionKind = "range"
+)
+
+// Assertion is a check on a decoded response derived from a schema. It applies to every
+// value at its path: "[]" stands for all array items and "{}" for all map values, and
+// values missing from the response are left to the exists assertions.
+type Assertion struct {
+	// Path of the checked value, e.g. "items[].price", the empty path is the response
+	Path string
+	Kind AssertionKind
+	// Description states the expectation, e.g. `status is one of "open", "closed"`
+	Description string
+	// target is the path the check runs on, the parent object for exists assertions
+	target string
+	check  func(value interface{}) string
+}
+
+func (a Assertion) String() string {
+	return a.Description
+}
+
+// Check runs the assertion against a response decoded with UseNumber, and reports the
+// first value failing it
+func (a Assertion) Check(document interface{}) error {
+	for _, located := range valuesAt(document, a.target) {
+		if message := a.check(located.value); message != "" {
+			return fmt.Errorf("%s: %s", pathLabel(located.path), message)
+		}
+	}
+	return nil
+}
+
+// SchemaAssertions derives the assertions of a schema: the presence of every required
+// property, and the type, enum values and numeric range of every value, descending into
+// properties, array items and map values. $refs are not followed.
+func SchemaAssertions(s Schema) []Assertion {
+	var assertions []Assertion
+	collectAssertions(s, "", false, &assertions)
+	return assertions
+}
+
+func collectAssertions(s Schema, path string, nullable bool, assertions *[]Assertion) {
+	if types := assertedTypes(s); len(types) > 0 {
+		*assertions = append(*assertions, typeAssertion(path, types, nullable))
+	}
+	values, hasEnum := s["enum"].([]interface{})
+	if constant, ok := s["const"]; ok && !hasEnum {
+		values, hasEnum = []interface{}{constant}, true
+	}
+	if hasEnum {
+		*assertions = append(*assertions, enumAssertion(path, values, nullable))
+	}
+	if assertion, ok := rangeAssertion(s, path); ok {
+		*assertions = append(*assertions, assertion)
+	}
+
+	props, _ := s["properties"].(Schema)
+	required := make(map[string]bool)
+	if names, ok := s["required"].([]string); ok {
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	for _, name := range orderedProperties(s) {
+		prop, ok := props[name].(Schema)
+		if !ok {
+			continue
+		}
+		propPath := joinPath(path, name)
+		if required[name] {
+			*assertions = append(*assertions, existsAssertion(path, name))
+		}
+		unwrapped, propNullable := unwrapNullable(prop)
+		collectAssertions(unwrapped, propPath, propNullable, assertions)
+	}
+	if items, ok := s["items"].(Schema); ok {
+		unwrapped, itemsNullable := unwrapNullable(items)
+		collectAssertions(unwrapped, path+"[]", itemsNullable, assertions)
+	}
+	if values, ok := s["additionalProperties"].(Schema); ok {
+		unwrapped, valuesNullable := unwrapNullable(values)
+		collectAssertions(unwrapped, path+"{}", valuesNullable, assertions)
+	}
+}
+
+// assertedTypes returns the types of a schema, from its type keyword or the variants
+// of an anyOf, nil when any of them is unconstrained
+func assertedTypes(s Schema) []string {
+	switch t := s["type"].(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	}
+	variants, ok := s["anyOf"].([]Schema)
+	if !ok {
+		return nil
+	}
+	var types []string
+	for _, variant := range variants {
+		variantTypes := assertedTypes(variant)
+		if variantTypes == nil {
+			return nil
+		}
+		for _, t := range variantTypes {
+			if !containsString(types, t) {
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
+func existsAssertion(parent, name string) Assertion {
+	return Assertion{
+		Path:        joinPath(parent, name),
+		Kind:        AssertExists,
+		Description: joinPath(parent, name) + " is present",
+		target:      parent,
+		check: func(value interface{}) string {
+			// a parent of another type is reported by its type assertion
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return ""
+			}
+			if _, ok := object[name]; !ok {
+				return fmt.Sprintf("missing required property %q", name)
+			}
+			return ""
+		},
+	}
+}
+
+func typeAssertion(path string, types []string, nullable bool) Assertion {
+	if nullable && !containsString(types, "null") {
+		types = append(types[:len(types):len(types)], "null")
+	}
+	return Assertion{
+		Path:        path,
+		Kind:        AssertType,
+		Description: fmt.Sprintf("%s is %s", pathLabel(path), strings.Join(types, " or ")),
+		target:      path,
+		check: func(value interface{}) string {
+			actual := jsonTypeOfValue(value)
+			for _, t := range types {
+				if t == actual || (t == "number" && actual == "integer") {
+					return ""
+				}
+			}
+			return fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), actual)
+		},
+	}
+}
+
+func enumAssertion(path string, values []interface{}, nullable bool) Assertion {
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = jsonLiteral(v)
+	}
+	return Assertion{
+		Path:        path,
+		Kind:        AssertEnum,
+		Description: fmt.Sprintf("%s is one of %s", pathLabel(path), strings.Join(literals, ", ")),
+		target:      path,
+		check: func(value interface{}) string {
+			if (value == nil && nullable) || containsJSON(values, value) {
+				return ""
+			}
+			return fmt.Sprintf("%s is not one of %s", valueSnippet(value), strings.Join(literals, ", "))
+		},
+	}
+}
+
+// rangeAssertion checks the bounds of a number, other values are left to the type assertion
+func rangeAssertion(s Schema, path string) (Assertion, bool) {
+	type bound struct {
+		keyword string
+		symbol  string
+		fails   func(cmp int) bool
+	}
+	var bounds []bound
+	var labels []string
+	for _, b := range []bound{
+		{"minimum", ">=", func(cmp int) bool { return cmp < 0 }},
+		{"exclusiveMinimum", ">", func(cmp int) bool { return cmp <= 0 }},
+		{"maximum", "<=", func(cmp int) bool { return cmp > 0 }},
+		{"exclusiveMaximum", "<", func(cmp int) bool { return cmp >= 0 }},
+	} {
+		if _, ok := toRat(s[b.keyword]); ok {
+			bounds = append(bounds, b)
+			labels = append(labels, b.symbol+" "+jsonLiteral(s[b.keyword]))
+		}
+	}
+	if len(bounds) == 0 {
+		return Assertion{}, false
+	}
+	return Assertion{
+		Path:        path,
+		Kind:        AssertRange,
+		Description: fmt.Sprintf("%s is %s", pathLabel(path), strings.Join(labels, " and ")),
+		target:      path,
+		check: func(value interface{}) string {
+			n, ok := toRat(value)
+			if !ok {
+				return ""
+			}
+			for i, b := range bounds {
+				limit, _ := toRat(s[b.keyword])
+				if b.fails(n.Cmp(limit)) {
+					return fmt.Sprintf("expected a number %s, got %s", labels[i], valueSnippet(value))
+				}
+			}
+			return ""
+		},
+	}, true
+}
+
+// locatedValue is a value of a response with its concrete path, e.g. "items[2].price"
+type locatedValue struct {
+	path  string
+	value interface{}
+}
+
+// valuesAt returns the values at a path of a response: all items for "[]", all map values
+// for "{}", nothing where the response lacks a property or has another type
+func valuesAt(document interface{}, path string) []locatedValue {
+	current := []locatedValue{{value: document}}
+	if path == "" {
+		return current
+	}
+	for _, token := range strings.Split(path, ".") {
+		name := strings.TrimRight(token, "[]{}")
+		suffix := token[len(name):]
+		next := current
+		if name != "" {
+			next = nil
+			for _, located := range current {
+				object, _ := located.value.(map[string]interface{})
+				if value, ok := object[name]; ok {
+					next = append(next, locatedValue{path: joinPath(located.path, name), value: value})
+				}
+			}
+		}
+		for ; suffix != ""; suffix = suffix[2:] {
+			var expanded []locatedValue
+			for _, located := range next {
+				switch container := located.value.(type) {
+				case []interface{}:
+					if strings.HasPrefix(suffix, "[]") {
+						for i, item := range container {
+							expanded = append(expanded, locatedValue{path: fmt.Sprintf("%s[%d]", located.path, i), value: item})
+						}
+					}
+				case map[string]interface{}:
+					if strings.HasPrefix(suffix, "{}") {
+						for _, key := range sortedMapKeys(container) {
+							expanded = append(expanded, locatedValue{path: joinPath(located.path, key), value: container[key]})
+						}
+					}
+				}
+			}
+			next = expanded
+		}
+		current = next
+	}
+	return current
+}
+
+// pathLabel names a path in messages, the empty path is the response
+func pathLabel(path string) string {
+	if path == "" {
+		return "response"
+	}
+	return path
+}
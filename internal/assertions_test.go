@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var assertionSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"status":   Schema{"type": "string", "enum": []interface{}{"open", "closed"}},
+		"priority": Schema{"type": "integer", "minimum": 1, "maximum": 5},
+		"assignee": Schema{"type": []string{"string", "null"}},
+		"items": Schema{
+			"type": "array",
+			"items": Schema{
+				"type":       "object",
+				"properties": Schema{"price": Schema{"type": "number", "exclusiveMinimum": 0}},
+				"required":   []string{"price"},
+			},
+		},
+		"labels": Schema{"type": "object", "additionalProperties": Schema{"type": "string"}},
+		"kind":   Schema{"type": "string", "const": "ticket"},
+	},
+	"required": []string{"status", "priority", "assignee", "items"},
+}
+
+func TestSchemaAssertions(t *testing.T) {
+	var descriptions []string
+	for _, assertion := range SchemaAssertions(assertionSchema) {
+		descriptions = append(descriptions, string(assertion.Kind)+" "+assertion.Path+": "+assertion.Description)
+	}
+	expected := []string{
+		"type : response is object",
+		"exists status: status is present",
+		"type status: status is string",
+		"enum status: status is one of \"open\", \"closed\"",
+		"exists priority: priority is present",
+		"type priority: priority is integer",
+		"range priority: priority is >= 1 and <= 5",
+		"exists assignee: assignee is present",
+		"type assignee: assignee is string or null",
+		"exists items: items is present",
+		"type items: items is array",
+		"type items[]: items[] is object",
+		"exists items[].price: items[].price is present",
+		"type items[].price: items[].price is number",
+		"range items[].price: items[].price is > 0",
+		"type kind: kind is string",
+		"enum kind: kind is one of \"ticket\"",
+		"type labels: labels is object",
+		"type labels{}: labels{} is string",
+	}
+	if !reflect.DeepEqual(descriptions, expected) {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(descriptions, "\n"))
+	}
+}
+
+func TestAssertionCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected []string
+	}{
+		{
+			name:     "valid",
+			response: `{"status":"open","priority":3,"assignee":null,"items":[{"price":1.5}],"labels":{"team":"web"},"kind":"ticket"}`,
+		},
+		{
+			name:     "missing properties",
+			response: `{"status":"open","items":[{"price":2},{}]}`,
+			expected: []string{
+				`response: missing required property "priority"`,
+				`response: missing required property "assignee"`,
+				`items[1]: missing required property "price"`,
+			},
+		},
+		{
+			name:     "wrong values",
+			response: `{"status":"pending","priority":7.5,"assignee":1,"items":[{"price":0}],"labels":{"team":2},"kind":"bug"}`,
+			expected: []string{
+				`status: "pending" is not one of "open", "closed"`,
+				`priority: expected integer, got number`,
+				`priority: expected a number <= 5, got 7.5`,
+				`assignee: expected string or null, got integer`,
+				`items[0].price: expected a number > 0, got 0`,
+				`kind: "bug" is not one of "ticket"`,
+				`labels.team: expected string, got integer`,
+			},
+		},
+		{
+			name:     "not an object",
+			response: `[1]`,
+			expected: []string{`response: expected object, got array`},
+		},
+	}
+	assertions := SchemaAssertions(assertionSchema)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := json.NewDecoder(strings.NewReader(tt.response))
+			decoder.UseNumber()
+			var document interface{}
+			if err := decoder.Decode(&document); err != nil {
+				t.Fatal(err)
+			}
+			var failures []string
+			for _, assertion := range assertions {
+				if err := assertion.Check(document); err != nil {
+					failures = append(failures, err.Error())
+				}
+			}
+			if !reflect.DeepEqual(failures, tt.expected) {
+				t.Errorf("expected\n%s\ngot\n%s", strings.Join(tt.expected, "\n"), strings.Join(failures, "\n"))
+			}
+		})
+	}
+}
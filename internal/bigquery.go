@@ -0,0 +1,116 @@
+package internal
+
+import "regexp"
+
+// BigQueryField is a column of a BigQuery table schema
+type BigQueryField struct {
+	Name string `json:"name"`
+	// Type is a legacy SQL type name, e.g. STRING, INTEGER or RECORD
+	Type string `json:"type"`
+	// Mode is REQUIRED, NULLABLE or REPEATED
+	Mode        string          `json:"mode"`
+	Description string          `json:"description,omitempty"`
+	Fields      []BigQueryField `json:"fields,omitempty"`
+}
+
+// bigQueryFormats maps string formats to the BigQuery types storing them natively
+var bigQueryFormats = map[string]string{
+	"date-time": "TIMESTAMP",
+	"date":      "DATE",
+	"time":      "TIME",
+}
+
+// invalidColumnName matches the characters BigQuery rejects in column names
+var invalidColumnName = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// RenderBigQuery converts an object schema into the fields of a BigQuery table schema.
+// Nested objects become RECORD fields, arrays REPEATED fields, and nullable or optional
+// properties NULLABLE fields. Values BigQuery cannot type, such as maps, unions and arrays
+// of arrays, become JSON columns, and invalid column names are rewritten; both are
+// reported as warnings.
+func RenderBigQuery(schema Schema, opts *Options) []BigQueryField {
+	return bigQueryFields(schema, "", opts)
+}
+
+func bigQueryFields(s Schema, path string, opts *Options) []BigQueryField {
+	props, _ := s["properties"].(Schema)
+	required := make(map[string]bool)
+	if names, ok := s["required"].([]string); ok {
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	fields := []BigQueryField{}
+	for _, name := range orderedProperties(s) {
+		prop, ok := props[name].(Schema)
+		if !ok {
+			continue
+		}
+		fieldPath := joinPath(path, name)
+		unwrapped, nullable := unwrapNullable(prop)
+		field := BigQueryField{Name: bigQueryColumnName(name, fieldPath, opts), Mode: "REQUIRED"}
+		if !required[name] || nullable {
+			field.Mode = "NULLABLE"
+		}
+		if description, ok := unwrapped["description"].(string); ok {
+			field.Description = description
+		}
+		if unwrapped["type"] == "array" {
+			field.Mode = "REPEATED"
+			items, _ := unwrapped["items"].(Schema)
+			items, _ = unwrapNullable(items)
+			if items["type"] == "array" {
+				opts.Warn(fieldPath, "arrays of arrays cannot be repeated in BigQuery, stored as JSON")
+				field.Type = "JSON"
+				fields = append(fields, field)
+				continue
+			}
+			unwrapped = items
+			fieldPath += "[]"
+		}
+		field.Type, field.Fields = bigQueryType(unwrapped, fieldPath, opts)
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// bigQueryType returns the type of a value, and the fields of records
+func bigQueryType(s Schema, path string, opts *Options) (string, []BigQueryField) {
+	switch s["type"] {
+	case "string":
+		format, _ := s["format"].(string)
+		if t, ok := bigQueryFormats[format]; ok {
+			return t, nil
+		}
+		return "STRING", nil
+	case "integer":
+		return "INTEGER", nil
+	case "number":
+		return "FLOAT", nil
+	case "boolean":
+		return "BOOLEAN", nil
+	case "object":
+		// records need at least one field
+		if props, ok := s["properties"].(Schema); ok && len(props) > 0 {
+			if _, isMap := mapValues(s); !isMap {
+				return "RECORD", bigQueryFields(s, path, opts)
+			}
+		}
+		opts.Warn(path, "maps and objects without properties have no BigQuery type, stored as JSON")
+		return "JSON", nil
+	}
+	opts.Warn(path, "value without a single type cannot be typed in BigQuery, stored as JSON")
+	return "JSON", nil
+}
+
+// bigQueryColumnName rewrites a property name into a valid column name
+func bigQueryColumnName(name, path string, opts *Options) string {
+	column := invalidColumnName.ReplaceAllString(name, "_")
+	if column == "" || (column[0] >= '0' && column[0] <= '9') {
+		column = "_" + column
+	}
+	if column != name {
+		opts.Warn(path, "property name is not a valid BigQuery column name, renamed to %q", column)
+	}
+	return column
+}
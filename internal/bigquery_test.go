@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderBigQuery(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"id":      Schema{"type": "string", "description": "Invoice number"},
+			"created": Schema{"type": "string", "format": "date-time"},
+			"due":     Schema{"type": "string", "format": "date"},
+			"total":   Schema{"type": "number"},
+			"paid":    Schema{"type": []string{"boolean", "null"}},
+			"lines": Schema{"type": "array", "items": Schema{
+				"type": "object",
+				"properties": Schema{
+					"sku": Schema{"type": "string"},
+					"qty": Schema{"type": "integer"},
+				},
+				"required": []string{"sku", "qty"},
+			}},
+			"customer": Schema{"anyOf": []Schema{
+				{"type": "object", "properties": Schema{"name": Schema{"type": "string"}}, "required": []string{"name"}},
+				{"type": "null"},
+			}},
+			"tags":      Schema{"type": "array", "items": Schema{"type": "string"}},
+			"labels":    Schema{"type": "object", "additionalProperties": Schema{"type": "string"}},
+			"matrix":    Schema{"type": "array", "items": Schema{"type": "array", "items": Schema{"type": "integer"}}},
+			"value":     Schema{"anyOf": []Schema{{"type": "string"}, {"type": "integer"}}},
+			"unit-code": Schema{"type": "string"},
+		},
+		"required": []string{"id", "created", "due", "total", "paid", "lines", "customer", "tags"},
+	}
+	var warnings []string
+	opts := DefaultOptions()
+	opts.WarningHandler = func(w Warning) { warnings = append(warnings, w.Path) }
+	expected := []BigQueryField{
+		{Name: "id", Type: "STRING", Mode: "REQUIRED", Description: "Invoice number"},
+		{Name: "created", Type: "TIMESTAMP", Mode: "REQUIRED"},
+		{Name: "due", Type: "DATE", Mode: "REQUIRED"},
+		{Name: "total", Type: "FLOAT", Mode: "REQUIRED"},
+		{Name: "paid", Type: "BOOLEAN", Mode: "NULLABLE"},
+		{Name: "lines", Type: "RECORD", Mode: "REPEATED", Fields: []BigQueryField{
+			{Name: "sku", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "qty", Type: "INTEGER", Mode: "REQUIRED"},
+		}},
+		{Name: "customer", Type: "RECORD", Mode: "NULLABLE", Fields: []BigQueryField{
+			{Name: "name", Type: "STRING", Mode: "REQUIRED"},
+		}},
+		{Name: "tags", Type: "STRING", Mode: "REPEATED"},
+		{Name: "labels", Type: "JSON", Mode: "NULLABLE"},
+		{Name: "matrix", Type: "JSON", Mode: "REPEATED"},
+		{Name: "unit_code", Type: "STRING", Mode: "NULLABLE"},
+		{Name: "value", Type: "JSON", Mode: "NULLABLE"},
+	}
+	if fields := RenderBigQuery(schema, opts); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %+v, got %+v", expected, fields)
+	}
+	if expected := []string{"labels", "matrix", "unit-code", "value"}; !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected warnings at %v, got %v", expected, warnings)
+	}
+}
+
+func TestBigQueryColumnName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "total", expected: "total"},
+		{name: "unit price", expected: "unit_price"},
+		{name: "2fa", expected: "_2fa"},
+		{name: "", expected: "_"},
+	}
+	for _, tt := range tests {
+		if got := bigQueryColumnName(tt.name, tt.name, DefaultOptions()); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"strings"
+)
+
+// RenderChangelog renders schema changes as a markdown changelog, breaking changes first
+func RenderChangelog(title string, changes []SchemaChange) string {
+	var b strings.Builder
+	b.WriteString("# " + title + "\n\n")
+	if len(changes) == 0 {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+	var breaking, other []SchemaChange
+	for _, change := range changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		} else {
+			other = append(other, change)
+		}
+	}
+	writeChangelogSection(&b, "Breaking changes", breaking)
+	writeChangelogSection(&b, "Other changes", other)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeChangelogSection(b *strings.Builder, heading string, changes []SchemaChange) {
+	if len(changes) == 0 {
+		return
+	}
+	b.WriteString("## " + heading + "\n\n")
+	for _, change := range changes {
+		path := "(root)"
+		if change.Path != "" {
+			path = "`" + change.Path + "`"
+		}
+		b.WriteString("- " + path + ": " + change.Message + "\n")
+	}
+	b.WriteString("\n")
+}
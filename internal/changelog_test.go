@@ -0,0 +1,27 @@
+package internal
+
+import "testing"
+
+func TestRenderChangelog(t *testing.T) {
+	changes := []SchemaChange{
+		{Path: "currency", Kind: ChangeAdded, Breaking: true, Message: "added required property (string)"},
+		{Path: "status", Kind: ChangeModified, Keyword: "description", Message: `description changed from "a" to "b"`},
+		{Kind: ChangeModified, Keyword: "nullable", Message: "became nullable"},
+	}
+	expected := "# Order: version 1 to 2\n" +
+		"\n" +
+		"## Breaking changes\n" +
+		"\n" +
+		"- `currency`: added required property (string)\n" +
+		"\n" +
+		"## Other changes\n" +
+		"\n" +
+		"- `status`: description changed from \"a\" to \"b\"\n" +
+		"- (root): became nullable\n"
+	if got := RenderChangelog("Order: version 1 to 2", changes); got != expected {
+		t.Errorf("RenderChangelog() =\n%s\nwant\n%s", got, expected)
+	}
+	if got := RenderChangelog("Order", nil); got != "# Order\n\nNo changes.\n" {
+		t.Errorf("RenderChangelog() without changes = %q", got)
+	}
+}
@@ -0,0 +1,43 @@
+package internal
+
+// Clone returns a deep copy of the schema, sharing nothing with it: subschemas, lists such
+// as required and enum, and values decoded from JSON are all copied
+func (s Schema) Clone() Schema {
+	if s == nil {
+		return nil
+	}
+	return cloneValue(s).(Schema)
+}
+
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Schema:
+		out := make(Schema, len(v))
+		for k, item := range v {
+			out[k] = cloneValue(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = cloneValue(item)
+		}
+		return out
+	case []Schema:
+		out := make([]Schema, len(v))
+		for i, item := range v {
+			out[i] = cloneValue(item).(Schema)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = cloneValue(item)
+		}
+		return out
+	case []string:
+		return append([]string(nil), v...)
+	}
+	// scalars are immutable
+	return value
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaClone(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema Schema
+		mutate func(s Schema)
+	}{
+		{
+			name:   "nested properties",
+			schema: EmployeeSchema,
+			mutate: func(s Schema) {
+				company := s["properties"].(Schema)["companies"].(Schema)["items"].(Schema)
+				company["properties"].(Schema)["address"].(Schema)["description"] = "modified"
+			},
+		},
+		{
+			name:   "required list",
+			schema: EmployeeSchema,
+			mutate: func(s Schema) { s["required"].([]string)[0] = "modified" },
+		},
+		{
+			name:   "anyOf variants",
+			schema: EmployeeSchema,
+			mutate: func(s Schema) { s["properties"].(Schema)["tags"].(Schema)["anyOf"].([]Schema)[1]["type"] = "string" },
+		},
+		{
+			name: "decoded values",
+			schema: Schema{
+				"enum":     []interface{}{"a", map[string]interface{}{"b": []interface{}{"c"}}},
+				"examples": []interface{}{map[string]interface{}{"name": "Ada"}},
+			},
+			mutate: func(s Schema) {
+				s["enum"].([]interface{})[1].(map[string]interface{})["b"].([]interface{})[0] = "modified"
+				s["examples"].([]interface{})[0].(map[string]interface{})["name"] = "modified"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.schema.Clone()
+			clone := tt.schema.Clone()
+			if !reflect.DeepEqual(clone, tt.schema) {
+				t.Fatalf("expected %v, got %v", tt.schema, clone)
+			}
+			tt.mutate(clone)
+			if !reflect.DeepEqual(tt.schema, original) {
+				t.Errorf("modifying the clone changed the schema: %v", tt.schema)
+			}
+		})
+	}
+
+	if Schema(nil).Clone() != nil {
+		t.Errorf("expected nil clone of a nil schema")
+	}
+}
+
+func TestSchemaCloneInterned(t *testing.T) {
+	interned := Intern(EmployeeSchema.Clone())
+	clone := interned.Clone()
+	clone["properties"].(Schema)["name"].(Schema)["description"] = "modified"
+	tags := clone["properties"].(Schema)["tags"].(Schema)["anyOf"].([]Schema)[0]["items"].(Schema)
+	if _, ok := tags["description"]; ok {
+		t.Errorf("expected the subschemas shared by interning to be copied separately")
+	}
+	if _, ok := interned["properties"].(Schema)["name"].(Schema)["description"]; ok {
+		t.Errorf("expected the interned schema to be unchanged")
+	}
+}
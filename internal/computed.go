@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ComputedProperty describes a schema property backed by a method instead of a struct field
+type ComputedProperty struct {
+	Name   string
+	Method string
+	Type   reflect.Type
+}
+
+// computed property registry keyed by the struct type
+var (
+	computedMu       sync.RWMutex
+	computedRegistry = make(map[reflect.Type][]ComputedProperty)
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// RegisterComputed exposes the result of a method of t as the property name.
+// The method must take no arguments and return a single value, optionally followed by an error.
+// Registering the same property name again replaces it.
+func RegisterComputed(t reflect.Type, name, method string) error {
+	t = deref(t)
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: computed properties require a struct, got %s", ErrUnsupportedType, t)
+	}
+	m, ok := reflect.PointerTo(t).MethodByName(method)
+	if !ok {
+		return fmt.Errorf("method %s not found on %s", method, t)
+	}
+	// the receiver is the first input of a method obtained from a type
+	mt := m.Type
+	if mt.NumIn() != 1 || mt.NumOut() == 0 || mt.NumOut() > 2 ||
+		(mt.NumOut() == 2 && mt.Out(1) != errorType) {
+		return fmt.Errorf("method %s.%s must take no arguments and return a value and an optional error", t, method)
+	}
+	prop := ComputedProperty{Name: name, Method: method, Type: mt.Out(0)}
+	computedMu.Lock()
+	defer computedMu.Unlock()
+	props := computedRegistry[t]
+	for i, existing := range props {
+		if existing.Name == name {
+			props[i] = prop
+			return nil
+		}
+	}
+	computedRegistry[t] = append(props, prop)
+	return nil
+}
+
+// computedProperties returns the computed properties registered for a struct in registration order
+func computedProperties(t reflect.Type) []ComputedProperty {
+	computedMu.RLock()
+	defer computedMu.RUnlock()
+	return append([]ComputedProperty(nil), computedRegistry[t]...)
+}
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterComputed(t *testing.T) {
+	profileType := reflect.TypeOf(Profile{})
+	tests := []struct {
+		name        string
+		input       reflect.Type
+		property    string
+		method      string
+		shouldError bool
+	}{
+		{name: "value receiver", input: profileType, property: "displayName", method: "DisplayName"},
+		{name: "pointer receiver with error", input: profileType, property: "initials", method: "Initials"},
+		{name: "missing method", input: profileType, property: "age", method: "Age", shouldError: true},
+		{name: "method with arguments", input: profileType, property: "greeting", method: "Greet", shouldError: true},
+		{name: "non-struct type", input: reflect.TypeOf(""), property: "len", method: "Len", shouldError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterComputed(tt.input, tt.property, tt.method)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("computed properties are appended to the schema", func(t *testing.T) {
+		result, err := runJsonTypeOf(profileType)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, ProfileSchema) {
+			t.Errorf("expected %+v, got %+v", ProfileSchema, result)
+		}
+	})
+}
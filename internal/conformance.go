@@ -0,0 +1,233 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// metaSchemaJSON is the subset of the JSON Schema 2020-12 meta-schema checked by standard
+// validators when they compile a schema: the type of the value of every keyword of the
+// core, applicator, validation, meta-data, format and content vocabularies
+const metaSchemaJSON = `{
+	"$ref": "#/$defs/schema",
+	"$defs": {
+		"schema": {
+			"type": ["object", "boolean"],
+			"properties": {
+				"$id": {"type": "string"},
+				"$schema": {"type": "string"},
+				"$ref": {"type": "string"},
+				"$anchor": {"type": "string", "pattern": "^[A-Za-z_][-A-Za-z0-9._]*$"},
+				"$dynamicRef": {"type": "string"},
+				"$dynamicAnchor": {"type": "string", "pattern": "^[A-Za-z_][-A-Za-z0-9._]*$"},
+				"$vocabulary": {"type": "object", "additionalProperties": {"type": "boolean"}},
+				"$comment": {"type": "string"},
+				"$defs": {"$ref": "#/$defs/schemaMap"},
+				"prefixItems": {"$ref": "#/$defs/schemaArray"},
+				"items": {"$ref": "#/$defs/schema"},
+				"contains": {"$ref": "#/$defs/schema"},
+				"additionalProperties": {"$ref": "#/$defs/schema"},
+				"properties": {"$ref": "#/$defs/schemaMap"},
+				"patternProperties": {"$ref": "#/$defs/schemaMap"},
+				"dependentSchemas": {"$ref": "#/$defs/schemaMap"},
+				"propertyNames": {"$ref": "#/$defs/schema"},
+				"if": {"$ref": "#/$defs/schema"},
+				"then": {"$ref": "#/$defs/schema"},
+				"else": {"$ref": "#/$defs/schema"},
+				"allOf": {"$ref": "#/$defs/schemaArray"},
+				"anyOf": {"$ref": "#/$defs/schemaArray"},
+				"oneOf": {"$ref": "#/$defs/schemaArray"},
+				"not": {"$ref": "#/$defs/schema"},
+				"unevaluatedItems": {"$ref": "#/$defs/schema"},
+				"unevaluatedProperties": {"$ref": "#/$defs/schema"},
+				"type": {
+					"anyOf": [
+						{"$ref": "#/$defs/simpleType"},
+						{"type": "array", "items": {"$ref": "#/$defs/simpleType"}, "minItems": 1, "uniqueItems": true}
+					]
+				},
+				"enum": {"type": "array"},
+				"multipleOf": {"type": "number", "exclusiveMinimum": 0},
+				"maximum": {"type": "number"},
+				"exclusiveMaximum": {"type": "number"},
+				"minimum": {"type": "number"},
+				"exclusiveMinimum": {"type": "number"},
+				"maxLength": {"$ref": "#/$defs/nonNegativeInteger"},
+				"minLength": {"$ref": "#/$defs/nonNegativeInteger"},
+				"pattern": {"type": "string"},
+				"maxItems": {"$ref": "#/$defs/nonNegativeInteger"},
+				"minItems": {"$ref": "#/$defs/nonNegativeInteger"},
+				"uniqueItems": {"type": "boolean"},
+				"maxContains": {"$ref": "#/$defs/nonNegativeInteger"},
+				"minContains": {"$ref": "#/$defs/nonNegativeInteger"},
+				"maxProperties": {"$ref": "#/$defs/nonNegativeInteger"},
+				"minProperties": {"$ref": "#/$defs/nonNegativeInteger"},
+				"required": {"$ref": "#/$defs/stringArray"},
+				"dependentRequired": {"type": "object", "additionalProperties": {"$ref": "#/$defs/stringArray"}},
+				"title": {"type": "string"},
+				"description": {"type": "string"},
+				"default": {},
+				"deprecated": {"type": "boolean"},
+				"readOnly": {"type": "boolean"},
+				"writeOnly": {"type": "boolean"},
+				"examples": {"type": "array"},
+				"format": {"type": "string"},
+				"contentEncoding": {"type": "string"},
+				"contentMediaType": {"type": "string"},
+				"contentSchema": {"$ref": "#/$defs/schema"}
+			}
+		},
+		"schemaArray": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/schema"}},
+		"schemaMap": {"type": "object", "additionalProperties": {"$ref": "#/$defs/schema"}},
+		"simpleType": {"enum": ["array", "boolean", "integer", "null", "number", "object", "string"]},
+		"nonNegativeInteger": {"type": "integer", "minimum": 0},
+		"stringArray": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}
+	}
+}`
+
+var (
+	metaSchemaOnce sync.Once
+	metaSchema     Schema
+	// metaKeywords are the keywords of the JSON Schema 2020-12 vocabularies
+	metaKeywords map[string]bool
+)
+
+// loadMetaSchema parses the bundled meta-schema on first use
+func loadMetaSchema() {
+	metaSchemaOnce.Do(func() {
+		schema, err := ParseSchema([]byte(metaSchemaJSON))
+		if err != nil {
+			panic(fmt.Sprintf("gptschema: invalid bundled meta-schema: %v", err))
+		}
+		metaSchema = schema
+		keywords := schema["$defs"].(Schema)["schema"].(Schema)["properties"].(Schema)
+		metaKeywords = make(map[string]bool, len(keywords)+1)
+		for keyword := range keywords {
+			metaKeywords[keyword] = true
+		}
+		metaKeywords["const"] = true
+	})
+}
+
+// goOnlyPattern matches the regular expression syntax of Go that ECMA-262, the dialect
+// of patterns in JSON Schema, does not accept: named groups, inline flags, \A, \z, \Q
+// and POSIX classes
+var goOnlyPattern = regexp.MustCompile(`\(\?P<|\(\?[imsU-]+[:)]|\\[AzQ]|\[\[:`)
+
+// CheckConformance runs a schema through the checks standard JSON Schema 2020-12 validators
+// perform: the bundled meta-schema, keywords outside the vocabularies, patterns outside
+// the syntax shared by Go and ECMA-262, and examples and defaults not matching their schema.
+// An error is only returned for schemas that cannot be evaluated, such as unresolved $refs.
+func CheckConformance(schema Schema, opts *Options) ([]LintIssue, error) {
+	loadMetaSchema()
+	document, err := jsonValue(schema)
+	if err != nil {
+		return nil, err
+	}
+	var issues []LintIssue
+	mismatches, err := ValidateValue(metaSchema, document, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range mismatches {
+		message := m.Message
+		if m.Value != "" {
+			message += " (" + m.Value + ")"
+		}
+		issues = append(issues, LintIssue{Path: m.Path, Rule: "meta-schema", Message: message})
+	}
+
+	root := withSchemaTypes(schema)
+	v, base := newValidator(root, opts)
+	var walkErr error
+	transformSchema(root, "", func(s Schema, path string) Schema {
+		for _, keyword := range sortedKeys(s) {
+			if !metaKeywords[keyword] {
+				issues = append(issues, LintIssue{Path: path, Rule: "unknown-keyword",
+					Message: fmt.Sprintf("keyword %q is not part of JSON Schema 2020-12", keyword)})
+			}
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			issues = append(issues, checkPattern(pattern, path)...)
+		}
+		patterns, _ := s["patternProperties"].(Schema)
+		for _, pattern := range sortedKeys(patterns) {
+			issues = append(issues, checkPattern(pattern, path)...)
+		}
+		examples, _ := s["examples"].([]interface{})
+		for i, example := range examples {
+			message, err := checkInstance(v, s, example, path, base)
+			if err != nil {
+				walkErr = err
+			} else if message != "" {
+				issues = append(issues, LintIssue{Path: path, Rule: "invalid-example",
+					Message: fmt.Sprintf("example %d does not match the schema: %s", i, message)})
+			}
+		}
+		if value, ok := s["default"]; ok {
+			message, err := checkInstance(v, s, value, path, base)
+			if err != nil {
+				walkErr = err
+			} else if message != "" {
+				issues = append(issues, LintIssue{Path: path, Rule: "invalid-default",
+					Message: "default does not match the schema: " + message})
+			}
+		}
+		return s
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues, nil
+}
+
+// checkPattern reports a pattern that Go or ECMA-262 validators cannot compile
+func checkPattern(pattern, path string) []LintIssue {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return []LintIssue{{Path: path, Rule: "pattern-syntax",
+			Message: fmt.Sprintf("pattern %q does not compile with RE2: %v", pattern, err)}}
+	}
+	if goOnlyPattern.MatchString(pattern) {
+		return []LintIssue{{Path: path, Rule: "pattern-syntax",
+			Message: fmt.Sprintf("pattern %q uses syntax of Go regular expressions that ECMA-262 does not accept", pattern)}}
+	}
+	return nil
+}
+
+// checkInstance validates a value embedded in a schema against it and joins the mismatches
+func checkInstance(v *validator, s Schema, value interface{}, path, base string) (string, error) {
+	instance, err := jsonValue(value)
+	if err != nil {
+		return "", err
+	}
+	mismatches := v.check(s, instance, path, base)
+	if v.err != nil {
+		return "", v.err
+	}
+	messages := make([]string, len(mismatches))
+	for i, m := range mismatches {
+		messages[i] = m.Error()
+	}
+	return strings.Join(messages, "; "), nil
+}
+
+// jsonValue converts a Go value into its JSON form decoded with UseNumber, the input of the validator
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema to JSON: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode schema JSON: %w", err)
+	}
+	return value, nil
+}
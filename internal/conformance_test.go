@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckConformance(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   Schema
+		expected []LintIssue
+	}{
+		{name: "employee", schema: EmployeeSchema},
+		{name: "address", schema: AddressSchema},
+		{name: "shipment", schema: ShipmentSchema},
+		{name: "maps", schema: StructWithMapsSchema},
+		{
+			name: "meta-schema",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"age":  Schema{"type": "integer", "minimum": "0"},
+					"kind": Schema{"type": "intger"},
+				},
+				"required": []string{"age", "age"},
+			},
+			expected: []LintIssue{
+				{Path: "properties.age.minimum", Rule: "meta-schema", Message: `expected number, got string ("0")`},
+				{Path: "properties.kind.type", Rule: "meta-schema",
+					Message: `expected one of "array", "boolean", "integer", "null", "number", "object", "string" ("intger")`},
+				{Path: "required[1]", Rule: "meta-schema", Message: `expected unique items ("age")`},
+			},
+		},
+		{
+			name: "unknown keywords",
+			schema: Schema{
+				"type":       "object",
+				"properties": Schema{"note": Schema{"type": "string", "nullable": true}},
+			},
+			expected: []LintIssue{
+				{Path: "note", Rule: "unknown-keyword", Message: `keyword "nullable" is not part of JSON Schema 2020-12`},
+			},
+		},
+		{
+			name: "patterns",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"code": Schema{"type": "string", "pattern": `^[A-Z]{3}\z`},
+					"ref":  Schema{"type": "string", "pattern": `^(?=a)`},
+					"sku":  Schema{"type": "string", "pattern": `^[A-Z]{3}-\d+$`},
+				},
+			},
+			expected: []LintIssue{
+				{Path: "code", Rule: "pattern-syntax",
+					Message: `pattern "^[A-Z]{3}\\z" uses syntax of Go regular expressions that ECMA-262 does not accept`},
+				{Path: "ref", Rule: "pattern-syntax",
+					Message: "pattern \"^(?=a)\" does not compile with RE2: error parsing regexp: invalid or unsupported Perl syntax: `(?=`"},
+			},
+		},
+		{
+			name: "examples and defaults",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"qty":    Schema{"type": "integer", "minimum": 1, "examples": []interface{}{2, "3"}},
+					"status": Schema{"type": "string", "enum": []interface{}{"open", "closed"}, "default": "draft"},
+				},
+			},
+			expected: []LintIssue{
+				{Path: "qty", Rule: "invalid-example", Message: `example 1 does not match the schema: qty: expected integer, got string ("3")`},
+				{Path: "status", Rule: "invalid-default",
+					Message: `default does not match the schema: status: expected one of "open", "closed" ("draft")`},
+			},
+		},
+		{
+			name: "examples resolve references",
+			schema: Schema{
+				"$defs": Schema{"Code": Schema{"type": "string", "minLength": 2}},
+				"type":  "object",
+				"properties": Schema{
+					"code": Schema{"$ref": "#/$defs/Code", "examples": []interface{}{"AB"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := CheckConformance(tt.schema, DefaultOptions())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(issues, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, issues)
+			}
+		})
+	}
+}
+
+func TestCheckConformance_UnresolvedRef(t *testing.T) {
+	schema := Schema{"$ref": "#/$defs/Missing", "examples": []interface{}{1}}
+	if _, err := CheckConformance(schema, DefaultOptions()); err == nil {
+		t.Errorf("expected an error for an unresolved $ref")
+	}
+}
@@ -1,14 +1,20 @@
 package internal
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 )
 
 var (
 	ErrUnsupportedType = errors.New("unsupported type for JSON schema")
 	ErrCircularRef     = errors.New("circular reference detected")
+	ErrInvalidTag      = errors.New("invalid struct tag")
+	ErrInvalidOption   = errors.New("invalid option")
+	ErrSchemaTooLarge  = errors.New("schema exceeds the size budget")
 )
 
 // Schema represents a JSON schema
@@ -18,6 +24,114 @@ type Schema map[string]interface{}
 type Options struct {
 	AllowAdditionalProperty bool
 	MaxDepth                int
+	// Strict keeps the output compatible with OpenAI's strict mode.
+	// When disabled, map types are allowed and emitted as dynamic objects.
+	Strict bool
+	// ExpandEnumMaps turns maps keyed by a registered enum into objects
+	// with one property per enum value, which is strict mode compatible.
+	ExpandEnumMaps bool
+	// DetectEnums enables enum autodetection for types generated by enumer or stringer.
+	DetectEnums bool
+	// Int64AsString maps int64 and uint64 to strings with a numeric pattern.
+	Int64AsString bool
+	// NumericFormats annotates numbers with int32/int64/float/double formats (non-strict mode only).
+	NumericFormats bool
+	// RecursiveRefs describes recursive types with $refs to the root or to $defs,
+	// it takes precedence over RecursionUnroll.
+	RecursiveRefs bool
+	// Defs moves every struct type but the root to $defs, recursive types included.
+	Defs bool
+	// DefNaming selects how the types moved to $defs are named.
+	DefNaming DefNaming
+	// RecursionUnroll expands self-referencing types this many levels deep
+	// instead of failing, the innermost occurrence becomes a null leaf.
+	RecursionUnroll int
+	// NamingConvention converts the names of fields without a json tag name.
+	NamingConvention NamingConvention
+	// EmbeddingPolicy resolves conflicts between outer and embedded fields.
+	EmbeddingPolicy EmbeddingPolicy
+	// JSONv2 applies the omitempty, omitzero, ",string" and name matching rules of encoding/json/v2.
+	JSONv2 bool
+	// NullableStyle selects how optional fields are made nullable.
+	NullableStyle NullableStyle
+	// RequiredOrder selects the order of the property names in required arrays.
+	RequiredOrder RequiredOrder
+	// MaxSchemaBytes fails generation when the marshaled schema is larger, 0 disables the check.
+	MaxSchemaBytes int
+	// Humanizer derives the description of properties without one from their name, nil disables it.
+	Humanizer func(name string) string
+	// MaxDescriptionLength truncates longer descriptions, 0 disables truncation.
+	MaxDescriptionLength int
+	// LimitPolicy selects how generation handles exceeded size limits.
+	LimitPolicy LimitPolicy
+	// Limits overrides the limits of the selected provider, nil uses the provider limits.
+	Limits *Limits
+	// WarningHandler receives the adjustments made to the schema, such as truncations.
+	WarningHandler func(Warning)
+	// TimeFormat selects how time.Time fields are described.
+	TimeFormat TimeFormat
+	// LenientDecode converts compatible scalars to the type of their field when decoding.
+	LenientDecode bool
+	// RefLoader loads the documents of external $refs when inlining references and of schemaRef tags.
+	RefLoader func(uri string) (Schema, error)
+	// SchemaIDBase stamps the root schema with an $id derived from this base URI and the Go type.
+	SchemaIDBase string
+	// AllowedKeywords fails generation when the schema uses other keywords, nil disables the check.
+	AllowedKeywords map[string]bool
+	// Provider selects the profile whose rejected keywords are stripped from transmitted schemas.
+	Provider Provider
+	// Intern shares structurally identical subschemas in the generated schema.
+	Intern bool
+	// UseGenerated returns the schemas registered by generated code instead of reflecting types.
+	UseGenerated bool
+	// Discriminator names the property identifying the variant of union schemas, empty disables it.
+	Discriminator string
+	// SensitiveDescription is appended to the description of fields tagged sensitive:"true".
+	SensitiveDescription string
+	// Redactor rewrites the strings of sensitive fields when decoding, nil disables redaction.
+	Redactor Redactor
+	// ExplodeArray is the path of the array whose items get a row each in flattened tables.
+	ExplodeArray string
+	// ArraySeparator joins the values of arrays and maps in flattened tables.
+	ArraySeparator string
+	// DescriptionsFile names the sidecar file of FileDescriptions, recorded in provenance comments.
+	DescriptionsFile string
+	// FileDescriptions replaces the descriptions of the fields at these paths after generation, before localization.
+	FileDescriptions map[string]string
+	// Locale names the locale of LocalizedDescriptions, recorded in provenance comments.
+	Locale string
+	// LocalizedDescriptions replaces the descriptions of the fields at these paths after generation.
+	LocalizedDescriptions map[string]string
+	// Report receives the nesting of the generated schema, nil skips the measurement.
+	Report *GenerationReport
+	// Arena allocates the maps of the generated schema, nil allocates them from the heap.
+	Arena *Arena
+	// Subtrees holds the options scoped to the subtree at each path, applied over a copy
+	// of the options when the conversion reaches the path.
+	Subtrees map[string][]func(*Options)
+	// Provenance stamps the root schema with a $comment recording the Go type, module versions and options.
+	Provenance bool
+
+	// configuration errors recorded while applying options
+	errs []error
+	// path of the property being converted, matched against Subtrees
+	path string
+	// arrayDepth is the number of arrays enclosing the type being converted, arrays are
+	// bounded by MaxDepth separately from the nesting of objects
+	arrayDepth int
+	// definitions holds the struct types described with $refs during a conversion with
+	// RecursiveRefs or Defs
+	definitions *definitions
+}
+
+// AddError records an invalid option value, reported by Validate
+func (o *Options) AddError(format string, args ...interface{}) {
+	o.errs = append(o.errs, fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidOption}, args...)...))
+}
+
+// Validate reports the configuration errors recorded while applying options
+func (o *Options) Validate() error {
+	return errors.Join(o.errs...)
 }
 
 // DefaultOptions returns default generation options
@@ -28,6 +142,17 @@ func DefaultOptions() *Options {
 	return &Options{
 		AllowAdditionalProperty: false,
 		MaxDepth:                50,
+		Strict:                  true,
+		DetectEnums:             true,
+		TimeFormat:              TimeRFC3339,
+		EmbeddingPolicy:         DepthBased,
+		NullableStyle:           NullableMixed,
+		RequiredOrder:           DeclarationOrder,
+		DefNaming:               DefNameType,
+		LimitPolicy:             LimitsIgnore,
+		UseGenerated:            true,
+		SensitiveDescription:    DefaultSensitiveDescription,
+		ArraySeparator:          DefaultArraySeparator,
 	}
 }
 
@@ -41,6 +166,21 @@ func deref(t reflect.Type) reflect.Type {
 	return t
 }
 
+// isInlined reports whether the properties of a field are merged into its parent:
+// untagged embedded structs like encoding/json, and fields with the encoding/json/v2
+// inline option, which is only supported on structs
+func isInlined(field reflect.StructField, jsonTag string) (bool, error) {
+	isStruct := deref(field.Type).Kind() == reflect.Struct
+	if hasTagOption(jsonTag, "inline") {
+		if !isStruct {
+			return false, fmt.Errorf("%w: inline option on non-struct field %q", ErrInvalidTag, field.Name)
+		}
+		return true, nil
+	}
+	tagName, _, _ := strings.Cut(jsonTag, ",")
+	return field.Anonymous && isStruct && tagName == "", nil
+}
+
 // parse json tag
 func parseJSONTag(fieldName, tag string) (name string, optional bool) {
 	if tag == "" {
@@ -62,11 +202,88 @@ func parseJSONTag(fieldName, tag string) (name string, optional bool) {
 	return name, optional
 }
 
+// check whether a json tag carries an option such as "string"
+func hasTagOption(tag, option string) bool {
+	parts := strings.Split(tag, ",")
+	for _, part := range parts[1:] {
+		if part == option {
+			return true
+		}
+	}
+	return false
+}
+
+// parse the required tag, set reports whether the tag is present
+func parseRequiredTag(fieldName string, field reflect.StructField) (required, set bool, err error) {
+	tag, set := field.Tag.Lookup("required")
+	if !set {
+		return false, false, nil
+	}
+	switch tag {
+	case "true":
+		return true, true, nil
+	case "false":
+		return false, true, nil
+	default:
+		return false, false, fmt.Errorf("%w: required tag on field %q must be true or false, got %q", ErrInvalidTag, fieldName, tag)
+	}
+}
+
+// patterns of scalars encoded inside JSON strings
+const (
+	intPattern    = "^-?[0-9]+$"
+	uintPattern   = "^[0-9]+$"
+	numberPattern = "^-?[0-9]+(\\.[0-9]+)?([eE][+-]?[0-9]+)?$"
+	boolPattern   = "^(true|false)$"
+)
+
+// quotedScalarSchema describes a scalar encoded as a JSON string, which is how
+// encoding/json marshals fields with the ",string" option
+func quotedScalarSchema(t reflect.Type) (Schema, bool) {
+	switch deref(t).Kind() {
+	case reflect.String:
+		return Schema{"type": "string"}, true
+	case reflect.Bool:
+		return Schema{"type": "string", "pattern": boolPattern}, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Schema{"type": "string", "pattern": intPattern}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "string", "pattern": uintPattern}, true
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "string", "pattern": numberPattern}, true
+	default:
+		return nil, false
+	}
+}
+
+// numericFormat returns the OpenAPI format matching the size of a numeric kind
+func numericFormat(k reflect.Kind) string {
+	switch k {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return "int32"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	default:
+		return "int64"
+	}
+}
+
+// numeric types are plain JSON types unless format annotations are requested,
+// they are never emitted in strict mode since OpenAI rejects numeric formats
+func numericSchema(t reflect.Type, jsonType string, opts *Options) interface{} {
+	if !opts.NumericFormats || opts.Strict {
+		return jsonType
+	}
+	return Schema{"type": jsonType, "format": numericFormat(t.Kind())}
+}
+
 // ========== Parsing functions ==========
 // convert array into json type
 func parseArrayItemType(
 	t reflect.Type,
-	visited map[reflect.Type]bool,
+	visited map[reflect.Type]int,
 	depth int,
 	opts *Options) (interface{}, error) {
 	schema, err := JsonTypeOf(t.Elem(), visited, depth, opts)
@@ -83,92 +300,640 @@ func parseArrayItemType(
 	}
 }
 
+// NullableStyle selects the encoding of optional fields
+type NullableStyle string
+
+const (
+	// NullableMixed uses a type array for primitives and anyOf for other schemas
+	NullableMixed NullableStyle = "mixed"
+	// NullableTypeArray adds null to the type of every schema with a single type
+	NullableTypeArray NullableStyle = "type_array"
+	// NullableAnyOf wraps every optional schema in anyOf with null
+	NullableAnyOf NullableStyle = "any_of"
+)
+
+// Valid reports whether s is one of the supported nullable styles
+func (s NullableStyle) Valid() bool {
+	switch s {
+	case NullableMixed, NullableTypeArray, NullableAnyOf:
+		return true
+	}
+	return false
+}
+
+// wrap a converted type into a property schema
+func propertySchema(schema interface{}, optional bool, style NullableStyle) interface{} {
+	switch v := schema.(type) {
+	case string:
+		if !optional {
+			return Schema{"type": v}
+		}
+		if style == NullableAnyOf {
+			return Schema{"anyOf": []Schema{{"type": v}, {"type": "null"}}}
+		}
+		// Although all fields must be required,
+		// it is possible to emulate an optional parameter by using a union type with null.
+		return Schema{"type": []string{v, "null"}}
+	case Schema:
+		// null leaves of unrolled recursion are already nullable
+		if !optional || v["type"] == "null" {
+			return v
+		}
+		if style == NullableTypeArray {
+			if nullable, ok := nullableTypeArray(v); ok {
+				return nullable
+			}
+		}
+		return Schema{
+			"anyOf": []Schema{ // OpenAI supports anyOf key
+				v,
+				{"type": "null"},
+			},
+		}
+	default:
+		return v
+	}
+}
+
+// nullableTypeArray adds null to the type of a schema with a single type, enums
+// also list null as an allowed value. The schema is copied since converted schemas may be shared
+func nullableTypeArray(s Schema) (Schema, bool) {
+	jsonType, ok := s["type"].(string)
+	if !ok {
+		return nil, false
+	}
+	nullable := make(Schema, len(s))
+	for k, v := range s {
+		nullable[k] = v
+	}
+	nullable["type"] = []string{jsonType, "null"}
+	if values, ok := s["enum"].([]interface{}); ok {
+		nullable["enum"] = append(values[:len(values):len(values)], nil)
+	}
+	return nullable, true
+}
+
+// attach a description to a property schema, the schema is copied
+// since converted schemas may be shared
+func withDescription(schema interface{}, description string) interface{} {
+	s, ok := schema.(Schema)
+	if !ok {
+		return schema
+	}
+	described := make(Schema, len(s)+1)
+	for k, v := range s {
+		described[k] = v
+	}
+	described["description"] = description
+	return described
+}
+
+// hasDescription reports whether a property schema is already described,
+// e.g. by its type like Unix timestamps
+func hasDescription(schema interface{}) bool {
+	s, ok := schema.(Schema)
+	if !ok {
+		return false
+	}
+	_, ok = s["description"]
+	return ok
+}
+
+// convert map into json, only string and integer keys are supported
+// which mirrors the keys encoding/json can marshal without a TextMarshaler
+func parseMapValueType(
+	t reflect.Type,
+	visited map[reflect.Type]int,
+	depth int,
+	opts *Options) (interface{}, error) {
+	switch t.Key().Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil, ErrUnsupportedType
+	}
+	values, err := parseArrayItemType(t, visited, depth, opts)
+	if err != nil {
+		return nil, errorAt(err, "{}")
+	}
+	return values, nil
+}
+
+// apply the keys tag to a map schema, the value schema is moved
+// from additionalProperties into patternProperties under the given pattern
+func applyKeysTag(t reflect.Type, fieldName, pattern string, schema interface{}) (Schema, error) {
+	s, ok := schema.(Schema)
+	if !ok || deref(t).Kind() != reflect.Map || s["properties"] != nil {
+		return nil, fmt.Errorf("%w: keys tag on non-map field %q", ErrInvalidTag, fieldName)
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, fmt.Errorf("%w: keys tag on field %q: %v", ErrInvalidTag, fieldName, err)
+	}
+	return Schema{
+		"type":                 "object",
+		"patternProperties":    Schema{pattern: s["additionalProperties"]},
+		"additionalProperties": false,
+	}, nil
+}
+
+// apply the items tag to an array schema, items:"nullable" lets the pointer
+// elements of the array be null
+func applyItemsTag(t reflect.Type, fieldName, value string, schema interface{}, style NullableStyle) (Schema, error) {
+	if value != "nullable" {
+		return nil, fmt.Errorf("%w: items tag on field %q must be \"nullable\", got %q", ErrInvalidTag, fieldName, value)
+	}
+	t = deref(t)
+	s, ok := schema.(Schema)
+	if !ok || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) || t.Elem().Kind() != reflect.Pointer {
+		return nil, fmt.Errorf("%w: items tag on field %q requires an array of pointers", ErrInvalidTag, fieldName)
+	}
+	items, _ := s["items"].(Schema)
+	var item interface{} = items
+	// plain primitives become a type union like optional fields
+	if jsonType, ok := items["type"].(string); ok && len(items) == 1 {
+		item = jsonType
+	}
+	nullable := make(Schema, len(s))
+	for k, v := range s {
+		nullable[k] = v
+	}
+	nullable["items"] = propertySchema(item, true, style)
+	return nullable, nil
+}
+
+// apply the contains tag to an array schema, contains:"admin" requires at least one item
+// equal to the value, decoded as JSON for non-string items. Strict mode rejects the keyword,
+// the tag is then ignored with a warning.
+func applyContainsTag(t reflect.Type, fieldName, value string, schema interface{}, opts *Options) (interface{}, error) {
+	t = deref(t)
+	s, ok := schema.(Schema)
+	if !ok || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("%w: contains tag on non-array field %q", ErrInvalidTag, fieldName)
+	}
+	elem := deref(t.Elem())
+	raw := []byte(value)
+	switch elem.Kind() {
+	case reflect.String:
+		raw, _ = json.Marshal(value)
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+	default:
+		return nil, fmt.Errorf("%w: contains tag on field %q requires an array of scalars", ErrInvalidTag, fieldName)
+	}
+	item := reflect.New(elem)
+	if err := json.Unmarshal(raw, item.Interface()); err != nil {
+		return nil, fmt.Errorf("%w: contains tag on field %q: %q is not a valid %s", ErrInvalidTag, fieldName, value, elem)
+	}
+	if opts.Strict {
+		opts.Warn(fieldName, "contains is not supported in strict mode, the tag is ignored")
+		return schema, nil
+	}
+	contains := make(Schema, len(s)+1)
+	for k, v := range s {
+		contains[k] = v
+	}
+	contains["contains"] = Schema{"const": item.Elem().Interface()}
+	return contains, nil
+}
+
+// rawMessageType is json.RawMessage, which holds any JSON value
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// scalarTypes are the JSON types accepted by the types tag
+var scalarTypes = []string{"string", "integer", "number", "boolean", "null"}
+
+// parseTypesTag reads the types of a types:"string|integer" tag, allowed on interface{}
+// and json.RawMessage fields whose value may take several scalar forms
+func parseTypesTag(t reflect.Type, fieldName, tag string) ([]string, error) {
+	t = deref(t)
+	if t.Kind() != reflect.Interface && t != rawMessageType {
+		return nil, fmt.Errorf("%w: types tag on field %q requires an interface{} or json.RawMessage field", ErrInvalidTag, fieldName)
+	}
+	var types []string
+	for _, name := range strings.Split(tag, "|") {
+		name = strings.TrimSpace(name)
+		if !containsString(scalarTypes, name) {
+			return nil, fmt.Errorf("%w: types tag on field %q: unknown type %q, expected one of %s", ErrInvalidTag, fieldName, name, strings.Join(scalarTypes, ", "))
+		}
+		if containsString(types, name) {
+			return nil, fmt.Errorf("%w: types tag on field %q: duplicate type %q", ErrInvalidTag, fieldName, name)
+		}
+		types = append(types, name)
+	}
+	return types, nil
+}
+
+// typesSchema describes a value of one of several scalar types with a type array, or
+// anyOf for the NullableAnyOf style. Optional values also accept null.
+func typesSchema(types []string, optional bool, style NullableStyle) Schema {
+	if optional && !containsString(types, "null") {
+		types = append(types[:len(types):len(types)], "null")
+	}
+	if len(types) == 1 {
+		return Schema{"type": types[0]}
+	}
+	if style != NullableAnyOf {
+		return Schema{"type": types}
+	}
+	variants := make([]Schema, len(types))
+	for i, t := range types {
+		variants[i] = Schema{"type": t}
+	}
+	return Schema{"anyOf": variants}
+}
+
+// parseDependentRequiredTag reads the property names of a dependentRequired:"street,city" tag
+func parseDependentRequiredTag(fieldName, tag string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(tag, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == fieldName {
+			return nil, fmt.Errorf("%w: dependentRequired tag on field %q must list other properties, got %q", ErrInvalidTag, fieldName, tag)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// propertyOwner records the field a property name was taken from
+type propertyOwner struct {
+	field     string
+	converted bool
+	// jsonName is the name encoding/json gives the field, before the naming convention
+	jsonName string
+	// embedding level of the struct declaring the field
+	level int
+}
+
+// propertySet accumulates the properties of a struct and its embedded structs
+type propertySet struct {
+	props    Schema
+	required []string
+	owners   map[string]propertyOwner
+	// byJSONName maps the encoding/json names of the described fields to their property
+	byJSONName map[string]string
+	// positions holds the index path of the field of each property
+	positions map[string][]int
+	// dependents holds the properties required when a property is present
+	dependents map[string][]string
+	// index path of the struct being walked
+	index []int
+	// embedding level of the struct being walked
+	level  int
+	policy EmbeddingPolicy
+}
+
+// add describes a property taken from the field at position i of the struct being walked
+func (s *propertySet) add(name string, prop interface{}, i int, required bool) {
+	s.props[name] = prop
+	s.positions[name] = append(s.index[:len(s.index):len(s.index)], i)
+	if required {
+		s.required = append(s.required, name)
+	}
+}
+
+// claim reserves a property name for a field and reports whether the field should be
+// described. Conflicts between fields of different embedding levels are resolved by the
+// embedding policy, two fields of the same level mapping to the same name are an error
+// rather than one silently replacing the other. Like encoding/json, fields hide each other
+// by their encoding/json name first, so a field renamed by the naming convention is still
+// hidden by a shallower field tagged with its Go name.
+func (s *propertySet) claim(name string, owner propertyOwner) (bool, error) {
+	owner.level = s.level
+	if owner.jsonName == "" {
+		owner.jsonName = name
+	}
+	if other, ok := s.byJSONName[owner.jsonName]; ok && other != name {
+		described, err := s.resolve(other, s.owners[other], owner)
+		if !described || err != nil {
+			return false, err
+		}
+	}
+	if existing, ok := s.owners[name]; ok {
+		described, err := s.resolve(name, existing, owner)
+		if !described || err != nil {
+			return false, err
+		}
+	}
+	s.owners[name] = owner
+	s.byJSONName[owner.jsonName] = name
+	return true, nil
+}
+
+// resolve settles the conflict of a field with the existing owner of property name,
+// reporting whether the field is described; the existing property is removed if not
+func (s *propertySet) resolve(name string, existing, owner propertyOwner) (bool, error) {
+	outer, inner := existing, owner
+	if inner.level < outer.level {
+		outer, inner = inner, outer
+	}
+	resolved := outer.level < inner.level
+	switch s.policy {
+	case OuterWins:
+		resolved = resolved && outer.level == 0
+	case ErrorOnConflict:
+		resolved = false
+	}
+	if !resolved {
+		cause := ""
+		if existing.converted || owner.converted {
+			cause = " under the naming convention"
+		}
+		return false, fmt.Errorf("%w: fields %q and %q both map to property %q%s",
+			ErrInvalidTag, existing.field, owner.field, name, cause)
+	}
+	// the shallower field hides the other one
+	if existing.level < owner.level {
+		return false, nil
+	}
+	s.remove(name)
+	return true, nil
+}
+
+// remove drops a property hidden by a shallower field
+func (s *propertySet) remove(name string) {
+	if owner, ok := s.owners[name]; ok && s.byJSONName[owner.jsonName] == name {
+		delete(s.byJSONName, owner.jsonName)
+	}
+	delete(s.owners, name)
+	delete(s.props, name)
+	delete(s.positions, name)
+	delete(s.dependents, name)
+	for i, r := range s.required {
+		if r == name {
+			s.required = append(s.required[:i:i], s.required[i+1:]...)
+			break
+		}
+	}
+}
+
 // convert struct into json
 func structProperties(
 	t reflect.Type,
-	visited map[reflect.Type]bool,
+	visited map[reflect.Type]int,
 	depth int,
-	opts *Options) (Schema, []string, error) {
-	props := make(Schema)
-	var required []string
+	opts *Options) (*propertySet, error) {
+	set := &propertySet{
+		props:      opts.Arena.schema(),
+		required:   opts.Arena.strings(),
+		owners:     make(map[string]propertyOwner),
+		byJSONName: make(map[string]string),
+		positions:  make(map[string][]int),
+		dependents: make(map[string][]string),
+		policy:     opts.EmbeddingPolicy,
+	}
+	if err := collectProperties(t, visited, depth, opts, set); err != nil {
+		return nil, err
+	}
+	// hidden fields are replaced in the order they are met, sort to keep the order stable
+	sortRequired(set.required, set.positions, opts.RequiredOrder)
+	for _, name := range sortedKeys(set.props) {
+		for _, dependent := range set.dependents[name] {
+			if _, ok := set.props[dependent]; !ok {
+				return nil, errorAt(fmt.Errorf("%w: dependentRequired tag on property %q names unknown property %q", ErrInvalidTag, name, dependent), name)
+			}
+		}
+	}
+	return set, nil
+}
+
+// collectProperties adds the fields of t and of its embedded structs to the set
+func collectProperties(
+	t reflect.Type,
+	visited map[reflect.Type]int,
+	depth int,
+	opts *Options,
+	set *propertySet) error {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		// skip unexported fields
 		if field.PkgPath != "" {
 			continue
 		}
-		// handle embedded structs
-		if field.Anonymous {
-			embeddedProps, embeddedRequired, err := structProperties(field.Type, visited, depth, opts)
-			if err != nil {
-				return nil, nil, err
-			}
-			// merge embedded properties
-			for k, v := range embeddedProps {
-				props[k] = v
-			}
-			required = append(required, embeddedRequired...)
-			continue
-		}
 		// parse json tag
 		jsonTag := field.Tag.Get("json")
 		// The json:"-" tag tells the encoding/json package
 		// to ignore this field during marshaling and unmarshaling.
+		// Only the exact tag is skipped, json:"-," names the field "-".
 		if jsonTag == "-" {
 			continue
 		}
-		fieldName, isOptional := parseJSONTag(field.Name, jsonTag)
-		// generate the schema of the field
-		fieldSchema, err := JsonTypeOf(field.Type, visited, depth, opts)
+		// handle embedded structs and fields with the encoding/json/v2 inline option,
+		// their properties are merged one level deeper
+		if inline, err := isInlined(field, jsonTag); err != nil {
+			return err
+		} else if inline {
+			set.level++
+			set.index = append(set.index, i)
+			err := collectProperties(deref(field.Type), visited, depth, opts, set)
+			set.index = set.index[:len(set.index)-1]
+			set.level--
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		fieldName, isOptional, converted := propertyName(field, jsonTag, opts)
+		jsonName, _ := parseJSONTag(field.Name, jsonTag)
+		described, err := set.claim(fieldName, propertyOwner{field: typeName(t) + "." + field.Name, converted: converted, jsonName: jsonName})
 		if err != nil {
-			return nil, nil, err
-		}
-		switch v := fieldSchema.(type) {
-		case string:
-			if isOptional {
-				// Although all fields must be required,
-				// it is possible to emulate an optional parameter by using a union type with null.
-				props[fieldName] = Schema{"type": []string{v, "null"}}
-			} else {
-				props[fieldName] = Schema{"type": v}
-			}
-		case Schema:
-			if isOptional {
-				props[fieldName] = Schema{
-					"anyOf": []Schema{ // OpenAI supports anyOf key
-						v,
-						{"type": "null"},
-					},
-				}
-			} else {
-				props[fieldName] = v
+			return errorAt(err, fieldName)
+		}
+		if !described {
+			continue
+		}
+		// options scoped to the subtree of the property apply from here on
+		opts := opts.scope(joinPath(opts.path, fieldName))
+		// generate the schema of the field
+		var fieldSchema interface{}
+		types, multiType := field.Tag.Lookup("types")
+		ref, curated := field.Tag.Lookup("schemaRef")
+		backRef := false
+		switch {
+		case curated && multiType:
+			return errorAt(fmt.Errorf("%w: schemaRef and types tags on field %q cannot be combined", ErrInvalidTag, fieldName), fieldName)
+		case curated:
+			// hand-written schemas replace the reflected one
+			fieldSchema, err = schemaRefSchema(fieldName, ref, opts)
+			if err != nil {
+				return errorAt(err, fieldName)
+			}
+		case multiType:
+			fieldTypes, err := parseTypesTag(field.Type, fieldName, types)
+			if err != nil {
+				return errorAt(err, fieldName)
 			}
+			fieldSchema = fieldTypes
 		default:
-			props[fieldName] = v
+			if opts.definitions != nil {
+				opts.definitions.push(fieldName)
+				// a pointer to a type on the current path closes a cycle, only null ends it
+				backRef = field.Type.Kind() == reflect.Pointer && visited[deref(field.Type)] > 0
+			}
+			parent := opts.descend(fieldName)
+			fieldSchema, err = JsonTypeOf(field.Type, visited, depth, opts)
+			opts.path = parent
+			if opts.definitions != nil {
+				opts.definitions.pop()
+			}
+			if err != nil {
+				return errorAt(prependCyclePath(err, t, field.Name), fieldName)
+			}
+		}
+		// the ",string" option stores scalars inside JSON strings
+		if hasTagOption(jsonTag, "string") && !curated && (!opts.JSONv2 || quotedV2(field.Type)) {
+			if quoted, ok := quotedScalarSchema(field.Type); ok {
+				fieldSchema = quoted
+			}
+		}
+		if pattern, ok := field.Tag.Lookup("keys"); ok {
+			fieldSchema, err = applyKeysTag(field.Type, fieldName, pattern, fieldSchema)
+			if err != nil {
+				return errorAt(err, fieldName)
+			}
+		}
+		if items, ok := field.Tag.Lookup("items"); ok {
+			fieldSchema, err = applyItemsTag(field.Type, fieldName, items, fieldSchema, opts.NullableStyle)
+			if err != nil {
+				return errorAt(err, fieldName)
+			}
+		}
+		if value, ok := field.Tag.Lookup("contains"); ok {
+			fieldSchema, err = applyContainsTag(field.Type, fieldName, value, fieldSchema, opts)
+			if err != nil {
+				return errorAt(err, fieldName)
+			}
+		}
+		if tag, ok := field.Tag.Lookup("dependentRequired"); ok {
+			dependents, err := parseDependentRequiredTag(fieldName, tag)
+			if err != nil {
+				return errorAt(err, fieldName)
+			}
+			if opts.Strict {
+				opts.Warn(fieldName, "dependentRequired is not supported in strict mode, the tag is ignored")
+			} else {
+				set.dependents[fieldName] = dependents
+			}
+		}
+		isRequired, requiredSet, err := parseRequiredTag(fieldName, field)
+		if err != nil {
+			return errorAt(err, fieldName)
+		}
+		// required:"true" keeps omitempty fields strictly typed without the null union
+		if requiredSet && isRequired {
+			isOptional = false
+		}
+		// a required non-nullable back-reference could only be satisfied by an infinite document
+		if backRef {
+			isOptional = true
+		}
+		var prop interface{}
+		if fieldTypes, ok := fieldSchema.([]string); ok {
+			prop = typesSchema(fieldTypes, isOptional, opts.NullableStyle)
+		} else {
+			prop = propertySchema(fieldSchema, isOptional, opts.NullableStyle)
+		}
+		// formats of wrapper types are mapped centrally by the registered detectors
+		if format, ok := detectFormat(field); ok {
+			prop = withFormat(prop, format)
+		}
+		if description, ok := field.Tag.Lookup("description"); ok {
+			prop = withDescription(prop, description)
+		} else if opts.Humanizer != nil && !hasDescription(prop) {
+			if description := opts.Humanizer(fieldName); description != "" {
+				prop = withDescription(prop, description)
+			}
+		}
+		hints, err := parseLengthHints(fieldName, field)
+		if err != nil {
+			return errorAt(err, fieldName)
+		}
+		prop = withLengthHints(prop, hints)
+		sensitive, err := parseSensitiveTag(fieldName, field)
+		if err != nil {
+			return errorAt(err, fieldName)
+		}
+		if sensitive {
+			prop = withSensitiveNote(prop, opts.SensitiveDescription)
 		}
-		// All fields must be in required array for OpenAI structured outputs
-		required = append(required, fieldName)
+		// All fields must be in required array for OpenAI structured outputs,
+		// outside of strict mode required:"false" leaves the field out
+		set.add(fieldName, prop, i, opts.Strict || !requiredSet || isRequired)
 	}
-	return props, required, nil
+	// derived values exposed from methods are described like regular fields
+	for k, computed := range computedProperties(t) {
+		described, err := set.claim(computed.Name, propertyOwner{field: typeName(t) + "." + computed.Method + "()"})
+		if err != nil {
+			return errorAt(err, computed.Name)
+		}
+		if !described {
+			continue
+		}
+		opts := opts.scope(joinPath(opts.path, computed.Name))
+		if opts.definitions != nil {
+			opts.definitions.push(computed.Name)
+		}
+		parent := opts.descend(computed.Name)
+		computedSchema, err := JsonTypeOf(computed.Type, visited, depth, opts)
+		opts.path = parent
+		if opts.definitions != nil {
+			opts.definitions.pop()
+		}
+		if err != nil {
+			return errorAt(prependCyclePath(err, t, computed.Method+"()"), computed.Name)
+		}
+		// computed properties follow the fields
+		set.add(computed.Name, propertySchema(computedSchema, false, opts.NullableStyle), t.NumField()+k, true)
+	}
+	return nil
 }
 
 // JsonTypeOf converts a Go reflect.Type to a JSON Schema representation
 func JsonTypeOf(
 	t reflect.Type,
-	visited map[reflect.Type]bool,
+	visited map[reflect.Type]int,
 	depth int,
 	opts *Options) (interface{}, error) {
-	// check depth to prevent infinite recursion
-	if depth > opts.MaxDepth {
-		return nil, ErrCircularRef
-	}
+	// check depth to prevent infinite recursion, depth counts the enclosing objects
 	t = deref(t)
+	if depth > opts.MaxDepth || opts.arrayDepth > opts.MaxDepth {
+		return nil, &CircularRefError{Path: []string{typeName(t)}, DepthExceeded: true}
+	}
+	// time.Time is a struct without exported fields, described by its serialized form
+	if t == timeType {
+		return timeSchema(opts.TimeFormat), nil
+	}
+	if (opts.RecursiveRefs || opts.Defs) && opts.definitions == nil {
+		return convertWithDefinitions(t, visited, depth, opts)
+	}
+	// visited counts the occurrences of each struct on the current path, so a type reused
+	// by sibling fields is not mistaken for a cycle
 	if t.Kind() == reflect.Struct {
-		if visited[t] {
-			return nil, ErrCircularRef
+		if occurrences := visited[t]; occurrences > 0 {
+			if opts.definitions != nil {
+				return opts.definitions.ref(t), nil
+			}
+			if opts.RecursionUnroll == 0 {
+				return nil, &CircularRefError{Path: []string{typeName(t)}, cycleType: t}
+			}
+			// the innermost occurrence of an unrolled type is terminated with a null leaf
+			if occurrences > opts.RecursionUnroll {
+				return Schema{"type": "null"}, nil
+			}
+		} else if opts.definitions != nil && opts.definitions.enter(t) {
+			return opts.definitions.ref(t), nil
 		}
-		visited[t] = true
-		defer delete(visited, t)
+		visited[t]++
+		defer func() {
+			if visited[t]--; visited[t] == 0 {
+				delete(visited, t)
+			}
+		}()
+	}
+	// enums take precedence over the kind of the type
+	if values, ok := lookupEnum(t, opts); ok {
+		return enumSchema(t, values), nil
 	}
 	switch t.Kind() {
 	case reflect.String:
@@ -176,31 +941,74 @@ func JsonTypeOf(
 	case reflect.Bool:
 		return "boolean", nil
 	// numbers
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return "integer", nil
+	case reflect.Int64, reflect.Uint64:
+		// 64-bit integers can exceed the precision of JSON numbers
+		if opts.Int64AsString {
+			quoted, _ := quotedScalarSchema(t)
+			return quoted, nil
+		}
+		return numericSchema(t, "integer", opts), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return numericSchema(t, "integer", opts), nil
 	case reflect.Float32, reflect.Float64:
-		return "number", nil
+		return numericSchema(t, "number", opts), nil
 	//array items
 	case reflect.Slice, reflect.Array:
-		items, err := parseArrayItemType(t, visited, depth+1, opts)
+		// arrays do not nest objects, they don't consume the object depth
+		opts.arrayDepth++
+		parent := opts.descend("[]")
+		items, err := parseArrayItemType(t, visited, depth, opts.scope(opts.path))
+		opts.path = parent
+		opts.arrayDepth--
+		if err != nil {
+			return nil, errorAt(err, "[]")
+		}
+		schema := opts.Arena.schema()
+		schema["type"], schema["items"] = "array", items
+		return schema, nil
+	// dynamic object, only allowed outside of strict mode
+	case reflect.Map:
+		if opts.ExpandEnumMaps {
+			if keys, ok := enumValues(t.Key()); ok {
+				return enumMapProperties(t, keys, visited, depth+1, opts)
+			}
+		}
+		if opts.Strict {
+			return nil, ErrUnsupportedType
+		}
+		parent := opts.descend("{}")
+		values, err := parseMapValueType(t, visited, depth+1, opts.scope(opts.path))
+		opts.path = parent
 		if err != nil {
 			return nil, err
 		}
-		return Schema{"type": "array", "items": items}, nil
+		schema := opts.Arena.schema()
+		schema["type"], schema["additionalProperties"] = "object", values
+		return schema, nil
 	// object item
 	case reflect.Struct:
-		props, required, err := structProperties(t, visited, depth+1, opts)
+		set, err := structProperties(t, visited, depth+1, opts)
 		if err != nil {
 			return nil, err
 		}
-		schema := Schema{
-			"type":                 "object",
-			"properties":           props,
-			"additionalProperties": opts.AllowAdditionalProperty,
+		schema := opts.Arena.schema()
+		schema["type"] = "object"
+		schema["properties"] = set.props
+		schema["additionalProperties"] = opts.AllowAdditionalProperty
+		if len(set.required) > 0 {
+			schema["required"] = set.required
+		}
+		if len(set.dependents) > 0 {
+			dependents := make(Schema, len(set.dependents))
+			for name, names := range set.dependents {
+				dependents[name] = names
+			}
+			schema["dependentRequired"] = dependents
 		}
-		if len(required) > 0 {
-			schema["required"] = required
+		// the outermost occurrence of a type holds its definition
+		if opts.definitions != nil && visited[t] == 1 {
+			return opts.definitions.define(t, schema), nil
 		}
 		return schema, nil
 	default:
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -116,6 +117,20 @@ func TestParseJSONTag(t *testing.T) {
 			expectedName: "my_field",
 			expectedOpt:  true,
 		},
+		{
+			name:         "field literally named dash",
+			fieldName:    "MyField",
+			tag:          "-,",
+			expectedName: "-",
+			expectedOpt:  false,
+		},
+		{
+			name:         "field named dash with omitempty",
+			fieldName:    "MyField",
+			tag:          "-,omitempty",
+			expectedName: "-",
+			expectedOpt:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -305,6 +320,16 @@ func TestStructConversion(t *testing.T) {
 			input:    reflect.TypeOf(CollectionWithPointers{}),
 			expected: CollectionWithPointersSchema,
 		},
+		{
+			name:     "struct with descriptions",
+			input:    reflect.TypeOf(DescribedContact{}),
+			expected: DescribedContactSchema,
+		},
+		{
+			name:     "struct with dash tags",
+			input:    reflect.TypeOf(StructWithDashTags{}),
+			expected: StructWithDashTagsSchema,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -341,13 +366,13 @@ func TestArrayWithUnsupportedElementType(t *testing.T) {
 func TestCircularReferenceDetection(t *testing.T) {
 	t.Run("circular reference in struct", func(t *testing.T) {
 		opts := DefaultOptions()
-		visited := make(map[reflect.Type]bool)
+		visited := make(map[reflect.Type]int)
 		nodeType := reflect.TypeOf(Node{})
 
 		// Manually mark as visited to simulate circular reference
-		visited[nodeType] = true
+		visited[nodeType] = 1
 		_, err := JsonTypeOf(nodeType, visited, 0, opts)
-		if err != ErrCircularRef {
+		if !errors.Is(err, ErrCircularRef) {
 			t.Errorf("expected ErrCircularRef, got %v", err)
 		}
 	})
@@ -357,10 +382,642 @@ func TestCircularReferenceDetection(t *testing.T) {
 			AllowAdditionalProperty: false,
 			MaxDepth:                0,
 		}
-		visited := make(map[reflect.Type]bool)
+		visited := make(map[reflect.Type]int)
 		_, err := JsonTypeOf(reflect.TypeOf(SimpleStruct{}), visited, 1, opts)
-		if err != ErrCircularRef {
+		if !errors.Is(err, ErrCircularRef) {
 			t.Errorf("expected ErrCircularRef due to max depth, got %v", err)
 		}
 	})
 }
+
+func TestMapConversion(t *testing.T) {
+	t.Run("maps are rejected in strict mode", func(t *testing.T) {
+		_, err := runJsonTypeOf(reflect.TypeOf(StructWithMaps{}))
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) || !errors.Is(err, ErrUnsupportedType) || schemaErr.Code != UnsupportedType {
+			t.Errorf("expected an UnsupportedType SchemaError in strict mode, got %v", err)
+		}
+	})
+
+	t.Run("maps with keys tag in non-strict mode", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.Strict = false
+		result, err := JsonTypeOf(reflect.TypeOf(StructWithMaps{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, StructWithMapsSchema) {
+			t.Errorf("expected %+v, got %+v", StructWithMapsSchema, result)
+		}
+	})
+
+	t.Run("unsupported map key type", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.Strict = false
+		_, err := JsonTypeOf(reflect.TypeOf(map[float64]string{}), visited, depth, opts)
+		if !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("expected ErrUnsupportedType for float keys, got %v", err)
+		}
+	})
+
+	t.Run("keys tag on non-map field", func(t *testing.T) {
+		type BadKeys struct {
+			Name string `json:"name" keys:"^[a-z]+$"`
+		}
+		opts, visited, depth := getInputs()
+		opts.Strict = false
+		_, err := JsonTypeOf(reflect.TypeOf(BadKeys{}), visited, depth, opts)
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("expected ErrInvalidTag, got %v", err)
+		}
+	})
+
+	t.Run("keys tag with invalid pattern", func(t *testing.T) {
+		type BadPattern struct {
+			Labels map[string]string `json:"labels" keys:"^[a-z"`
+		}
+		opts, visited, depth := getInputs()
+		opts.Strict = false
+		_, err := JsonTypeOf(reflect.TypeOf(BadPattern{}), visited, depth, opts)
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("expected ErrInvalidTag, got %v", err)
+		}
+	})
+}
+
+func TestHasTagOption(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		option   string
+		expected bool
+	}{
+		{name: "empty tag", tag: "", option: "string", expected: false},
+		{name: "name only", tag: "string", option: "string", expected: false},
+		{name: "string option", tag: "id,string", option: "string", expected: true},
+		{name: "multiple options", tag: "id,omitempty,string", option: "string", expected: true},
+		{name: "missing option", tag: "id,omitempty", option: "string", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasTagOption(tt.tag, tt.option); got != tt.expected {
+				t.Errorf("hasTagOption() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInt64AsStringConversion(t *testing.T) {
+	opts, visited, depth := getInputs()
+	opts.Int64AsString = true
+	result, err := JsonTypeOf(reflect.TypeOf(Invoice{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, InvoiceInt64AsStringSchema) {
+		t.Errorf("expected %+v, got %+v", InvoiceInt64AsStringSchema, result)
+	}
+}
+
+func TestNumericFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		expected interface{}
+	}{
+		{name: "int8", input: reflect.TypeOf(int8(0)), expected: Schema{"type": "integer", "format": "int32"}},
+		{name: "uint16", input: reflect.TypeOf(uint16(0)), expected: Schema{"type": "integer", "format": "int32"}},
+		{name: "int32", input: reflect.TypeOf(int32(0)), expected: Schema{"type": "integer", "format": "int32"}},
+		{name: "uint32", input: reflect.TypeOf(uint32(0)), expected: Schema{"type": "integer", "format": "int64"}},
+		{name: "int", input: reflect.TypeOf(int(0)), expected: Schema{"type": "integer", "format": "int64"}},
+		{name: "int64", input: reflect.TypeOf(int64(0)), expected: Schema{"type": "integer", "format": "int64"}},
+		{name: "float32", input: reflect.TypeOf(float32(0)), expected: Schema{"type": "number", "format": "float"}},
+		{name: "float64", input: reflect.TypeOf(float64(0)), expected: Schema{"type": "number", "format": "double"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.NumericFormats = true
+			opts.Strict = false
+			result, err := JsonTypeOf(tt.input, visited, depth, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("formats are stripped in strict mode", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.NumericFormats = true
+		result, err := JsonTypeOf(reflect.TypeOf(int64(0)), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "integer" {
+			t.Errorf("expected plain integer in strict mode, got %+v", result)
+		}
+	})
+}
+
+func TestRequiredFalseTag(t *testing.T) {
+	t.Run("ignored in strict mode", func(t *testing.T) {
+		result, err := runJsonTypeOf(reflect.TypeOf(StructWithRequiredTags{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []string{"name", "comment", "rating", "source", "stored"}
+		if got := result.(Schema)["required"]; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected required %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("excluded from required in non-strict mode", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.Strict = false
+		result, err := JsonTypeOf(reflect.TypeOf(StructWithRequiredTags{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		schema := result.(Schema)
+		expected := []string{"name", "source", "stored"}
+		if got := schema["required"]; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected required %v, got %v", expected, got)
+		}
+		// nullability still follows omitempty
+		rating := schema["properties"].(Schema)["rating"]
+		if !reflect.DeepEqual(rating, Schema{"type": []string{"integer", "null"}}) {
+			t.Errorf("expected nullable rating, got %+v", rating)
+		}
+	})
+
+	t.Run("invalid tag value", func(t *testing.T) {
+		type BadRequired struct {
+			Name string `json:"name" required:"maybe"`
+		}
+		_, err := runJsonTypeOf(reflect.TypeOf(BadRequired{}))
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("expected ErrInvalidTag, got %v", err)
+		}
+	})
+}
+
+func TestRequiredTrueTag(t *testing.T) {
+	for _, strict := range []bool{true, false} {
+		opts, visited, depth := getInputs()
+		opts.Strict = strict
+		result, err := JsonTypeOf(reflect.TypeOf(StructWithRequiredTags{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		props := result.(Schema)["properties"].(Schema)
+		if !reflect.DeepEqual(props["stored"], Schema{"type": "string"}) {
+			t.Errorf("strict=%v: expected plain string for stored, got %+v", strict, props["stored"])
+		}
+		if !reflect.DeepEqual(props["source"], Schema{"type": []string{"string", "null"}}) {
+			t.Errorf("strict=%v: expected nullable source, got %+v", strict, props["source"])
+		}
+	}
+}
+
+func TestRecursionUnroll(t *testing.T) {
+	t.Run("self reference unrolled one level", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.RecursionUnroll = 1
+		result, err := JsonTypeOf(reflect.TypeOf(Node{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, NodeUnrolledSchema) {
+			t.Errorf("expected %+v, got %+v", NodeUnrolledSchema, result)
+		}
+		if len(visited) != 0 {
+			t.Errorf("expected visited to be empty after generation, got %v", visited)
+		}
+	})
+
+	t.Run("indirect cycle unrolled", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.RecursionUnroll = 2
+		result, err := JsonTypeOf(reflect.TypeOf(Tree{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Tree -> Branch -> Tree -> Branch -> Tree -> Branch -> null
+		schema := result.(Schema)
+		for level := 0; level < 3; level++ {
+			branch := schema["properties"].(Schema)["children"].(Schema)["items"].(Schema)
+			parent := branch["properties"].(Schema)["parent"].(Schema)
+			if level == 2 {
+				if !reflect.DeepEqual(parent, Schema{"type": "null"}) {
+					t.Errorf("expected null leaf at level %d, got %+v", level, parent)
+				}
+				break
+			}
+			schema = parent["anyOf"].([]Schema)[0]
+		}
+	})
+
+	t.Run("max depth still applies", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.RecursionUnroll = 10
+		opts.MaxDepth = 5
+		_, err := JsonTypeOf(reflect.TypeOf(Node{}), visited, depth, opts)
+		if !errors.Is(err, ErrCircularRef) {
+			t.Errorf("expected ErrCircularRef, got %v", err)
+		}
+	})
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := DefaultOptions()
+	if err := opts.Validate(); err != nil {
+		t.Errorf("expected default options to be valid, got %v", err)
+	}
+	opts.AddError("max depth must be at least 1, got %d", -1)
+	err := opts.Validate()
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestPropertyNameCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		errorMsg string
+	}{
+		{
+			name:     "tag matching an untagged field name",
+			input:    reflect.TypeOf(TagFallbackCollision{}),
+			errorMsg: `invalid struct tag: fields "TagFallbackCollision.Title" and "TagFallbackCollision.Heading" both map to property "Title"`,
+		},
+		{
+			name:     "embedded structs at the same level",
+			input:    reflect.TypeOf(AmbiguousEmbedding{}),
+			errorMsg: `invalid struct tag: fields "Timestamps.Updated" and "Revision.Updated" both map to property "Updated"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runJsonTypeOf(tt.input)
+			if !errors.Is(err, ErrInvalidTag) {
+				t.Fatalf("expected ErrInvalidTag, got %v", err)
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("mismatch error message, expect=%s, got=%s", tt.errorMsg, err.Error())
+			}
+		})
+	}
+
+	t.Run("shallower fields hide embedded ones", func(t *testing.T) {
+		result, err := runJsonTypeOf(reflect.TypeOf(ShadowedTimestamps{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, ShadowedTimestampsSchema) {
+			t.Errorf("expected %+v, got %+v", ShadowedTimestampsSchema, result)
+		}
+	})
+}
+
+func TestContainsAndDependentRequiredTags(t *testing.T) {
+	opts, visited, depth := getInputs()
+	opts.Strict = false
+	result, err := JsonTypeOf(reflect.TypeOf(Shipment{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, ShipmentSchema) {
+		t.Errorf("expected %+v, got %+v", ShipmentSchema, result)
+	}
+
+	var warnings []string
+	opts, visited, depth = getInputs()
+	opts.WarningHandler = func(w Warning) { warnings = append(warnings, w.String()) }
+	result, err = JsonTypeOf(reflect.TypeOf(Shipment{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := result.(Schema)["dependentRequired"]; ok {
+		t.Errorf("expected strict mode to leave dependentRequired out, got %+v", result)
+	}
+	expected := []string{
+		"tags: contains is not supported in strict mode, the tag is ignored",
+		"codes: contains is not supported in strict mode, the tag is ignored",
+		"street: dependentRequired is not supported in strict mode, the tag is ignored",
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected warnings %v, got %v", expected, warnings)
+	}
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		errorMsg string
+	}{
+		{
+			name: "contains on non-array",
+			input: struct {
+				Tag string `json:"tag" contains:"x"`
+			}{},
+			errorMsg: `invalid struct tag: contains tag on non-array field "tag"`,
+		},
+		{
+			name: "contains of invalid value",
+			input: struct {
+				Codes []int `json:"codes" contains:"seven"`
+			}{},
+			errorMsg: `invalid struct tag: contains tag on field "codes": "seven" is not a valid int`,
+		},
+		{
+			name: "contains on array of objects",
+			input: struct {
+				Items []Address `json:"items" contains:"{}"`
+			}{},
+			errorMsg: `invalid struct tag: contains tag on field "items" requires an array of scalars`,
+		},
+		{
+			name: "unknown dependent property",
+			input: struct {
+				Street string `json:"street" dependentRequired:"city"`
+			}{},
+			errorMsg: `invalid struct tag: dependentRequired tag on property "street" names unknown property "city"`,
+		},
+		{
+			name: "self dependency",
+			input: struct {
+				Street string `json:"street" dependentRequired:"street"`
+			}{},
+			errorMsg: `invalid struct tag: dependentRequired tag on field "street" must list other properties, got "street"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.Strict = false
+			_, err := JsonTypeOf(reflect.TypeOf(tt.input), visited, depth, opts)
+			if !errors.Is(err, ErrInvalidTag) || err.Error() != tt.errorMsg {
+				t.Errorf("expected error %s, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestTypesTag(t *testing.T) {
+	result, err := runJsonTypeOf(reflect.TypeOf(Measurement{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, MeasurementSchema) {
+		t.Errorf("expected %+v, got %+v", MeasurementSchema, result)
+	}
+
+	opts, visited, depth := getInputs()
+	opts.NullableStyle = NullableAnyOf
+	result, err = JsonTypeOf(reflect.TypeOf(Measurement{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	offset := result.(Schema)["properties"].(Schema)["offset"]
+	expected := Schema{"anyOf": []Schema{{"type": "integer"}, {"type": "string"}, {"type": "null"}}}
+	if !reflect.DeepEqual(offset, expected) {
+		t.Errorf("expected %+v, got %+v", expected, offset)
+	}
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		errorMsg string
+	}{
+		{
+			name: "concrete field",
+			input: struct {
+				Value string `json:"value" types:"string|integer"`
+			}{},
+			errorMsg: `invalid struct tag: types tag on field "value" requires an interface{} or json.RawMessage field`,
+		},
+		{
+			name: "unknown type",
+			input: struct {
+				Value interface{} `json:"value" types:"string|object"`
+			}{},
+			errorMsg: `invalid struct tag: types tag on field "value": unknown type "object", expected one of string, integer, number, boolean, null`,
+		},
+		{
+			name: "duplicate type",
+			input: struct {
+				Value interface{} `json:"value" types:"string|string"`
+			}{},
+			errorMsg: `invalid struct tag: types tag on field "value": duplicate type "string"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runJsonTypeOf(reflect.TypeOf(tt.input))
+			if !errors.Is(err, ErrInvalidTag) || err.Error() != tt.errorMsg {
+				t.Errorf("expected error %s, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestRequiredOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		order    RequiredOrder
+		expected []string
+	}{
+		{name: "declaration order", input: reflect.TypeOf(ReorderedTimestamps{}), order: DeclarationOrder, expected: []string{"Created", "Updated", "Note"}},
+		{name: "alphabetical", input: reflect.TypeOf(ReorderedTimestamps{}), order: Alphabetical, expected: []string{"Created", "Note", "Updated"}},
+		{name: "declaration order of tagged fields", input: reflect.TypeOf(Account{}), order: DeclarationOrder, expected: []string{"id", "email", "backup", "aliases", "name"}},
+		{name: "alphabetical tagged fields", input: reflect.TypeOf(Account{}), order: Alphabetical, expected: []string{"aliases", "backup", "email", "id", "name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.RequiredOrder = tt.order
+			result, err := JsonTypeOf(tt.input, visited, depth, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if required := result.(Schema)["required"]; !reflect.DeepEqual(required, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, required)
+			}
+		})
+	}
+
+	t.Run("embedded structs before hiding fields", func(t *testing.T) {
+		result, err := runJsonTypeOf(reflect.TypeOf(ReorderedTimestamps{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, ReorderedTimestampsSchema) {
+			t.Errorf("expected %+v, got %+v", ReorderedTimestampsSchema, result)
+		}
+	})
+}
+
+func TestEmbeddingPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		policy   EmbeddingPolicy
+		updated  interface{}
+		errorMsg string
+	}{
+		{name: "depth based outer field", input: reflect.TypeOf(ShadowedTimestamps{}), policy: DepthBased, updated: "integer"},
+		{name: "depth based embedded fields", input: reflect.TypeOf(DeepEmbedding{}), policy: DepthBased, updated: "string"},
+		{name: "outer wins outer field", input: reflect.TypeOf(ShadowedTimestamps{}), policy: OuterWins, updated: "integer"},
+		{
+			name:     "outer wins embedded fields",
+			input:    reflect.TypeOf(DeepEmbedding{}),
+			policy:   OuterWins,
+			errorMsg: `invalid struct tag: fields "Timestamps.Updated" and "Revision.Updated" both map to property "Updated"`,
+		},
+		{
+			name:     "error on conflict",
+			input:    reflect.TypeOf(ShadowedTimestamps{}),
+			policy:   ErrorOnConflict,
+			errorMsg: `invalid struct tag: fields "ShadowedTimestamps.Updated" and "Timestamps.Updated" both map to property "Updated"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.EmbeddingPolicy = tt.policy
+			result, err := JsonTypeOf(tt.input, visited, depth, opts)
+			if tt.errorMsg != "" {
+				if err == nil || err.Error() != tt.errorMsg {
+					t.Errorf("expected error %s, got %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			updated := result.(Schema)["properties"].(Schema)["Updated"]
+			if !reflect.DeepEqual(updated, Schema{"type": tt.updated}) {
+				t.Errorf("expected Updated to be %v, got %+v", tt.updated, updated)
+			}
+		})
+	}
+}
+
+func TestInlineFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		expected Schema
+	}{
+		{name: "inline option", input: reflect.TypeOf(Customer{}), expected: CustomerSchema},
+		{name: "embedded struct with a json name", input: reflect.TypeOf(NamedEmbedding{}), expected: NamedEmbeddingSchema},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runJsonTypeOf(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("inline option on a non-struct field", func(t *testing.T) {
+		_, err := runJsonTypeOf(reflect.TypeOf(InvalidInline{}))
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("expected ErrInvalidTag, got %v", err)
+		}
+	})
+}
+
+func TestItemsTag(t *testing.T) {
+	result, err := runJsonTypeOf(reflect.TypeOf(Survey{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, SurveySchema) {
+		t.Errorf("expected %+v, got %+v", SurveySchema, result)
+	}
+
+	for _, input := range []reflect.Type{reflect.TypeOf(InvalidItemsTags{}), reflect.TypeOf(UnknownItemsTag{})} {
+		t.Run(input.Name(), func(t *testing.T) {
+			_, err := runJsonTypeOf(input)
+			if !errors.Is(err, ErrInvalidTag) {
+				t.Errorf("expected ErrInvalidTag, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNullableStyle(t *testing.T) {
+	RegisterEnum(reflect.TypeOf(Mood("")), []interface{}{"happy", "sad"})
+	address := Schema{
+		"type": "object",
+		"properties": Schema{
+			"street": Schema{"type": "string"},
+			"city":   Schema{"type": "string"},
+		},
+		"required":             []string{"street", "city"},
+		"additionalProperties": false,
+	}
+	mood := Schema{"type": "string", "enum": []interface{}{"happy", "sad"}}
+	tags := Schema{"type": "array", "items": Schema{"type": "string"}}
+	null := Schema{"type": "null"}
+	tests := []struct {
+		style    NullableStyle
+		expected Schema
+	}{
+		{
+			style: NullableMixed,
+			expected: Schema{
+				"nick":    Schema{"type": []string{"string", "null"}},
+				"address": Schema{"anyOf": []Schema{address, null}},
+				"mood":    Schema{"anyOf": []Schema{mood, null}},
+				"tags":    Schema{"anyOf": []Schema{tags, null}},
+			},
+		},
+		{
+			style: NullableTypeArray,
+			expected: Schema{
+				"nick": Schema{"type": []string{"string", "null"}},
+				"address": Schema{
+					"type":                 []string{"object", "null"},
+					"properties":           address["properties"],
+					"required":             address["required"],
+					"additionalProperties": false,
+				},
+				"mood": Schema{"type": []string{"string", "null"}, "enum": []interface{}{"happy", "sad", nil}},
+				"tags": Schema{"type": []string{"array", "null"}, "items": Schema{"type": "string"}},
+			},
+		},
+		{
+			style: NullableAnyOf,
+			expected: Schema{
+				"nick":    Schema{"anyOf": []Schema{{"type": "string"}, null}},
+				"address": Schema{"anyOf": []Schema{address, null}},
+				"mood":    Schema{"anyOf": []Schema{mood, null}},
+				"tags":    Schema{"anyOf": []Schema{tags, null}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.NullableStyle = tt.style
+			result, err := JsonTypeOf(reflect.TypeOf(OptionalFields{}), visited, depth, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			props := result.(Schema)["properties"]
+			if !reflect.DeepEqual(props, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, props)
+			}
+		})
+	}
+}
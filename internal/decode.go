@@ -0,0 +1,300 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// decodeField is the Go side of a JSON property
+type decodeField struct {
+	Type reflect.Type
+	// GoName is set when the property name was produced by the naming convention,
+	// encoding/json only matches the Go name of untagged fields
+	GoName string
+	// Inline lists the Go names of the inline fields holding the field,
+	// encoding/json expects their properties nested under these names
+	Inline []string
+	// Quoted fields use the ",string" option, encoding/json already decodes them
+	Quoted bool
+	// Sensitive fields are tagged sensitive:"true", their values are redacted
+	Sensitive bool
+}
+
+// fieldTypes maps the JSON property names of a struct to their Go fields,
+// following the same rules as structProperties.
+func fieldTypes(t reflect.Type, opts *Options, inline []string, fields map[string]decodeField) {
+	found := make(map[string]fieldCandidate)
+	collectFieldTypes(t, opts, inline, 0, found)
+	names := make([]string, 0, len(found))
+	for jsonName := range found {
+		names = append(names, jsonName)
+	}
+	sort.Strings(names)
+	for _, jsonName := range names {
+		fields[found[jsonName].name] = found[jsonName].field
+	}
+}
+
+// fieldCandidate is a field competing for its encoding/json name
+type fieldCandidate struct {
+	name  string
+	field decodeField
+	level int
+}
+
+// collectFieldTypes adds the fields of t to found keyed by their encoding/json name,
+// a field hides the fields of the same name embedded deeper like with encoding/json
+func collectFieldTypes(t reflect.Type, opts *Options, inline []string, level int, found map[string]fieldCandidate) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		if inlined, err := isInlined(field, jsonTag); err == nil && inlined {
+			path := inline
+			if !field.Anonymous {
+				path = append(inline[:len(inline):len(inline)], field.Name)
+			}
+			collectFieldTypes(deref(field.Type), opts, path, level+1, found)
+			continue
+		}
+		jsonName, _ := parseJSONTag(field.Name, jsonTag)
+		if existing, ok := found[jsonName]; ok && existing.level <= level {
+			continue
+		}
+		name, _, converted := propertyName(field, jsonTag, opts)
+		quoted := hasTagOption(jsonTag, "string") && (!opts.JSONv2 || quotedV2(field.Type))
+		sensitive, _ := parseSensitiveTag(name, field)
+		decoded := decodeField{Type: field.Type, Inline: inline, Quoted: quoted, Sensitive: sensitive}
+		if converted {
+			decoded.GoName = field.Name
+		}
+		found[jsonName] = fieldCandidate{name: name, field: decoded, level: level}
+	}
+}
+
+// CoerceValue rewrites a JSON value decoded with UseNumber so it unmarshals into t
+// under the given options, e.g. numeric strings emitted for int64 fields
+// are turned back into numbers when Int64AsString is enabled, and epoch or date-only
+// times are turned into the RFC 3339 strings time.Time expects. Properties named by the
+// naming convention are renamed back to the Go field names, and properties of inline
+// fields are nested back under them. With LenientDecode, compatible scalars are converted
+// to the type of their field, such as "42" for an integer or 1 for a boolean, and every
+// conversion is reported as a warning. With a Redactor, the values of fields tagged
+// sensitive:"true" are redacted.
+func CoerceValue(t reflect.Type, value interface{}, opts *Options) interface{} {
+	return coerceValue(t, value, "", opts)
+}
+
+// coerceValue is CoerceValue for the value at path, array elements are indexed as in "items[2]"
+func coerceValue(t reflect.Type, value interface{}, path string, opts *Options) interface{} {
+	t = deref(t)
+	if t == timeType {
+		return coerceTime(value, opts.TimeFormat)
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := make(map[string]decodeField)
+			fieldTypes(t, opts, nil, fields)
+			type move struct {
+				field decodeField
+				key   string
+				value interface{}
+			}
+			var moves []move
+			for key, item := range v {
+				name, field, ok := lookupField(fields, key, opts)
+				if !ok {
+					continue
+				}
+				if _, exact := v[name]; exact && name != key {
+					// the property itself wins over keys matching it case-insensitively,
+					// such as the converted name of a field it hides
+					delete(v, key)
+					continue
+				}
+				if !field.Quoted {
+					item = coerceValue(field.Type, item, joinPath(path, key), opts)
+				}
+				if field.Sensitive && opts.Redactor != nil {
+					if field.Quoted {
+						// scalars quoted by ",string" cannot be rewritten and still decode
+						item = nil
+					} else {
+						item = redactValue(item, joinPath(path, key), opts.Redactor)
+					}
+				}
+				target := key
+				if field.GoName != "" {
+					target = field.GoName
+				}
+				if target == key && len(field.Inline) == 0 {
+					v[key] = item
+					continue
+				}
+				delete(v, key)
+				moves = append(moves, move{field: field, key: target, value: item})
+			}
+			for _, m := range moves {
+				parent := v
+				for _, name := range m.field.Inline {
+					child, ok := parent[name].(map[string]interface{})
+					if !ok {
+						child = make(map[string]interface{})
+						parent[name] = child
+					}
+					parent = child
+				}
+				parent[m.key] = m.value
+			}
+		case reflect.Map:
+			// expanded enum maps emulate missing keys with null in strict mode
+			_, expanded := enumValues(t.Key())
+			expanded = expanded && opts.ExpandEnumMaps
+			for key, item := range v {
+				if expanded && item == nil {
+					delete(v, key)
+					continue
+				}
+				v[key] = coerceValue(t.Elem(), item, joinPath(path, key), opts)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				v[i] = coerceValue(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i), opts)
+			}
+		}
+	case string:
+		if opts.Int64AsString {
+			switch t.Kind() {
+			case reflect.Int64:
+				if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+					return json.Number(v)
+				}
+			case reflect.Uint64:
+				if _, err := strconv.ParseUint(v, 10, 64); err == nil {
+					return json.Number(v)
+				}
+			}
+		}
+	}
+	if opts.LenientDecode {
+		return coerceScalar(t, value, path, opts)
+	}
+	return value
+}
+
+// coerceScalar converts a scalar to the kind of t when the conversion is lossless
+func coerceScalar(t reflect.Type, value interface{}, path string, opts *Options) interface{} {
+	var coerced interface{}
+	switch v := value.(type) {
+	case string:
+		s := strings.TrimSpace(v)
+		switch {
+		case isIntKind(t.Kind()):
+			if n, ok := integralNumber(s); ok {
+				coerced = n
+			}
+		case isFloatKind(t.Kind()):
+			// ParseFloat also accepts literals JSON does not, such as NaN or +1
+			if _, err := strconv.ParseFloat(s, 64); err == nil && json.Valid([]byte(s)) {
+				coerced = json.Number(s)
+			}
+		case t.Kind() == reflect.Bool:
+			if b, err := strconv.ParseBool(s); err == nil {
+				coerced = b
+			}
+		}
+	case json.Number:
+		switch {
+		case t.Kind() == reflect.String:
+			coerced = v.String()
+		case t.Kind() == reflect.Bool:
+			if v == "0" || v == "1" {
+				coerced = v == "1"
+			}
+		case isIntKind(t.Kind()):
+			// integral floats such as 42.0 are not accepted for integers by encoding/json
+			if _, err := v.Int64(); err != nil {
+				if n, ok := integralNumber(v.String()); ok {
+					coerced = n
+				}
+			}
+		}
+	case bool:
+		if t.Kind() == reflect.String {
+			coerced = strconv.FormatBool(v)
+		}
+	}
+	if coerced == nil {
+		return value
+	}
+	opts.Warn(path, "coerced %s %s to %s", jsonKind(value), jsonLiteral(value), kindLabel(t.Kind()))
+	return coerced
+}
+
+// integralNumber parses a number without a fractional part, such as "42" or "42.0"
+func integralNumber(s string) (json.Number, bool) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return json.Number(strconv.FormatInt(n, 10)), true
+	}
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return json.Number(strconv.FormatUint(n, 10)), true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) >= 1<<53 {
+		return "", false
+	}
+	return json.Number(strconv.FormatFloat(f, 'f', -1, 64)), true
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// jsonKind names the JSON type of a decoded value
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "value"
+}
+
+// kindLabel names the JSON Schema type of a Go kind
+func kindLabel(k reflect.Kind) string {
+	switch {
+	case isIntKind(k):
+		return "integer"
+	case isFloatKind(k):
+		return "number"
+	case k == reflect.Bool:
+		return "boolean"
+	}
+	return "string"
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCoerceValue(t *testing.T) {
+	input := map[string]interface{}{
+		"id":      "9007199254740993",
+		"owner":   "not a number",
+		"count":   json.Number("3"),
+		"related": []interface{}{"1", "-2"},
+	}
+	expected := map[string]interface{}{
+		"id":      json.Number("9007199254740993"),
+		"owner":   "not a number",
+		"count":   json.Number("3"),
+		"related": []interface{}{json.Number("1"), json.Number("-2")},
+	}
+	t.Run("numeric strings become numbers", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.Int64AsString = true
+		result := CoerceValue(reflect.TypeOf(Invoice{}), input, opts)
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected %+v, got %+v", expected, result)
+		}
+	})
+
+	t.Run("values are untouched without the option", func(t *testing.T) {
+		value := map[string]interface{}{"id": "42"}
+		result := CoerceValue(reflect.TypeOf(Invoice{}), value, DefaultOptions())
+		if !reflect.DeepEqual(result, map[string]interface{}{"id": "42"}) {
+			t.Errorf("expected value to be unchanged, got %+v", result)
+		}
+	})
+}
+
+func TestFieldTypesDashTags(t *testing.T) {
+	fields := make(map[string]decodeField)
+	fieldTypes(reflect.TypeOf(StructWithDashTags{}), DefaultOptions(), nil, fields)
+	if _, ok := fields["-"]; !ok {
+		t.Errorf("expected field named \"-\" to be present")
+	}
+	if _, ok := fields["Skipped"]; ok {
+		t.Errorf("expected json:\"-\" field to be skipped")
+	}
+}
+
+func TestCoerceValueInline(t *testing.T) {
+	value := map[string]interface{}{"name": "Ada", "street": "1 Main St", "city": "London"}
+	expected := map[string]interface{}{
+		"name":    "Ada",
+		"Address": map[string]interface{}{"street": "1 Main St", "city": "London"},
+	}
+	result := CoerceValue(reflect.TypeOf(Customer{}), value, DefaultOptions())
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestCoerceValueLenient(t *testing.T) {
+	type Line struct {
+		Qty   int     `json:"qty"`
+		Price float64 `json:"price"`
+		Gift  bool    `json:"gift"`
+		SKU   string  `json:"sku"`
+	}
+	type Order struct {
+		Lines  []Line         `json:"lines"`
+		Counts map[string]int `json:"counts"`
+		Total  float64        `json:"total"`
+	}
+	input := map[string]interface{}{
+		"lines": []interface{}{
+			map[string]interface{}{"qty": json.Number("2"), "price": json.Number("1.5"), "gift": false, "sku": "A1"},
+			map[string]interface{}{"qty": " 3 ", "price": "2.5", "gift": json.Number("1"), "sku": json.Number("42")},
+			map[string]interface{}{"qty": json.Number("4.0"), "price": "NaN", "gift": "true", "sku": true},
+		},
+		"counts": map[string]interface{}{"a": "+7"},
+		"total":  "cheap",
+	}
+	expected := map[string]interface{}{
+		"lines": []interface{}{
+			map[string]interface{}{"qty": json.Number("2"), "price": json.Number("1.5"), "gift": false, "sku": "A1"},
+			map[string]interface{}{"qty": json.Number("3"), "price": json.Number("2.5"), "gift": true, "sku": "42"},
+			map[string]interface{}{"qty": json.Number("4"), "price": "NaN", "gift": true, "sku": "true"},
+		},
+		"counts": map[string]interface{}{"a": json.Number("7")},
+		"total":  "cheap",
+	}
+	var warnings []string
+	opts := DefaultOptions()
+	opts.LenientDecode = true
+	opts.WarningHandler = func(w Warning) { warnings = append(warnings, w.String()) }
+	result := CoerceValue(reflect.TypeOf(Order{}), input, opts)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+	sort.Strings(warnings)
+	expectedWarnings := []string{
+		`counts.a: coerced string "+7" to integer`,
+		`lines[1].gift: coerced number 1 to boolean`,
+		`lines[1].price: coerced string "2.5" to number`,
+		`lines[1].qty: coerced string " 3 " to integer`,
+		`lines[1].sku: coerced number 42 to string`,
+		`lines[2].gift: coerced string "true" to boolean`,
+		`lines[2].qty: coerced number 4.0 to integer`,
+		`lines[2].sku: coerced boolean true to string`,
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("expected warnings %v, got %v", expectedWarnings, warnings)
+	}
+}
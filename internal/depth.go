@@ -0,0 +1,47 @@
+package internal
+
+// GenerationReport describes the shape of a generated schema
+type GenerationReport struct {
+	// ObjectDepth is the deepest nesting of objects, structs and maps, the root object
+	// counting as 1. It is the nesting constrained by provider limits and by MaxDepth.
+	ObjectDepth int
+	// ArrayDepth is the largest number of arrays enclosing a value, e.g. 3 for [][][]string,
+	// bounded by MaxDepth separately from the objects.
+	ArrayDepth int
+}
+
+// MeasureNesting returns the deepest object and array nesting of a schema, descending into
+// properties, array items, map values and the variants of unions
+func MeasureNesting(s Schema) GenerationReport {
+	var report GenerationReport
+	measureNesting(s, 0, 0, &report)
+	return report
+}
+
+func measureNesting(s Schema, objects, arrays int, report *GenerationReport) {
+	props, hasProps := s["properties"].(Schema)
+	values, isMap := mapValues(s)
+	if hasProps || isMap {
+		objects++
+	}
+	report.ObjectDepth = max(report.ObjectDepth, objects)
+	report.ArrayDepth = max(report.ArrayDepth, arrays)
+	for _, prop := range props {
+		if sub, ok := prop.(Schema); ok {
+			measureNesting(sub, objects, arrays, report)
+		}
+	}
+	if isMap {
+		measureNesting(values, objects, arrays, report)
+	}
+	if items, ok := s["items"].(Schema); ok {
+		measureNesting(items, objects, arrays+1, report)
+	}
+	for _, keyword := range []string{"anyOf", "oneOf", "allOf"} {
+		if variants, ok := s[keyword].([]Schema); ok {
+			for _, variant := range variants {
+				measureNesting(variant, objects, arrays, report)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type nestedLists []nestedLists
+
+func TestArrayDepth(t *testing.T) {
+	type Grid struct {
+		Cells [][][]string `json:"cells"`
+	}
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		maxDepth int
+		exceeded bool
+	}{
+		{name: "arrays do not consume the object depth", input: reflect.TypeOf(Grid{}), maxDepth: 3},
+		{name: "arrays are bounded by the max depth", input: reflect.TypeOf(Grid{}), maxDepth: 2, exceeded: true},
+		{name: "recursive slice type", input: reflect.TypeOf(nestedLists{}), maxDepth: 50, exceeded: true},
+		{name: "objects inside arrays", input: reflect.TypeOf(Employee{}), maxDepth: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.MaxDepth = tt.maxDepth
+			_, err := JsonTypeOf(tt.input, visited, depth, opts)
+			var cycleErr *CircularRefError
+			if exceeded := errors.As(err, &cycleErr) && cycleErr.DepthExceeded; exceeded != tt.exceeded {
+				t.Errorf("expected depth exceeded %v, got %v", tt.exceeded, err)
+			}
+			if !tt.exceeded && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if opts.arrayDepth != 0 {
+				t.Errorf("expected the array depth to be restored, got %d", opts.arrayDepth)
+			}
+		})
+	}
+}
+
+func TestMeasureNesting(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   Schema
+		expected GenerationReport
+	}{
+		{"flat object", SimpleStructSchema, GenerationReport{ObjectDepth: 1}},
+		{"objects in arrays", EmployeeSchema, GenerationReport{ObjectDepth: 3, ArrayDepth: 1}},
+		{"maps count as objects", StructWithMapsSchema, GenerationReport{ObjectDepth: 3}},
+		{
+			name: "nested arrays",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"cells": Schema{"type": "array", "items": Schema{"type": "array", "items": Schema{
+						"anyOf": []Schema{{"type": "array", "items": Schema{"type": "string"}}, {"type": "null"}},
+					}}},
+				},
+			},
+			expected: GenerationReport{ObjectDepth: 1, ArrayDepth: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if report := MeasureNesting(tt.schema); report != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, report)
+			}
+		})
+	}
+}
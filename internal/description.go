@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ellipsis marks truncated descriptions, it counts toward the length limit
+const ellipsis = "…"
+
+// TruncateDescriptions shortens the descriptions longer than max characters,
+// ending them with an ellipsis and reporting a warning for each of them
+func TruncateDescriptions(schema Schema, max int, opts *Options) Schema {
+	return transformSchema(schema, "", func(s Schema, path string) Schema {
+		description, ok := s["description"].(string)
+		if !ok {
+			return s
+		}
+		length := utf8.RuneCountInString(description)
+		if length <= max {
+			return s
+		}
+		s["description"] = string([]rune(description)[:max-1]) + ellipsis
+		opts.Warn(path, "description truncated from %d to %d characters", length, max)
+		return s
+	})
+}
+
+// Humanize derives a description from a property name: createdAt, created_at and
+// created-at all become "Created at", acronyms keep their case (userID is "User ID")
+func Humanize(name string) string {
+	words := splitWords(strings.ReplaceAll(name, "-", "_"))
+	for i, word := range words {
+		if isAcronym(word) {
+			continue
+		}
+		word = strings.ToLower(word)
+		if i == 0 {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			word = string(runes)
+		}
+		words[i] = word
+	}
+	return strings.Join(words, " ")
+}
+
+// isAcronym reports whether a word has several letters, all of them capitals
+func isAcronym(word string) bool {
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters > 1
+}
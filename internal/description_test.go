@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTruncateDescriptions(t *testing.T) {
+	schema := Schema{
+		"type":        "object",
+		"description": "short",
+		"properties": Schema{
+			"name": Schema{"type": "string", "description": "The full legal name"},
+			"tags": Schema{
+				"type":  "array",
+				"items": Schema{"type": "string", "description": "ラベルの説明です"},
+			},
+		},
+	}
+	var warnings []Warning
+	opts := DefaultOptions()
+	opts.WarningHandler = func(w Warning) {
+		warnings = append(warnings, w)
+	}
+	result := TruncateDescriptions(schema, 6, opts)
+	expected := Schema{
+		"type":        "object",
+		"description": "short",
+		"properties": Schema{
+			"name": Schema{"type": "string", "description": "The f…"},
+			"tags": Schema{
+				"type":  "array",
+				"items": Schema{"type": "string", "description": "ラベルの説…"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+	expectedWarnings := []string{
+		"name: description truncated from 19 to 6 characters",
+		"tags[]: description truncated from 8 to 6 characters",
+	}
+	if len(warnings) != len(expectedWarnings) {
+		t.Fatalf("expected %d warnings, got %v", len(expectedWarnings), warnings)
+	}
+	for _, w := range warnings {
+		found := false
+		for _, e := range expectedWarnings {
+			found = found || w.String() == e
+		}
+		if !found {
+			t.Errorf("unexpected warning %s", w)
+		}
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"createdAt", "Created at"},
+		{"created_at", "Created at"},
+		{"created-at", "Created at"},
+		{"CreatedAt", "Created at"},
+		{"userID", "User ID"},
+		{"HTTPStatus", "HTTP status"},
+		{"line2", "Line2"},
+		{"x", "X"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Humanize(tt.name); got != tt.expected {
+			t.Errorf("Humanize(%q) = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestHumanizer(t *testing.T) {
+	type Event struct {
+		CreatedAt int64  `json:"createdAt"`
+		Title     string `json:"title" description:"Event title"`
+		OwnerID   *int   `json:"ownerID,omitempty"`
+		Internal  string `json:"internal"`
+	}
+	opts, visited, depth := getInputs()
+	opts.Humanizer = func(name string) string {
+		if name == "internal" {
+			return ""
+		}
+		return Humanize(name)
+	}
+	result, err := JsonTypeOf(reflect.TypeOf(Event{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props := result.(Schema)["properties"].(Schema)
+	expected := Schema{
+		"createdAt": Schema{"type": "integer", "description": "Created at"},
+		"title":     Schema{"type": "string", "description": "Event title"},
+		"ownerID":   Schema{"type": []string{"integer", "null"}, "description": "Owner ID"},
+		"internal":  Schema{"type": "string"},
+	}
+	if !reflect.DeepEqual(props, expected) {
+		t.Errorf("expected %+v, got %+v", expected, props)
+	}
+}
@@ -0,0 +1,262 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is a JSON Schema dialect a schema can be converted to
+type Dialect string
+
+const (
+	// DialectDraft202012 is JSON Schema 2020-12, the dialect of generated schemas
+	DialectDraft202012 Dialect = "2020-12"
+	// DialectDraft07 is JSON Schema draft-07: definitions and items arrays for tuples
+	DialectDraft07 Dialect = "draft-07"
+	// DialectOpenAPI30 is the OpenAPI 3.0 schema object: nullable instead of null types
+	DialectOpenAPI30 Dialect = "openapi-3.0"
+)
+
+// Valid reports whether d is one of the supported dialects
+func (d Dialect) Valid() bool {
+	switch d {
+	case DialectDraft202012, DialectDraft07, DialectOpenAPI30:
+		return true
+	}
+	return false
+}
+
+// ConvertDialect rewrites a schema written in any of the dialects to the target dialect.
+// OpenAPI 3.0 has no definitions, $refs are inlined with InlineRefs, and no tuples.
+func ConvertDialect(schema Schema, dialect Dialect, opts *Options) (Schema, error) {
+	schema = withSchemaTypes(schema)
+	if dialect == DialectOpenAPI30 {
+		inlined, err := InlineRefs(schema, opts)
+		if err != nil {
+			return nil, err
+		}
+		schema = inlined
+	}
+	var err error
+	result := transformSchema(schema, "", func(s Schema, path string) Schema {
+		if err != nil {
+			return s
+		}
+		switch dialect {
+		case DialectOpenAPI30:
+			if _, ok := s["prefixItems"]; ok {
+				err = fmt.Errorf("%s: tuples are not supported by OpenAPI 3.0", displayPath(path))
+				return s
+			}
+			if _, ok := s["items"].([]Schema); ok {
+				err = fmt.Errorf("%s: tuples are not supported by OpenAPI 3.0", displayPath(path))
+				return s
+			}
+			// OpenAPI 3.0 has neither keyword, the constraints are lost
+			for _, keyword := range []string{"contains", "dependentRequired", "dependencies"} {
+				if _, ok := s[keyword]; ok {
+					delete(s, keyword)
+					opts.Warn(path, "%s is not supported by OpenAPI 3.0 and was removed", keyword)
+				}
+			}
+			return toNullableKeyword(s)
+		case DialectDraft07:
+			s = fromNullableKeyword(s)
+			renameDefs(s, "$defs", "definitions")
+			renameKeyword(s, "dependentRequired", "dependencies")
+			if prefix, ok := s["prefixItems"]; ok {
+				delete(s, "prefixItems")
+				if rest, ok := s["items"]; ok {
+					s["additionalItems"] = rest
+				}
+				s["items"] = prefix
+			}
+		default:
+			s = fromNullableKeyword(s)
+			renameDefs(s, "definitions", "$defs")
+			dependentRequired(s)
+			if tuple, ok := s["items"].([]Schema); ok {
+				s["prefixItems"] = tuple
+				delete(s, "items")
+				if rest, ok := s["additionalItems"]; ok {
+					s["items"] = rest
+					delete(s, "additionalItems")
+				}
+			}
+		}
+		return s
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// renameDefs moves the definitions keyword and rewrites the $refs pointing into it
+func renameDefs(s Schema, from, to string) {
+	if defs, ok := s[from]; ok {
+		delete(s, from)
+		s[to] = defs
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		uri, fragment, found := strings.Cut(ref, "#/"+from+"/")
+		if found {
+			s["$ref"] = uri + "#/" + to + "/" + fragment
+		}
+	}
+}
+
+// renameKeyword moves a keyword, merging its entries into an existing target
+func renameKeyword(s Schema, from, to string) {
+	entries, ok := s[from].(Schema)
+	if !ok {
+		return
+	}
+	delete(s, from)
+	merged, _ := s[to].(Schema)
+	out := make(Schema, len(entries)+len(merged))
+	for k, v := range merged {
+		out[k] = v
+	}
+	for k, v := range entries {
+		out[k] = v
+	}
+	s[to] = out
+}
+
+// dependentRequired moves the property lists of the draft-07 dependencies keyword to
+// dependentRequired, schema dependencies are kept
+func dependentRequired(s Schema) {
+	dependencies, ok := s["dependencies"].(Schema)
+	if !ok {
+		return
+	}
+	lists := make(Schema)
+	if existing, ok := s["dependentRequired"].(Schema); ok {
+		for name, v := range existing {
+			lists[name] = v
+		}
+	}
+	rest := make(Schema)
+	for name, v := range dependencies {
+		if names, ok := dependentNames(v); ok {
+			lists[name] = names
+		} else {
+			rest[name] = v
+		}
+	}
+	if len(rest) == len(dependencies) {
+		return
+	}
+	delete(s, "dependencies")
+	if len(rest) > 0 {
+		s["dependencies"] = rest
+	}
+	s["dependentRequired"] = lists
+}
+
+// toNullableKeyword replaces null types and null anyOf variants with nullable: true
+func toNullableKeyword(s Schema) Schema {
+	if types, ok := s["type"].([]string); ok {
+		var rest []string
+		for _, t := range types {
+			if t != "null" {
+				rest = append(rest, t)
+			}
+		}
+		if len(rest) < len(types) {
+			s["nullable"] = true
+		}
+		switch len(rest) {
+		case 0:
+			delete(s, "type")
+		case 1:
+			s["type"] = rest[0]
+		default:
+			delete(s, "type")
+			variants := make([]Schema, len(rest))
+			for i, t := range rest {
+				variants[i] = Schema{"type": t}
+			}
+			s["anyOf"] = variants
+		}
+	}
+	variants, ok := s["anyOf"].([]Schema)
+	if !ok {
+		return s
+	}
+	var rest []Schema
+	for _, variant := range variants {
+		if !(len(variant) == 1 && variant["type"] == "null") {
+			rest = append(rest, variant)
+		}
+	}
+	if len(rest) == len(variants) {
+		return s
+	}
+	s["nullable"] = true
+	if len(rest) == 0 {
+		delete(s, "anyOf")
+		return s
+	}
+	if len(rest) > 1 {
+		s["anyOf"] = rest
+		return s
+	}
+	delete(s, "anyOf")
+	merged := make(Schema, len(s)+len(rest[0]))
+	for k, v := range rest[0] {
+		merged[k] = v
+	}
+	for k, v := range s {
+		merged[k] = v
+	}
+	return merged
+}
+
+// fromNullableKeyword replaces nullable: true with a null type, or an anyOf null variant
+// for objects, arrays and schemas without a single type
+func fromNullableKeyword(s Schema) Schema {
+	nullable, ok := s["nullable"].(bool)
+	if !ok {
+		return s
+	}
+	delete(s, "nullable")
+	if !nullable {
+		return s
+	}
+	// primitives use a type array and other schemas anyOf, like NullableMixed
+	if t, ok := s["type"].(string); ok && t != "object" && t != "array" {
+		s["type"] = []string{t, "null"}
+		if values, ok := s["enum"].([]interface{}); ok && !containsNil(values) {
+			s["enum"] = append(values[:len(values):len(values)], nil)
+		}
+		return s
+	}
+	if variants, ok := s["anyOf"].([]Schema); ok && len(s) == 1 {
+		s["anyOf"] = append(variants[:len(variants):len(variants)], Schema{"type": "null"})
+		return s
+	}
+	// the allOf OpenAPI 3.0 wraps a nullable $ref in
+	if members, ok := s["allOf"].([]Schema); ok && len(members) == 1 && len(s) == 1 {
+		return Schema{"anyOf": []Schema{members[0], {"type": "null"}}}
+	}
+	return Schema{"anyOf": []Schema{s, {"type": "null"}}}
+}
+
+func containsNil(values []interface{}) bool {
+	for _, v := range values {
+		if v == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// displayPath names the root schema in messages
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestConvertDialect(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   Schema
+		dialect  Dialect
+		expected Schema
+	}{
+		{
+			name: "type array to nullable",
+			schema: Schema{"type": "object", "properties": Schema{
+				"status": Schema{"type": []string{"string", "null"}, "enum": []interface{}{"open", nil}},
+				"home": Schema{"anyOf": []Schema{
+					{"type": "object", "properties": Schema{"city": Schema{"type": "string"}}},
+					{"type": "null"},
+				}, "description": "home address"},
+				"id": Schema{"anyOf": []Schema{{"type": "string"}, {"type": "integer"}, {"type": "null"}}},
+			}},
+			dialect: DialectOpenAPI30,
+			expected: Schema{"type": "object", "properties": Schema{
+				"status": Schema{"type": "string", "nullable": true, "enum": []interface{}{"open", nil}},
+				"home": Schema{
+					"type":        "object",
+					"properties":  Schema{"city": Schema{"type": "string"}},
+					"nullable":    true,
+					"description": "home address",
+				},
+				"id": Schema{"anyOf": []Schema{{"type": "string"}, {"type": "integer"}}, "nullable": true},
+			}},
+		},
+		{
+			name: "refs are inlined for OpenAPI",
+			schema: Schema{
+				"type":        "object",
+				"properties":  Schema{"pet": Schema{"$ref": "#/definitions/Pet"}},
+				"definitions": Schema{"Pet": Schema{"type": []string{"string", "null"}}},
+			},
+			dialect: DialectOpenAPI30,
+			expected: Schema{
+				"type":       "object",
+				"properties": Schema{"pet": Schema{"type": "string", "nullable": true}},
+			},
+		},
+		{
+			name: "nullable to 2020-12",
+			schema: Schema{"type": "object", "properties": Schema{
+				"status": Schema{"type": "string", "nullable": true, "enum": []interface{}{"open"}},
+				"home":   Schema{"allOf": []Schema{{"$ref": "#/definitions/Address"}}, "nullable": true},
+				"note":   Schema{"type": "string", "nullable": false},
+			}, "definitions": Schema{"Address": Schema{"type": "object"}}},
+			dialect: DialectDraft202012,
+			expected: Schema{"type": "object", "properties": Schema{
+				"status": Schema{"type": []string{"string", "null"}, "enum": []interface{}{"open", nil}},
+				"home":   Schema{"anyOf": []Schema{{"$ref": "#/$defs/Address"}, {"type": "null"}}},
+				"note":   Schema{"type": "string"},
+			}, "$defs": Schema{"Address": Schema{"type": "object"}}},
+		},
+		{
+			name: "2020-12 to draft-07",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"point": Schema{"type": "array", "prefixItems": []Schema{{"type": "number"}, {"type": "number"}}, "items": false},
+					"owner": Schema{"$ref": "#/$defs/Person"},
+				},
+				"$defs": Schema{"Person": Schema{"type": "object", "properties": Schema{"friend": Schema{"$ref": "#/$defs/Person"}}}},
+			},
+			dialect: DialectDraft07,
+			expected: Schema{
+				"type": "object",
+				"properties": Schema{
+					"point": Schema{"type": "array", "items": []Schema{{"type": "number"}, {"type": "number"}}, "additionalItems": false},
+					"owner": Schema{"$ref": "#/definitions/Person"},
+				},
+				"definitions": Schema{"Person": Schema{"type": "object", "properties": Schema{"friend": Schema{"$ref": "#/definitions/Person"}}}},
+			},
+		},
+		{
+			name:     "draft-07 tuples to 2020-12",
+			schema:   Schema{"type": "array", "items": []Schema{{"type": "string"}}, "additionalItems": Schema{"type": "integer"}},
+			dialect:  DialectDraft202012,
+			expected: Schema{"type": "array", "prefixItems": []Schema{{"type": "string"}}, "items": Schema{"type": "integer"}},
+		},
+		{
+			name:     "dependentRequired to draft-07",
+			schema:   Schema{"type": "object", "dependentRequired": Schema{"street": []string{"city"}}, "dependencies": Schema{"zip": Schema{"required": []string{"city"}}}},
+			dialect:  DialectDraft07,
+			expected: Schema{"type": "object", "dependencies": Schema{"street": []string{"city"}, "zip": Schema{"required": []string{"city"}}}},
+		},
+		{
+			name:     "draft-07 dependencies to 2020-12",
+			schema:   Schema{"type": "object", "dependencies": Schema{"street": []string{"city"}, "zip": Schema{"required": []string{"city"}}}},
+			dialect:  DialectDraft202012,
+			expected: Schema{"type": "object", "dependentRequired": Schema{"street": []string{"city"}}, "dependencies": Schema{"zip": Schema{"required": []string{"city"}}}},
+		},
+		{
+			name:     "contains is traversed",
+			schema:   Schema{"type": "array", "contains": Schema{"type": "string", "nullable": true}},
+			dialect:  DialectDraft202012,
+			expected: Schema{"type": "array", "contains": Schema{"type": []string{"string", "null"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ConvertDialect(tt.schema, tt.dialect, DefaultOptions())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestConvertDialect_OpenAPIDropsKeywords(t *testing.T) {
+	var warnings []string
+	opts := DefaultOptions()
+	opts.WarningHandler = func(w Warning) { warnings = append(warnings, w.String()) }
+	result, err := ConvertDialect(ShipmentSchema, DialectOpenAPI30, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := result["dependentRequired"]; ok {
+		t.Errorf("expected dependentRequired to be removed, got %+v", result)
+	}
+	expected := []string{
+		"(root): dependentRequired is not supported by OpenAPI 3.0 and was removed",
+		"codes: contains is not supported by OpenAPI 3.0 and was removed",
+		"tags: contains is not supported by OpenAPI 3.0 and was removed",
+	}
+	sort.Strings(warnings)
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected warnings %v, got %v", expected, warnings)
+	}
+}
+
+func TestConvertDialect_RoundTrip(t *testing.T) {
+	openapi, err := ConvertDialect(SurveySchema, DialectOpenAPI30, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	back, err := ConvertDialect(openapi, DialectDraft202012, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(back, SurveySchema) {
+		t.Errorf("expected the round trip to restore %+v, got %+v", SurveySchema, back)
+	}
+}
+
+func TestConvertDialect_DecodedJSON(t *testing.T) {
+	var schema Schema
+	data := `{"type":"object","properties":{"tags":{"type":"array","items":[{"type":"string"}]},"n":{"type":["integer","null"]}},"required":["tags","n"]}`
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ConvertDialect(schema, DialectDraft202012, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Schema{
+		"type": "object",
+		"properties": Schema{
+			"tags": Schema{"type": "array", "prefixItems": []Schema{{"type": "string"}}},
+			"n":    Schema{"type": []string{"integer", "null"}},
+		},
+		"required": []string{"tags", "n"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+
+	_, err = ConvertDialect(schema, DialectOpenAPI30, DefaultOptions())
+	if err == nil || err.Error() != "tags: tuples are not supported by OpenAPI 3.0" {
+		t.Errorf("expected tuple error, got %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeKind classifies a difference between two schemas
+type ChangeKind string
+
+const (
+	// ChangeAdded is a property that only exists in the new schema
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved is a property that only exists in the old schema
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified is a keyword of a property that differs between the schemas
+	ChangeModified ChangeKind = "changed"
+)
+
+// SchemaChange is a difference between two versions of a schema
+type SchemaChange struct {
+	// Path of the property, e.g. "lines[].qty", empty for the root
+	Path string
+	Kind ChangeKind
+	// Keyword that changed, e.g. "type" or "required", empty for added and removed properties
+	Keyword string
+	// Old and New are the values of the keyword, or the schema of the added or removed property
+	Old interface{}
+	New interface{}
+	// Breaking is set when a response valid under the old schema may be rejected by the new one
+	Breaking bool
+	// Message describes the change, e.g. "type changed from number to integer"
+	Message string
+}
+
+// String renders the change as "path: message"
+func (c SchemaChange) String() string {
+	return displayPath(c.Path) + ": " + c.Message
+}
+
+// diffKeywords are the constraints of the markdown constraints column and $ref,
+// adding or changing them may reject old responses
+var diffKeywords = append(constraintKeywords[:len(constraintKeywords):len(constraintKeywords)], "$ref")
+
+// DiffSchemas lists the differences between two schemas, property by property, sorted by path.
+// Nullable properties are compared without their null variant, a change of nullability is
+// reported with the "nullable" keyword.
+func DiffSchemas(oldSchema, newSchema Schema) []SchemaChange {
+	var changes []SchemaChange
+	diffSchema(normalizeForDiff(oldSchema), normalizeForDiff(newSchema), "", &changes)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// normalizeForDiff gives both schemas the same Go types through a JSON round trip,
+// so that generated and decoded schemas compare equal
+func normalizeForDiff(s Schema) Schema {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return s
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return s
+	}
+	return withSchemaTypes(decoded)
+}
+
+func diffSchema(oldSchema, newSchema Schema, path string, changes *[]SchemaChange) {
+	report := func(keyword string, oldValue, newValue interface{}, breaking bool, format string, args ...interface{}) {
+		*changes = append(*changes, SchemaChange{
+			Path:     path,
+			Kind:     ChangeModified,
+			Keyword:  keyword,
+			Old:      oldValue,
+			New:      newValue,
+			Breaking: breaking,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	oldInner, oldNullable := unwrapNullable(oldSchema)
+	newInner, newNullable := unwrapNullable(newSchema)
+	if oldNullable != newNullable {
+		if newNullable {
+			report("nullable", false, true, false, "became nullable")
+		} else {
+			report("nullable", true, false, true, "is no longer nullable")
+		}
+	}
+
+	oldTypes, newTypes := schemaTypes(oldInner), schemaTypes(newInner)
+	if !reflect.DeepEqual(oldTypes, newTypes) {
+		report("type", oldInner["type"], newInner["type"], !containsAll(newTypes, oldTypes),
+			"type changed from %s to %s", typeLabel(oldTypes), typeLabel(newTypes))
+		// the content of a different type is not comparable
+		return
+	}
+
+	diffEnum(oldInner, newInner, report)
+	for _, keyword := range diffKeywords {
+		if keyword == "enum" {
+			continue
+		}
+		oldValue, hadValue := oldInner[keyword]
+		newValue, hasValue := newInner[keyword]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		switch {
+		case !hasValue:
+			report(keyword, oldValue, nil, false, "%s %s removed", keyword, jsonLiteral(oldValue))
+		case !hadValue:
+			report(keyword, nil, newValue, true, "%s %s added", keyword, jsonLiteral(newValue))
+		default:
+			report(keyword, oldValue, newValue, true, "%s changed from %s to %s", keyword, jsonLiteral(oldValue), jsonLiteral(newValue))
+		}
+	}
+	for _, keyword := range []string{"title", "description"} {
+		if oldValue, newValue := oldInner[keyword], newInner[keyword]; !reflect.DeepEqual(oldValue, newValue) {
+			report(keyword, oldValue, newValue, false, "%s changed from %s to %s", keyword, annotationLiteral(oldValue), annotationLiteral(newValue))
+		}
+	}
+	if oldVariants, newVariants := oldInner["anyOf"], newInner["anyOf"]; !reflect.DeepEqual(oldVariants, newVariants) {
+		report("anyOf", oldVariants, newVariants, true, "union variants changed")
+	}
+
+	diffProperties(oldInner, newInner, path, changes)
+	if oldItems, ok := oldInner["items"].(Schema); ok {
+		if newItems, ok := newInner["items"].(Schema); ok {
+			diffSchema(oldItems, newItems, path+"[]", changes)
+		}
+	}
+	oldAdditional, newAdditional := oldInner["additionalProperties"], newInner["additionalProperties"]
+	oldValues, oldIsSchema := oldAdditional.(Schema)
+	newValues, newIsSchema := newAdditional.(Schema)
+	switch {
+	case oldIsSchema && newIsSchema:
+		diffSchema(oldValues, newValues, path+"{}", changes)
+	case !reflect.DeepEqual(oldAdditional, newAdditional):
+		// closing an object rejects the properties it used to accept
+		report("additionalProperties", oldAdditional, newAdditional, newAdditional == false,
+			"additionalProperties changed from %s to %s", jsonLiteral(oldAdditional), jsonLiteral(newAdditional))
+	}
+	for _, keyword := range []string{"$defs", "definitions"} {
+		oldDefs, _ := oldInner[keyword].(Schema)
+		newDefs, _ := newInner[keyword].(Schema)
+		for _, name := range unionKeys(oldDefs, newDefs) {
+			oldDef, hadDef := oldDefs[name].(Schema)
+			newDef, hasDef := newDefs[name].(Schema)
+			defPath := joinPath(keyword, name)
+			switch {
+			case !hasDef:
+				*changes = append(*changes, SchemaChange{Path: defPath, Kind: ChangeRemoved, Old: oldDef, Breaking: true, Message: "definition removed"})
+			case !hadDef:
+				*changes = append(*changes, SchemaChange{Path: defPath, Kind: ChangeAdded, New: newDef, Message: "definition added"})
+			default:
+				diffSchema(oldDef, newDef, defPath, changes)
+			}
+		}
+	}
+}
+
+// diffProperties compares the properties of two object schemas and their required status
+func diffProperties(oldSchema, newSchema Schema, path string, changes *[]SchemaChange) {
+	oldProps, _ := oldSchema["properties"].(Schema)
+	newProps, _ := newSchema["properties"].(Schema)
+	oldRequired, _ := oldSchema["required"].([]string)
+	newRequired, _ := newSchema["required"].([]string)
+	for _, name := range unionKeys(oldProps, newProps) {
+		propPath := joinPath(path, name)
+		oldProp, hadProp := oldProps[name].(Schema)
+		newProp, hasProp := newProps[name].(Schema)
+		wasRequired, isRequired := containsString(oldRequired, name), containsString(newRequired, name)
+		switch {
+		case !hasProp:
+			*changes = append(*changes, SchemaChange{
+				Path: propPath, Kind: ChangeRemoved, Old: oldProp, Breaking: true,
+				Message: "removed " + requiredLabel(wasRequired) + " property",
+			})
+		case !hadProp:
+			*changes = append(*changes, SchemaChange{
+				Path: propPath, Kind: ChangeAdded, New: newProp, Breaking: isRequired,
+				Message: fmt.Sprintf("added %s property (%s)", requiredLabel(isRequired), propertyTypeLabel(newProp)),
+			})
+		default:
+			if wasRequired != isRequired {
+				*changes = append(*changes, SchemaChange{
+					Path: propPath, Kind: ChangeModified, Keyword: "required", Old: wasRequired, New: isRequired,
+					Breaking: isRequired, Message: "became " + requiredLabel(isRequired),
+				})
+			}
+			diffSchema(oldProp, newProp, propPath, changes)
+		}
+	}
+}
+
+// diffEnum reports removed and added enum values, removals reject old responses
+func diffEnum(oldSchema, newSchema Schema,
+	report func(keyword string, oldValue, newValue interface{}, breaking bool, format string, args ...interface{})) {
+	oldValues, hadEnum := oldSchema["enum"].([]interface{})
+	newValues, hasEnum := newSchema["enum"].([]interface{})
+	if !hadEnum && !hasEnum {
+		return
+	}
+	if !hasEnum {
+		report("enum", oldValues, nil, false, "enum removed")
+		return
+	}
+	if !hadEnum {
+		report("enum", nil, newValues, true, "enum %s added", literalList(newValues))
+		return
+	}
+	removed, added := enumDifference(oldValues, newValues), enumDifference(newValues, oldValues)
+	var parts []string
+	if len(removed) > 0 {
+		parts = append(parts, "values "+literalList(removed)+" removed")
+	}
+	if len(added) > 0 {
+		parts = append(parts, "values "+literalList(added)+" added")
+	}
+	if len(parts) > 0 {
+		report("enum", oldValues, newValues, len(removed) > 0, "enum %s", strings.Join(parts, ", "))
+	}
+}
+
+// enumDifference returns the values of a missing from b
+func enumDifference(a, b []interface{}) []interface{} {
+	var missing []interface{}
+	for _, v := range a {
+		found := false
+		for _, w := range b {
+			if reflect.DeepEqual(v, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// schemaTypes returns the sorted types of a schema
+func schemaTypes(s Schema) []string {
+	switch t := s["type"].(type) {
+	case string:
+		return []string{t}
+	case []string:
+		types := append([]string(nil), t...)
+		sort.Strings(types)
+		return types
+	}
+	return nil
+}
+
+func containsAll(set, values []string) bool {
+	for _, v := range values {
+		if !containsString(set, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func typeLabel(types []string) string {
+	if len(types) == 0 {
+		return "any"
+	}
+	return strings.Join(types, " or ")
+}
+
+// propertyTypeLabel names the type of a property, e.g. "string" or "nullable array of string"
+func propertyTypeLabel(s Schema) string {
+	inner, nullable := unwrapNullable(s)
+	label := TypeLabel(inner)
+	if nullable {
+		return "nullable " + label
+	}
+	return label
+}
+
+func requiredLabel(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
+
+func annotationLiteral(v interface{}) string {
+	if v == nil {
+		return "none"
+	}
+	return jsonLiteral(v)
+}
+
+func literalList(values []interface{}) string {
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = jsonLiteral(v)
+	}
+	return strings.Join(literals, ", ")
+}
+
+// unionKeys returns the keys of both schemas, sorted
+func unionKeys(a, b Schema) []string {
+	keys := sortedKeys(a)
+	for _, k := range sortedKeys(b) {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	oldSchema, err := runJsonTypeOf(reflect.TypeOf(OrderV1{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	newSchema, err := runJsonTypeOf(reflect.TypeOf(OrderV2{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"currency: added required property (string)",
+		"lines[].note: added required property (nullable string)",
+		"notes: removed required property",
+		"status: description changed from \"Order status\" to \"Current order status\"",
+		"total: type changed from number to integer",
+	}
+	changes := DiffSchemas(oldSchema.(Schema), newSchema.(Schema))
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("DiffSchemas() =\n%v\nwant\n%v", got, expected)
+	}
+	for _, change := range changes {
+		if change.Breaking == (change.Keyword == "description") {
+			t.Errorf("unexpected breaking=%v for %s", change.Breaking, change)
+		}
+	}
+
+	if changes := DiffSchemas(oldSchema.(Schema), oldSchema.(Schema)); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+func TestDiffSchemas_Keywords(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldSchema Schema
+		newSchema Schema
+		expected  string
+		breaking  bool
+	}{
+		{
+			name:      "widened type",
+			oldSchema: Schema{"type": "integer"},
+			newSchema: Schema{"type": []string{"integer", "string"}},
+			expected:  "(root): type changed from integer to integer or string",
+		},
+		{
+			name:      "became nullable",
+			oldSchema: Schema{"type": "string"},
+			newSchema: Schema{"type": []string{"string", "null"}},
+			expected:  "(root): became nullable",
+		},
+		{
+			name:      "no longer nullable",
+			oldSchema: Schema{"anyOf": []Schema{{"type": "object"}, {"type": "null"}}},
+			newSchema: Schema{"type": "object"},
+			expected:  "(root): is no longer nullable",
+			breaking:  true,
+		},
+		{
+			name:      "enum values",
+			oldSchema: Schema{"type": "string", "enum": []interface{}{"a", "b"}},
+			newSchema: Schema{"type": "string", "enum": []interface{}{"a", "c"}},
+			expected:  `(root): enum values "b" removed, values "c" added`,
+			breaking:  true,
+		},
+		{
+			name:      "enum values added",
+			oldSchema: Schema{"type": "string", "enum": []interface{}{"a"}},
+			newSchema: Schema{"type": "string", "enum": []interface{}{"a", "b"}},
+			expected:  `(root): enum values "b" added`,
+		},
+		{
+			name:      "constraint added",
+			oldSchema: Schema{"type": "string"},
+			newSchema: Schema{"type": "string", "pattern": "^[a-z]+$"},
+			expected:  `(root): pattern "^[a-z]+$" added`,
+			breaking:  true,
+		},
+		{
+			name:      "constraint removed",
+			oldSchema: Schema{"type": "integer", "maximum": 10},
+			newSchema: Schema{"type": "integer"},
+			expected:  "(root): maximum 10 removed",
+		},
+		{
+			name:      "map values",
+			oldSchema: Schema{"type": "object", "additionalProperties": Schema{"type": "string"}},
+			newSchema: Schema{"type": "object", "additionalProperties": Schema{"type": "integer"}},
+			expected:  "{}: type changed from string to integer",
+			breaking:  true,
+		},
+		{
+			name:      "closed object",
+			oldSchema: Schema{"type": "object", "additionalProperties": true},
+			newSchema: Schema{"type": "object", "additionalProperties": false},
+			expected:  "(root): additionalProperties changed from true to false",
+			breaking:  true,
+		},
+		{
+			name:      "became optional",
+			oldSchema: Schema{"type": "object", "properties": Schema{"a": Schema{"type": "string"}}, "required": []string{"a"}},
+			newSchema: Schema{"type": "object", "properties": Schema{"a": Schema{"type": "string"}}},
+			expected:  "a: became optional",
+		},
+		{
+			name:      "definitions",
+			oldSchema: Schema{"$defs": Schema{"Node": Schema{"type": "object"}}},
+			newSchema: Schema{"$defs": Schema{"Node": Schema{"type": "array"}}},
+			expected:  "$defs.Node: type changed from object to array",
+			breaking:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := DiffSchemas(tt.oldSchema, tt.newSchema)
+			if len(changes) != 1 {
+				t.Fatalf("expected a single change, got %v", changes)
+			}
+			if changes[0].String() != tt.expected || changes[0].Breaking != tt.breaking {
+				t.Errorf("got %q breaking=%v, want %q breaking=%v", changes[0], changes[0].Breaking, tt.expected, tt.breaking)
+			}
+		})
+	}
+}
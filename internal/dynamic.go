@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitPropertyPath splits a property path into the path of its object and its name,
+// e.g. "items[].extra" into "items[]" and "extra"
+func splitPropertyPath(path string) (parent, name string) {
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return "", path
+}
+
+// objectAt returns the object schema at path, without its null variant
+func objectAt(s Schema, path string) (Schema, error) {
+	object, ok := s.At(path)
+	if ok {
+		object, _ = unwrapNullable(object)
+	}
+	if _, isObject := object["properties"].(Schema); !isObject {
+		if path == "" {
+			return nil, fmt.Errorf("the root is not an object schema")
+		}
+		return nil, fmt.Errorf("%s is not an object schema", displayPath(path))
+	}
+	return object, nil
+}
+
+// AddProperty adds the property at path to its object, listed last in required as strict
+// mode requires. The objects along the path are modified in place.
+func AddProperty(s Schema, path string, prop Schema) error {
+	parent, name := splitPropertyPath(path)
+	if name == "" {
+		return fmt.Errorf("invalid property path %q", path)
+	}
+	object, err := objectAt(s, parent)
+	if err != nil {
+		return err
+	}
+	props := object["properties"].(Schema)
+	if _, exists := props[name]; exists {
+		return fmt.Errorf("property %s already exists", displayPath(path))
+	}
+	props[name] = prop
+	required, _ := object["required"].([]string)
+	object["required"] = append(required[:len(required):len(required)], name)
+	return nil
+}
+
+// RemoveProperty removes the property at path from its object and from its required list.
+// The objects along the path are modified in place.
+func RemoveProperty(s Schema, path string) error {
+	parent, name := splitPropertyPath(path)
+	object, err := objectAt(s, parent)
+	if err != nil {
+		return err
+	}
+	props := object["properties"].(Schema)
+	if _, exists := props[name]; !exists {
+		return fmt.Errorf("property %s does not exist", displayPath(path))
+	}
+	delete(props, name)
+	if required, ok := object["required"].([]string); ok {
+		kept := make([]string, 0, len(required))
+		for _, r := range required {
+			if r != name {
+				kept = append(kept, r)
+			}
+		}
+		if len(kept) > 0 {
+			object["required"] = kept
+		} else {
+			delete(object, "required")
+		}
+	}
+	return nil
+}
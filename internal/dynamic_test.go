@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddAndRemoveProperty(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"title": Schema{"type": "string"},
+			"lines": Schema{"type": "array", "items": Schema{
+				"type":                 "object",
+				"properties":           Schema{"qty": Schema{"type": "integer"}},
+				"required":             []string{"qty"},
+				"additionalProperties": false,
+			}},
+			"vendor": Schema{"anyOf": []Schema{{
+				"type":                 "object",
+				"properties":           Schema{"name": Schema{"type": "string"}},
+				"required":             []string{"name"},
+				"additionalProperties": false,
+			}, {"type": "null"}}},
+		},
+		"required":             []string{"title", "lines", "vendor"},
+		"additionalProperties": false,
+	}
+	required := schema["required"].([]string)
+	for _, path := range []string{"notes", "lines[].discount", "vendor.vat"} {
+		if err := AddProperty(schema, path, Schema{"type": "string"}); err != nil {
+			t.Fatalf("adding %s: unexpected error: %v", path, err)
+		}
+		if prop, ok := schema.At(path); !ok || prop["type"] != "string" {
+			t.Errorf("expected %s to be added, got %v", path, prop)
+		}
+	}
+	if !reflect.DeepEqual(schema["required"], []string{"title", "lines", "vendor", "notes"}) {
+		t.Errorf("unexpected required %v", schema["required"])
+	}
+	if !reflect.DeepEqual(required, []string{"title", "lines", "vendor"}) {
+		t.Errorf("expected the previous required list to stay unchanged, got %v", required)
+	}
+	lines, _ := schema.At("lines[]")
+	if !reflect.DeepEqual(lines["required"], []string{"qty", "discount"}) {
+		t.Errorf("unexpected required %v", lines["required"])
+	}
+
+	if err := RemoveProperty(schema, "lines[].qty"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := schema.At("lines[].qty"); ok || !reflect.DeepEqual(lines["required"], []string{"discount"}) {
+		t.Errorf("expected qty to be removed, got %v", lines)
+	}
+	if err := RemoveProperty(schema, "lines[].discount"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := lines["required"]; ok {
+		t.Errorf("expected the empty required list to be removed, got %v", lines["required"])
+	}
+
+	errors := []struct {
+		name string
+		err  error
+	}{
+		{"existing property", AddProperty(schema, "title", Schema{"type": "string"})},
+		{"not an object", AddProperty(schema, "title.first", Schema{"type": "string"})},
+		{"missing parent", AddProperty(schema, "missing.name", Schema{"type": "string"})},
+		{"empty name", AddProperty(schema, "vendor.", Schema{"type": "string"})},
+		{"missing property", RemoveProperty(schema, "missing")},
+	}
+	for _, e := range errors {
+		if e.err == nil {
+			t.Errorf("%s: expected an error", e.name)
+		}
+	}
+}
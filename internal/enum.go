@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// enum registry keyed by the named Go type, values are stored
+// as their underlying string/int64/uint64 so they marshal as plain JSON
+var (
+	enumMu       sync.RWMutex
+	enumRegistry = make(map[reflect.Type][]interface{})
+)
+
+// RegisterEnum records the allowed values of a named type.
+// Registering the same type again replaces its values.
+func RegisterEnum(t reflect.Type, values []interface{}) {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+	enumRegistry[t] = values
+}
+
+// enumValues returns the registered values of a type
+func enumValues(t reflect.Type) ([]interface{}, bool) {
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+	values, ok := enumRegistry[t]
+	return values, ok
+}
+
+// maximum value scanned when detecting stringer generated enums
+const maxStringerScan = 256
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// lookupEnum returns the enum values of a type, registered enums
+// take precedence over the autodetected ones
+func lookupEnum(t reflect.Type, opts *Options) ([]interface{}, bool) {
+	if values, ok := enumValues(t); ok {
+		return values, true
+	}
+	if opts.DetectEnums {
+		return detectEnum(t)
+	}
+	return nil, false
+}
+
+// detectEnum recognizes types generated by enumer or stringer.
+// A Values() method returning the type's values is used first (enumer),
+// then integer types implementing fmt.Stringer are scanned for names
+// which don't follow stringer's "Type(N)" out of range pattern.
+// Only types printing that pattern for some scanned value are stringer
+// output, other Stringers such as time.Duration or os.FileMode name
+// every value and are not enums.
+// The values are returned the way encoding/json marshals them.
+func detectEnum(t reflect.Type) (values []interface{}, ok bool) {
+	if t.Name() == "" {
+		return nil, false
+	}
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil, false
+	}
+	// user methods may panic on unexpected input, treat it as not an enum
+	defer func() {
+		if r := recover(); r != nil {
+			values, ok = nil, false
+		}
+	}()
+	if method, found := t.MethodByName("Values"); found {
+		mt := method.Type
+		if mt.NumIn() == 1 && mt.NumOut() == 1 &&
+			mt.Out(0).Kind() == reflect.Slice && mt.Out(0).Elem() == t {
+			result := method.Func.Call([]reflect.Value{reflect.Zero(t)})[0]
+			for i := 0; i < result.Len(); i++ {
+				v, err := marshaledValue(result.Index(i))
+				if err != nil {
+					return nil, false
+				}
+				values = append(values, v)
+			}
+			return values, len(values) > 0
+		}
+	}
+	if t.Kind() == reflect.String || !t.Implements(stringerType) {
+		return nil, false
+	}
+	outOfRange := false
+	for i := 0; i < maxStringerScan; i++ {
+		v := reflect.New(t).Elem()
+		// narrow types such as int8 end the scan before their values wrap around
+		if v.CanInt() {
+			if v.OverflowInt(int64(i)) {
+				break
+			}
+			v.SetInt(int64(i))
+		} else {
+			if v.OverflowUint(uint64(i)) {
+				break
+			}
+			v.SetUint(uint64(i))
+		}
+		name := v.Interface().(fmt.Stringer).String()
+		if name == fmt.Sprintf("%s(%d)", t.Name(), i) {
+			outOfRange = true
+			continue
+		}
+		marshaled, err := marshaledValue(v)
+		if err != nil {
+			return nil, false
+		}
+		values = append(values, marshaled)
+	}
+	return values, outOfRange && len(values) > 0
+}
+
+// marshaledValue encodes a value with encoding/json and decodes it back
+// into a plain string/int64/uint64 so custom MarshalJSON methods are honored
+func marshaledValue(v reflect.Value) (interface{}, error) {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if n, ok := decoded.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		return strconv.ParseUint(n.String(), 10, 64)
+	}
+	return decoded, nil
+}
+
+// enumSchema builds the schema of an enum type, the JSON type
+// follows the values since custom marshalers may encode integers as strings
+func enumSchema(t reflect.Type, values []interface{}) Schema {
+	jsonType := "integer"
+	if len(values) > 0 {
+		if _, ok := values[0].(string); ok {
+			jsonType = "string"
+		}
+	} else if t.Kind() == reflect.String {
+		jsonType = "string"
+	}
+	// the registered values are shared, edits of the schema must not change them
+	return Schema{"type": jsonType, "enum": append([]interface{}(nil), values...)}
+}
+
+// enumKeyName converts an enum value into the property name
+// encoding/json would use when it is a map key
+func enumKeyName(v interface{}) string {
+	switch k := v.(type) {
+	case string:
+		return k
+	default:
+		return fmt.Sprint(k)
+	}
+}
+
+// expand a map keyed by a registered enum into an object with one property per value
+func enumMapProperties(
+	t reflect.Type,
+	keys []interface{},
+	visited map[reflect.Type]int,
+	depth int,
+	opts *Options) (Schema, error) {
+	valueSchema, err := JsonTypeOf(t.Elem(), visited, depth, opts)
+	if err != nil {
+		return nil, err
+	}
+	props := make(Schema)
+	var required []string
+	for _, key := range keys {
+		name := enumKeyName(key)
+		if opts.Strict {
+			// strict mode requires every property, so missing keys are emulated with null
+			props[name] = propertySchema(valueSchema, true, opts.NullableStyle)
+			required = append(required, name)
+		} else {
+			props[name] = propertySchema(valueSchema, false, opts.NullableStyle)
+		}
+	}
+	schema := Schema{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		if opts.RequiredOrder == Alphabetical {
+			sort.Strings(required)
+		}
+		schema["required"] = required
+	}
+	return schema, nil
+}
@@ -0,0 +1,231 @@
+package internal
+
+import (
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEnumMapExpansion(t *testing.T) {
+	RegisterEnum(reflect.TypeOf(Weekday("")), []interface{}{"mon", "tue"})
+	RegisterEnum(reflect.TypeOf(Priority(0)), []interface{}{int64(1), int64(2)})
+
+	t.Run("strict mode requires nullable properties", func(t *testing.T) {
+		type StrictSchedule struct {
+			Hours map[Weekday]int `json:"hours"`
+		}
+		opts, visited, depth := getInputs()
+		opts.ExpandEnumMaps = true
+		result, err := JsonTypeOf(reflect.TypeOf(StrictSchedule{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := Schema{
+			"type": "object",
+			"properties": Schema{
+				"hours": Schema{
+					"type": "object",
+					"properties": Schema{
+						"mon": Schema{"type": []string{"integer", "null"}},
+						"tue": Schema{"type": []string{"integer", "null"}},
+					},
+					"required":             []string{"mon", "tue"},
+					"additionalProperties": false,
+				},
+			},
+			"required":             []string{"hours"},
+			"additionalProperties": false,
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected %+v, got %+v", expected, result)
+		}
+	})
+
+	t.Run("non-strict mode uses optional properties", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.ExpandEnumMaps = true
+		opts.Strict = false
+		result, err := JsonTypeOf(reflect.TypeOf(Schedule{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := Schema{
+			"type": "object",
+			"properties": Schema{
+				"hours": Schema{
+					"type": "object",
+					"properties": Schema{
+						"mon": Schema{"type": "integer"},
+						"tue": Schema{"type": "integer"},
+					},
+					"additionalProperties": false,
+				},
+				"notes": Schema{
+					"type": "object",
+					"properties": Schema{
+						"1": Schema{"type": "string"},
+						"2": Schema{"type": "string"},
+					},
+					"additionalProperties": false,
+				},
+				"other": Schema{
+					"anyOf": []Schema{
+						{
+							"type":                 "object",
+							"additionalProperties": Schema{"type": "integer"},
+						},
+						{"type": "null"},
+					},
+				},
+			},
+			"required":             []string{"hours", "notes", "other"},
+			"additionalProperties": false,
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected %+v, got %+v", expected, result)
+		}
+	})
+
+	t.Run("expansion disabled keeps maps unsupported in strict mode", func(t *testing.T) {
+		_, err := runJsonTypeOf(reflect.TypeOf(map[Weekday]int{}))
+		if err != ErrUnsupportedType {
+			t.Errorf("expected ErrUnsupportedType, got %v", err)
+		}
+	})
+}
+
+func TestEnumFieldConversion(t *testing.T) {
+	RegisterEnum(reflect.TypeOf(Status("")), []interface{}{"draft", "published", "archived"})
+	RegisterEnum(reflect.TypeOf(Priority(0)), []interface{}{int64(1), int64(2)})
+
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		expected interface{}
+	}{
+		{
+			name:     "string enum",
+			input:    reflect.TypeOf(Status("")),
+			expected: StatusSchema,
+		},
+		{
+			name:     "integer enum",
+			input:    reflect.TypeOf(Priority(0)),
+			expected: Schema{"type": "integer", "enum": []interface{}{int64(1), int64(2)}},
+		},
+		{
+			name:     "struct with enum fields",
+			input:    reflect.TypeOf(Article{}),
+			expected: ArticleSchema,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runJsonTypeOf(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+// narrowLevel is a stringer enum whose scan would wrap around past 127
+type narrowLevel int8
+
+func (l narrowLevel) String() string {
+	switch l {
+	case 0:
+		return "Low"
+	case 1:
+		return "High"
+	default:
+		return "narrowLevel(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+func TestDetectEnumNarrowType(t *testing.T) {
+	values, ok := detectEnum(reflect.TypeOf(narrowLevel(0)))
+	if !ok || !reflect.DeepEqual(values, []interface{}{int64(0), int64(1)}) {
+		t.Errorf("expected the values of the int8 enum only, got %v", values)
+	}
+}
+
+func TestEnumSchemaCopiesRegisteredValues(t *testing.T) {
+	RegisterEnum(reflect.TypeOf(Status("")), []interface{}{"draft", "published"})
+	first, err := runJsonTypeOf(reflect.TypeOf(Status("")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first.(Schema)["enum"].([]interface{})[0] = "modified"
+	second, err := runJsonTypeOf(reflect.TypeOf(Status("")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values := second.(Schema)["enum"]; !reflect.DeepEqual(values, []interface{}{"draft", "published"}) {
+		t.Errorf("expected the registered values to be left unchanged, got %v", values)
+	}
+}
+
+func TestEnumAutodetection(t *testing.T) {
+	expected := Schema{
+		"type": "object",
+		"properties": Schema{
+			"primary": Schema{"type": "string", "enum": []interface{}{"red", "green"}},
+			"dose":    Schema{"type": "integer", "enum": []interface{}{int64(0), int64(1), int64(2)}},
+		},
+		"required":             []string{"primary", "dose"},
+		"additionalProperties": false,
+	}
+	t.Run("detect enumer and stringer types", func(t *testing.T) {
+		result, err := runJsonTypeOf(reflect.TypeOf(Palette{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected %+v, got %+v", expected, result)
+		}
+	})
+
+	t.Run("detection disabled", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.DetectEnums = false
+		result, err := JsonTypeOf(reflect.TypeOf(Pill(0)), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "integer" {
+			t.Errorf("expected plain integer, got %+v", result)
+		}
+	})
+
+	t.Run("stringers outside stringer output are not enums", func(t *testing.T) {
+		for _, v := range []interface{}{time.Duration(0), os.FileMode(0), net.Flags(0)} {
+			if values, ok := detectEnum(reflect.TypeOf(v)); ok {
+				t.Errorf("expected no enum for %T, got %d values", v, len(values))
+			}
+		}
+		type Job struct {
+			Timeout time.Duration `json:"timeout"`
+		}
+		result, err := runJsonTypeOf(reflect.TypeOf(Job{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		timeout := result.(Schema)["properties"].(Schema)["timeout"]
+		if !reflect.DeepEqual(timeout, Schema{"type": "integer"}) {
+			t.Errorf("expected time.Duration to stay a plain integer, got %+v", timeout)
+		}
+	})
+
+	t.Run("plain types are not enums", func(t *testing.T) {
+		if _, ok := detectEnum(reflect.TypeOf(Status(""))); ok {
+			t.Errorf("expected no enum for a type without Values or String")
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ErrorCode classifies the failures of schema generation
+type ErrorCode string
+
+const (
+	// UnsupportedType is a Go type without a JSON Schema representation, such as a channel
+	UnsupportedType ErrorCode = "unsupported_type"
+	// CircularRef is a type referencing itself without recursion unrolling
+	CircularRef ErrorCode = "circular_ref"
+	// MaxDepth is a type nested deeper than the maximum depth
+	MaxDepth ErrorCode = "max_depth"
+	// LimitExceeded is a schema over a size budget or a provider limit
+	LimitExceeded ErrorCode = "limit_exceeded"
+	// TagSyntax is an invalid or misplaced struct tag
+	TagSyntax ErrorCode = "tag_syntax"
+	// InvalidOption is an invalid option value
+	InvalidOption ErrorCode = "invalid_option"
+	// LintFailed is a schema rejected by a lint check, such as a disallowed keyword
+	LintFailed ErrorCode = "lint_failed"
+	// InvalidSchema is a schema built or modified at runtime that is not valid
+	InvalidSchema ErrorCode = "invalid_schema"
+	// Internal is any other failure, such as a schema that cannot be marshaled
+	Internal ErrorCode = "internal"
+)
+
+// SchemaError classifies a failure with a code and locates it with the path of the
+// property, e.g. "companies[].address", empty for the root. Its message is the one of the
+// wrapped cause, so errors.Is and errors.As keep matching the cause.
+type SchemaError struct {
+	Code ErrorCode
+	Path string
+	Err  error
+}
+
+func (e *SchemaError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+// NewSchemaError wraps err with a code, nil is returned for a nil error and a SchemaError
+// is returned unchanged
+func NewSchemaError(code ErrorCode, err error) error {
+	var schemaErr *SchemaError
+	if err == nil || errors.As(err, &schemaErr) {
+		return err
+	}
+	return &SchemaError{Code: code, Err: err}
+}
+
+// ClassifyError wraps err in a SchemaError with the code matching its cause
+func ClassifyError(err error) error {
+	return NewSchemaError(errorCode(err), err)
+}
+
+// errorCode returns the code of the sentinel or error type wrapped by err
+func errorCode(err error) ErrorCode {
+	var cycleErr *CircularRefError
+	switch {
+	case errors.As(err, &cycleErr):
+		if cycleErr.DepthExceeded {
+			return MaxDepth
+		}
+		return CircularRef
+	case errors.Is(err, ErrUnsupportedType):
+		return UnsupportedType
+	case errors.Is(err, ErrInvalidTag):
+		return TagSyntax
+	case errors.Is(err, ErrInvalidOption):
+		return InvalidOption
+	case errors.Is(err, ErrSchemaTooLarge):
+		return LimitExceeded
+	}
+	return Internal
+}
+
+// errorAt classifies err and prepends a path segment to its path, a property name or
+// "[]" and "{}" for array items and map values
+func errorAt(err error, segment string) error {
+	err = ClassifyError(err)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		return err
+	}
+	switch {
+	case schemaErr.Path == "":
+		schemaErr.Path = segment
+	case strings.HasPrefix(schemaErr.Path, "[") || strings.HasPrefix(schemaErr.Path, "{"):
+		schemaErr.Path = segment + schemaErr.Path
+	default:
+		schemaErr.Path = segment + "." + schemaErr.Path
+	}
+	return err
+}
+
+// CircularRefError reports the chain of types and fields that formed a cycle,
+// or the path that exceeded the maximum depth. It wraps ErrCircularRef.
+type CircularRefError struct {
+	// Path lists the "Type.Field" edges leading to the repeated type, which is the last element
+	Path []string
+	// DepthExceeded is set when the maximum depth was reached instead of a repeated type
+	DepthExceeded bool
+
+	// the type closing the cycle, the path is complete once it reaches its first occurrence
+	cycleType reflect.Type
+	complete  bool
+}
+
+func (e *CircularRefError) Error() string {
+	if e.DepthExceeded {
+		return ErrCircularRef.Error() + ": max depth exceeded at " + strings.Join(e.Path, " → ")
+	}
+	return ErrCircularRef.Error() + ": " + strings.Join(e.Path, " → ")
+}
+
+func (e *CircularRefError) Unwrap() error {
+	return ErrCircularRef
+}
+
+// typeName returns a readable name for named and anonymous types
+func typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return t.String()
+}
+
+// prependCyclePath records the field edge an error went through while unwinding
+func prependCyclePath(err error, t reflect.Type, fieldName string) error {
+	var cycleErr *CircularRefError
+	if !errors.As(err, &cycleErr) || cycleErr.complete {
+		return err
+	}
+	cycleErr.Path = append([]string{typeName(t) + "." + fieldName}, cycleErr.Path...)
+	if t == cycleErr.cycleType {
+		cycleErr.complete = true
+	}
+	return err
+}
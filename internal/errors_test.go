@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCircularRefErrorPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		maxDepth int
+		expected []string
+		message  string
+	}{
+		{
+			name:     "self reference",
+			input:    reflect.TypeOf(Node{}),
+			maxDepth: 50,
+			expected: []string{"Node.Next", "Node"},
+			message:  "circular reference detected: Node.Next → Node",
+		},
+		{
+			name:     "indirect cycle",
+			input:    reflect.TypeOf(Tree{}),
+			maxDepth: 50,
+			expected: []string{"Tree.Children", "Branch.Parent", "Tree"},
+			message:  "circular reference detected: Tree.Children → Branch.Parent → Tree",
+		},
+		{
+			name:     "max depth exceeded",
+			input:    reflect.TypeOf(Employee{}),
+			maxDepth: 1,
+			expected: []string{"Employee.Companies", "Company.Name", "string"},
+			message:  "circular reference detected: max depth exceeded at Employee.Companies → Company.Name → string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.MaxDepth = tt.maxDepth
+			_, err := JsonTypeOf(tt.input, visited, depth, opts)
+			if !errors.Is(err, ErrCircularRef) {
+				t.Fatalf("expected ErrCircularRef, got %v", err)
+			}
+			var cycleErr *CircularRefError
+			if !errors.As(err, &cycleErr) {
+				t.Fatalf("expected *CircularRefError, got %T", err)
+			}
+			if !reflect.DeepEqual(cycleErr.Path, tt.expected) {
+				t.Errorf("expected path %v, got %v", tt.expected, cycleErr.Path)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("mismatch error message, expect=%s, got=%s", tt.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestSchemaError(t *testing.T) {
+	type Address struct {
+		Updates chan int `json:"updates"`
+	}
+	type Company struct {
+		Addresses []Address `json:"addresses"`
+	}
+	type Tagged struct {
+		Labels []string `json:"labels" items:"nullable"`
+	}
+	type Deep struct {
+		Groups map[string][]func() `json:"groups"`
+	}
+	tests := []struct {
+		name   string
+		input  reflect.Type
+		strict bool
+		code   ErrorCode
+		path   string
+	}{
+		{name: "unsupported type", input: reflect.TypeOf(Company{}), strict: true, code: UnsupportedType, path: "addresses[].updates"},
+		{name: "tag syntax", input: reflect.TypeOf(Tagged{}), strict: true, code: TagSyntax, path: "labels"},
+		{name: "map values", input: reflect.TypeOf(Deep{}), code: UnsupportedType, path: "groups{}[]"},
+		{name: "circular reference", input: reflect.TypeOf(Node{}), strict: true, code: CircularRef, path: "next"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.Strict = tt.strict
+			_, err := JsonTypeOf(tt.input, visited, depth, opts)
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("expected a SchemaError, got %v", err)
+			}
+			if schemaErr.Code != tt.code || schemaErr.Path != tt.path {
+				t.Errorf("expected %s at %q, got %s at %q", tt.code, tt.path, schemaErr.Code, schemaErr.Path)
+			}
+			if schemaErr.Error() != schemaErr.Err.Error() {
+				t.Errorf("expected the message of the cause, got %q", schemaErr.Error())
+			}
+		})
+	}
+	if err := ClassifyError(&CircularRefError{DepthExceeded: true}); err.(*SchemaError).Code != MaxDepth {
+		t.Errorf("expected MaxDepth, got %v", err.(*SchemaError).Code)
+	}
+	if NewSchemaError(Internal, nil) != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+}
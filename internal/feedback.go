@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxFeedbackItems bounds the corrections listed in a feedback prompt
+const maxFeedbackItems = 20
+
+// FeedbackPrompt renders validation errors as corrective instructions for a retry message
+func FeedbackPrompt(errs []ValidationError) string {
+	if len(errs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Your previous response did not match the required JSON schema:\n")
+	for i, e := range errs {
+		if i == maxFeedbackItems {
+			fmt.Fprintf(&b, "- …and %d more problems.\n", len(errs)-i)
+			break
+		}
+		b.WriteString("- " + feedbackLine(e) + "\n")
+	}
+	b.WriteString("Fix these problems and respond again with the complete JSON.")
+	return b.String()
+}
+
+func feedbackLine(e ValidationError) string {
+	subject := "The response"
+	if e.Path != "" {
+		subject = "The field '" + e.Path + "'"
+	}
+	returned := ""
+	if e.Value != "" {
+		returned = ", you returned " + e.Value
+	}
+	switch e.Keyword {
+	case "type":
+		var types []string
+		switch t := e.Schema["type"].(type) {
+		case string:
+			types = []string{withArticle(t)}
+		case []string:
+			for _, name := range t {
+				types = append(types, withArticle(name))
+			}
+		}
+		actual := withArticle(snippetType(e.Value))
+		if actual != e.Value {
+			actual += " " + e.Value
+		}
+		return fmt.Sprintf("%s must be %s, you returned %s.", subject, strings.Join(types, " or "), actual)
+	case "required":
+		return subject + " is missing, it is required."
+	case "additionalProperties":
+		return subject + " is not allowed, remove it."
+	case "enum":
+		values, _ := e.Schema["enum"].([]interface{})
+		literals := make([]string, len(values))
+		for i, v := range values {
+			literals[i] = jsonLiteral(v)
+		}
+		return fmt.Sprintf("%s must be one of %s%s.", subject, strings.Join(literals, ", "), returned)
+	case "const":
+		return fmt.Sprintf("%s must be %s%s.", subject, jsonLiteral(e.Schema["const"]), returned)
+	case "pattern":
+		return fmt.Sprintf("%s must match the regular expression %s%s.", subject, e.Schema["pattern"], returned)
+	case "minLength":
+		return fmt.Sprintf("%s must be at least %v characters long%s.", subject, e.Schema["minLength"], returned)
+	case "maxLength":
+		return fmt.Sprintf("%s must be at most %v characters long%s.", subject, e.Schema["maxLength"], returned)
+	case "minItems":
+		return fmt.Sprintf("%s must have at least %s.", subject, items(e.Schema["minItems"]))
+	case "maxItems":
+		return fmt.Sprintf("%s must have at most %s.", subject, items(e.Schema["maxItems"]))
+	case "minimum":
+		return fmt.Sprintf("%s must be at least %v%s.", subject, e.Schema["minimum"], returned)
+	case "maximum":
+		return fmt.Sprintf("%s must be at most %v%s.", subject, e.Schema["maximum"], returned)
+	}
+	return fmt.Sprintf("%s is invalid: %s%s.", subject, e.Message, returned)
+}
+
+// snippetType names the JSON type of a value snippet, which may be truncated
+func snippetType(snippet string) string {
+	switch {
+	case snippet == "":
+		return "nothing"
+	case snippet[0] == '"':
+		return "string"
+	case snippet[0] == '[':
+		return "array"
+	case snippet[0] == '{':
+		return "object"
+	case snippet == "true" || snippet == "false":
+		return "boolean"
+	case snippet == "null":
+		return "null"
+	}
+	return "number"
+}
+
+// withArticle prefixes a JSON type with its indefinite article, null stays bare
+func withArticle(t string) string {
+	switch t {
+	case "null", "nothing":
+		return t
+	case "integer", "object", "array":
+		return "an " + t
+	}
+	return "a " + t
+}
+
+// items counts array items in a sentence
+func items(n interface{}) string {
+	if fmt.Sprint(n) == "1" {
+		return "1 item"
+	}
+	return fmt.Sprintf("%v items", n)
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFeedbackPrompt(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"age":    Schema{"type": "integer", "minimum": 0},
+			"name":   Schema{"type": "string", "maxLength": 5},
+			"status": Schema{"type": []string{"string", "null"}, "enum": []interface{}{"open", "closed", nil}},
+			"tags":   Schema{"type": "array", "items": Schema{"type": "string"}, "minItems": 1},
+			"code":   Schema{"type": "string", "pattern": "^[A-Z]+$"},
+			"score":  Schema{"type": "number", "multipleOf": 0.5},
+		},
+		"required":             []string{"age", "name", "status", "tags", "code", "score", "email"},
+		"additionalProperties": false,
+	}
+	data := `{"age":"42","name":"too long","status":"lost","tags":[],"code":"abc","score":0.3,"extra":1}`
+	errs, err := ValidateValue(schema, decodeJSON(t, data), DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `Your previous response did not match the required JSON schema:
+- The field 'age' must be an integer, you returned a string "42".
+- The field 'code' must match the regular expression ^[A-Z]+$, you returned "abc".
+- The field 'email' is missing, it is required.
+- The field 'extra' is not allowed, remove it.
+- The field 'name' must be at most 5 characters long, you returned "too long".
+- The field 'score' is invalid: expected a multiple of 0.5, you returned 0.3.
+- The field 'status' must be one of "open", "closed", null, you returned "lost".
+- The field 'tags' must have at least 1 item.
+Fix these problems and respond again with the complete JSON.`
+	if got := FeedbackPrompt(errs); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+	if got := FeedbackPrompt(nil); got != "" {
+		t.Errorf("expected no feedback without errors, got %q", got)
+	}
+}
+
+func TestFeedbackPrompt_RootAndLimit(t *testing.T) {
+	errs := []ValidationError{{Keyword: "type", Schema: Schema{"type": "object"}, Value: "null"}}
+	if got := FeedbackPrompt(errs); !strings.Contains(got, "- The response must be an object, you returned null.") {
+		t.Errorf("unexpected root feedback %q", got)
+	}
+	many := make([]ValidationError, maxFeedbackItems+5)
+	for i := range many {
+		many[i] = ValidationError{Path: "x", Keyword: "required"}
+	}
+	got := FeedbackPrompt(many)
+	if strings.Count(got, "is missing") != maxFeedbackItems || !strings.Contains(got, "- …and 5 more problems.\n") {
+		t.Errorf("expected the list to be cut, got %q", got)
+	}
+}
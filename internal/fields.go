@@ -0,0 +1,324 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldInfo describes a property found while flattening a schema
+type FieldInfo struct {
+	// Path of the property, e.g. "companies[].address.city"
+	Path string
+	// Schema of the property with the null variant removed
+	Schema Schema
+	// Required reports whether the property is listed in its object's required array
+	Required bool
+	// Nullable reports whether the property accepts null
+	Nullable bool
+	// Description of the property, if any
+	Description string
+}
+
+// unwrapNullable removes the null variant of a property schema, both the
+// type array form and the anyOf form produced for optional fields are handled
+func unwrapNullable(s Schema) (Schema, bool) {
+	switch types := s["type"].(type) {
+	case []string:
+		var kept []string
+		for _, t := range types {
+			if t != "null" {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == len(types) {
+			return s, false
+		}
+		unwrapped := make(Schema, len(s))
+		for k, v := range s {
+			unwrapped[k] = v
+		}
+		if len(kept) == 1 {
+			unwrapped["type"] = kept[0]
+		} else {
+			unwrapped["type"] = kept
+		}
+		// nullable enums list null as an allowed value
+		if values, ok := s["enum"].([]interface{}); ok {
+			var nonNull []interface{}
+			for _, v := range values {
+				if v != nil {
+					nonNull = append(nonNull, v)
+				}
+			}
+			unwrapped["enum"] = nonNull
+		}
+		return unwrapped, true
+	case string:
+		return s, types == "null"
+	}
+	if variants, ok := s["anyOf"].([]Schema); ok {
+		var kept []Schema
+		for _, v := range variants {
+			if v["type"] != "null" {
+				kept = append(kept, v)
+			}
+		}
+		if len(kept) == 1 && len(kept) != len(variants) {
+			unwrapped := make(Schema, len(kept[0])+1)
+			for k, v := range kept[0] {
+				unwrapped[k] = v
+			}
+			if description, ok := s["description"]; ok {
+				unwrapped["description"] = description
+			}
+			return unwrapped, true
+		}
+	}
+	return s, false
+}
+
+// PropertyNames returns the property names of an object schema, the required ones first
+// in declaration order followed by the others sorted by name
+func (s Schema) PropertyNames() []string {
+	return orderedProperties(s)
+}
+
+// orderedProperties returns the property names of an object schema, the required ones
+// first in declaration order followed by the others sorted by name
+func orderedProperties(s Schema) []string {
+	props, _ := s["properties"].(Schema)
+	seen := make(map[string]bool)
+	var names []string
+	if required, ok := s["required"].([]string); ok {
+		for _, name := range required {
+			if _, exists := props[name]; exists && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	var rest []string
+	for name := range props {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// FlattenFields calls fn for every property of an object schema, nested
+// objects are visited after their parent property, array items use "[]"
+// and map values use "{}" in the path
+func FlattenFields(s Schema, fn func(FieldInfo)) {
+	flattenObject(s, "", fn)
+}
+
+func flattenObject(s Schema, path string, fn func(FieldInfo)) {
+	props, _ := s["properties"].(Schema)
+	required := make(map[string]bool)
+	if names, ok := s["required"].([]string); ok {
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	for _, name := range orderedProperties(s) {
+		prop, ok := props[name].(Schema)
+		if !ok {
+			continue
+		}
+		unwrapped, nullable := unwrapNullable(prop)
+		description, _ := unwrapped["description"].(string)
+		fieldPath := joinPath(path, name)
+		fn(FieldInfo{
+			Path:        fieldPath,
+			Schema:      unwrapped,
+			Required:    required[name],
+			Nullable:    nullable,
+			Description: description,
+		})
+		flattenNested(unwrapped, fieldPath, fn)
+	}
+}
+
+// descend into the objects nested in a property
+func flattenNested(s Schema, path string, fn func(FieldInfo)) {
+	if _, ok := s["properties"].(Schema); ok {
+		flattenObject(s, path, fn)
+	}
+	if items, ok := s["items"].(Schema); ok {
+		items, _ = unwrapNullable(items)
+		flattenNested(items, path+"[]", fn)
+	}
+	if values, ok := s["additionalProperties"].(Schema); ok {
+		values, _ = unwrapNullable(values)
+		flattenNested(values, path+"{}", fn)
+	}
+	if patterns, ok := s["patternProperties"].(Schema); ok {
+		for _, pattern := range sortedKeys(patterns) {
+			if values, ok := patterns[pattern].(Schema); ok {
+				values, _ = unwrapNullable(values)
+				flattenNested(values, path+"{}", fn)
+			}
+		}
+	}
+}
+
+// LeafPath describes a scalar value reachable in the documents of a schema
+type LeafPath struct {
+	// Path of the value, e.g. "companies[].address.city", "[]" stands for array items
+	// and "{}" for map values
+	Path string
+	// Type is the JSON type of the value, types of multi-type values are joined with "|"
+	Type string
+	// Nullable reports whether the value accepts null, or sits in a nullable object
+	Nullable bool
+	// Required reports whether the property and every enclosing property are required
+	Required bool
+}
+
+// Paths returns the scalar leaves of the schema in property order, required properties
+// first, descending into nested objects, array items and map values
+func (s Schema) Paths() []LeafPath {
+	var paths []LeafPath
+	collectLeaves(s, "", true, false, &paths)
+	return paths
+}
+
+// RequiredPaths returns the leaves whose property and enclosing properties are all required
+func (s Schema) RequiredPaths() []LeafPath {
+	var required []LeafPath
+	for _, path := range s.Paths() {
+		if path.Required {
+			required = append(required, path)
+		}
+	}
+	return required
+}
+
+// collectLeaves appends the leaves of a schema with the null variant already removed
+func collectLeaves(s Schema, path string, required, nullable bool, paths *[]LeafPath) {
+	if props, ok := s["properties"].(Schema); ok {
+		requiredNames := make(map[string]bool)
+		if names, ok := s["required"].([]string); ok {
+			for _, name := range names {
+				requiredNames[name] = true
+			}
+		}
+		for _, name := range orderedProperties(s) {
+			prop, ok := props[name].(Schema)
+			if !ok {
+				continue
+			}
+			unwrapped, propNullable := unwrapNullable(prop)
+			collectLeaves(unwrapped, joinPath(path, name), required && requiredNames[name], nullable || propNullable, paths)
+		}
+		return
+	}
+	if items, ok := s["items"].(Schema); ok {
+		items, itemsNullable := unwrapNullable(items)
+		collectLeaves(items, path+"[]", required, nullable || itemsNullable, paths)
+		return
+	}
+	values, ok := s["additionalProperties"].(Schema)
+	if patterns, isMap := s["patternProperties"].(Schema); isMap && !ok {
+		for _, pattern := range sortedKeys(patterns) {
+			if values, ok = patterns[pattern].(Schema); ok {
+				break
+			}
+		}
+	}
+	if ok {
+		values, valuesNullable := unwrapNullable(values)
+		collectLeaves(values, path+"{}", required, nullable || valuesNullable, paths)
+		return
+	}
+	// unions of several types keep their null variant
+	if variants, ok := s["anyOf"].([]Schema); ok {
+		for _, variant := range variants {
+			nullable = nullable || variant["type"] == "null"
+		}
+	}
+	*paths = append(*paths, LeafPath{Path: path, Type: leafType(s), Nullable: nullable, Required: required})
+}
+
+// leafType returns the JSON type of a scalar schema, the types of type arrays and of the
+// non-null anyOf variants are joined with "|"
+func leafType(s Schema) string {
+	switch types := s["type"].(type) {
+	case string:
+		return types
+	case []string:
+		return strings.Join(types, "|")
+	}
+	if variants, ok := s["anyOf"].([]Schema); ok {
+		var types []string
+		for _, variant := range variants {
+			if t := leafType(variant); t != "" && t != "null" && !containsString(types, t) {
+				types = append(types, t)
+			}
+		}
+		return strings.Join(types, "|")
+	}
+	return ""
+}
+
+// At returns the schema of the value at a path of Paths or FlattenFields, e.g.
+// "companies[].address" or "labels{}", the empty path is the schema itself. Nullable
+// objects and local $refs are followed on the way, the returned schema is the one
+// declared at the path, null variant included.
+func (s Schema) At(path string) (Schema, bool) {
+	current := s
+	if path == "" {
+		return current, true
+	}
+	for _, token := range strings.Split(path, ".") {
+		name := strings.TrimRight(token, "[]{}")
+		suffix := token[len(name):]
+		if name != "" {
+			props, _ := s.descend(current)["properties"].(Schema)
+			prop, ok := props[name].(Schema)
+			if !ok {
+				return nil, false
+			}
+			current = prop
+		}
+		for ; suffix != ""; suffix = suffix[2:] {
+			parent := s.descend(current)
+			var next Schema
+			switch {
+			case strings.HasPrefix(suffix, "[]"):
+				next, _ = parent["items"].(Schema)
+			case strings.HasPrefix(suffix, "{}"):
+				next, _ = parent["additionalProperties"].(Schema)
+				if patterns, ok := parent["patternProperties"].(Schema); ok && next == nil {
+					for _, pattern := range sortedKeys(patterns) {
+						if next, ok = patterns[pattern].(Schema); ok {
+							break
+						}
+					}
+				}
+			default:
+				return nil, false
+			}
+			if next == nil {
+				return nil, false
+			}
+			current = next
+		}
+	}
+	return current, true
+}
+
+// descend prepares a subschema of s to be walked into: a local $ref is resolved against
+// s and the null variant is removed
+func (s Schema) descend(sub Schema) Schema {
+	sub, _ = unwrapNullable(sub)
+	if ref, ok := sub["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+		r := &refResolver{docs: map[string]Schema{"": s}}
+		if target, err := r.target("", ref[1:]); err == nil {
+			sub, _ = unwrapNullable(target)
+		}
+	}
+	return sub
+}
@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnwrapNullable(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        Schema
+		expected     Schema
+		expectedNull bool
+	}{
+		{
+			name:         "plain type",
+			input:        Schema{"type": "string"},
+			expected:     Schema{"type": "string"},
+			expectedNull: false,
+		},
+		{
+			name:         "type array",
+			input:        Schema{"type": []string{"integer", "null"}, "description": "count"},
+			expected:     Schema{"type": "integer", "description": "count"},
+			expectedNull: true,
+		},
+		{
+			name:         "nullable enum",
+			input:        Schema{"type": []string{"string", "null"}, "enum": []interface{}{"a", "b", nil}},
+			expected:     Schema{"type": "string", "enum": []interface{}{"a", "b"}},
+			expectedNull: true,
+		},
+		{
+			name: "anyOf with null",
+			input: Schema{
+				"anyOf":       []Schema{AddressSchema, {"type": "null"}},
+				"description": "home",
+			},
+			expected: func() Schema {
+				s := Schema{"description": "home"}
+				for k, v := range AddressSchema {
+					s[k] = v
+				}
+				return s
+			}(),
+			expectedNull: true,
+		},
+		{
+			name:         "null leaf",
+			input:        Schema{"type": "null"},
+			expected:     Schema{"type": "null"},
+			expectedNull: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, nullable := unwrapNullable(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+			if nullable != tt.expectedNull {
+				t.Errorf("expected nullable=%v, got %v", tt.expectedNull, nullable)
+			}
+		})
+	}
+}
+
+func TestFlattenFields(t *testing.T) {
+	var paths []string
+	nullable := make(map[string]bool)
+	FlattenFields(EmployeeSchema, func(field FieldInfo) {
+		paths = append(paths, field.Path)
+		nullable[field.Path] = field.Nullable
+	})
+	expected := []string{
+		"name",
+		"companies",
+		"companies[].name",
+		"companies[].address",
+		"companies[].address.street",
+		"companies[].address.city",
+		"companies[].address.zip_code",
+		"tags",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+	if !nullable["tags"] || !nullable["companies[].address.zip_code"] || nullable["name"] {
+		t.Errorf("unexpected nullability %v", nullable)
+	}
+}
+
+func TestSchemaPaths(t *testing.T) {
+	expected := []LeafPath{
+		{Path: "name", Type: "string", Required: true},
+		{Path: "companies[].name", Type: "string", Required: true},
+		{Path: "companies[].address.street", Type: "string", Required: true},
+		{Path: "companies[].address.city", Type: "string", Required: true},
+		{Path: "companies[].address.zip_code", Type: "string", Nullable: true, Required: true},
+		{Path: "tags[]", Type: "string", Nullable: true, Required: true},
+	}
+	if paths := EmployeeSchema.Paths(); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %+v, got %+v", expected, paths)
+	}
+
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"id":     Schema{"type": "integer"},
+			"value":  Schema{"type": []string{"number", "string"}},
+			"labels": Schema{"type": "object", "additionalProperties": Schema{"type": "string"}},
+			"owner": Schema{
+				"type":       "object",
+				"properties": Schema{"email": Schema{"type": "string"}},
+				"required":   []string{"email"},
+			},
+			"shape": Schema{"anyOf": []Schema{{"type": "string"}, {"type": "integer"}, {"type": "null"}}},
+		},
+		"required": []string{"id", "value", "shape"},
+	}
+	expected = []LeafPath{
+		{Path: "id", Type: "integer", Required: true},
+		{Path: "value", Type: "number|string", Required: true},
+		{Path: "shape", Type: "string|integer", Nullable: true, Required: true},
+		{Path: "labels{}", Type: "string"},
+		{Path: "owner.email", Type: "string"},
+	}
+	if paths := schema.Paths(); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %+v, got %+v", expected, paths)
+	}
+	if required := schema.RequiredPaths(); !reflect.DeepEqual(required, expected[:3]) {
+		t.Errorf("expected %+v, got %+v", expected[:3], required)
+	}
+}
+
+func TestSchemaAt(t *testing.T) {
+	bundled := Schema{
+		"type": "object",
+		"properties": Schema{
+			"billing": Schema{"anyOf": []Schema{{"$ref": "#/$defs/Address"}, {"type": "null"}}},
+			"labels":  Schema{"type": "object", "patternProperties": Schema{"^[a-z]+$": Schema{"type": "string"}}},
+			"matrix":  Schema{"type": "array", "items": Schema{"type": "array", "items": Schema{"type": "number"}}},
+		},
+		"$defs": Schema{"Address": AddressSchema},
+	}
+	tests := []struct {
+		name     string
+		schema   Schema
+		path     string
+		expected interface{}
+	}{
+		{name: "root", schema: EmployeeSchema, path: "", expected: EmployeeSchema},
+		{name: "nested object", schema: EmployeeSchema, path: "companies[].address", expected: AddressSchema},
+		{name: "nullable leaf", schema: EmployeeSchema, path: "companies[].address.zip_code",
+			expected: AddressSchema["properties"].(Schema)["zip_code"]},
+		{name: "array items", schema: EmployeeSchema, path: "tags[]", expected: Schema{"type": "string"}},
+		{name: "through a $ref", schema: bundled, path: "billing.city", expected: Schema{"type": "string"}},
+		{name: "map values", schema: bundled, path: "labels{}", expected: Schema{"type": "string"}},
+		{name: "nested arrays", schema: bundled, path: "matrix[][]", expected: Schema{"type": "number"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.schema.At(tt.path)
+			if !ok {
+				t.Fatalf("expected %q to be found", tt.path)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+	for _, path := range []string{"missing", "name[]", "companies.name", "tags{}", "name]"} {
+		if _, ok := EmployeeSchema.At(path); ok {
+			t.Errorf("expected %q not to be found", path)
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package internal
 
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
 type SimpleStruct struct {
 	Name  string
 	Age   int
@@ -237,3 +243,732 @@ type Node struct {
 	Value string `json:"value"`
 	Next  *Node  `json:"next,omitempty"`
 }
+
+// ==========================================
+
+// Map fields, only supported in non-strict mode
+type StructWithMaps struct {
+	Scores map[string]int            `json:"scores"`
+	Labels map[string]string         `json:"labels,omitempty" keys:"^[a-z]{2}$"`
+	Nested map[string]StructWithTags `json:"nested"`
+}
+
+var StructWithMapsSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"scores": Schema{
+			"type":                 "object",
+			"additionalProperties": Schema{"type": "integer"},
+		},
+		"labels": Schema{
+			"anyOf": []Schema{
+				{
+					"type": "object",
+					"patternProperties": Schema{
+						"^[a-z]{2}$": Schema{"type": "string"},
+					},
+					"additionalProperties": false,
+				},
+				{"type": "null"},
+			},
+		},
+		"nested": Schema{
+			"type":                 "object",
+			"additionalProperties": StructWithTagsSchema,
+		},
+	},
+	"required":             []string{"scores", "labels", "nested"},
+	"additionalProperties": false,
+}
+
+// ==========================================
+
+// Maps keyed by an enum type
+type Weekday string
+
+type Priority int
+
+type Schedule struct {
+	Hours map[Weekday]int      `json:"hours"`
+	Notes map[Priority]*string `json:"notes"`
+	Other map[string]int       `json:"other,omitempty"`
+}
+
+// ==========================================
+
+// Fields typed with a registered enum
+type Status string
+
+type Article struct {
+	Title   string   `json:"title"`
+	Status  Status   `json:"status"`
+	History []Status `json:"history"`
+	Next    *Status  `json:"next,omitempty"`
+}
+
+var StatusSchema = Schema{
+	"type": "string",
+	"enum": []interface{}{"draft", "published", "archived"},
+}
+
+var ArticleSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"title":  Schema{"type": "string"},
+		"status": StatusSchema,
+		"history": Schema{
+			"type":  "array",
+			"items": StatusSchema,
+		},
+		"next": Schema{
+			"anyOf": []Schema{
+				StatusSchema,
+				{"type": "null"},
+			},
+		},
+	},
+	"required":             []string{"title", "status", "history", "next"},
+	"additionalProperties": false,
+}
+
+// ==========================================
+
+// Enum generated by stringer, out of range values print as "Pill(N)"
+type Pill int
+
+func (p Pill) String() string {
+	switch p {
+	case 0:
+		return "Placebo"
+	case 1:
+		return "Aspirin"
+	case 2:
+		return "Ibuprofen"
+	default:
+		return "Pill(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// Enum generated by enumer with -json, values marshal as their names
+type Color int
+
+func (c Color) Values() []Color {
+	return []Color{0, 1}
+}
+
+func (c Color) String() string {
+	return [...]string{"red", "green"}[c]
+}
+
+func (c Color) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + c.String() + `"`), nil
+}
+
+type Palette struct {
+	Primary Color `json:"primary"`
+	Dose    Pill  `json:"dose"`
+}
+
+// ==========================================
+
+// 64-bit identifiers and quoted scalars
+type Invoice struct {
+	ID      int64   `json:"id"`
+	Owner   uint64  `json:"owner"`
+	Count   int     `json:"count"`
+	Total   float64 `json:"total,string"`
+	Paid    bool    `json:"paid,string"`
+	Related []int64 `json:"related"`
+}
+
+var InvoiceInt64AsStringSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"id":    Schema{"type": "string", "pattern": "^-?[0-9]+$"},
+		"owner": Schema{"type": "string", "pattern": "^[0-9]+$"},
+		"count": Schema{"type": "integer"},
+		"total": Schema{"type": "string", "pattern": "^-?[0-9]+(\\.[0-9]+)?([eE][+-]?[0-9]+)?$"},
+		"paid":  Schema{"type": "string", "pattern": "^(true|false)$"},
+		"related": Schema{
+			"type":  "array",
+			"items": Schema{"type": "string", "pattern": "^-?[0-9]+$"},
+		},
+	},
+	"required":             []string{"id", "owner", "count", "total", "paid", "related"},
+	"additionalProperties": false,
+}
+
+// ==========================================
+
+// Struct exposing derived values through methods
+type Profile struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+}
+
+func (p Profile) DisplayName() string {
+	return p.First + " " + p.Last
+}
+
+func (p *Profile) Initials() ([]string, error) {
+	return []string{p.First[:1], p.Last[:1]}, nil
+}
+
+func (p Profile) Greet(prefix string) string {
+	return prefix + p.First
+}
+
+var ProfileSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"first":       Schema{"type": "string"},
+		"last":        Schema{"type": "string"},
+		"displayName": Schema{"type": "string"},
+		"initials": Schema{
+			"type":  "array",
+			"items": Schema{"type": "string"},
+		},
+	},
+	"required":             []string{"first", "last", "displayName", "initials"},
+	"additionalProperties": false,
+}
+
+// ==========================================
+
+// Requiredness independent of omitempty
+type StructWithRequiredTags struct {
+	Name    string  `json:"name"`
+	Comment string  `json:"comment" required:"false"`
+	Rating  *int    `json:"rating,omitempty" required:"false"`
+	Source  *string `json:"source,omitempty"`
+	Stored  *string `json:"stored,omitempty" required:"true"`
+}
+
+// ==========================================
+
+// json:"-" skips the field while json:"-," names it "-"
+type StructWithDashTags struct {
+	Skipped string `json:"-"`
+	Dash    string `json:"-,"`
+	Name    string `json:"name"`
+}
+
+var StructWithDashTagsSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"-":    Schema{"type": "string"},
+		"name": Schema{"type": "string"},
+	},
+	"required":             []string{"-", "name"},
+	"additionalProperties": false,
+}
+
+// Indirect cycle through a slice (Tree.Children -> Branch.Parent -> Tree)
+type Tree struct {
+	Name     string   `json:"name"`
+	Children []Branch `json:"children"`
+}
+
+type Branch struct {
+	Label  string `json:"label"`
+	Parent *Tree  `json:"parent,omitempty"`
+}
+
+// Node unrolled one level deep, the innermost occurrence is a null leaf
+var NodeUnrolledSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"value": Schema{"type": "string"},
+		"next": Schema{
+			"anyOf": []Schema{
+				{
+					"type": "object",
+					"properties": Schema{
+						"value": Schema{"type": "string"},
+						"next":  Schema{"type": "null"},
+					},
+					"required":             []string{"value", "next"},
+					"additionalProperties": false,
+				},
+				{"type": "null"},
+			},
+		},
+	},
+	"required":             []string{"value", "next"},
+	"additionalProperties": false,
+}
+
+// Node described with $refs, the root refers to itself with "#"
+var NodeRecursiveSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"value": Schema{"type": "string"},
+		"next": Schema{
+			"anyOf": []Schema{{"$ref": "#"}, {"type": "null"}},
+		},
+	},
+	"required":             []string{"value", "next"},
+	"additionalProperties": false,
+}
+
+// Forest holds recursive trees below its root, described in $defs
+type Forest struct {
+	Trees  []Tree `json:"trees"`
+	Oldest *Tree  `json:"oldest,omitempty"`
+}
+
+var ForestRecursiveSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"trees": Schema{
+			"type":  "array",
+			"items": Schema{"$ref": "#/$defs/Tree"},
+		},
+		"oldest": Schema{
+			"anyOf": []Schema{{"$ref": "#/$defs/Tree"}, {"type": "null"}},
+		},
+	},
+	"required":             []string{"trees", "oldest"},
+	"additionalProperties": false,
+	"$defs": Schema{
+		"Tree": Schema{
+			"type": "object",
+			"properties": Schema{
+				"name": Schema{"type": "string"},
+				"children": Schema{
+					"type": "array",
+					"items": Schema{
+						"type": "object",
+						"properties": Schema{
+							"label": Schema{"type": "string"},
+							"parent": Schema{
+								"anyOf": []Schema{{"$ref": "#/$defs/Tree"}, {"type": "null"}},
+							},
+						},
+						"required":             []string{"label", "parent"},
+						"additionalProperties": false,
+					},
+				},
+			},
+			"required":             []string{"name", "children"},
+			"additionalProperties": false,
+		},
+	},
+}
+
+// ==========================================
+
+// Fields with descriptions
+type DescribedContact struct {
+	Name  string   `json:"name" description:"Full name of the contact"`
+	Phone *string  `json:"phone,omitempty" description:"Phone number, null when unknown"`
+	Tags  []string `json:"tags" description:"Free-form labels"`
+}
+
+var DescribedContactSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"name": Schema{
+			"type":        "string",
+			"description": "Full name of the contact",
+		},
+		"phone": Schema{
+			"type":        []string{"string", "null"},
+			"description": "Phone number, null when unknown",
+		},
+		"tags": Schema{
+			"type":        "array",
+			"items":       Schema{"type": "string"},
+			"description": "Free-form labels",
+		},
+	},
+	"required":             []string{"name", "phone", "tags"},
+	"additionalProperties": false,
+}
+
+// ==========================================
+
+// Wrapper types mapped to formats by detectors
+type EmailAddress string
+
+type UserID string
+
+type Account struct {
+	ID      UserID         `json:"id"`
+	Email   EmailAddress   `json:"email"`
+	Backup  *EmailAddress  `json:"backup,omitempty"`
+	Aliases []EmailAddress `json:"aliases"`
+	Name    string         `json:"name"`
+}
+
+var AccountSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"id":      Schema{"type": "string", "format": "uuid"},
+		"email":   Schema{"type": "string", "format": "email"},
+		"backup":  Schema{"type": []string{"string", "null"}, "format": "email"},
+		"aliases": Schema{"type": "array", "items": Schema{"type": "string"}},
+		"name":    Schema{"type": "string"},
+	},
+	"required":             []string{"id", "email", "backup", "aliases", "name"},
+	"additionalProperties": false,
+}
+
+// ==========================================
+
+// Struct with time fields
+type Event struct {
+	Name     string     `json:"name"`
+	StartsAt time.Time  `json:"startsAt"`
+	EndsAt   *time.Time `json:"endsAt,omitempty"`
+}
+
+// ==========================================
+
+// Struct mixing tagged and untagged fields
+type MixedTagging struct {
+	UserID        string
+	HTTPServerURL string `json:",omitempty"`
+	Email         string `json:"email"`
+	Address2      string
+}
+
+// Struct whose converted name collides with a tagged field
+type NamingCollision struct {
+	UserName string
+	Login    string `json:"user_name"`
+}
+
+// Struct whose tagged field hides an embedded field renamed by the naming convention
+type ConventionShadowing struct {
+	Timestamps
+	Updated int `json:"Updated"`
+}
+
+// ==========================================
+
+// Structs with colliding property names
+type TagFallbackCollision struct {
+	Title   string
+	Heading string `json:"Title"`
+}
+
+type Timestamps struct {
+	Updated string
+	Created string
+}
+
+type Revision struct {
+	Updated int
+}
+
+type AmbiguousEmbedding struct {
+	Timestamps
+	Revision
+}
+
+// The Updated field of the outer struct hides the embedded one, like encoding/json
+type ShadowedTimestamps struct {
+	Updated int
+	Timestamps
+}
+
+var ShadowedTimestampsSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"Updated": Schema{"type": "integer"},
+		"Created": Schema{"type": "string"},
+	},
+	"required":             []string{"Updated", "Created"},
+	"additionalProperties": false,
+}
+
+// Struct with contains and dependentRequired tags, emitted outside of strict mode
+type Shipment struct {
+	Tags   []string `json:"tags" contains:"fragile"`
+	Codes  []int    `json:"codes" contains:"7"`
+	Street string   `json:"street" required:"false" dependentRequired:"city, zip"`
+	City   string   `json:"city" required:"false"`
+	Zip    string   `json:"zip" required:"false"`
+}
+
+var ShipmentSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"tags":   Schema{"type": "array", "items": Schema{"type": "string"}, "contains": Schema{"const": "fragile"}},
+		"codes":  Schema{"type": "array", "items": Schema{"type": "integer"}, "contains": Schema{"const": 7}},
+		"street": Schema{"type": "string"},
+		"city":   Schema{"type": "string"},
+		"zip":    Schema{"type": "string"},
+	},
+	"required":             []string{"tags", "codes"},
+	"dependentRequired":    Schema{"street": []string{"city", "zip"}},
+	"additionalProperties": false,
+}
+
+// Struct with fields holding one of several scalar types
+type Measurement struct {
+	Value  interface{}     `json:"value" types:"number|string"`
+	Unit   json.RawMessage `json:"unit" types:"string"`
+	Offset interface{}     `json:"offset,omitempty" types:"integer|string"`
+	Flag   interface{}     `json:"flag" types:"boolean|null"`
+}
+
+var MeasurementSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"value":  Schema{"type": []string{"number", "string"}},
+		"unit":   Schema{"type": "string"},
+		"offset": Schema{"type": []string{"integer", "string", "null"}},
+		"flag":   Schema{"type": []string{"boolean", "null"}},
+	},
+	"required":             []string{"value", "unit", "offset", "flag"},
+	"additionalProperties": false,
+}
+
+// The embedded struct comes first, the outer Updated field hides Timestamps.Updated
+type ReorderedTimestamps struct {
+	Timestamps
+	Updated int
+	Note    string
+}
+
+var ReorderedTimestampsSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"Created": Schema{"type": "string"},
+		"Updated": Schema{"type": "integer"},
+		"Note":    Schema{"type": "string"},
+	},
+	"required":             []string{"Created", "Updated", "Note"},
+	"additionalProperties": false,
+}
+
+type RevisionHolder struct {
+	Revision
+}
+
+// Timestamps.Updated is shallower than Revision.Updated, no field is declared on the struct itself
+type DeepEmbedding struct {
+	Timestamps
+	RevisionHolder
+}
+
+// ==========================================
+
+// Structs flattened with the encoding/json/v2 inline option
+type PostalAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type Customer struct {
+	Name    string        `json:"name"`
+	Address PostalAddress `json:",inline"`
+}
+
+var CustomerSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"name":   Schema{"type": "string"},
+		"street": Schema{"type": "string"},
+		"city":   Schema{"type": "string"},
+	},
+	"required":             []string{"name", "street", "city"},
+	"additionalProperties": false,
+}
+
+type InvalidInline struct {
+	Tags []string `json:",inline"`
+}
+
+// Embedded structs named by a json tag are regular properties
+type NamedEmbedding struct {
+	BaseInfo `json:"base"`
+}
+
+var NamedEmbeddingSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"base": Schema{
+			"type": "object",
+			"properties": Schema{
+				"id":         Schema{"type": "integer"},
+				"created_at": Schema{"type": "string"},
+			},
+			"required":             []string{"id", "created_at"},
+			"additionalProperties": false,
+		},
+	},
+	"required":             []string{"base"},
+	"additionalProperties": false,
+}
+
+// ==========================================
+
+// Tags whose meaning differs between encoding/json and encoding/json/v2
+type V2Tags struct {
+	Count int      `json:"count,omitempty"`
+	Note  string   `json:"note,omitempty"`
+	Ready bool     `json:"ready,omitzero"`
+	Flag  bool     `json:"flag,string"`
+	Total int      `json:"total,string"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// ==========================================
+
+// Arrays whose pointer elements may be null
+type Survey struct {
+	Scores    []*int           `json:"scores" items:"nullable"`
+	Answers   []*PostalAddress `json:"answers" items:"nullable"`
+	Reviewers []*string        `json:"reviewers"`
+}
+
+var SurveySchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"scores": Schema{
+			"type":  "array",
+			"items": Schema{"type": []string{"integer", "null"}},
+		},
+		"answers": Schema{
+			"type": "array",
+			"items": Schema{
+				"anyOf": []Schema{
+					{
+						"type": "object",
+						"properties": Schema{
+							"street": Schema{"type": "string"},
+							"city":   Schema{"type": "string"},
+						},
+						"required":             []string{"street", "city"},
+						"additionalProperties": false,
+					},
+					{"type": "null"},
+				},
+			},
+		},
+		"reviewers": Schema{
+			"type":  "array",
+			"items": Schema{"type": "string"},
+		},
+	},
+	"required":             []string{"scores", "answers", "reviewers"},
+	"additionalProperties": false,
+}
+
+type InvalidItemsTags struct {
+	Values []int `json:"values" items:"nullable"`
+}
+
+type UnknownItemsTag struct {
+	Values []*int `json:"values" items:"optional"`
+}
+
+// ==========================================
+
+// Optional fields of every kind
+type Mood string
+
+type OptionalFields struct {
+	Nick    *string        `json:"nick,omitempty"`
+	Address *PostalAddress `json:"address,omitempty"`
+	Mood    *Mood          `json:"mood,omitempty"`
+	Tags    []string       `json:"tags,omitempty"`
+}
+
+// ==========================================
+
+// Tool argument struct declaring its tool definition
+type WeatherToolArgs struct {
+	_    struct{} `tool:"name=lookup_weather,description=Look up the weather, by city"`
+	City string   `json:"city"`
+}
+
+var WeatherToolArgsSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"city": Schema{"type": "string"},
+	},
+	"required":             []string{"city"},
+	"additionalProperties": false,
+}
+
+// ==========================================
+
+// Two versions of a contract for diffs
+type OrderV1 struct {
+	ID     string        `json:"id"`
+	Status string        `json:"status" description:"Order status"`
+	Total  float64       `json:"total"`
+	Notes  string        `json:"notes,omitempty"`
+	Lines  []OrderV1Line `json:"lines"`
+}
+
+type OrderV1Line struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type OrderV2 struct {
+	ID       string        `json:"id"`
+	Status   string        `json:"status" description:"Current order status"`
+	Total    int           `json:"total"`
+	Currency string        `json:"currency"`
+	Lines    []OrderV2Line `json:"lines"`
+}
+
+type OrderV2Line struct {
+	SKU  string  `json:"sku"`
+	Qty  int     `json:"qty"`
+	Note *string `json:"note,omitempty"`
+}
+
+// ==========================================
+
+// Fields holding personal data
+type SupportTicket struct {
+	Summary  string          `json:"summary"`
+	Email    string          `json:"email" description:"Customer email" sensitive:"true"`
+	Phones   []string        `json:"phones" sensitive:"true"`
+	Customer *TicketCustomer `json:"customer,omitempty" sensitive:"true"`
+}
+
+type TicketCustomer struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+var SupportTicketSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"summary": Schema{"type": "string"},
+		"email": Schema{
+			"type":        "string",
+			"description": "Customer email " + DefaultSensitiveDescription,
+		},
+		"phones": Schema{
+			"type":        "array",
+			"items":       Schema{"type": "string"},
+			"description": DefaultSensitiveDescription,
+		},
+		"customer": Schema{
+			"anyOf": []Schema{
+				{
+					"type": "object",
+					"properties": Schema{
+						"name": Schema{"type": "string"},
+						"age":  Schema{"type": "integer"},
+					},
+					"required":             []string{"name", "age"},
+					"additionalProperties": false,
+				},
+				{"type": "null"},
+			},
+			"description": DefaultSensitiveDescription,
+		},
+	},
+	"required":             []string{"summary", "email", "phones", "customer"},
+	"additionalProperties": false,
+}
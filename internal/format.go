@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"reflect"
+	"sync"
+)
+
+// FormatDetector maps a field to a JSON Schema format, t is the field type with pointers removed
+type FormatDetector func(t reflect.Type, field reflect.StructField) (format string, ok bool)
+
+// format detector registry, consulted in registration order
+var (
+	formatMu        sync.RWMutex
+	formatDetectors []FormatDetector
+)
+
+// RegisterFormatDetector adds a detector consulted for every struct field
+func RegisterFormatDetector(detector FormatDetector) {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	formatDetectors = append(formatDetectors, detector)
+}
+
+// detectFormat returns the format of the first detector matching the field
+func detectFormat(field reflect.StructField) (string, bool) {
+	formatMu.RLock()
+	detectors := formatDetectors
+	formatMu.RUnlock()
+	t := deref(field.Type)
+	for _, detect := range detectors {
+		if format, ok := detect(t, field); ok && format != "" {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// withFormat annotates a scalar property schema with a format, the non-null variant of
+// an anyOf union is annotated the same way while objects and arrays are returned unchanged.
+// The schema is copied since converted schemas may be shared
+func withFormat(schema interface{}, format string) interface{} {
+	s, ok := schema.(Schema)
+	if !ok {
+		return schema
+	}
+	if variants, ok := s["anyOf"].([]Schema); ok {
+		formatted := make([]Schema, len(variants))
+		for i, variant := range variants {
+			formatted[i] = variant
+			if variant["type"] != "null" {
+				formatted[i] = withFormat(variant, format).(Schema)
+			}
+		}
+		union := make(Schema, len(s))
+		for k, v := range s {
+			union[k] = v
+		}
+		union["anyOf"] = formatted
+		return union
+	}
+	if s["type"] == "object" || s["type"] == "array" || s["type"] == nil {
+		return s
+	}
+	formatted := make(Schema, len(s)+1)
+	for k, v := range s {
+		formatted[k] = v
+	}
+	formatted["format"] = format
+	return formatted
+}
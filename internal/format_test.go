@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterFormatDetector(t *testing.T) {
+	RegisterFormatDetector(func(t reflect.Type, field reflect.StructField) (string, bool) {
+		switch t {
+		case reflect.TypeOf(EmailAddress("")):
+			return "email", true
+		case reflect.TypeOf(UserID("")):
+			return "uuid", true
+		}
+		return "", false
+	})
+	// later detectors only apply when the earlier ones do not match
+	RegisterFormatDetector(func(t reflect.Type, field reflect.StructField) (string, bool) {
+		return "idn-email", t == reflect.TypeOf(EmailAddress(""))
+	})
+	result, err := runJsonTypeOf(reflect.TypeOf(Account{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, AccountSchema) {
+		t.Errorf("expected %+v, got %+v", AccountSchema, result)
+	}
+}
+
+func TestWithFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   interface{}
+		expected interface{}
+	}{
+		{
+			name:     "scalar",
+			schema:   Schema{"type": "string"},
+			expected: Schema{"type": "string", "format": "email"},
+		},
+		{
+			name:     "nullable scalar",
+			schema:   Schema{"type": []string{"string", "null"}},
+			expected: Schema{"type": []string{"string", "null"}, "format": "email"},
+		},
+		{
+			name:     "array",
+			schema:   Schema{"type": "array", "items": Schema{"type": "string"}},
+			expected: Schema{"type": "array", "items": Schema{"type": "string"}},
+		},
+		{
+			name:     "object union",
+			schema:   Schema{"anyOf": []Schema{{"type": "object"}, {"type": "null"}}},
+			expected: Schema{"anyOf": []Schema{{"type": "object"}, {"type": "null"}}},
+		},
+		{
+			name:     "scalar union",
+			schema:   Schema{"anyOf": []Schema{{"type": "string"}, {"type": "null"}}},
+			expected: Schema{"anyOf": []Schema{{"type": "string", "format": "email"}, {"type": "null"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := withFormat(tt.schema, "email")
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// generated schema registry, populated by the code written by "gptschema generate -registry"
+var (
+	generatedMu      sync.RWMutex
+	generatedSchemas = make(map[generatedKey]Schema)
+)
+
+// generatedKey identifies a schema by type and by the options it was generated with
+type generatedKey struct {
+	t       reflect.Type
+	options string
+}
+
+// postProcessingOptions are applied to generated schemas after the conversion of the type,
+// they do not need to match for a registered schema to be used
+var postProcessingOptions = map[string]bool{
+	"max_description_length": true,
+	"max_schema_bytes":       true,
+	"limit_policy":           true,
+	"locale":                 true,
+	"descriptions_file":      true,
+}
+
+// conversionSignature identifies the options affecting the conversion of a type
+func conversionSignature(o *Options) string {
+	var changed []string
+	for _, option := range changedOptions(o) {
+		if !postProcessingOptions[option[:strings.Index(option, "=")]] {
+			changed = append(changed, option)
+		}
+	}
+	return strings.Join(changed, ",")
+}
+
+// RegisterGenerated records the schema generated for t with the options o,
+// registering t again with equivalent options replaces the schema
+func RegisterGenerated(t reflect.Type, o *Options, schema Schema) {
+	generatedMu.Lock()
+	defer generatedMu.Unlock()
+	generatedSchemas[generatedKey{t: deref(t), options: conversionSignature(o)}] = schema
+}
+
+// LookupGenerated returns a copy of the schema registered for t with options converting
+// types like o. Custom humanizers cannot be compared and always fall back to reflection.
+func LookupGenerated(t reflect.Type, o *Options) (Schema, bool) {
+	if !o.UseGenerated || o.Humanizer != nil {
+		return nil, false
+	}
+	generatedMu.RLock()
+	schema, ok := generatedSchemas[generatedKey{t: deref(t), options: conversionSignature(o)}]
+	generatedMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	// callers may modify the schema they receive
+	return transformSchema(schema, "", func(s Schema, _ string) Schema { return s }), true
+}
+
+// GeneratedSchema is a schema registered by generated code
+type GeneratedSchema struct {
+	Type reflect.Type
+	// Options lists the options it was generated with that differ from the defaults, as name=value
+	Options string
+	Schema  Schema
+}
+
+// GeneratedSchemas returns copies of the registered schemas, sorted by type and options
+func GeneratedSchemas() []GeneratedSchema {
+	generatedMu.RLock()
+	schemas := make([]GeneratedSchema, 0, len(generatedSchemas))
+	for key, schema := range generatedSchemas {
+		schemas = append(schemas, GeneratedSchema{Type: key.t, Options: key.options, Schema: schema})
+	}
+	generatedMu.RUnlock()
+	for i := range schemas {
+		schemas[i].Schema = transformSchema(schemas[i].Schema, "", func(s Schema, _ string) Schema { return s })
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		if a, b := schemas[i].Type.String(), schemas[j].Type.String(); a != b {
+			return a < b
+		}
+		return schemas[i].Options < schemas[j].Options
+	})
+	return schemas
+}
+
+// ParseSchema decodes a JSON schema into the Go types produced by generation: subschemas
+// are Schema, combinators []Schema, required lists and type arrays []string and integers
+// int. Values of other keywords, such as enum, const or default, are otherwise kept as decoded.
+func ParseSchema(data []byte) (Schema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded map[string]interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	return parsedSchema(decoded), nil
+}
+
+// parsedSchema converts a decoded JSON object into a Schema
+func parsedSchema(decoded map[string]interface{}) Schema {
+	s := make(Schema, len(decoded))
+	for keyword, value := range decoded {
+		switch keyword {
+		case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+			s[keyword] = parsedSchemaMap(value, parsedSchemaValue)
+		case "dependentRequired":
+			s[keyword] = parsedSchemaMap(value, parsedStrings)
+		case "dependencies":
+			s[keyword] = parsedSchemaMap(value, func(v interface{}) interface{} {
+				if _, ok := v.([]interface{}); ok {
+					return parsedStrings(v)
+				}
+				return parsedSchemaValue(v)
+			})
+		case "items", "additionalItems", "additionalProperties", "contains", "not", "if", "then", "else",
+			"propertyNames", "unevaluatedProperties", "unevaluatedItems":
+			if _, ok := value.([]interface{}); ok {
+				s[keyword] = parsedSchemas(value)
+			} else {
+				s[keyword] = parsedSchemaValue(value)
+			}
+		case "anyOf", "allOf", "oneOf", "prefixItems":
+			s[keyword] = parsedSchemas(value)
+		case "required", "type":
+			s[keyword] = parsedStrings(value)
+		default:
+			s[keyword] = parsedNumbers(value)
+		}
+	}
+	return s
+}
+
+// parsedNumbers converts the numbers of a decoded value to int, or float64 when they
+// are not integers
+func parsedNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := strconv.Atoi(v.String()); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = parsedNumbers(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = parsedNumbers(item)
+		}
+		return out
+	}
+	return v
+}
+
+// parsedSchemaValue converts a decoded subschema, booleans are kept
+func parsedSchemaValue(v interface{}) interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return parsedSchema(m)
+	}
+	return parsedNumbers(v)
+}
+
+// parsedSchemaMap converts the values of a decoded object keyed by names
+func parsedSchemaMap(v interface{}, convert func(interface{}) interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	out := make(Schema, len(m))
+	for name, value := range m {
+		out[name] = convert(value)
+	}
+	return out
+}
+
+// parsedSchemas converts a decoded array of subschemas
+func parsedSchemas(v interface{}) interface{} {
+	items, ok := v.([]interface{})
+	if !ok {
+		return v
+	}
+	out := make([]Schema, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		out = append(out, parsedSchema(m))
+	}
+	return out
+}
+
+// parsedStrings converts a decoded array of strings, single strings are kept
+func parsedStrings(v interface{}) interface{} {
+	items, ok := v.([]interface{})
+	if !ok {
+		return v
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return v
+		}
+		out = append(out, str)
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseSchema(t *testing.T) {
+	for _, schema := range []Schema{EmployeeSchema, ShipmentSchema, DescribedContactSchema} {
+		data, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		parsed, err := ParseSchema(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(parsed, schema) {
+			t.Errorf("expected %+v, got %+v", schema, parsed)
+		}
+	}
+	if _, err := ParseSchema([]byte(`{"type":`)); err == nil {
+		t.Errorf("expected an error for invalid JSON")
+	}
+}
+
+func TestLookupGenerated(t *testing.T) {
+	type Registered struct {
+		Name string `json:"name"`
+	}
+	rt := reflect.TypeOf(Registered{})
+	registered := Schema{"type": "object", "description": "registered"}
+	RegisterGenerated(rt, DefaultOptions(), registered)
+
+	opts := DefaultOptions()
+	opts.MaxDescriptionLength = 10
+	schema, ok := LookupGenerated(reflect.PointerTo(rt), opts)
+	if !ok || !reflect.DeepEqual(schema, registered) {
+		t.Fatalf("expected the registered schema, got %+v", schema)
+	}
+	schema["description"] = "modified"
+	if registered["description"] != "registered" {
+		t.Errorf("expected the registered schema to be copied")
+	}
+
+	for name, configure := range map[string]func(o *Options){
+		"other options": func(o *Options) { o.Strict = false },
+		"disabled":      func(o *Options) { o.UseGenerated = false },
+		"humanizer":     func(o *Options) { o.Humanizer = Humanize },
+	} {
+		opts := DefaultOptions()
+		configure(opts)
+		if _, ok := LookupGenerated(rt, opts); ok {
+			t.Errorf("%s: expected reflection to be used", name)
+		}
+	}
+}
+
+func TestGeneratedSchemas(t *testing.T) {
+	type Listed struct {
+		Name string `json:"name"`
+	}
+	rt := reflect.TypeOf(Listed{})
+	relaxed := DefaultOptions()
+	relaxed.Strict = false
+	RegisterGenerated(rt, DefaultOptions(), Schema{"type": "object"})
+	RegisterGenerated(rt, relaxed, Schema{"type": "object", "additionalProperties": true})
+
+	var listed []GeneratedSchema
+	for _, generated := range GeneratedSchemas() {
+		if generated.Type == rt {
+			listed = append(listed, generated)
+		}
+	}
+	if len(listed) != 2 || listed[0].Options != "" || listed[1].Options != "strict=false" {
+		t.Fatalf("expected the two registrations sorted by options, got %+v", listed)
+	}
+	listed[0].Schema["type"] = "string"
+	if schema, _ := LookupGenerated(rt, DefaultOptions()); schema["type"] != "object" {
+		t.Errorf("expected the registered schema to be copied")
+	}
+}
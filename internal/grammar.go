@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// regular expressions of JSON scalars
+const (
+	jsonStringPattern  = `"(?:[^"\\\x00-\x1f]|\\(?:["\\\/bfnrt]|u[0-9a-fA-F]{4}))*"`
+	jsonIntegerPattern = `-?(?:0|[1-9][0-9]*)`
+	jsonNumberPattern  = `-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?`
+)
+
+// larkTerminals are the terminals of JSON scalars, in the order they are defined
+var larkTerminals = []struct{ name, definition string }{
+	{"STRING", "/" + jsonStringPattern + "/"},
+	{"INTEGER", "/" + jsonIntegerPattern + "/"},
+	{"NUMBER", "/" + jsonNumberPattern + "/"},
+	{"BOOLEAN", `"true" | "false"`},
+	{"NULL", `"null"`},
+}
+
+// larkScalarTerminals maps JSON types to their terminal
+var larkScalarTerminals = map[string]string{
+	"string":  "STRING",
+	"integer": "INTEGER",
+	"number":  "NUMBER",
+	"boolean": "BOOLEAN",
+	"null":    "NULL",
+}
+
+// larkDroppedKeywords are constraints the terminals do not enforce
+var larkDroppedKeywords = []string{
+	"pattern", "format", "minLength", "maxLength", "minimum", "maximum",
+	"exclusiveMinimum", "exclusiveMaximum", "multipleOf", "minItems", "maxItems", "uniqueItems",
+}
+
+// invalidRuleName matches the characters Lark rejects in rule names
+var invalidRuleName = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// larkGrammar collects the rules of a grammar, one per object
+type larkGrammar struct {
+	rules     []string
+	names     map[string]bool
+	terminals map[string]bool
+	opts      *Options
+}
+
+// RenderLarkGrammar converts an object schema into a Lark grammar accepting the compact
+// JSON documents of the schema, with the properties in the order of orderedProperties.
+// Objects, arrays, scalars, enums and constants are supported, nullable ones included.
+// Optional properties are left out and constraints on scalars and arrays are dropped, both
+// are reported as warnings. Maps and unions cannot be expressed and are returned as an
+// ErrUnsupportedType error.
+func RenderLarkGrammar(schema Schema, opts *Options) (string, error) {
+	g := &larkGrammar{names: make(map[string]bool), terminals: make(map[string]bool), opts: opts}
+	if _, err := g.object(schema, "", "start"); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, rule := range g.rules {
+		b.WriteString(rule)
+		b.WriteString("\n")
+	}
+	if len(g.terminals) > 0 {
+		b.WriteString("\n")
+	}
+	for _, terminal := range larkTerminals {
+		if g.terminals[terminal.name] {
+			fmt.Fprintf(&b, "%s: %s\n", terminal.name, terminal.definition)
+		}
+	}
+	return b.String(), nil
+}
+
+// larkLiteral returns the Lark string matching the JSON encoding of v
+func larkLiteral(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Quote(string(data)), nil
+}
+
+// ruleName returns an unused rule name for the object at path
+func (g *larkGrammar) ruleName(path string) string {
+	name := strings.ReplaceAll(strings.ReplaceAll(path, "[]", "_item"), "{}", "_value")
+	name = strings.Trim(invalidRuleName.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "object_" + name
+	}
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// object adds the rule of an object schema and returns its name
+func (g *larkGrammar) object(s Schema, path, name string) (string, error) {
+	if _, ok := s["additionalProperties"].(Schema); ok {
+		return "", fmt.Errorf("%w: %s: maps cannot be expressed in a grammar", ErrUnsupportedType, displayPath(path))
+	}
+	if _, ok := s["patternProperties"]; ok {
+		return "", fmt.Errorf("%w: %s: maps cannot be expressed in a grammar", ErrUnsupportedType, displayPath(path))
+	}
+	if name == "" {
+		name = g.ruleName(path)
+	} else {
+		g.names[name] = true
+	}
+	// reserve the position of the rule so parents come before their children
+	index := len(g.rules)
+	g.rules = append(g.rules, "")
+	props, _ := s["properties"].(Schema)
+	required := make(map[string]bool)
+	if names, ok := s["required"].([]string); ok {
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	parts := []string{`"{"`}
+	for _, prop := range orderedProperties(s) {
+		propPath := joinPath(path, prop)
+		if !required[prop] {
+			g.opts.Warn(propPath, "optional property left out of the grammar")
+			continue
+		}
+		sub, _ := props[prop].(Schema)
+		expression, err := g.value(sub, propPath)
+		if err != nil {
+			return "", err
+		}
+		key, err := larkLiteral(prop)
+		if err != nil {
+			return "", err
+		}
+		if len(parts) > 1 {
+			parts = append(parts, `","`)
+		}
+		parts = append(parts, key, `":"`, expression)
+	}
+	parts = append(parts, `"}"`)
+	g.rules[index] = name + ": " + strings.Join(parts, " ")
+	return name, nil
+}
+
+// value returns the expression matching the values of a schema
+func (g *larkGrammar) value(s Schema, path string) (string, error) {
+	unwrapped, nullable := unwrapNullable(s)
+	expression, err := g.nonNullValue(unwrapped, path)
+	if err != nil || !nullable || expression == "NULL" {
+		return expression, err
+	}
+	g.terminals["NULL"] = true
+	return "(" + expression + " | NULL)", nil
+}
+
+func (g *larkGrammar) nonNullValue(s Schema, path string) (string, error) {
+	if value, ok := s["const"]; ok {
+		return larkLiteral(value)
+	}
+	if values, ok := s["enum"].([]interface{}); ok {
+		literals := make([]string, len(values))
+		for i, value := range values {
+			literal, err := larkLiteral(value)
+			if err != nil {
+				return "", err
+			}
+			literals[i] = literal
+		}
+		return "(" + strings.Join(literals, " | ") + ")", nil
+	}
+	for _, keyword := range []string{"anyOf", "oneOf", "allOf", "$ref"} {
+		if _, ok := s[keyword]; ok {
+			return "", fmt.Errorf("%w: %s: %s cannot be expressed in a grammar", ErrUnsupportedType, displayPath(path), keyword)
+		}
+	}
+	for _, keyword := range larkDroppedKeywords {
+		if _, ok := s[keyword]; ok {
+			g.opts.Warn(path, "keyword %q is not enforced by the grammar", keyword)
+		}
+	}
+	t, _ := s["type"].(string)
+	switch t {
+	case "object":
+		return g.object(s, path, "")
+	case "array":
+		items, ok := s["items"].(Schema)
+		if !ok {
+			return "", fmt.Errorf("%w: %s: arrays without items cannot be expressed in a grammar", ErrUnsupportedType, displayPath(path))
+		}
+		item, err := g.value(items, path+"[]")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(`"[" (%s ("," %s)*)? "]"`, item, item), nil
+	}
+	terminal, ok := larkScalarTerminals[t]
+	if !ok {
+		return "", fmt.Errorf("%w: %s: type %v cannot be expressed in a grammar", ErrUnsupportedType, displayPath(path), s["type"])
+	}
+	g.terminals[terminal] = true
+	return terminal, nil
+}
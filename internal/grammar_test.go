@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderLarkGrammar(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"kind":     Schema{"const": "ticket"},
+			"priority": Schema{"type": "string", "enum": []interface{}{"low", "high"}},
+			"level":    Schema{"type": []string{"integer", "null"}, "enum": []interface{}{1, 2, nil}},
+			"code":     Schema{"type": "string", "pattern": "^[A-Z]+$"},
+			"owner": Schema{"anyOf": []Schema{
+				{
+					"type":       "object",
+					"properties": Schema{"name": Schema{"type": "string"}, "active": Schema{"type": "boolean"}},
+					"required":   []string{"name", "active"},
+				},
+				{"type": "null"},
+			}},
+			"tags":  Schema{"type": "array", "items": Schema{"type": "string"}},
+			"score": Schema{"type": "number"},
+		},
+		"required": []string{"kind", "priority", "level", "code", "owner", "tags"},
+	}
+	var warnings []string
+	opts := DefaultOptions()
+	opts.WarningHandler = func(w Warning) { warnings = append(warnings, w.Path) }
+	grammar, err := RenderLarkGrammar(schema, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `start: "{" "\"kind\"" ":" "\"ticket\"" "," "\"priority\"" ":" ("\"low\"" | "\"high\"") "," ` +
+		`"\"level\"" ":" (("1" | "2") | NULL) "," "\"code\"" ":" STRING "," "\"owner\"" ":" (owner | NULL) "," ` +
+		`"\"tags\"" ":" "[" (STRING ("," STRING)*)? "]" "}"
+owner: "{" "\"name\"" ":" STRING "," "\"active\"" ":" BOOLEAN "}"
+
+STRING: /"(?:[^"\\\x00-\x1f]|\\(?:["\\\/bfnrt]|u[0-9a-fA-F]{4}))*"/
+BOOLEAN: "true" | "false"
+NULL: "null"
+`
+	if grammar != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, grammar)
+	}
+	if !reflect.DeepEqual(warnings, []string{"code", "score"}) {
+		t.Errorf("expected warnings for the pattern and the optional property, got %v", warnings)
+	}
+}
+
+func TestRenderLarkGrammar_RuleNames(t *testing.T) {
+	object := Schema{"type": "object", "properties": Schema{}, "required": []string{}}
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"Start": object,
+			"a-b":   object,
+			"a_b":   object,
+			"3d":    object,
+			"lines": Schema{"type": "array", "items": object},
+		},
+		"required": []string{"Start", "a-b", "a_b", "3d", "lines"},
+	}
+	grammar, err := RenderLarkGrammar(schema, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, rule := range []string{"\nstart_2: ", "\na_b: ", "\na_b_2: ", "\nobject_3d: ", "\nlines_item: "} {
+		if !strings.Contains(grammar, rule) {
+			t.Errorf("expected rule %q in:\n%s", rule, grammar)
+		}
+	}
+}
+
+func TestRenderLarkGrammar_Unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		prop Schema
+	}{
+		{"map", Schema{"type": "object", "additionalProperties": Schema{"type": "string"}}},
+		{"union", Schema{"anyOf": []Schema{{"type": "string"}, {"type": "integer"}}}},
+		{"reference", Schema{"$ref": "#/$defs/Owner"}},
+		{"array without items", Schema{"type": "array"}},
+		{"any value", Schema{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := Schema{"type": "object", "properties": Schema{"value": tt.prop}, "required": []string{"value"}}
+			if _, err := RenderLarkGrammar(schema, DefaultOptions()); !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("expected ErrUnsupportedType, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// parseLengthHints reads the maxWords and hint tags of a field into the text appended to
+// its description, e.g. "at most 50 words; one sentence". maxWords only applies to strings
+// and arrays of strings, where it bounds each item.
+func parseLengthHints(fieldName string, field reflect.StructField) (string, error) {
+	var hints []string
+	if value, ok := field.Tag.Lookup("maxWords"); ok {
+		t := deref(field.Type)
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = deref(t.Elem())
+		}
+		if t.Kind() != reflect.String {
+			return "", fmt.Errorf("%w: maxWords tag on non-string field %q", ErrInvalidTag, fieldName)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("%w: maxWords tag on field %q must be a positive integer, got %q", ErrInvalidTag, fieldName, value)
+		}
+		if n == 1 {
+			hints = append(hints, "at most 1 word")
+		} else {
+			hints = append(hints, fmt.Sprintf("at most %d words", n))
+		}
+	}
+	if value, ok := field.Tag.Lookup("hint"); ok {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return "", fmt.Errorf("%w: hint tag on field %q must not be empty", ErrInvalidTag, fieldName)
+		}
+		hints = append(hints, value)
+	}
+	return strings.Join(hints, "; "), nil
+}
+
+// withLengthHints appends the hints to a property description in parentheses, or makes
+// them the description of an undescribed property
+func withLengthHints(prop interface{}, hints string) interface{} {
+	s, ok := prop.(Schema)
+	if !ok || hints == "" {
+		return prop
+	}
+	if description, ok := s["description"].(string); ok && description != "" {
+		return withDescription(s, description+" ("+hints+")")
+	}
+	first, size := utf8.DecodeRuneInString(hints)
+	return withDescription(s, string(unicode.ToUpper(first))+hints[size:])
+}
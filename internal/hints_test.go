@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLengthHintTags(t *testing.T) {
+	type Ticket struct {
+		Summary string   `json:"summary" description:"Ticket summary" maxWords:"50" hint:"one sentence"`
+		Title   string   `json:"title" maxWords:"8"`
+		Tone    string   `json:"tone" hint:"  formal or casual "`
+		Tags    []string `json:"tags" maxWords:"1"`
+		Body    *string  `json:"body,omitempty" maxWords:"200"`
+	}
+	result, err := runJsonTypeOf(reflect.TypeOf(Ticket{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props := result.(Schema)["properties"].(Schema)
+	expected := map[string]string{
+		"summary": "Ticket summary (at most 50 words; one sentence)",
+		"title":   "At most 8 words",
+		"tone":    "Formal or casual",
+		"tags":    "At most 1 word",
+		"body":    "At most 200 words",
+	}
+	for name, description := range expected {
+		if got := props[name].(Schema)["description"]; got != description {
+			t.Errorf("%s: expected %q, got %q", name, description, got)
+		}
+	}
+
+	invalid := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "non-string field", value: struct {
+			Count int `json:"count" maxWords:"3"`
+		}{}},
+		{name: "zero words", value: struct {
+			Text string `json:"text" maxWords:"0"`
+		}{}},
+		{name: "not a number", value: struct {
+			Text string `json:"text" maxWords:"few"`
+		}{}},
+		{name: "empty hint", value: struct {
+			Text string `json:"text" hint:" "`
+		}{}},
+	}
+	for _, tt := range invalid {
+		if _, err := runJsonTypeOf(reflect.TypeOf(tt.value)); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("%s: expected ErrInvalidTag, got %v", tt.name, err)
+		}
+	}
+}
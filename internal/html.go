@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+)
+
+// FieldNode is a property of an object schema with its nested properties
+type FieldNode struct {
+	FieldInfo
+	Name     string
+	Children []FieldNode
+}
+
+// FieldTree returns the properties of an object schema as a tree,
+// array items and map values are represented by their nested properties
+func FieldTree(s Schema) []FieldNode {
+	return fieldTree(s, "")
+}
+
+func fieldTree(s Schema, path string) []FieldNode {
+	props, _ := s["properties"].(Schema)
+	required := make(map[string]bool)
+	if names, ok := s["required"].([]string); ok {
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	var nodes []FieldNode
+	for _, name := range orderedProperties(s) {
+		prop, ok := props[name].(Schema)
+		if !ok {
+			continue
+		}
+		unwrapped, nullable := unwrapNullable(prop)
+		description, _ := unwrapped["description"].(string)
+		fieldPath := joinPath(path, name)
+		nodes = append(nodes, FieldNode{
+			FieldInfo: FieldInfo{
+				Path:        fieldPath,
+				Schema:      unwrapped,
+				Required:    required[name],
+				Nullable:    nullable,
+				Description: description,
+			},
+			Name:     name,
+			Children: nestedTree(unwrapped, fieldPath),
+		})
+	}
+	return nodes
+}
+
+// nested properties of a property, following array items and map values
+func nestedTree(s Schema, path string) []FieldNode {
+	if _, ok := s["properties"].(Schema); ok {
+		return fieldTree(s, path)
+	}
+	if items, ok := s["items"].(Schema); ok {
+		items, _ = unwrapNullable(items)
+		return nestedTree(items, path+"[]")
+	}
+	if values, ok := s["additionalProperties"].(Schema); ok {
+		values, _ = unwrapNullable(values)
+		return nestedTree(values, path+"{}")
+	}
+	return nil
+}
+
+// HTMLDocument is a named schema rendered in an HTML page
+type HTMLDocument struct {
+	Name   string
+	Schema Schema
+}
+
+// view models of the HTML page
+type htmlSection struct {
+	Name        string
+	Anchor      string
+	Description string
+	Fields      []htmlField
+}
+
+type htmlField struct {
+	Name        string
+	Anchor      string
+	Type        string
+	Required    bool
+	Nullable    bool
+	Description string
+	Constraints []string
+	Children    []htmlField
+}
+
+func htmlFields(doc string, nodes []FieldNode) []htmlField {
+	fields := make([]htmlField, len(nodes))
+	for i, node := range nodes {
+		fields[i] = htmlField{
+			Name:        node.Name,
+			Anchor:      htmlAnchor(doc, node.Path),
+			Type:        TypeLabel(node.Schema),
+			Required:    node.Required,
+			Nullable:    node.Nullable,
+			Description: node.Description,
+			Constraints: Constraints(node.Schema),
+			Children:    htmlFields(doc, node.Children),
+		}
+	}
+	return fields
+}
+
+var htmlTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 60rem; color: #1f2328; }
+ul { list-style: none; padding-left: 1.25rem; }
+summary { cursor: pointer; }
+code { background: #f6f8fa; padding: 0 .25rem; border-radius: 4px; }
+.type { color: #0969da; }
+.badge { font-size: .75rem; border: 1px solid #d0d7de; border-radius: 1rem; padding: 0 .4rem; margin-left: .25rem; }
+.note { color: #59636e; margin: .15rem 0 .15rem 1rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<nav>
+<ul>
+{{- range .Sections}}
+<li><a href="#{{.Anchor}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+</nav>
+{{- range .Sections}}
+<section id="{{.Anchor}}">
+<h2><a href="#{{.Anchor}}">{{.Name}}</a></h2>
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+{{template "fields" .Fields}}
+</section>
+{{- end}}
+</body>
+</html>
+{{define "fields"}}<ul>
+{{- range .}}
+<li id="{{.Anchor}}">
+{{- if .Children}}
+<details open>
+<summary>{{template "field" .}}</summary>
+{{template "fields" .Children}}
+</details>
+{{- else}}
+{{template "field" .}}
+{{- end}}
+</li>
+{{- end}}
+</ul>{{end}}
+{{define "field"}}<a href="#{{.Anchor}}"><code>{{.Name}}</code></a> <span class="type">{{.Type}}</span>
+{{- if .Required}}<span class="badge">required</span>{{end}}
+{{- if .Nullable}}<span class="badge">nullable</span>{{end}}
+{{- with .Description}}<p class="note">{{.}}</p>{{end}}
+{{- range .Constraints}}<p class="note">{{.}}</p>{{end}}
+{{- end}}
+`))
+
+// htmlAnchor builds the id of a schema or of one of its fields
+func htmlAnchor(doc, path string) string {
+	replacer := strings.NewReplacer("[]", "-items", "{}", "-values", ".", "-", " ", "-")
+	if path == "" {
+		return "schema-" + replacer.Replace(doc)
+	}
+	return "schema-" + replacer.Replace(doc) + "-" + replacer.Replace(path)
+}
+
+// RenderHTML renders named schemas into a static HTML page with collapsible nested
+// objects and an anchor per schema and field
+func RenderHTML(title string, docs []HTMLDocument) (string, error) {
+	sections := make([]htmlSection, len(docs))
+	for i, doc := range docs {
+		description, _ := doc.Schema["description"].(string)
+		sections[i] = htmlSection{
+			Name:        doc.Name,
+			Anchor:      htmlAnchor(doc.Name, ""),
+			Description: description,
+			Fields:      htmlFields(doc.Name, FieldTree(doc.Schema)),
+		}
+	}
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, struct {
+		Title    string
+		Sections []htmlSection
+	}{title, sections})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
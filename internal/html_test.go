@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldTree(t *testing.T) {
+	nodes := FieldTree(EmployeeSchema)
+	if len(nodes) != 3 {
+		t.Fatalf("expected 3 root fields, got %d", len(nodes))
+	}
+	companies := nodes[1]
+	if companies.Name != "companies" || len(companies.Children) != 2 {
+		t.Fatalf("expected companies with 2 nested fields, got %+v", companies)
+	}
+	address := companies.Children[1]
+	if address.Path != "companies[].address" || len(address.Children) != 3 {
+		t.Errorf("expected address with 3 nested fields, got %+v", address)
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	page, err := RenderHTML("Contracts <v1>", []HTMLDocument{
+		{Name: "employee", Schema: EmployeeSchema},
+		{Name: "contact", Schema: DescribedContactSchema},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, part := range []string{
+		"<title>Contracts &lt;v1&gt;</title>",
+		`<li><a href="#schema-employee">employee</a></li>`,
+		`<section id="schema-contact">`,
+		`<li id="schema-employee-companies-items-address-zip_code">`,
+		"<details open>",
+		`<p class="note">Full name of the contact</p>`,
+	} {
+		if !strings.Contains(page, part) {
+			t.Errorf("RenderHTML() result missing expected part %s", part)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// interner shares structurally identical subschemas, each distinct subschema gets an id
+// used in the keys of the schemas containing it
+type interner struct {
+	ids     map[string]int
+	schemas []Schema
+}
+
+// Intern replaces structurally identical subschemas with a single shared instance, so
+// large schemas repeating {"type":"string"} thousands of times hold it once. The root is
+// not shared, the input is not modified and the result must be treated as immutable.
+func Intern(schema Schema) Schema {
+	in := &interner{ids: make(map[string]int)}
+	root, _ := in.children(schema)
+	return root
+}
+
+// intern returns the shared instance of a subschema and its id
+func (in *interner) intern(s Schema) (Schema, int) {
+	copied, key := in.children(s)
+	if id, ok := in.ids[key]; ok {
+		return in.schemas[id], id
+	}
+	id := len(in.schemas)
+	in.ids[key] = id
+	in.schemas = append(in.schemas, copied)
+	return copied, id
+}
+
+// children copies a schema with its subschemas interned, and returns the key identifying
+// its structure: subschemas are written by id, other values with their Go type
+func (in *interner) children(s Schema) (Schema, string) {
+	out := make(Schema, len(s))
+	var key strings.Builder
+	for _, k := range sortedKeys(s) {
+		fmt.Fprintf(&key, "%q:", k)
+		switch v := s[k].(type) {
+		case Schema:
+			shared, id := in.intern(v)
+			out[k] = shared
+			fmt.Fprintf(&key, "#%d,", id)
+		case []Schema:
+			shared := make([]Schema, len(v))
+			key.WriteString("[")
+			for i, item := range v {
+				var id int
+				shared[i], id = in.intern(item)
+				fmt.Fprintf(&key, "#%d,", id)
+			}
+			key.WriteString("],")
+			out[k] = shared
+		default:
+			out[k] = v
+			fmt.Fprintf(&key, "%#v,", v)
+		}
+	}
+	return out, key.String()
+}
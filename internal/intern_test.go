@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIntern(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"name": Schema{"type": "string"},
+			"city": Schema{"type": "string"},
+			"tags": Schema{"type": "array", "items": Schema{"type": "string"}},
+			"code": Schema{"type": []string{"string", "null"}},
+			"kind": Schema{"type": []interface{}{"string", "null"}},
+			"home": Schema{"anyOf": []Schema{{"type": "string"}, {"type": "null"}}},
+		},
+		"required": []string{"name", "city", "tags", "code", "kind", "home"},
+	}
+	interned := Intern(schema)
+	if !reflect.DeepEqual(interned, schema) {
+		t.Fatalf("expected %+v, got %+v", schema, interned)
+	}
+	props := interned["properties"].(Schema)
+	same := func(a, b interface{}) bool {
+		return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+	}
+	name := props["name"].(Schema)
+	for _, shared := range []interface{}{
+		props["city"],
+		props["tags"].(Schema)["items"],
+		props["home"].(Schema)["anyOf"].([]Schema)[0],
+	} {
+		if !same(name, shared) {
+			t.Errorf("expected %+v to be shared", shared)
+		}
+	}
+	// equal JSON with different Go types is kept apart
+	if same(props["code"], props["kind"]) {
+		t.Errorf("expected subschemas with different value types to stay distinct")
+	}
+	// the input is not modified
+	if same(schema["properties"].(Schema)["name"], schema["properties"].(Schema)["city"]) {
+		t.Errorf("expected the input to be left unchanged")
+	}
+}
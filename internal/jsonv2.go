@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// omittedV2 reports whether encoding/json/v2 may omit a field: omitzero omits zero values,
+// while omitempty only omits values encoded as null, "", {} or [], so numbers, booleans
+// and structs tagged with omitempty are always present
+func omittedV2(t reflect.Type, jsonTag string) bool {
+	if hasTagOption(jsonTag, "omitzero") {
+		return true
+	}
+	if !hasTagOption(jsonTag, "omitempty") {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Pointer, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// quotedV2 reports whether the ",string" option changes the encoding of a type,
+// encoding/json/v2 only applies it to numbers
+func quotedV2(t reflect.Type) bool {
+	switch deref(t).Kind() {
+	case reflect.String, reflect.Bool:
+		return false
+	default:
+		return true
+	}
+}
+
+// lookupField finds the property name and field matching a key, encoding/json matches
+// names case-insensitively while encoding/json/v2 requires an exact match
+func lookupField(fields map[string]decodeField, key string, opts *Options) (string, decodeField, bool) {
+	if field, ok := fields[key]; ok || opts.JSONv2 {
+		return key, field, ok
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return name, field, true
+		}
+	}
+	return "", decodeField{}, false
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSONv2Semantics(t *testing.T) {
+	quotedBool, _ := quotedScalarSchema(reflect.TypeOf(false))
+	quotedInt, _ := quotedScalarSchema(reflect.TypeOf(0))
+	tests := []struct {
+		property string
+		v1       interface{}
+		v2       interface{}
+	}{
+		{property: "count", v1: Schema{"type": []string{"integer", "null"}}, v2: Schema{"type": "integer"}},
+		{property: "note", v1: Schema{"type": []string{"string", "null"}}, v2: Schema{"type": []string{"string", "null"}}},
+		{property: "ready", v1: Schema{"type": "boolean"}, v2: Schema{"type": []string{"boolean", "null"}}},
+		{property: "flag", v1: quotedBool, v2: Schema{"type": "boolean"}},
+		{property: "total", v1: quotedInt, v2: quotedInt},
+	}
+	for _, v2 := range []bool{false, true} {
+		opts, visited, depth := getInputs()
+		opts.JSONv2 = v2
+		result, err := JsonTypeOf(reflect.TypeOf(V2Tags{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		props := result.(Schema)["properties"].(Schema)
+		for _, tt := range tests {
+			expected := tt.v1
+			if v2 {
+				expected = tt.v2
+			}
+			if !reflect.DeepEqual(props[tt.property], expected) {
+				t.Errorf("v2=%t %s: expected %+v, got %+v", v2, tt.property, expected, props[tt.property])
+			}
+		}
+	}
+}
+
+func TestLookupField(t *testing.T) {
+	fields := map[string]decodeField{"count": {Type: reflect.TypeOf(0)}}
+	opts := DefaultOptions()
+	if _, _, ok := lookupField(fields, "Count", opts); !ok {
+		t.Errorf("expected case-insensitive match with encoding/json semantics")
+	}
+	opts.JSONv2 = true
+	if _, _, ok := lookupField(fields, "Count", opts); ok {
+		t.Errorf("expected exact match with encoding/json/v2 semantics")
+	}
+	if _, _, ok := lookupField(fields, "count", opts); !ok {
+		t.Errorf("expected exact match to be found")
+	}
+}
@@ -0,0 +1,120 @@
+package internal
+
+// jtdNumberTypes maps numeric formats to JTD types, JTD has no 64-bit integers
+// so int64 values are accepted as float64
+var jtdNumberTypes = map[string]string{
+	"int32":  "int32",
+	"int64":  "float64",
+	"float":  "float32",
+	"double": "float64",
+}
+
+// RenderJTD converts a schema generated in non-strict mode with numeric formats into a
+// JSON Type Definition (RFC 8927). Constraints JTD cannot express, such as patterns,
+// ranges and non-string enums, are dropped. Unions other than nullable become the empty
+// form, which accepts any value, and are reported as warnings.
+func RenderJTD(schema Schema, opts *Options) Schema {
+	return jtdForm(schema, "", opts)
+}
+
+func jtdForm(s Schema, path string, opts *Options) Schema {
+	s, nullable := unwrapNullable(s)
+	jtd := make(Schema)
+	if description, ok := s["description"].(string); ok {
+		jtd["metadata"] = Schema{"description": description}
+	}
+	switch s["type"] {
+	case "string":
+		if values, ok := s["enum"].([]interface{}); ok && allStrings(values) {
+			jtd["enum"] = values
+		} else if s["format"] == "date-time" {
+			jtd["type"] = "timestamp"
+		} else {
+			jtd["type"] = "string"
+		}
+	case "boolean":
+		jtd["type"] = "boolean"
+	case "integer", "number":
+		format, _ := s["format"].(string)
+		t, ok := jtdNumberTypes[format]
+		if !ok {
+			t = "float64"
+			if s["type"] == "integer" {
+				t = "int32"
+			}
+		}
+		jtd["type"] = t
+	case "array":
+		if items, ok := s["items"].(Schema); ok {
+			jtd["elements"] = jtdForm(items, path+"[]", opts)
+		} else {
+			jtd["elements"] = Schema{}
+		}
+	case "object":
+		jtdObject(s, jtd, path, opts)
+	case "null":
+		nullable = true
+	default:
+		_, isUnion := s["anyOf"]
+		if _, ok := s["type"].([]string); ok || isUnion {
+			opts.Warn(path, "union cannot be expressed in JTD, any value is accepted")
+		}
+	}
+	if nullable {
+		jtd["nullable"] = true
+	}
+	return jtd
+}
+
+func jtdObject(s, jtd Schema, path string, opts *Options) {
+	if values, ok := s["additionalProperties"].(Schema); ok {
+		jtd["values"] = jtdForm(values, path+"{}", opts)
+		return
+	}
+	if patterns, ok := s["patternProperties"].(Schema); ok {
+		for _, pattern := range sortedKeys(patterns) {
+			if values, ok := patterns[pattern].(Schema); ok {
+				jtd["values"] = jtdForm(values, path+"{}", opts)
+				return
+			}
+		}
+	}
+	props, _ := s["properties"].(Schema)
+	required := make(map[string]bool)
+	if names, ok := s["required"].([]string); ok {
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	properties, optional := make(Schema), make(Schema)
+	for name, prop := range props {
+		sub, ok := prop.(Schema)
+		if !ok {
+			continue
+		}
+		if required[name] {
+			properties[name] = jtdForm(sub, joinPath(path, name), opts)
+		} else {
+			optional[name] = jtdForm(sub, joinPath(path, name), opts)
+		}
+	}
+	// the properties form needs properties or optionalProperties, even when empty
+	if len(properties) > 0 || len(optional) == 0 {
+		jtd["properties"] = properties
+	}
+	if len(optional) > 0 {
+		jtd["optionalProperties"] = optional
+	}
+	if s["additionalProperties"] == true {
+		jtd["additionalProperties"] = true
+	}
+}
+
+func allStrings(values []interface{}) bool {
+	for _, v := range values {
+		if _, ok := v.(string); !ok {
+			return false
+		}
+	}
+	return len(values) > 0
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderJTD(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   Schema
+		expected Schema
+	}{
+		{
+			name: "scalars",
+			schema: Schema{"type": "object", "properties": Schema{
+				"name":    Schema{"type": "string", "description": "display name", "minLength": 1},
+				"created": Schema{"type": "string", "format": "date-time"},
+				"age":     Schema{"type": "integer", "format": "int32"},
+				"views":   Schema{"type": "integer", "format": "int64"},
+				"ratio":   Schema{"type": "number", "format": "float"},
+				"active":  Schema{"type": "boolean"},
+			}, "required": []string{"name", "created", "age", "views", "ratio", "active"}, "additionalProperties": false},
+			expected: Schema{"properties": Schema{
+				"name":    Schema{"type": "string", "metadata": Schema{"description": "display name"}},
+				"created": Schema{"type": "timestamp"},
+				"age":     Schema{"type": "int32"},
+				"views":   Schema{"type": "float64"},
+				"ratio":   Schema{"type": "float32"},
+				"active":  Schema{"type": "boolean"},
+			}},
+		},
+		{
+			name: "nullable, optional and enums",
+			schema: Schema{"type": "object", "properties": Schema{
+				"status": Schema{"type": []string{"string", "null"}, "enum": []interface{}{"open", "closed", nil}},
+				"level":  Schema{"type": "integer", "enum": []interface{}{1, 2}},
+				"home": Schema{"anyOf": []Schema{
+					{"type": "object", "properties": Schema{}, "additionalProperties": false},
+					{"type": "null"},
+				}},
+			}, "required": []string{"status", "home"}},
+			expected: Schema{
+				"properties": Schema{
+					"status": Schema{"enum": []interface{}{"open", "closed"}, "nullable": true},
+					"home":   Schema{"properties": Schema{}, "nullable": true},
+				},
+				"optionalProperties": Schema{"level": Schema{"type": "int32"}},
+			},
+		},
+		{
+			name: "collections",
+			schema: Schema{"type": "object", "properties": Schema{
+				"tags":   Schema{"type": "array", "items": Schema{"type": "string"}},
+				"scores": Schema{"type": "object", "additionalProperties": Schema{"type": "number", "format": "double"}},
+				"extra":  Schema{"type": "object", "properties": Schema{}, "additionalProperties": true},
+			}, "required": []string{"tags", "scores", "extra"}},
+			expected: Schema{"properties": Schema{
+				"tags":   Schema{"elements": Schema{"type": "string"}},
+				"scores": Schema{"values": Schema{"type": "float64"}},
+				"extra":  Schema{"properties": Schema{}, "additionalProperties": true},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderJTD(tt.schema, DefaultOptions())
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRenderJTD_Union(t *testing.T) {
+	var warnings []string
+	opts := DefaultOptions()
+	opts.WarningHandler = func(w Warning) { warnings = append(warnings, w.String()) }
+	schema := Schema{"type": "object", "properties": Schema{
+		"id": Schema{"anyOf": []Schema{{"type": "string"}, {"type": "integer"}}},
+	}, "required": []string{"id"}}
+	result := RenderJTD(schema, opts)
+	expected := Schema{"properties": Schema{"id": Schema{}}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+	if want := []string{"id: union cannot be expressed in JTD, any value is accepted"}; !reflect.DeepEqual(warnings, want) {
+		t.Errorf("expected warnings %v, got %v", want, warnings)
+	}
+}
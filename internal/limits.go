@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Limits are the numeric limits a provider enforces on schemas, checked by Lint and by
+// generation with a limit policy. A zero value disables the check.
+type Limits struct {
+	// MaxNesting is the depth of nested objects
+	MaxNesting int
+	// MaxProperties is the total number of properties
+	MaxProperties int
+	// MaxEnumValues is the total number of enum values
+	MaxEnumValues int
+	// MaxStringLength is the total length of the property names and enum strings
+	MaxStringLength int
+	// MaxLargeEnumStringLength is the total length of the strings of a single enum
+	// with more than LargeEnumValues values
+	MaxLargeEnumStringLength int
+	LargeEnumValues          int
+	// MaxSchemaBytes is the size budget of the marshaled schema, used when WithMaxSchemaBytes is not set
+	MaxSchemaBytes int
+}
+
+// Validate reports negative limits
+func (l Limits) Validate() error {
+	for _, limit := range []struct {
+		name  string
+		value int
+	}{
+		{"MaxNesting", l.MaxNesting},
+		{"MaxProperties", l.MaxProperties},
+		{"MaxEnumValues", l.MaxEnumValues},
+		{"MaxStringLength", l.MaxStringLength},
+		{"MaxLargeEnumStringLength", l.MaxLargeEnumStringLength},
+		{"LargeEnumValues", l.LargeEnumValues},
+		{"MaxSchemaBytes", l.MaxSchemaBytes},
+	} {
+		if limit.value < 0 {
+			return fmt.Errorf("limit %s must not be negative, got %d", limit.name, limit.value)
+		}
+	}
+	return nil
+}
+
+// openAILimits are the limits published for OpenAI structured outputs
+// https://platform.openai.com/docs/guides/structured-outputs#supported-schemas
+var openAILimits = Limits{
+	MaxNesting:               10,
+	MaxProperties:            5000,
+	MaxEnumValues:            1000,
+	MaxStringLength:          120000,
+	MaxLargeEnumStringLength: 15000,
+	LargeEnumValues:          250,
+}
+
+var (
+	limitsMu sync.RWMutex
+	// providerLimits start from the OpenAI limits, the other providers do not publish
+	// theirs and accept schemas within them
+	providerLimits = map[Provider]Limits{
+		ProviderOpenAI:    openAILimits,
+		ProviderAnthropic: openAILimits,
+		ProviderGemini:    openAILimits,
+	}
+)
+
+// ProviderLimits returns the limits of a provider, the OpenAI limits when provider is empty
+func ProviderLimits(provider Provider) Limits {
+	if provider == "" {
+		provider = ProviderOpenAI
+	}
+	limitsMu.RLock()
+	defer limitsMu.RUnlock()
+	return providerLimits[provider]
+}
+
+// SetProviderLimits replaces the limits of a provider for every later call
+func SetProviderLimits(provider Provider, limits Limits) error {
+	if !provider.Valid() {
+		return fmt.Errorf("%w: unknown provider %q", ErrInvalidOption, provider)
+	}
+	if err := limits.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOption, err)
+	}
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	providerLimits[provider] = limits
+	return nil
+}
+
+// EffectiveLimits returns the limits set with WithLimits, or those of the selected provider
+func (o *Options) EffectiveLimits() Limits {
+	if o.Limits != nil {
+		return *o.Limits
+	}
+	return ProviderLimits(o.Provider)
+}
+
+// SchemaBudget returns the size budget of generated schemas, WithMaxSchemaBytes taking
+// precedence over the limits, 0 when there is none
+func (o *Options) SchemaBudget() int {
+	if o.MaxSchemaBytes > 0 {
+		return o.MaxSchemaBytes
+	}
+	return o.EffectiveLimits().MaxSchemaBytes
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProviderLimits(t *testing.T) {
+	if got := ProviderLimits(""); got != openAILimits {
+		t.Errorf("expected the OpenAI limits without a provider, got %+v", got)
+	}
+	defer SetProviderLimits(ProviderGemini, ProviderLimits(ProviderGemini))
+	raised := openAILimits
+	raised.MaxProperties = 10000
+	if err := SetProviderLimits(ProviderGemini, raised); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ProviderLimits(ProviderGemini); got != raised {
+		t.Errorf("expected %+v, got %+v", raised, got)
+	}
+	if got := ProviderLimits(ProviderOpenAI); got != openAILimits {
+		t.Errorf("expected the other profiles to keep their limits, got %+v", got)
+	}
+
+	if err := SetProviderLimits("mistral", raised); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption for an unknown provider, got %v", err)
+	}
+	raised.MaxNesting = -1
+	if err := SetProviderLimits(ProviderGemini, raised); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption for a negative limit, got %v", err)
+	}
+}
+
+func TestEffectiveLimits(t *testing.T) {
+	o := DefaultOptions()
+	if got := o.EffectiveLimits(); got != openAILimits {
+		t.Errorf("expected the OpenAI limits by default, got %+v", got)
+	}
+	if got := o.SchemaBudget(); got != 0 {
+		t.Errorf("expected no size budget by default, got %d", got)
+	}
+	o.Limits = &Limits{MaxNesting: 3, MaxSchemaBytes: 2048}
+	if got := o.EffectiveLimits(); got != *o.Limits {
+		t.Errorf("expected the overridden limits, got %+v", got)
+	}
+	if got := o.SchemaBudget(); got != 2048 {
+		t.Errorf("expected the budget of the limits, got %d", got)
+	}
+	o.MaxSchemaBytes = 1024
+	if got := o.SchemaBudget(); got != 1024 {
+		t.Errorf("expected WithMaxSchemaBytes to take precedence, got %d", got)
+	}
+}
+
+func TestLint_DisabledLimits(t *testing.T) {
+	values := make([]interface{}, openAILimits.MaxEnumValues+1)
+	for i := range values {
+		values[i] = i
+	}
+	schema := Schema{"type": "integer", "enum": values}
+	if issues := LintLimits(schema, openAILimits); len(issues) != 1 {
+		t.Errorf("expected a max-enum-values issue, got %+v", issues)
+	}
+	if issues := LintLimits(schema, Limits{}); len(issues) != 0 {
+		t.Errorf("expected zero limits to be skipped, got %+v", issues)
+	}
+}
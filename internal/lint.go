@@ -0,0 +1,268 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// limitRules are the rules checking published size limits, as opposed to the
+// structural rules of strict mode
+var limitRules = map[string]bool{
+	"max-nesting":            true,
+	"max-properties":         true,
+	"max-enum-values":        true,
+	"max-string-length":      true,
+	"max-enum-string-length": true,
+}
+
+// keywords rejected by OpenAI strict mode
+var strictUnsupportedKeywords = []string{
+	"patternProperties",
+	"propertyNames",
+	"unevaluatedProperties",
+	"minProperties",
+	"maxProperties",
+	"allOf",
+	"not",
+	"if",
+	"then",
+	"else",
+	"dependentRequired",
+	"contains",
+}
+
+// StripUnsupported removes the keywords rejected by OpenAI strict mode, such as not and
+// contains, and reports a warning for each removed keyword. The input is not modified.
+func StripUnsupported(schema Schema) (Schema, []Warning) {
+	return stripKeywords(schema, strictUnsupportedKeywords, nil, "it is not supported in strict mode")
+}
+
+// LintKeywords reports every keyword of the schema and its subschemas missing from allowed
+func LintKeywords(schema Schema, allowed map[string]bool) []LintIssue {
+	var issues []LintIssue
+	transformSchema(schema, "", func(s Schema, path string) Schema {
+		for _, keyword := range sortedKeys(s) {
+			if !allowed[keyword] {
+				issues = append(issues, LintIssue{Path: path, Rule: "disallowed-keyword", Message: fmt.Sprintf("keyword %q is not allowed", keyword)})
+			}
+		}
+		return s
+	})
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues
+}
+
+// LintIssue describes a part of a schema a provider would reject
+type LintIssue struct {
+	// Path of the offending property, e.g. "companies[].address", empty for the root
+	Path string
+	// Rule is the name of the violated rule
+	Rule string
+	// Message explains the issue
+	Message string
+	// Suggestion is a replacement value fixing the issue, such as a valid property name
+	Suggestion string
+}
+
+func (i LintIssue) String() string {
+	path := i.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s (%s)", path, i.Message, i.Rule)
+}
+
+// linter walks a schema and collects issues
+type linter struct {
+	strict     bool
+	limits     Limits
+	issues     []LintIssue
+	properties int
+	enumValues int
+	// total length of the property names and enum strings
+	stringLength int
+}
+
+func (l *linter) report(path, rule, format string, args ...interface{}) {
+	l.issues = append(l.issues, LintIssue{Path: path, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// Lint checks a schema against the requirements of OpenAI structured outputs and the given limits.
+// Strict mode adds the rules of strict structured outputs on top of the limits, and the
+// registered custom rules run in every mode.
+func Lint(schema Schema, strict bool, limits Limits) []LintIssue {
+	l := &linter{strict: strict, limits: limits}
+	if strict && schema["type"] != "object" {
+		l.report("", "root-object", "the root schema must be an object")
+	}
+	l.walk(schema, "", 0)
+	if exceeds(l.properties, limits.MaxProperties) {
+		l.report("", "max-properties", "schema has %d properties, the limit is %d", l.properties, limits.MaxProperties)
+	}
+	if exceeds(l.enumValues, limits.MaxEnumValues) {
+		l.report("", "max-enum-values", "schema has %d enum values, the limit is %d", l.enumValues, limits.MaxEnumValues)
+	}
+	if exceeds(l.stringLength, limits.MaxStringLength) {
+		l.report("", "max-string-length", "property names and enum values total %d characters, the limit is %d",
+			l.stringLength, limits.MaxStringLength)
+	}
+	return append(l.issues, lintCustom(schema)...)
+}
+
+// LimitPolicy selects how schema generation handles exceeded size limits
+type LimitPolicy string
+
+const (
+	// LimitsIgnore leaves the limits to Lint
+	LimitsIgnore LimitPolicy = "ignore"
+	// LimitsWarn reports exceeded limits as warnings
+	LimitsWarn LimitPolicy = "warn"
+	// LimitsFail fails generation when a limit is exceeded
+	LimitsFail LimitPolicy = "fail"
+)
+
+// Valid reports whether p is one of the supported limit policies
+func (p LimitPolicy) Valid() bool {
+	switch p {
+	case LimitsIgnore, LimitsWarn, LimitsFail:
+		return true
+	}
+	return false
+}
+
+// LintLimits only checks the size limits, which apply in every mode
+func LintLimits(schema Schema, limits Limits) []LintIssue {
+	var issues []LintIssue
+	for _, issue := range Lint(schema, false, limits) {
+		if limitRules[issue.Rule] {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// exceeds reports whether a value is above a limit, 0 disabling the limit
+func exceeds(value, limit int) bool {
+	return limit > 0 && value > limit
+}
+
+// join a parent path and a property name
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func (l *linter) walk(schema Schema, path string, nesting int) {
+	if l.strict {
+		for _, keyword := range strictUnsupportedKeywords {
+			if _, ok := schema[keyword]; ok {
+				l.report(path, "unsupported-keyword", "keyword %q is not supported in strict mode", keyword)
+			}
+		}
+	}
+	if values, ok := schema["enum"].([]interface{}); ok {
+		l.enumValues += len(values)
+		enumLength := 0
+		for _, v := range values {
+			if str, ok := v.(string); ok {
+				enumLength += utf8.RuneCountInString(str)
+			}
+		}
+		l.stringLength += enumLength
+		if len(values) > l.limits.LargeEnumValues && exceeds(enumLength, l.limits.MaxLargeEnumStringLength) {
+			l.report(path, "max-enum-string-length", "enum of %d values totals %d characters, the limit is %d above %d values",
+				len(values), enumLength, l.limits.MaxLargeEnumStringLength, l.limits.LargeEnumValues)
+		}
+	}
+	if props, ok := schema["properties"].(Schema); ok {
+		l.walkObject(schema, props, path, nesting+1)
+	}
+	if items, ok := schema["items"].(Schema); ok {
+		l.walk(items, path+"[]", nesting)
+	}
+	if values, ok := schema["additionalProperties"].(Schema); ok {
+		l.walk(values, path+"{}", nesting+1)
+	}
+	if patterns, ok := schema["patternProperties"].(Schema); ok {
+		for _, v := range patterns {
+			if sub, ok := v.(Schema); ok {
+				l.walk(sub, path+"{}", nesting+1)
+			}
+		}
+	}
+	if contains, ok := schema["contains"].(Schema); ok {
+		l.walk(contains, path+"[]", nesting)
+	}
+	// tuples, prefixItems (2020-12) or an items array (draft-07)
+	for _, keyword := range []string{"prefixItems", "items"} {
+		if entries, ok := schema[keyword].([]Schema); ok {
+			for _, entry := range entries {
+				l.walk(entry, path+"[]", nesting)
+			}
+		}
+	}
+	for _, keyword := range []string{"anyOf", "allOf", "oneOf"} {
+		if variants, ok := schema[keyword].([]Schema); ok {
+			for _, variant := range variants {
+				l.walk(variant, path, nesting)
+			}
+		}
+	}
+	if not, ok := schema["not"].(Schema); ok {
+		l.walk(not, path, nesting)
+	}
+	// definitions are checked once, as if they were roots, wherever they are referenced
+	for _, keyword := range defsKeywords {
+		if defs, ok := schema[keyword].(Schema); ok {
+			for _, name := range sortedKeys(defs) {
+				if def, ok := defs[name].(Schema); ok {
+					l.walk(def, joinPath(keyword, name), 0)
+				}
+			}
+		}
+	}
+}
+
+func (l *linter) walkObject(schema, props Schema, path string, nesting int) {
+	if exceeds(nesting, l.limits.MaxNesting) {
+		l.report(path, "max-nesting", "objects are nested %d levels deep, the limit is %d", nesting, l.limits.MaxNesting)
+	}
+	l.properties += len(props)
+	for name := range props {
+		l.stringLength += utf8.RuneCountInString(name)
+	}
+	if l.strict {
+		if schema["additionalProperties"] != false {
+			l.report(path, "additional-properties", "additionalProperties must be false")
+		}
+		required := make(map[string]bool)
+		if names, ok := schema["required"].([]string); ok {
+			for _, name := range names {
+				required[name] = true
+			}
+		}
+		for _, name := range sortedKeys(props) {
+			if !required[name] {
+				l.report(joinPath(path, name), "required-properties", "every property must be required")
+			}
+		}
+	}
+	for _, name := range sortedKeys(props) {
+		if sub, ok := props[name].(Schema); ok {
+			l.walk(sub, joinPath(path, name), nesting)
+		}
+	}
+}
+
+// sortedKeys returns the keys of a schema in a stable order
+func sortedKeys(s Schema) []string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,234 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   Schema
+		strict   bool
+		expected []LintIssue
+	}{
+		{
+			name:     "generated schema passes",
+			schema:   EmployeeSchema,
+			strict:   true,
+			expected: nil,
+		},
+		{
+			name:   "root must be an object",
+			schema: Schema{"type": "array", "items": Schema{"type": "string"}},
+			strict: true,
+			expected: []LintIssue{
+				{Path: "", Rule: "root-object", Message: "the root schema must be an object"},
+			},
+		},
+		{
+			name: "additional properties and required",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"name": Schema{"type": "string"},
+					"tags": Schema{
+						"type":                 "object",
+						"additionalProperties": Schema{"type": "string"},
+					},
+				},
+				"required":             []string{"tags"},
+				"additionalProperties": false,
+			},
+			strict: true,
+			expected: []LintIssue{
+				{Path: "name", Rule: "required-properties", Message: "every property must be required"},
+			},
+		},
+		{
+			name: "unsupported keywords in strict mode",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"labels": StructWithMapsSchema["properties"].(Schema)["labels"],
+				},
+				"required":             []string{"labels"},
+				"additionalProperties": false,
+			},
+			strict: true,
+			expected: []LintIssue{
+				{Path: "labels", Rule: "unsupported-keyword", Message: "keyword \"patternProperties\" is not supported in strict mode"},
+			},
+		},
+		{
+			name: "subschemas of every keyword",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"pair": Schema{
+						"type":        "array",
+						"prefixItems": []Schema{{"type": "object", "properties": Schema{}}},
+					},
+					"shape": Schema{
+						"oneOf": []Schema{{"type": "object", "properties": Schema{}}},
+						"allOf": []Schema{{"type": "object", "properties": Schema{}}},
+					},
+					"point": Schema{"$ref": "#/$defs/Point"},
+				},
+				"required":             []string{"pair", "shape", "point"},
+				"additionalProperties": false,
+				"$defs": Schema{
+					"Point": Schema{
+						"type":                 "object",
+						"properties":           Schema{"x": Schema{"type": "integer"}},
+						"required":             []string{"x"},
+						"additionalProperties": true,
+					},
+				},
+			},
+			strict: true,
+			expected: []LintIssue{
+				{Path: "pair[]", Rule: "additional-properties", Message: "additionalProperties must be false"},
+				{Path: "shape", Rule: "unsupported-keyword", Message: "keyword \"allOf\" is not supported in strict mode"},
+				{Path: "shape", Rule: "additional-properties", Message: "additionalProperties must be false"},
+				{Path: "shape", Rule: "additional-properties", Message: "additionalProperties must be false"},
+				{Path: "$defs.Point", Rule: "additional-properties", Message: "additionalProperties must be false"},
+			},
+		},
+		{
+			name:     "non-strict mode only checks limits",
+			schema:   StructWithMapsSchema,
+			strict:   false,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := Lint(tt.schema, tt.strict, openAILimits)
+			if !reflect.DeepEqual(issues, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, issues)
+			}
+		})
+	}
+}
+
+func TestLintLimits(t *testing.T) {
+	t.Run("nesting", func(t *testing.T) {
+		schema := Schema{"type": "string"}
+		for i := 0; i < openAILimits.MaxNesting+1; i++ {
+			schema = Schema{
+				"type":                 "object",
+				"properties":           Schema{"child": schema},
+				"required":             []string{"child"},
+				"additionalProperties": false,
+			}
+		}
+		issues := Lint(schema, true, openAILimits)
+		if len(issues) != 1 || issues[0].Rule != "max-nesting" {
+			t.Errorf("expected a single max-nesting issue, got %+v", issues)
+		}
+	})
+
+	t.Run("enum values", func(t *testing.T) {
+		values := make([]interface{}, openAILimits.MaxEnumValues+1)
+		for i := range values {
+			values[i] = i
+		}
+		schema := Schema{
+			"type":                 "object",
+			"properties":           Schema{"code": Schema{"type": "integer", "enum": values}},
+			"required":             []string{"code"},
+			"additionalProperties": false,
+		}
+		issues := Lint(schema, true, openAILimits)
+		if len(issues) != 1 || issues[0].Rule != "max-enum-values" {
+			t.Errorf("expected a single max-enum-values issue, got %+v", issues)
+		}
+	})
+	t.Run("large enum string length", func(t *testing.T) {
+		values := make([]interface{}, openAILimits.LargeEnumValues+1)
+		for i := range values {
+			values[i] = fmt.Sprintf("%060d", i)
+		}
+		schema := Schema{
+			"type":                 "object",
+			"properties":           Schema{"code": Schema{"type": "string", "enum": values}},
+			"required":             []string{"code"},
+			"additionalProperties": false,
+		}
+		issues := Lint(schema, true, openAILimits)
+		if len(issues) != 1 || issues[0].Rule != "max-enum-string-length" || issues[0].Path != "code" {
+			t.Errorf("expected a single max-enum-string-length issue, got %+v", issues)
+		}
+	})
+
+	t.Run("total string length", func(t *testing.T) {
+		props := Schema{}
+		var required []string
+		// 4000 properties of 31 characters
+		for i := 0; i < 4000; i++ {
+			name := fmt.Sprintf("property_with_a_long_name_%05d", i)
+			props[name] = Schema{"type": "string"}
+			required = append(required, name)
+		}
+		schema := Schema{
+			"type":                 "object",
+			"properties":           props,
+			"required":             required,
+			"additionalProperties": false,
+		}
+		issues := Lint(schema, true, openAILimits)
+		if len(issues) != 1 || issues[0].Rule != "max-string-length" {
+			t.Errorf("expected a single max-string-length issue, got %+v", issues)
+		}
+	})
+
+	t.Run("only limits", func(t *testing.T) {
+		values := make([]interface{}, openAILimits.MaxEnumValues+1)
+		for i := range values {
+			values[i] = i
+		}
+		// not strict compatible, but within limits apart from the enum
+		schema := Schema{
+			"type":       "object",
+			"properties": Schema{"code": Schema{"type": "integer", "enum": values}},
+		}
+		issues := LintLimits(schema, openAILimits)
+		if len(issues) != 1 || issues[0].Rule != "max-enum-values" {
+			t.Errorf("expected a single max-enum-values issue, got %+v", issues)
+		}
+	})
+}
+
+func TestLintKeywords(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"age":  Schema{"type": "integer", "minimum": 0},
+			"tags": Schema{"type": "array", "items": Schema{"type": "string", "pattern": "^[a-z]+$"}},
+		},
+		"required":             []string{"age", "tags"},
+		"additionalProperties": false,
+	}
+	allowed := map[string]bool{"type": true, "properties": true, "required": true, "additionalProperties": true, "items": true}
+	issues := LintKeywords(schema, allowed)
+	expected := []LintIssue{
+		{Path: "age", Rule: "disallowed-keyword", Message: `keyword "minimum" is not allowed`},
+		{Path: "tags[]", Rule: "disallowed-keyword", Message: `keyword "pattern" is not allowed`},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("expected %+v, got %+v", expected, issues)
+	}
+	allowed["minimum"], allowed["pattern"] = true, true
+	if issues := LintKeywords(schema, allowed); len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestLintIssueString(t *testing.T) {
+	issue := LintIssue{Path: "", Rule: "root-object", Message: "the root schema must be an object"}
+	if got := issue.String(); got != "(root): the root schema must be an object (root-object)" {
+		t.Errorf("unexpected issue string %q", got)
+	}
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"sort"
+	"sync"
+)
+
+// LintRule is a custom check run by Lint on the schema and each of its subschemas
+type LintRule interface {
+	// Name identifies the rule in the reported issues
+	Name() string
+	// Check returns a message for each problem of the subschema at path
+	Check(schema Schema, path string) []string
+}
+
+// lintRuleFunc adapts a function to LintRule
+type lintRuleFunc struct {
+	name  string
+	check func(schema Schema, path string) []string
+}
+
+func (r lintRuleFunc) Name() string { return r.name }
+
+func (r lintRuleFunc) Check(schema Schema, path string) []string { return r.check(schema, path) }
+
+// NewLintRule returns a LintRule named name calling check
+func NewLintRule(name string, check func(schema Schema, path string) []string) LintRule {
+	return lintRuleFunc{name: name, check: check}
+}
+
+// custom lint rule registry, run in registration order
+var (
+	lintRuleMu sync.RWMutex
+	lintRules  []LintRule
+)
+
+// RegisterLintRule adds a rule run by every Lint call.
+// Registering a rule with the name of a registered rule replaces it.
+func RegisterLintRule(rule LintRule) {
+	lintRuleMu.Lock()
+	defer lintRuleMu.Unlock()
+	for i, existing := range lintRules {
+		if existing.Name() == rule.Name() {
+			lintRules[i] = rule
+			return
+		}
+	}
+	lintRules = append(lintRules, rule)
+}
+
+// lintCustom runs the registered rules on every subschema, the issues are sorted by path
+func lintCustom(schema Schema) []LintIssue {
+	lintRuleMu.RLock()
+	rules := lintRules
+	lintRuleMu.RUnlock()
+	if len(rules) == 0 {
+		return nil
+	}
+	var issues []LintIssue
+	transformSchema(schema, "", func(s Schema, path string) Schema {
+		for _, rule := range rules {
+			for _, message := range rule.Check(s, path) {
+				issues = append(issues, LintIssue{Path: path, Rule: rule.Name(), Message: message})
+			}
+		}
+		return s
+	})
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues
+}
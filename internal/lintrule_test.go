@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterLintRule(t *testing.T) {
+	saved := lintRules
+	defer func() { lintRules = saved }()
+	lintRules = nil
+
+	RegisterLintRule(NewLintRule("no-data-field", func(s Schema, path string) []string {
+		if props, ok := s["properties"].(Schema); ok && props["data"] != nil {
+			return []string{`property "data" is too vague`}
+		}
+		return nil
+	}))
+	RegisterLintRule(NewLintRule("property-description", func(s Schema, path string) []string {
+		return []string{"replaced"}
+	}))
+	RegisterLintRule(NewLintRule("property-description", func(s Schema, path string) []string {
+		if path != "" && s["description"] == nil {
+			return []string{"every property must have a description"}
+		}
+		return nil
+	}))
+
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"name": Schema{"type": "string", "description": "Full name"},
+			"meta": Schema{
+				"type":                 "object",
+				"description":          "Metadata",
+				"properties":           Schema{"data": Schema{"type": "string"}},
+				"required":             []string{"data"},
+				"additionalProperties": false,
+			},
+		},
+		"required":             []string{"name", "meta"},
+		"additionalProperties": false,
+	}
+	expected := []LintIssue{
+		{Path: "meta", Rule: "no-data-field", Message: `property "data" is too vague`},
+		{Path: "meta.data", Rule: "property-description", Message: "every property must have a description"},
+	}
+	if issues := Lint(schema, true, openAILimits); !reflect.DeepEqual(issues, expected) {
+		t.Errorf("expected %+v, got %+v", expected, issues)
+	}
+	if issues := Lint(schema, false, openAILimits); !reflect.DeepEqual(issues, expected) {
+		t.Errorf("expected custom rules in non-strict mode, got %+v", issues)
+	}
+}
@@ -0,0 +1,82 @@
+package internal
+
+import "strings"
+
+// Translations holds localized descriptions by locale, then by field path, e.g.
+// {"fr": {"title": "Titre de l'article", "items[].price": "Prix unitaire"}}
+type Translations map[string]map[string]string
+
+// Lookup returns the descriptions of a locale, falling back to its language for regional
+// locales: "pt-BR" uses the "pt" descriptions for the paths it does not translate
+func (t Translations) Lookup(locale string) (map[string]string, bool) {
+	descriptions, ok := t[locale]
+	language, _, regional := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	base, hasBase := t[language]
+	if !regional || !hasBase {
+		return descriptions, ok
+	}
+	merged := make(map[string]string, len(base)+len(descriptions))
+	for path, text := range base {
+		merged[path] = text
+	}
+	for path, text := range descriptions {
+		merged[path] = text
+	}
+	return merged, true
+}
+
+// LocalizeDescriptions replaces the descriptions of the fields of a schema with their
+// localized text, the empty path being the schema itself. Fields without a translation
+// keep their description, and the paths matching no field are reported as warnings.
+func LocalizeDescriptions(schema Schema, descriptions map[string]string, opts *Options) Schema {
+	used := make(map[string]bool)
+	localized := replaceDescriptions(schema, "", true, descriptions, func(path string, previous interface{}) {
+		used[path] = true
+	})
+	for _, path := range unusedPaths(descriptions, used) {
+		opts.Warn(path, "localized description matches no field of the schema, it is ignored")
+	}
+	return localized
+}
+
+// replaceDescriptions copies the schemas along the paths of descriptions and sets their
+// description, replaced is called with the description each one had. describe is false
+// for the variants of a property, which share its path but carry no description of their own.
+func replaceDescriptions(s Schema, path string, describe bool, descriptions map[string]string, replaced func(path string, previous interface{})) Schema {
+	out := make(Schema, len(s)+1)
+	for k, v := range s {
+		out[k] = v
+	}
+	if text, ok := descriptions[path]; ok && describe {
+		replaced(path, s["description"])
+		out["description"] = text
+	}
+	if props, ok := s["properties"].(Schema); ok {
+		described := make(Schema, len(props))
+		// sorted so that replacements are reported in a stable order
+		for _, name := range sortedKeys(props) {
+			prop := props[name]
+			if sub, ok := prop.(Schema); ok {
+				prop = replaceDescriptions(sub, joinPath(path, name), true, descriptions, replaced)
+			}
+			described[name] = prop
+		}
+		out["properties"] = described
+	}
+	if items, ok := s["items"].(Schema); ok {
+		out["items"] = replaceDescriptions(items, path+"[]", true, descriptions, replaced)
+	}
+	if values, ok := s["additionalProperties"].(Schema); ok {
+		out["additionalProperties"] = replaceDescriptions(values, path+"{}", true, descriptions, replaced)
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		if variants, ok := s[keyword].([]Schema); ok {
+			described := make([]Schema, len(variants))
+			for i, variant := range variants {
+				described[i] = replaceDescriptions(variant, path, false, descriptions, replaced)
+			}
+			out[keyword] = described
+		}
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLocalizeDescriptions(t *testing.T) {
+	descriptions := map[string]string{
+		"":                            "Employé",
+		"name":                        "Nom complet",
+		"companies[].address.city":    "Ville",
+		"tags":                        "Étiquettes",
+		"tags[]":                      "Étiquette",
+		"companies[].address.country": "Pays",
+	}
+	var warnings []string
+	opts := DefaultOptions()
+	opts.WarningHandler = func(w Warning) { warnings = append(warnings, w.Path) }
+	localized := LocalizeDescriptions(EmployeeSchema, descriptions, opts)
+
+	tests := []struct {
+		path     string
+		expected interface{}
+	}{
+		{"", "Employé"},
+		{"name", "Nom complet"},
+		{"companies[].address.city", "Ville"},
+		{"companies[].address.street", nil},
+		{"tags[]", "Étiquette"},
+	}
+	for _, tt := range tests {
+		s, ok := localized.At(tt.path)
+		if !ok {
+			t.Fatalf("no schema at %q", tt.path)
+		}
+		if s["description"] != tt.expected {
+			t.Errorf("%q: expected description %v, got %v", tt.path, tt.expected, s["description"])
+		}
+	}
+
+	tags := localized["properties"].(Schema)["tags"].(Schema)
+	if tags["description"] != "Étiquettes" {
+		t.Errorf("expected the nullable property to be described, got %v", tags)
+	}
+	for _, variant := range tags["anyOf"].([]Schema) {
+		if _, ok := variant["description"]; ok {
+			t.Errorf("expected variants without description, got %v", variant)
+		}
+	}
+	if !reflect.DeepEqual(warnings, []string{"companies[].address.country"}) {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+	if _, ok := EmployeeSchema["description"]; ok {
+		t.Errorf("expected the original schema to be left untouched")
+	}
+}
+
+func TestTranslationsLookup(t *testing.T) {
+	translations := Translations{
+		"pt":    {"title": "Título", "body": "Corpo"},
+		"pt-BR": {"body": "Conteúdo"},
+		"fr":    {"title": "Titre"},
+	}
+	tests := []struct {
+		locale   string
+		expected map[string]string
+		found    bool
+	}{
+		{"fr", map[string]string{"title": "Titre"}, true},
+		{"pt-BR", map[string]string{"title": "Título", "body": "Conteúdo"}, true},
+		{"pt_PT", map[string]string{"title": "Título", "body": "Corpo"}, true},
+		{"de", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			descriptions, ok := translations.Lookup(tt.locale)
+			if ok != tt.found || !reflect.DeepEqual(descriptions, tt.expected) {
+				t.Errorf("expected %v (%v), got %v (%v)", tt.expected, tt.found, descriptions, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// keywords listed in the constraints column, in display order
+var constraintKeywords = []string{
+	"const",
+	"enum",
+	"pattern",
+	"format",
+	"minimum",
+	"maximum",
+	"exclusiveMinimum",
+	"exclusiveMaximum",
+	"multipleOf",
+	"minLength",
+	"maxLength",
+	"minItems",
+	"maxItems",
+	"uniqueItems",
+	"contains",
+	"dependentRequired",
+	"not",
+}
+
+// TypeLabel describes the type of a schema in a few words, e.g. "array of string"
+func TypeLabel(s Schema) string {
+	switch t := s["type"].(type) {
+	case string:
+		switch t {
+		case "array":
+			if items, ok := s["items"].(Schema); ok {
+				items, nullable := unwrapNullable(items)
+				label := "array of " + TypeLabel(items)
+				if nullable {
+					label += " or null"
+				}
+				return label
+			}
+		case "object":
+			if values, ok := s["additionalProperties"].(Schema); ok {
+				return "map of " + TypeLabel(values)
+			}
+			if patterns, ok := s["patternProperties"].(Schema); ok {
+				for _, pattern := range sortedKeys(patterns) {
+					if values, ok := patterns[pattern].(Schema); ok {
+						return "map of " + TypeLabel(values)
+					}
+				}
+			}
+		}
+		return t
+	case []string:
+		return strings.Join(t, " or ")
+	}
+	if variants, ok := s["anyOf"].([]Schema); ok {
+		labels := make([]string, len(variants))
+		for i, v := range variants {
+			labels[i] = TypeLabel(v)
+		}
+		return strings.Join(labels, " or ")
+	}
+	return "any"
+}
+
+// Constraints lists the validation keywords of a schema, e.g. "pattern: `^[0-9]+$`"
+func Constraints(s Schema) []string {
+	var constraints []string
+	for _, keyword := range constraintKeywords {
+		value, ok := s[keyword]
+		if !ok {
+			continue
+		}
+		switch keyword {
+		case "enum":
+			values, _ := value.([]interface{})
+			formatted := make([]string, len(values))
+			for i, v := range values {
+				formatted[i] = "`" + jsonLiteral(v) + "`"
+			}
+			constraints = append(constraints, "enum: "+strings.Join(formatted, ", "))
+		case "pattern", "const":
+			constraints = append(constraints, fmt.Sprintf("%s: `%s`", keyword, literal(value)))
+		default:
+			constraints = append(constraints, fmt.Sprintf("%s: %v", keyword, value))
+		}
+	}
+	if patterns, ok := s["patternProperties"].(Schema); ok {
+		for _, pattern := range sortedKeys(patterns) {
+			constraints = append(constraints, fmt.Sprintf("keys: `%s`", pattern))
+		}
+	}
+	return constraints
+}
+
+// literal prints strings as is and other values as JSON
+func literal(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return jsonLiteral(v)
+}
+
+// jsonLiteral prints a value as JSON
+func jsonLiteral(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// escape text for a markdown table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// RenderMarkdown renders the properties of an object schema as a markdown table
+func RenderMarkdown(title string, s Schema) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if description, ok := s["description"].(string); ok {
+		fmt.Fprintf(&b, "%s\n\n", description)
+	}
+	b.WriteString("| Field | Type | Required | Nullable | Description | Constraints |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	FlattenFields(s, func(field FieldInfo) {
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n",
+			markdownCell(field.Path),
+			markdownCell(TypeLabel(field.Schema)),
+			yesNo(field.Required),
+			yesNo(field.Nullable),
+			markdownCell(field.Description),
+			markdownCell(strings.Join(Constraints(field.Schema), "<br>")),
+		)
+	})
+	return b.String()
+}
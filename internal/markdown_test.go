@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypeLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Schema
+		expected string
+	}{
+		{name: "scalar", input: Schema{"type": "string"}, expected: "string"},
+		{name: "array", input: Schema{"type": "array", "items": Schema{"type": "integer"}}, expected: "array of integer"},
+		{name: "nested array", input: Schema{"type": "array", "items": Schema{"type": "array", "items": Schema{"type": "string"}}}, expected: "array of array of string"},
+		{name: "map", input: Schema{"type": "object", "additionalProperties": Schema{"type": "integer"}}, expected: "map of integer"},
+		{name: "object", input: AddressSchema, expected: "object"},
+		{name: "type array", input: Schema{"type": []string{"string", "integer"}}, expected: "string or integer"},
+		{name: "unknown", input: Schema{}, expected: "any"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TypeLabel(tt.input); got != tt.expected {
+				t.Errorf("TypeLabel() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	input := Schema{
+		"type":    "string",
+		"enum":    []interface{}{"a", "b"},
+		"pattern": "^[a-z]$",
+	}
+	expected := []string{"enum: `\"a\"`, `\"b\"`", "pattern: `^[a-z]$`"}
+	if got := Constraints(input); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Constraints() = %v, want %v", got, expected)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	expected := "# Contact\n\n" +
+		"| Field | Type | Required | Nullable | Description | Constraints |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| `name` | string | yes | no | Full name of the contact |  |\n" +
+		"| `phone` | string | yes | yes | Phone number, null when unknown |  |\n" +
+		"| `tags` | array of string | yes | no | Free-form labels |  |\n"
+	if got := RenderMarkdown("Contact", DescribedContactSchema); got != expected {
+		t.Errorf("RenderMarkdown() =\n%s\nwant\n%s", got, expected)
+	}
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var mermaidIDReplacer = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// mermaidID turns a title and a path into a class identifier
+func mermaidID(title, path string) string {
+	id := mermaidIDReplacer.ReplaceAllString(title, "_")
+	if path != "" {
+		id += "_" + strings.Trim(mermaidIDReplacer.ReplaceAllString(path, "_"), "_")
+	}
+	return id
+}
+
+// objectTarget follows array items and map values down to an object schema,
+// returning the suffix added to the path and whether a collection was crossed
+func objectTarget(s Schema) (object Schema, suffix string, many bool) {
+	for {
+		if _, ok := s["properties"].(Schema); ok {
+			return s, suffix, many
+		}
+		if items, ok := s["items"].(Schema); ok {
+			s, _ = unwrapNullable(items)
+			suffix += "[]"
+			many = true
+			continue
+		}
+		if values, ok := s["additionalProperties"].(Schema); ok {
+			s, _ = unwrapNullable(values)
+			suffix += "{}"
+			many = true
+			continue
+		}
+		return nil, "", false
+	}
+}
+
+// RenderMermaid renders the object graph of a schema as a Mermaid class diagram.
+// Objects become classes, scalar fields are listed as members and object fields
+// become edges labeled with the field name and the multiplicity
+// ("1", "0..1" for nullable fields, "0..*" for arrays and maps).
+func RenderMermaid(title string, s Schema) string {
+	var classes, edges strings.Builder
+	renderMermaidClass(&classes, &edges, title, "", s)
+	return "classDiagram\n" + classes.String() + edges.String()
+}
+
+func renderMermaidClass(classes, edges *strings.Builder, title, path string, s Schema) {
+	id := mermaidID(title, path)
+	label := title
+	if path != "" {
+		label = title + "." + path
+	}
+	props, _ := s["properties"].(Schema)
+	var members []string
+	for _, name := range orderedProperties(s) {
+		prop, ok := props[name].(Schema)
+		if !ok {
+			continue
+		}
+		unwrapped, nullable := unwrapNullable(prop)
+		fieldPath := joinPath(path, name)
+		if object, suffix, many := objectTarget(unwrapped); object != nil {
+			multiplicity := "1"
+			switch {
+			case many:
+				multiplicity = "0..*"
+			case nullable:
+				multiplicity = "0..1"
+			}
+			target := mermaidID(title, fieldPath+suffix)
+			fmt.Fprintf(edges, "    %s --> \"%s\" %s : %s\n", id, multiplicity, target, name+suffix)
+			renderMermaidClass(classes, edges, title, fieldPath+suffix, object)
+			continue
+		}
+		member := name + ": " + TypeLabel(unwrapped)
+		if nullable && unwrapped["type"] != "null" {
+			member += "?"
+		}
+		members = append(members, member)
+	}
+	fmt.Fprintf(classes, "    class %s[\"%s\"] {\n", id, label)
+	for _, member := range members {
+		fmt.Fprintf(classes, "        %s\n", member)
+	}
+	classes.WriteString("    }\n")
+}
@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestRenderMermaid(t *testing.T) {
+	expected := `classDiagram
+    class Employee_companies_address["Employee.companies[].address"] {
+        street: string
+        city: string
+        zip_code: string?
+    }
+    class Employee_companies["Employee.companies[]"] {
+        name: string
+    }
+    class Employee["Employee"] {
+        name: string
+        tags: array of string?
+    }
+    Employee --> "0..*" Employee_companies : companies[]
+    Employee_companies --> "1" Employee_companies_address : address
+`
+	if got := RenderMermaid("Employee", EmployeeSchema); got != expected {
+		t.Errorf("RenderMermaid() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestRenderMermaidNullableObject(t *testing.T) {
+	expected := `classDiagram
+    class Node_next["Node.next"] {
+        value: string
+        next: null
+    }
+    class Node["Node"] {
+        value: string
+    }
+    Node --> "0..1" Node_next : next
+`
+	if got := RenderMermaid("Node", NodeUnrolledSchema); got != expected {
+		t.Errorf("RenderMermaid() =\n%s\nwant\n%s", got, expected)
+	}
+}
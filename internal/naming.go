@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NamingConvention converts the names of fields without a json tag name
+type NamingConvention string
+
+const (
+	// GoNames keeps the Go field names, like encoding/json
+	GoNames NamingConvention = ""
+	// SnakeCase converts UserID to user_id
+	SnakeCase NamingConvention = "snake_case"
+	// CamelCase converts UserID to userId
+	CamelCase NamingConvention = "camelCase"
+	// PascalCase converts UserID to UserId
+	PascalCase NamingConvention = "PascalCase"
+)
+
+// EmbeddingPolicy resolves conflicts between fields of a struct and fields of its embedded structs
+type EmbeddingPolicy string
+
+const (
+	// DepthBased follows encoding/json: the shallowest field wins
+	DepthBased EmbeddingPolicy = "depth_based"
+	// OuterWins lets fields declared on the struct itself win, conflicts
+	// between fields of embedded structs are errors
+	OuterWins EmbeddingPolicy = "outer_wins"
+	// ErrorOnConflict reports every conflict
+	ErrorOnConflict EmbeddingPolicy = "error_on_conflict"
+)
+
+// RequiredOrder selects the order of the property names in required arrays
+type RequiredOrder string
+
+const (
+	// DeclarationOrder lists the properties in the order of their fields, fields promoted
+	// from embedded structs at the position of the embedded struct, like encoding/json
+	DeclarationOrder RequiredOrder = "declaration"
+	// Alphabetical sorts the property names
+	Alphabetical RequiredOrder = "alphabetical"
+)
+
+// Valid reports whether o is one of the supported required orders
+func (o RequiredOrder) Valid() bool {
+	switch o {
+	case DeclarationOrder, Alphabetical:
+		return true
+	}
+	return false
+}
+
+// sortRequired orders the required property names, positions maps each property to the
+// index path of the field it comes from
+func sortRequired(required []string, positions map[string][]int, order RequiredOrder) {
+	if order == Alphabetical {
+		sort.Strings(required)
+		return
+	}
+	sort.SliceStable(required, func(i, j int) bool {
+		return lessIndex(positions[required[i]], positions[required[j]])
+	})
+}
+
+// lessIndex compares two field index paths lexicographically
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// Valid reports whether p is one of the supported embedding policies
+func (p EmbeddingPolicy) Valid() bool {
+	switch p {
+	case DepthBased, OuterWins, ErrorOnConflict:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether c is one of the supported naming conventions
+func (c NamingConvention) Valid() bool {
+	switch c {
+	case GoNames, SnakeCase, CamelCase, PascalCase:
+		return true
+	}
+	return false
+}
+
+// Apply converts a Go identifier to the naming convention
+func (c NamingConvention) Apply(name string) string {
+	if c == GoNames {
+		return name
+	}
+	words := splitWords(name)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if c == PascalCase || (c == CamelCase && i > 0) {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		words[i] = word
+	}
+	if c == SnakeCase {
+		return strings.Join(words, "_")
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits a Go identifier into words, keeping acronyms together
+// (HTTPServerURL is HTTP, Server, URL) and digits attached to the previous word
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if runes[i] == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if !unicode.IsUpper(runes[i]) || i == start {
+			continue
+		}
+		prev := runes[i-1]
+		// a new word starts after a lowercase letter or digit, or at the last
+		// capital of an acronym followed by a lowercase letter
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// propertyName returns the JSON name of a field, converting it with the naming
+// convention when the json tag does not name it
+func propertyName(field reflect.StructField, jsonTag string, opts *Options) (name string, optional, converted bool) {
+	name, optional = parseJSONTag(field.Name, jsonTag)
+	if opts.JSONv2 {
+		optional = omittedV2(field.Type, jsonTag)
+	}
+	if tagName, _, _ := strings.Cut(jsonTag, ","); tagName == "" && opts.NamingConvention != GoNames {
+		name = opts.NamingConvention.Apply(field.Name)
+		converted = true
+	}
+	return name, optional, converted
+}
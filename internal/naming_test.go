@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNamingConventionApply(t *testing.T) {
+	tests := []struct {
+		input  string
+		snake  string
+		camel  string
+		pascal string
+	}{
+		{input: "Name", snake: "name", camel: "name", pascal: "Name"},
+		{input: "UserID", snake: "user_id", camel: "userId", pascal: "UserId"},
+		{input: "HTTPServerURL", snake: "http_server_url", camel: "httpServerUrl", pascal: "HttpServerUrl"},
+		{input: "Address2", snake: "address2", camel: "address2", pascal: "Address2"},
+		{input: "ID", snake: "id", camel: "id", pascal: "Id"},
+		{input: "Created_At", snake: "created_at", camel: "createdAt", pascal: "CreatedAt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			for convention, expected := range map[NamingConvention]string{
+				GoNames:    tt.input,
+				SnakeCase:  tt.snake,
+				CamelCase:  tt.camel,
+				PascalCase: tt.pascal,
+			} {
+				if result := convention.Apply(tt.input); result != expected {
+					t.Errorf("%q: expected %s, got %s", convention, expected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestNamingConventionConversion(t *testing.T) {
+	opts, visited, depth := getInputs()
+	opts.NamingConvention = SnakeCase
+	result, err := JsonTypeOf(reflect.TypeOf(MixedTagging{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Schema{
+		"type": "object",
+		"properties": Schema{
+			"user_id":         Schema{"type": "string"},
+			"http_server_url": Schema{"type": []string{"string", "null"}},
+			"email":           Schema{"type": "string"},
+			"address2":        Schema{"type": "string"},
+		},
+		"required":             []string{"user_id", "http_server_url", "email", "address2"},
+		"additionalProperties": false,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+
+	t.Run("collisions are reported", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.NamingConvention = SnakeCase
+		_, err := JsonTypeOf(reflect.TypeOf(NamingCollision{}), visited, depth, opts)
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected ErrInvalidTag, got %v", err)
+		}
+		expected := `invalid struct tag: fields "NamingCollision.UserName" and "NamingCollision.Login" both map to property "user_name" under the naming convention`
+		if err.Error() != expected {
+			t.Errorf("expected %s, got %s", expected, err)
+		}
+	})
+
+	t.Run("embedded fields are hidden by their Go names", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.NamingConvention = SnakeCase
+		result, err := JsonTypeOf(reflect.TypeOf(ConventionShadowing{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := Schema{
+			"type": "object",
+			"properties": Schema{
+				"created": Schema{"type": "string"},
+				"Updated": Schema{"type": "integer"},
+			},
+			"required":             []string{"created", "Updated"},
+			"additionalProperties": false,
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected %+v, got %+v", expected, result)
+		}
+		fields := make(map[string]decodeField)
+		fieldTypes(reflect.TypeOf(ConventionShadowing{}), opts, nil, fields)
+		if _, ok := fields["updated"]; ok {
+			t.Errorf("expected the hidden field to be skipped, got %+v", fields)
+		}
+	})
+
+	t.Run("Go names are kept by default", func(t *testing.T) {
+		result, err := runJsonTypeOf(reflect.TypeOf(NamingCollision{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		props := result.(Schema)["properties"].(Schema)
+		if _, ok := props["UserName"]; !ok {
+			t.Errorf("expected UserName property, got %+v", props)
+		}
+	})
+}
+
+func TestCoerceValueNamingConvention(t *testing.T) {
+	opts := DefaultOptions()
+	opts.NamingConvention = SnakeCase
+	value := map[string]interface{}{"user_id": "u1", "email": "a@b.c", "unknown": 1}
+	expected := map[string]interface{}{"UserID": "u1", "email": "a@b.c", "unknown": 1}
+	result := CoerceValue(reflect.TypeOf(MixedTagging{}), value, opts)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+}
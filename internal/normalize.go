@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// annotationKeywords do not constrain values, the first value wins when flattening
+var annotationKeywords = map[string]bool{"description": true, "title": true}
+
+// FlattenAllOf merges allOf members into their parent schema wherever the members
+// combine into a single schema, bottom-up so nested allOf are flattened first.
+// Properties defined differently by two members can never be satisfied and are
+// reported as errors, other conflicting keywords leave the allOf in place.
+func FlattenAllOf(schema Schema) (Schema, error) {
+	var err error
+	result := transformSchema(schema, "", func(s Schema, path string) Schema {
+		members, ok := s["allOf"].([]Schema)
+		if !ok || err != nil {
+			return s
+		}
+		flattened, ok, mergeErr := mergeAllOf(s, members)
+		if mergeErr != nil {
+			err = fmt.Errorf("%s: %w", displayPath(path), mergeErr)
+			return s
+		}
+		if !ok {
+			return s
+		}
+		return flattened
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeAllOf merges the parent keywords and the members into one schema, ok is false when
+// keywords other than properties conflict
+func mergeAllOf(parent Schema, members []Schema) (Schema, bool, error) {
+	merged := make(Schema, len(parent))
+	for k, v := range parent {
+		if k != "allOf" {
+			merged[k] = v
+		}
+	}
+	var props Schema
+	if p, ok := merged["properties"].(Schema); ok {
+		props = make(Schema, len(p))
+		for name, prop := range p {
+			props[name] = prop
+		}
+	}
+	required, _ := merged["required"].([]string)
+	required = append([]string(nil), required...)
+	for _, member := range members {
+		for k, v := range member {
+			switch k {
+			case "properties":
+				memberProps, _ := v.(Schema)
+				if props == nil {
+					props = make(Schema, len(memberProps))
+				}
+				for name, prop := range memberProps {
+					if existing, ok := props[name]; ok && !reflect.DeepEqual(existing, prop) {
+						return nil, false, fmt.Errorf("property %q is defined differently in allOf members", name)
+					}
+					props[name] = prop
+				}
+			case "required":
+				names, _ := v.([]string)
+				for _, name := range names {
+					if !containsString(required, name) {
+						required = append(required, name)
+					}
+				}
+			case "additionalProperties":
+				// a closed member closes the merged object
+				if existing, ok := merged[k]; ok && existing != false && v != false && !reflect.DeepEqual(existing, v) {
+					return nil, false, nil
+				}
+				if _, ok := merged[k]; !ok || v == false {
+					merged[k] = v
+				}
+			default:
+				existing, ok := merged[k]
+				switch {
+				case !ok:
+					merged[k] = v
+				case annotationKeywords[k]:
+				case !reflect.DeepEqual(existing, v):
+					return nil, false, nil
+				}
+			}
+		}
+	}
+	if props != nil {
+		merged["properties"] = props
+	}
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+	return merged, true, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
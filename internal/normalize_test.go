@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlattenAllOf(t *testing.T) {
+	id := Schema{"type": "string"}
+	tests := []struct {
+		name     string
+		schema   Schema
+		expected Schema
+	}{
+		{
+			name: "object members",
+			schema: Schema{"description": "response", "allOf": []Schema{
+				{"type": "object", "properties": Schema{"id": id}, "required": []string{"id"}, "additionalProperties": false},
+				{"type": "object", "properties": Schema{"id": id, "score": Schema{"type": "number"}}, "required": []string{"score", "id"}},
+			}},
+			expected: Schema{
+				"description":          "response",
+				"type":                 "object",
+				"properties":           Schema{"id": id, "score": Schema{"type": "number"}},
+				"required":             []string{"id", "score"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name: "nested allOf",
+			schema: Schema{"allOf": []Schema{
+				{"allOf": []Schema{{"type": "object", "properties": Schema{"a": id}}}},
+				{"allOf": []Schema{{"type": "object", "properties": Schema{"b": id}}}},
+			}},
+			expected: Schema{"type": "object", "properties": Schema{"a": id, "b": id}},
+		},
+		{
+			name: "allOf in a property",
+			schema: Schema{"type": "object", "properties": Schema{
+				"name": Schema{"allOf": []Schema{{"type": "string"}, {"minLength": 1, "description": "first"}, {"description": "second"}}},
+			}},
+			expected: Schema{"type": "object", "properties": Schema{
+				"name": Schema{"type": "string", "minLength": 1, "description": "first"},
+			}},
+		},
+		{
+			name:     "conflicting keywords are kept",
+			schema:   Schema{"allOf": []Schema{{"type": "string"}, {"type": "integer"}}},
+			expected: Schema{"allOf": []Schema{{"type": "string"}, {"type": "integer"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FlattenAllOf(tt.schema)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFlattenAllOf_Conflict(t *testing.T) {
+	schema := Schema{"type": "object", "properties": Schema{
+		"user": Schema{"allOf": []Schema{
+			{"type": "object", "properties": Schema{"id": Schema{"type": "string"}}},
+			{"type": "object", "properties": Schema{"id": Schema{"type": "integer"}}},
+		}},
+	}}
+	_, err := FlattenAllOf(schema)
+	expected := `user: property "id" is defined differently in allOf members`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// RenderPrefill returns the opening of every valid JSON document of a schema, e.g.
+// `{"name": "` for an object whose first required property is a string, to prefill an
+// assistant turn. Required properties are followed in the order of orderedProperties
+// while their value is known, such as a constant, and the opening stops at the first
+// value that can take several forms, e.g. a nullable string or a number. Trailing
+// whitespace is trimmed since Anthropic rejects prefills ending with it.
+func RenderPrefill(s Schema) string {
+	var b strings.Builder
+	writePrefill(&b, s)
+	return strings.TrimRight(b.String(), " ")
+}
+
+// writePrefill writes the opening of the values of s and reports whether it wrote a
+// complete value, after which the document can go on
+func writePrefill(b *strings.Builder, s Schema) bool {
+	if value, ok := s["const"]; ok {
+		return writePrefillLiteral(b, value)
+	}
+	if values, ok := s["enum"].([]interface{}); ok && len(values) == 1 {
+		return writePrefillLiteral(b, values[0])
+	}
+	// type arrays, such as nullable types, and unions open with different characters
+	switch s["type"] {
+	case "string":
+		b.WriteString(`"`)
+	case "array":
+		b.WriteString("[")
+		if min, ok := numberKeyword(s, "minItems"); ok && min >= 1 {
+			if items, ok := s["items"].(Schema); ok {
+				writePrefill(b, items)
+			}
+		}
+	case "object":
+		b.WriteString("{")
+		props, _ := s["properties"].(Schema)
+		required := make(map[string]bool)
+		if names, ok := s["required"].([]string); ok {
+			for _, name := range names {
+				required[name] = true
+			}
+		}
+		for i, name := range orderedProperties(s) {
+			if !required[name] {
+				return false
+			}
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if !writePrefillLiteral(b, name) {
+				return false
+			}
+			b.WriteString(": ")
+			if sub, _ := props[name].(Schema); !writePrefill(b, sub) {
+				return false
+			}
+		}
+		// other properties may follow unless they are forbidden
+		if s["additionalProperties"] != false {
+			return false
+		}
+		b.WriteString("}")
+		return true
+	}
+	return false
+}
+
+func writePrefillLiteral(b *strings.Builder, value interface{}) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	b.Write(data)
+	return true
+}
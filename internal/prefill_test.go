@@ -0,0 +1,109 @@
+package internal
+
+import "testing"
+
+func TestRenderPrefill(t *testing.T) {
+	name := Schema{"type": "string"}
+	tests := []struct {
+		name     string
+		schema   Schema
+		expected string
+	}{
+		{
+			name: "string property",
+			schema: Schema{
+				"type":       "object",
+				"properties": Schema{"name": name, "age": Schema{"type": "integer"}},
+				"required":   []string{"name", "age"},
+			},
+			expected: `{"name": "`,
+		},
+		{
+			name: "number property without trailing space",
+			schema: Schema{
+				"type":       "object",
+				"properties": Schema{"age": Schema{"type": "integer"}, "name": name},
+				"required":   []string{"age", "name"},
+			},
+			expected: `{"age":`,
+		},
+		{
+			name: "constants are written",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"kind":    Schema{"type": "string", "const": "answer"},
+					"version": Schema{"type": "integer", "enum": []interface{}{2}},
+					"items":   Schema{"type": "array", "items": name, "minItems": 1},
+				},
+				"required": []string{"kind", "version", "items"},
+			},
+			expected: `{"kind": "answer", "version": 2, "items": ["`,
+		},
+		{
+			name: "nested objects",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"meta": Schema{
+						"type":                 "object",
+						"properties":           Schema{"kind": Schema{"const": "v1"}},
+						"required":             []string{"kind"},
+						"additionalProperties": false,
+					},
+					"text": name,
+				},
+				"required": []string{"meta", "text"},
+			},
+			expected: `{"meta": {"kind": "v1"}, "text": "`,
+		},
+		{
+			name: "complete document",
+			schema: Schema{
+				"type":                 "object",
+				"properties":           Schema{"ok": Schema{"const": true}},
+				"required":             []string{"ok"},
+				"additionalProperties": false,
+			},
+			expected: `{"ok": true}`,
+		},
+		{
+			name: "arrays may be empty",
+			schema: Schema{
+				"type":       "object",
+				"properties": Schema{"tags": Schema{"type": "array", "items": name}},
+				"required":   []string{"tags"},
+			},
+			expected: `{"tags": [`,
+		},
+		{
+			name: "nullable string",
+			schema: Schema{
+				"type":       "object",
+				"properties": Schema{"note": Schema{"type": []string{"string", "null"}}},
+				"required":   []string{"note"},
+			},
+			expected: `{"note":`,
+		},
+		{
+			name: "optional first property",
+			schema: Schema{
+				"type":       "object",
+				"properties": Schema{"note": name},
+			},
+			expected: `{`,
+		},
+		{
+			name:     "union root",
+			schema:   Schema{"anyOf": []Schema{{"type": "object"}, {"type": "string"}}},
+			expected: ``,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderPrefill(tt.schema); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// promptDot is the value of dot while walking a template, a nil type is a value the
+// checker cannot follow, such as the result of a function
+type promptDot struct {
+	t    reflect.Type
+	path string
+}
+
+// promptReference is a field reached by a template, whole references use the value of
+// the field as a whole, e.g. by printing it, and cover the fields nested in it
+type promptReference struct {
+	path  string
+	whole bool
+}
+
+// promptChecker resolves the field references of a template against a Go type
+type promptChecker struct {
+	tmpl       *template.Template
+	root       promptDot
+	opts       *Options
+	referenced []promptReference
+	issues     []LintIssue
+	// vars holds the values of the declared variables, scopes are not tracked
+	vars map[string]promptDot
+	// visiting guards against templates invoking themselves
+	visiting map[string]bool
+}
+
+// CheckPromptTemplate resolves the field references of a template executed with a value
+// of type t, such as {{.Customer.Name}} or {{range .Lines}}{{.SKU}}{{end}}, against the
+// fields of its schema. Fields are matched by Go name, as text/template does for structs,
+// or by property name, as it does for decoded JSON. References matching no field are
+// reported as unknown-field, and the fields of the schema no reference reaches as
+// unreferenced-field. Templates invoked with {{template}} are followed.
+func CheckPromptTemplate(t reflect.Type, schema Schema, tmpl *template.Template, opts *Options) []LintIssue {
+	c := &promptChecker{
+		tmpl:     tmpl,
+		root:     promptDot{t: t},
+		opts:     opts,
+		vars:     make(map[string]promptDot),
+		visiting: map[string]bool{tmpl.Name(): true},
+	}
+	c.walk(tmpl.Tree, tmpl.Tree.Root, c.root)
+
+	FlattenFields(schema, func(field FieldInfo) {
+		if !c.reaches(field.Path) {
+			c.issues = append(c.issues, LintIssue{Path: field.Path, Rule: "unreferenced-field",
+				Message: "field is not referenced by the template"})
+		}
+	})
+	sort.SliceStable(c.issues, func(i, j int) bool { return c.issues[i].Rule < c.issues[j].Rule })
+	return c.issues
+}
+
+// reaches reports whether a reference covers a field: the field itself, one of its
+// descendants, or one of its ancestors used as a whole
+func (c *promptChecker) reaches(path string) bool {
+	for _, ref := range c.referenced {
+		if ref.path == path || isPathPrefix(path, ref.path) || (ref.whole && isPathPrefix(ref.path, path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPathPrefix reports whether path is nested under prefix
+func isPathPrefix(prefix, path string) bool {
+	if prefix == "" {
+		return true
+	}
+	if !strings.HasPrefix(path, prefix) || len(path) == len(prefix) {
+		return false
+	}
+	next := path[len(prefix)]
+	return next == '.' || next == '['
+}
+
+func (c *promptChecker) walk(tree *parse.Tree, node parse.Node, dot promptDot) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(tree, child, dot)
+		}
+	case *parse.ActionNode:
+		// declarations print nothing
+		c.declare(n.Pipe, c.pipe(tree, n.Pipe, dot, len(n.Pipe.Decl) == 0))
+	case *parse.IfNode:
+		c.declare(n.Pipe, c.pipe(tree, n.Pipe, dot, false))
+		c.walk(tree, n.List, dot)
+		c.walk(tree, n.ElseList, dot)
+	case *parse.RangeNode:
+		element := c.element(c.pipe(tree, n.Pipe, dot, false))
+		if len(n.Pipe.Decl) == 2 {
+			c.declare(n.Pipe, promptDot{}, element)
+		} else {
+			c.declare(n.Pipe, element)
+		}
+		c.walk(tree, n.List, element)
+		c.walk(tree, n.ElseList, dot)
+	case *parse.WithNode:
+		value := c.pipe(tree, n.Pipe, dot, false)
+		c.declare(n.Pipe, value)
+		c.walk(tree, n.List, value)
+		c.walk(tree, n.ElseList, dot)
+	case *parse.TemplateNode:
+		arg := c.pipe(tree, n.Pipe, dot, false)
+		invoked := c.tmpl.Lookup(n.Name)
+		if invoked == nil || invoked.Tree == nil || c.visiting[n.Name] {
+			return
+		}
+		c.visiting[n.Name] = true
+		c.walk(invoked.Tree, invoked.Tree.Root, arg)
+		delete(c.visiting, n.Name)
+	}
+}
+
+// declare assigns values to the variables declared by a pipeline
+func (c *promptChecker) declare(pipe *parse.PipeNode, values ...promptDot) {
+	for i, variable := range pipe.Decl {
+		if i < len(values) {
+			c.vars[variable.Ident[0]] = values[i]
+		}
+	}
+}
+
+// pipe resolves the references of a pipeline and returns its result, whole is set when the
+// result is used as a whole, e.g. printed
+func (c *promptChecker) pipe(tree *parse.Tree, pipe *parse.PipeNode, dot promptDot, whole bool) promptDot {
+	if pipe == nil {
+		return promptDot{}
+	}
+	var result promptDot
+	for _, cmd := range pipe.Cmds {
+		// values piped into the next command are used as a whole
+		result = c.command(tree, cmd, dot, whole || len(pipe.Cmds) > 1)
+	}
+	return result
+}
+
+// command resolves the references of the arguments of a command, and returns the value of
+// its argument when the command is not a function or method call
+func (c *promptChecker) command(tree *parse.Tree, cmd *parse.CommandNode, dot promptDot, whole bool) promptDot {
+	if len(cmd.Args) == 1 {
+		return c.arg(tree, cmd.Args[0], dot, whole)
+	}
+	for _, arg := range cmd.Args {
+		c.arg(tree, arg, dot, true)
+	}
+	return promptDot{}
+}
+
+func (c *promptChecker) arg(tree *parse.Tree, node parse.Node, dot promptDot, whole bool) promptDot {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		if whole {
+			c.use(dot)
+		}
+		return dot
+	case *parse.FieldNode:
+		return c.resolve(tree, n, dot, n.Ident, whole)
+	case *parse.VariableNode:
+		value := c.root
+		if n.Ident[0] != "$" {
+			value = c.vars[n.Ident[0]]
+		}
+		if len(n.Ident) == 1 {
+			if whole {
+				c.use(value)
+			}
+			return value
+		}
+		return c.resolve(tree, n, value, n.Ident[1:], whole)
+	case *parse.ChainNode:
+		return c.resolve(tree, n, c.arg(tree, n.Node, dot, false), n.Field, whole)
+	case *parse.PipeNode:
+		return c.pipe(tree, n, dot, whole)
+	}
+	return promptDot{}
+}
+
+// use records a value of the template used as a whole, such as a printed {{.}}
+func (c *promptChecker) use(value promptDot) {
+	if value.t != nil {
+		c.referenced = append(c.referenced, promptReference{path: value.path, whole: true})
+	}
+}
+
+// resolve follows field names from dot, recording the reached fields and reporting the
+// names matching no field
+func (c *promptChecker) resolve(tree *parse.Tree, node parse.Node, dot promptDot, idents []string, whole bool) promptDot {
+	for i, ident := range idents {
+		if dot.t == nil {
+			return promptDot{}
+		}
+		t := deref(dot.t)
+		if _, ok := reflect.PointerTo(t).MethodByName(ident); ok {
+			return promptDot{}
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			next, ok := c.field(t, ident, dot.path)
+			if !ok {
+				location, _ := tree.ErrorContext(node)
+				c.issues = append(c.issues, LintIssue{
+					Path:       joinPath(dot.path, ident),
+					Rule:       "unknown-field",
+					Message:    fmt.Sprintf("%s: %s has no field %s", location, typeName(t), ident),
+					Suggestion: closestFieldName(t, ident),
+				})
+				return promptDot{}
+			}
+			dot = next
+		case reflect.Map:
+			dot = promptDot{t: t.Elem(), path: dot.path + "{}"}
+		default:
+			return promptDot{}
+		}
+		c.referenced = append(c.referenced, promptReference{path: dot.path, whole: whole && i == len(idents)-1})
+	}
+	return dot
+}
+
+// field finds a field of a struct by Go name or property name
+func (c *promptChecker) field(t reflect.Type, ident, path string) (promptDot, bool) {
+	if sf, ok := t.FieldByName(ident); ok && sf.PkgPath == "" {
+		jsonTag := sf.Tag.Get("json")
+		if jsonTag == "-" {
+			return promptDot{}, false
+		}
+		name, _, _ := propertyName(sf, jsonTag, c.opts)
+		return promptDot{t: sf.Type, path: joinPath(path, name)}, true
+	}
+	fields := make(map[string]decodeField)
+	fieldTypes(t, c.opts, nil, fields)
+	if field, ok := fields[ident]; ok {
+		return promptDot{t: field.Type, path: joinPath(path, ident)}, true
+	}
+	return promptDot{}, false
+}
+
+// closestFieldName suggests the exported field of a struct differing from a name by case
+func closestFieldName(t reflect.Type, ident string) string {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.PkgPath == "" && strings.EqualFold(field.Name, ident) {
+			return field.Name
+		}
+	}
+	return ""
+}
+
+// element returns the value of dot inside a range over a slice, array or map
+func (c *promptChecker) element(dot promptDot) promptDot {
+	if dot.t == nil {
+		return promptDot{}
+	}
+	t := deref(dot.t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return promptDot{t: t.Elem(), path: dot.path + "[]"}
+	case reflect.Map:
+		return promptDot{t: t.Elem(), path: dot.path + "{}"}
+	}
+	return promptDot{}
+}
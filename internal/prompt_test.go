@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"text/template"
+)
+
+type promptLine struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type promptTicket struct {
+	Summary  string            `json:"summary"`
+	Priority int               `json:"priority"`
+	Customer TicketCustomer    `json:"customer"`
+	Lines    []promptLine      `json:"lines"`
+	Labels   map[string]string `json:"labels"`
+	Internal string            `json:"-"`
+}
+
+func (promptTicket) Title() string { return "" }
+
+func TestCheckPromptTemplate(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		unknown      []string
+		unreferenced []string
+	}{
+		{
+			name: "every field by Go name",
+			text: `{{.Summary}} {{.Priority}} {{.Customer.Name}} {{.Customer.Age}}
+{{range .Lines}}{{.SKU}} x{{.Qty}}{{end}} {{.Labels}}`,
+		},
+		{
+			name: "property names, variables and whole values",
+			text: `{{with $c := .customer}}{{$c}}{{end}}{{range $i, $line := .lines}}{{$line.sku}}{{$line.qty}}{{end}}` +
+				`{{printf "%s %d" $.summary .priority | print}}{{range .labels}}{{.}}{{end}}`,
+		},
+		{
+			name:         "partial references",
+			text:         `{{if .Customer.Name}}{{.Summary}}{{end}}{{range .Lines}}{{.SKU}}{{end}}{{.Title}}`,
+			unreferenced: []string{"priority", "customer.age", "lines[].qty", "labels"},
+		},
+		{
+			name:         "unknown fields",
+			text:         `{{.summary}} {{.Sumary}} {{.Customer.Email}} {{.Internal}} {{range .Lines}}{{.sku}}{{.Price}}{{end}}`,
+			unknown:      []string{"Sumary", "customer.Email", "Internal", "lines[].Price"},
+			unreferenced: []string{"priority", "customer.name", "customer.age", "lines[].qty", "labels"},
+		},
+		{
+			name:         "invoked templates",
+			text:         `{{define "line"}}{{.SKU}}{{template "line" .}}{{end}}{{range .Lines}}{{template "line" .}}{{end}}`,
+			unreferenced: []string{"summary", "priority", "customer", "customer.name", "customer.age", "lines[].qty", "labels"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("prompt").Parse(tt.text))
+			opts, visited, depth := getInputs()
+			opts.Strict = false
+			schema, err := JsonTypeOf(reflect.TypeOf(promptTicket{}), visited, depth, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var unknown, unreferenced []string
+			for _, issue := range CheckPromptTemplate(reflect.TypeOf(promptTicket{}), schema.(Schema), tmpl, opts) {
+				switch issue.Rule {
+				case "unknown-field":
+					unknown = append(unknown, issue.Path)
+				case "unreferenced-field":
+					unreferenced = append(unreferenced, issue.Path)
+				}
+			}
+			if !reflect.DeepEqual(unknown, tt.unknown) {
+				t.Errorf("expected unknown %v, got %v", tt.unknown, unknown)
+			}
+			if !reflect.DeepEqual(unreferenced, tt.unreferenced) {
+				t.Errorf("expected unreferenced %v, got %v", tt.unreferenced, unreferenced)
+			}
+		})
+	}
+}
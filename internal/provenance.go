@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"runtime/debug"
+	"strings"
+)
+
+// modulePath is the module of this package, reported in provenance comments
+const modulePath = "github.com/akane9506/gptschema"
+
+// SchemaID derives the $id of the schema of t from a base URI, the import path and the type
+// name, e.g. https://schemas.example.com/github.com/acme/models/Invoice. Anonymous types
+// have no $id and return an empty string.
+func SchemaID(base string, t reflect.Type) string {
+	t = deref(t)
+	if t.Name() == "" {
+		return ""
+	}
+	return strings.TrimSuffix(base, "/") + "/" + t.PkgPath() + "/" + url.PathEscape(t.Name())
+}
+
+// ProvenanceComment records where a schema comes from: the Go type, the version of its
+// module and of gptschema from the build info, and the options differing from the defaults, e.g.
+// "generated by gptschema v0.4.0 from github.com/acme/models.Invoice (github.com/acme/models v1.2.0), options: strict=false".
+func ProvenanceComment(t reflect.Type, o *Options) string {
+	t = deref(t)
+	var b strings.Builder
+	b.WriteString("generated by gptschema")
+	if version := moduleVersion(modulePath); version != "" {
+		b.WriteString(" " + version)
+	}
+	b.WriteString(" from " + t.String())
+	if t.PkgPath() != "" {
+		b.WriteString(" (" + t.PkgPath())
+		if version := moduleVersion(t.PkgPath()); version != "" {
+			b.WriteString(" " + version)
+		}
+		b.WriteString(")")
+	}
+	options := changedOptions(o)
+	if len(options) == 0 {
+		b.WriteString(", default options")
+	} else {
+		b.WriteString(", options: " + strings.Join(options, ", "))
+	}
+	return b.String()
+}
+
+// moduleVersion returns the version of the module providing a package in the running binary,
+// empty when the build info or the module is unavailable
+func moduleVersion(pkgPath string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var best *debug.Module
+	for _, m := range append([]*debug.Module{&info.Main}, info.Deps...) {
+		if m.Path == "" || (pkgPath != m.Path && !strings.HasPrefix(pkgPath, m.Path+"/")) {
+			continue
+		}
+		if best == nil || len(m.Path) > len(best.Path) {
+			best = m
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	if best.Replace != nil && best.Replace.Version != "" {
+		return best.Replace.Version
+	}
+	return best.Version
+}
+
+// provenanceOptions are the generation options recorded in provenance comments
+var provenanceOptions = []struct {
+	name  string
+	value func(o *Options) interface{}
+}{
+	{"strict", func(o *Options) interface{} { return o.Strict }},
+	{"additional_properties", func(o *Options) interface{} { return o.AllowAdditionalProperty }},
+	{"max_depth", func(o *Options) interface{} { return o.MaxDepth }},
+	{"recursive_refs", func(o *Options) interface{} { return o.RecursiveRefs }},
+	{"defs", func(o *Options) interface{} { return o.Defs }},
+	{"def_naming", func(o *Options) interface{} { return o.DefNaming }},
+	{"recursion_unroll", func(o *Options) interface{} { return o.RecursionUnroll }},
+	{"expand_enum_maps", func(o *Options) interface{} { return o.ExpandEnumMaps }},
+	{"detect_enums", func(o *Options) interface{} { return o.DetectEnums }},
+	{"int64_as_string", func(o *Options) interface{} { return o.Int64AsString }},
+	{"numeric_formats", func(o *Options) interface{} { return o.NumericFormats }},
+	{"naming", func(o *Options) interface{} { return o.NamingConvention }},
+	{"embedding", func(o *Options) interface{} { return o.EmbeddingPolicy }},
+	{"json_v2", func(o *Options) interface{} { return o.JSONv2 }},
+	{"nullable_style", func(o *Options) interface{} { return o.NullableStyle }},
+	{"required_order", func(o *Options) interface{} { return o.RequiredOrder }},
+	{"time_format", func(o *Options) interface{} { return o.TimeFormat }},
+	{"humanized_descriptions", func(o *Options) interface{} { return o.Humanizer != nil }},
+	{"max_description_length", func(o *Options) interface{} { return o.MaxDescriptionLength }},
+	{"max_schema_bytes", func(o *Options) interface{} { return o.MaxSchemaBytes }},
+	{"limit_policy", func(o *Options) interface{} { return o.LimitPolicy }},
+	{"descriptions_file", func(o *Options) interface{} { return o.DescriptionsFile }},
+	{"locale", func(o *Options) interface{} { return o.Locale }},
+	{"subtrees", func(o *Options) interface{} { return subtreePaths(o) }},
+	{"custom_sensitive_description", func(o *Options) interface{} { return o.SensitiveDescription != DefaultSensitiveDescription }},
+}
+
+// changedOptions lists the options affecting the schema that differ from the defaults, as name=value
+func changedOptions(o *Options) []string {
+	defaults := DefaultOptions()
+	var changed []string
+	for _, option := range provenanceOptions {
+		if value := option.value(o); value != option.value(defaults) {
+			changed = append(changed, fmt.Sprintf("%s=%v", option.name, value))
+		}
+	}
+	return changed
+}
@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaID(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		typ      reflect.Type
+		expected string
+	}{
+		{name: "named type", base: "https://schemas.example.com", typ: reflect.TypeOf(Employee{}), expected: "https://schemas.example.com/github.com/akane9506/gptschema/internal/Employee"},
+		{name: "pointer and trailing slash", base: "urn:schemas/", typ: reflect.TypeOf(&Employee{}), expected: "urn:schemas/github.com/akane9506/gptschema/internal/Employee"},
+		{name: "anonymous struct", base: "https://schemas.example.com", typ: reflect.TypeOf(struct{}{}), expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SchemaID(tt.base, tt.typ); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProvenanceComment(t *testing.T) {
+	comment := ProvenanceComment(reflect.TypeOf(Employee{}), DefaultOptions())
+	if !strings.Contains(comment, "from internal.Employee (github.com/akane9506/gptschema/internal") || !strings.HasSuffix(comment, ", default options") {
+		t.Errorf("unexpected comment %q", comment)
+	}
+
+	options := DefaultOptions()
+	options.NamingConvention = SnakeCase
+	options.Int64AsString = true
+	options.NullableStyle = NullableAnyOf
+	expected := []string{"int64_as_string=true", "naming=snake_case", "nullable_style=any_of"}
+	if got := changedOptions(options); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
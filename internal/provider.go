@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Provider is a model provider whose structured outputs accept a subset of JSON Schema
+type Provider string
+
+const (
+	// ProviderOpenAI is OpenAI structured outputs in strict mode
+	ProviderOpenAI Provider = "openai"
+	// ProviderAnthropic is Anthropic structured outputs
+	ProviderAnthropic Provider = "anthropic"
+	// ProviderGemini is the response schema of the Gemini API
+	ProviderGemini Provider = "gemini"
+)
+
+// Valid reports whether p is one of the supported providers
+func (p Provider) Valid() bool {
+	_, ok := providerProfiles[p]
+	return ok
+}
+
+// providerProfile lists the keywords a provider rejects, the format values it accepts
+// and the rules property names must follow
+type providerProfile struct {
+	unsupported []string
+	formats     []string
+	names       nameRules
+}
+
+// nameRules restrict the property names a provider accepts
+type nameRules struct {
+	// invalid matches the characters rejected in property names
+	invalid *regexp.Regexp
+	// allowed describes the accepted characters in messages
+	allowed   string
+	maxLength int
+	// reserved names break the SDKs parsing responses, e.g. __proto__ in JavaScript
+	reserved []string
+}
+
+// providerProfiles follow the documented restrictions of each provider
+var providerProfiles = map[Provider]providerProfile{
+	ProviderOpenAI: {
+		unsupported: append(strictUnsupportedKeywords[:len(strictUnsupportedKeywords):len(strictUnsupportedKeywords)],
+			"default", "examples", "$comment"),
+		formats: []string{"date-time", "time", "date", "duration", "email", "hostname", "ipv4", "ipv6", "uuid"},
+		names: nameRules{
+			invalid: regexp.MustCompile(`[^a-zA-Z0-9_-]`), allowed: "letters, digits, _ and -",
+			maxLength: 64, reserved: []string{"__proto__"},
+		},
+	},
+	ProviderAnthropic: {
+		unsupported: []string{
+			"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf",
+			"minLength", "maxLength", "maxItems", "not", "contains", "dependentRequired",
+			"patternProperties", "if", "then", "else", "$comment",
+		},
+		formats: []string{"date-time", "time", "date", "duration", "email", "hostname", "uri", "ipv4", "ipv6", "uuid"},
+		names: nameRules{
+			invalid: regexp.MustCompile(`[^a-zA-Z0-9_.-]`), allowed: "letters, digits, _, . and -",
+			maxLength: 64, reserved: []string{"__proto__"},
+		},
+	},
+	ProviderGemini: {
+		unsupported: []string{
+			"$id", "$schema", "$comment", "default", "examples", "const", "not", "contains",
+			"dependentRequired", "patternProperties", "if", "then", "else", "allOf", "oneOf",
+			"uniqueItems", "multipleOf", "exclusiveMinimum", "exclusiveMaximum",
+		},
+		formats: []string{"date-time"},
+		names: nameRules{
+			invalid: regexp.MustCompile(`[^a-zA-Z0-9_]`), allowed: "letters, digits and _",
+			maxLength: 64, reserved: []string{"__proto__"},
+		},
+	},
+}
+
+// StripForProvider removes the keywords a provider rejects, and the format values it does
+// not know, and reports a warning for each removal. The input is not modified.
+func StripForProvider(schema Schema, provider Provider) (Schema, []Warning) {
+	profile := providerProfiles[provider]
+	return stripKeywords(schema, profile.unsupported, profile.formats, fmt.Sprintf("it is not supported by %s", provider))
+}
+
+// stripKeywords removes keywords from every subschema, and format values missing from
+// formats when formats is not nil
+func stripKeywords(schema Schema, keywords, formats []string, reason string) (Schema, []Warning) {
+	var warnings []Warning
+	stripped := transformSchema(schema, "", func(s Schema, path string) Schema {
+		for _, keyword := range keywords {
+			if _, ok := s[keyword]; ok {
+				delete(s, keyword)
+				warnings = append(warnings, Warning{Path: path, Message: fmt.Sprintf("keyword %q removed, %s", keyword, reason)})
+			}
+		}
+		if format, ok := s["format"].(string); ok && formats != nil && !containsString(formats, format) {
+			delete(s, "format")
+			warnings = append(warnings, Warning{Path: path, Message: fmt.Sprintf("format %q removed, %s", format, reason)})
+		}
+		return s
+	})
+	sort.SliceStable(warnings, func(i, j int) bool { return warnings[i].Path < warnings[j].Path })
+	return stripped, warnings
+}
+
+// LintPropertyNames reports the property names a provider rejects, with characters outside
+// its allowed set, longer than its limit or reserved, and suggests a valid name for each
+func LintPropertyNames(schema Schema, provider Provider) []LintIssue {
+	rules := providerProfiles[provider].names
+	var issues []LintIssue
+	transformSchema(schema, "", func(s Schema, path string) Schema {
+		properties, _ := s["properties"].(Schema)
+		for _, name := range sortedKeys(properties) {
+			var problem string
+			switch {
+			case name == "" || rules.invalid.MatchString(name):
+				problem = fmt.Sprintf("contains characters other than %s", rules.allowed)
+			case len(name) > rules.maxLength:
+				problem = fmt.Sprintf("is longer than %d characters", rules.maxLength)
+			case containsString(rules.reserved, name):
+				problem = "is reserved"
+			default:
+				continue
+			}
+			suggestion := rules.suggest(name)
+			issues = append(issues, LintIssue{
+				Path:       joinPath(path, name),
+				Rule:       "property-name",
+				Message:    fmt.Sprintf("%s rejects property name %q, it %s, rename it to %q", provider, name, problem, suggestion),
+				Suggestion: suggestion,
+			})
+		}
+		return s
+	})
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues
+}
+
+// suggest derives a valid property name: rejected characters become underscores,
+// repeated and surrounding underscores are dropped and the name is cut to the limit
+func (r nameRules) suggest(name string) string {
+	suggestion := r.invalid.ReplaceAllString(name, "_")
+	for strings.Contains(suggestion, "__") {
+		suggestion = strings.ReplaceAll(suggestion, "__", "_")
+	}
+	suggestion = strings.Trim(suggestion, "_")
+	if len(suggestion) > r.maxLength {
+		suggestion = strings.TrimRight(suggestion[:r.maxLength], "_")
+	}
+	if suggestion == "" {
+		suggestion = "property"
+	}
+	return suggestion
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripForProvider(t *testing.T) {
+	schema := Schema{
+		"type":     "object",
+		"$comment": "generated",
+		"properties": Schema{
+			"qty":     Schema{"type": "integer", "minimum": 1, "default": 1},
+			"email":   Schema{"type": "string", "format": "email"},
+			"website": Schema{"type": "string", "format": "uri"},
+			"tags":    Schema{"type": "array", "items": Schema{"type": "string", "maxLength": 20}},
+			"format":  Schema{"type": "string"},
+		},
+		"required":             []string{"qty", "email", "website", "tags", "format"},
+		"additionalProperties": false,
+	}
+	tests := []struct {
+		name     string
+		provider Provider
+		removed  []string
+		expected map[string]Schema
+	}{
+		{
+			name:     "openai",
+			provider: ProviderOpenAI,
+			removed: []string{
+				`(root): keyword "$comment" removed, it is not supported by openai`,
+				`qty: keyword "default" removed, it is not supported by openai`,
+				`website: format "uri" removed, it is not supported by openai`,
+			},
+			expected: map[string]Schema{
+				"qty":     {"type": "integer", "minimum": 1},
+				"email":   {"type": "string", "format": "email"},
+				"website": {"type": "string"},
+			},
+		},
+		{
+			name:     "anthropic",
+			provider: ProviderAnthropic,
+			removed: []string{
+				`(root): keyword "$comment" removed, it is not supported by anthropic`,
+				`qty: keyword "minimum" removed, it is not supported by anthropic`,
+				`tags[]: keyword "maxLength" removed, it is not supported by anthropic`,
+			},
+			expected: map[string]Schema{
+				"qty":     {"type": "integer", "default": 1},
+				"website": {"type": "string", "format": "uri"},
+			},
+		},
+		{
+			name:     "gemini",
+			provider: ProviderGemini,
+			removed: []string{
+				`(root): keyword "$comment" removed, it is not supported by gemini`,
+				`email: format "email" removed, it is not supported by gemini`,
+				`qty: keyword "default" removed, it is not supported by gemini`,
+				`website: format "uri" removed, it is not supported by gemini`,
+			},
+			expected: map[string]Schema{
+				"qty":   {"type": "integer", "minimum": 1},
+				"email": {"type": "string"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripped, warnings := StripForProvider(schema, tt.provider)
+			var removed []string
+			for _, w := range warnings {
+				removed = append(removed, w.String())
+			}
+			if !reflect.DeepEqual(removed, tt.removed) {
+				t.Errorf("expected %q, got %q", tt.removed, removed)
+			}
+			props := stripped["properties"].(Schema)
+			for name, expected := range tt.expected {
+				if !reflect.DeepEqual(props[name], expected) {
+					t.Errorf("expected %s to be %+v, got %+v", name, expected, props[name])
+				}
+			}
+			if _, ok := props["format"]; !ok {
+				t.Errorf("expected the property named format to be kept")
+			}
+		})
+	}
+	if _, ok := schema["$comment"]; !ok {
+		t.Errorf("expected the input to be left unmodified")
+	}
+}
+
+func TestLintPropertyNames(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"first name": Schema{"type": "string"},
+			"unit.price": Schema{"type": "number"},
+			"__proto__":  Schema{"type": "string"},
+			"address": Schema{
+				"type": "object",
+				"properties": Schema{
+					"a_very_long_property_name_that_keeps_going_past_the_limit_of_64_chars": Schema{"type": "string"},
+				},
+			},
+		},
+	}
+	issues := LintPropertyNames(schema, ProviderOpenAI)
+	long := "a_very_long_property_name_that_keeps_going_past_the_limit_of_64_chars"
+	expected := []LintIssue{
+		{Path: "__proto__", Rule: "property-name", Message: `openai rejects property name "__proto__", it is reserved, rename it to "proto"`, Suggestion: "proto"},
+		{
+			Path: "address." + long, Rule: "property-name",
+			Message:    `openai rejects property name "` + long + `", it is longer than 64 characters, rename it to "` + long[:63] + `"`,
+			Suggestion: long[:63],
+		},
+		{Path: "first name", Rule: "property-name", Message: `openai rejects property name "first name", it contains characters other than letters, digits, _ and -, rename it to "first_name"`, Suggestion: "first_name"},
+		{Path: "unit.price", Rule: "property-name", Message: `openai rejects property name "unit.price", it contains characters other than letters, digits, _ and -, rename it to "unit_price"`, Suggestion: "unit_price"},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("expected %+v, got %+v", expected, issues)
+	}
+	// anthropic accepts dots
+	for _, issue := range LintPropertyNames(schema, ProviderAnthropic) {
+		if issue.Path == "unit.price" {
+			t.Errorf("expected unit.price to be accepted by anthropic, got %+v", issue)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefNaming selects how the struct types moved to $defs are named
+type DefNaming string
+
+const (
+	// DefNameType names definitions after their Go type, anonymous structs after their field path
+	DefNameType DefNaming = "type"
+	// DefNamePath names every definition after the field path of its first occurrence
+	DefNamePath DefNaming = "path"
+	// DefNameQualified prefixes the Go type name with its package, e.g. ModelsAddress
+	DefNameQualified DefNaming = "qualified"
+)
+
+// Valid reports whether n is one of the supported naming strategies
+func (n DefNaming) Valid() bool {
+	switch n {
+	case DefNameType, DefNamePath, DefNameQualified:
+		return true
+	}
+	return false
+}
+
+// definitions describes struct types with $refs during a conversion: the root type is
+// referred to with "#", the other types are moved to $defs. Without Defs only the types
+// met again on the current path, the recursive ones, are moved.
+type definitions struct {
+	// root is the struct converted at the root of the schema, nil when the root is another type
+	root   reflect.Type
+	all    bool
+	naming DefNaming
+	// path holds the property names leading to the type being converted
+	path       []string
+	names      map[reflect.Type]string
+	taken      map[string]bool
+	referenced map[reflect.Type]bool
+	defs       Schema
+}
+
+// invalidDefName matches the characters left out of $defs names, e.g. in generic type names
+var invalidDefName = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// name returns the definition name of a type, chosen on the first call
+func (d *definitions) name(t reflect.Type) string {
+	if name, ok := d.names[t]; ok {
+		return name
+	}
+	var base string
+	switch {
+	case t.Name() == "" || d.naming == DefNamePath:
+		// anonymous structs are named after the property holding them
+		base = PascalCase.Apply(invalidDefName.ReplaceAllString(strings.Join(d.path, "_"), "_"))
+	case d.naming == DefNameQualified && t.PkgPath() != "":
+		base = PascalCase.Apply(path.Base(t.PkgPath())) + invalidDefName.ReplaceAllString(t.Name(), "_")
+	default:
+		base = invalidDefName.ReplaceAllString(t.Name(), "_")
+	}
+	if base == "" {
+		base = "Schema"
+	}
+	// different types may get the same name, e.g. types of different packages
+	name := base
+	for i := 2; d.taken[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	d.names[t] = name
+	d.taken[name] = true
+	return name
+}
+
+// enter starts the conversion of a struct type, reporting whether its definition is
+// already known and a reference can be used instead
+func (d *definitions) enter(t reflect.Type) bool {
+	if t == d.root || (!d.all && d.naming != DefNamePath) {
+		return false
+	}
+	// names are chosen at the first occurrence, the path of recursive references differs
+	name := d.name(t)
+	return d.all && d.defs[name] != nil
+}
+
+// ref returns the reference to a type
+func (d *definitions) ref(t reflect.Type) Schema {
+	if t == d.root {
+		return Schema{"$ref": "#"}
+	}
+	d.referenced[t] = true
+	return Schema{"$ref": "#/$defs/" + d.name(t)}
+}
+
+// define stores the schema of the outermost occurrence of a type in $defs and returns the
+// reference replacing it, schemas staying inlined are returned unchanged
+func (d *definitions) define(t reflect.Type, schema Schema) Schema {
+	if t == d.root || !(d.all || d.referenced[t]) {
+		return schema
+	}
+	d.defs[d.name(t)] = schema
+	return d.ref(t)
+}
+
+// push and pop follow the properties descended into
+func (d *definitions) push(name string) {
+	d.path = append(d.path, name)
+}
+
+func (d *definitions) pop() {
+	d.path = d.path[:len(d.path)-1]
+}
+
+// convertWithDefinitions converts a type describing struct types with $refs, the
+// definitions are attached to the converted schema
+func convertWithDefinitions(t reflect.Type, visited map[reflect.Type]int, depth int, opts *Options) (interface{}, error) {
+	d := &definitions{
+		all:        opts.Defs,
+		naming:     opts.DefNaming,
+		names:      make(map[reflect.Type]string),
+		taken:      make(map[string]bool),
+		referenced: make(map[reflect.Type]bool),
+		defs:       make(Schema),
+	}
+	if deref(t).Kind() == reflect.Struct && depth == 0 && opts.arrayDepth == 0 {
+		d.root = deref(t)
+	}
+	opts.definitions = d
+	defer func() { opts.definitions = nil }()
+	result, err := JsonTypeOf(t, visited, depth, opts)
+	if err != nil || len(d.defs) == 0 {
+		return result, err
+	}
+	schema, ok := result.(Schema)
+	if !ok {
+		return result, nil
+	}
+	described := make(Schema, len(schema)+1)
+	for k, v := range schema {
+		described[k] = v
+	}
+	described["$defs"] = d.defs
+	return described, nil
+}
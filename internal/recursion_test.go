@@ -0,0 +1,227 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecursiveRefs(t *testing.T) {
+	type Shipment struct {
+		From Address   `json:"from"`
+		To   Address   `json:"to"`
+		Legs []Address `json:"legs"`
+	}
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		expected interface{}
+	}{
+		{"direct self-reference", reflect.TypeOf(Node{}), NodeRecursiveSchema},
+		{"cycle below the root", reflect.TypeOf(Forest{}), ForestRecursiveSchema},
+		{
+			name:  "sibling reuse is not a cycle",
+			input: reflect.TypeOf(Shipment{}),
+			expected: Schema{
+				"type": "object",
+				"properties": Schema{
+					"from": AddressSchema,
+					"to":   AddressSchema,
+					"legs": Schema{"type": "array", "items": AddressSchema},
+				},
+				"required":             []string{"from", "to", "legs"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name:  "root array of recursive types",
+			input: reflect.TypeOf([]Node{}),
+			expected: Schema{
+				"type":  "array",
+				"items": Schema{"$ref": "#/$defs/Node"},
+				"$defs": Schema{
+					"Node": Schema{
+						"type": "object",
+						"properties": Schema{
+							"value": Schema{"type": "string"},
+							"next":  Schema{"anyOf": []Schema{{"$ref": "#/$defs/Node"}, {"type": "null"}}},
+						},
+						"required":             []string{"value", "next"},
+						"additionalProperties": false,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.RecursiveRefs = true
+			opts.RecursionUnroll = 2
+			result, err := JsonTypeOf(tt.input, visited, depth, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+			if opts.definitions != nil || len(visited) != 0 {
+				t.Errorf("expected the conversion state to be reset")
+			}
+		})
+	}
+}
+
+func TestRecursiveRefsRequiredSelfPointer(t *testing.T) {
+	type Category struct {
+		Name   string    `json:"name"`
+		Parent *Category `json:"parent"`
+		Root   *Category `json:"root" required:"true"`
+	}
+	opts, visited, depth := getInputs()
+	opts.RecursiveRefs = true
+	result, err := JsonTypeOf(reflect.TypeOf(Category{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// only null ends the cycle, a bare required $ref would admit no finite document
+	nullableRef := Schema{"anyOf": []Schema{{"$ref": "#"}, {"type": "null"}}}
+	expected := Schema{
+		"type": "object",
+		"properties": Schema{
+			"name":   Schema{"type": "string"},
+			"parent": nullableRef,
+			"root":   nullableRef,
+		},
+		"required":             []string{"name", "parent", "root"},
+		"additionalProperties": false,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestRecursiveRefsIndirectCycle(t *testing.T) {
+	opts, visited, depth := getInputs()
+	opts.RecursiveRefs = true
+	result, err := JsonTypeOf(reflect.TypeOf(Tree{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tree := result.(Schema)
+	if _, ok := tree["$defs"]; ok {
+		t.Errorf("expected references to the root without $defs, got %v", tree["$defs"])
+	}
+	parent, ok := tree.At("children[].parent")
+	if !ok || !reflect.DeepEqual(parent["anyOf"], []Schema{{"$ref": "#"}, {"type": "null"}}) {
+		t.Errorf("expected parent to refer to the root, got %v", parent)
+	}
+}
+
+type defsOrder struct {
+	Billing struct {
+		Street string `json:"street"`
+	} `json:"billing"`
+	Shipping struct {
+		Street string `json:"street"`
+	} `json:"shipping"`
+	Items []struct {
+		SKU    string  `json:"sku"`
+		Origin Address `json:"origin"`
+	} `json:"line_items"`
+	Address Address `json:"address"`
+}
+
+func TestDefs(t *testing.T) {
+	street := Schema{
+		"type":                 "object",
+		"properties":           Schema{"street": Schema{"type": "string"}},
+		"required":             []string{"street"},
+		"additionalProperties": false,
+	}
+	lineItem := func(origin string) Schema {
+		return Schema{
+			"type": "object",
+			"properties": Schema{
+				"sku":    Schema{"type": "string"},
+				"origin": Schema{"$ref": "#/$defs/" + origin},
+			},
+			"required":             []string{"sku", "origin"},
+			"additionalProperties": false,
+		}
+	}
+	order := func(billing, items, address string) Schema {
+		return Schema{
+			"billing":    Schema{"$ref": "#/$defs/" + billing},
+			"shipping":   Schema{"$ref": "#/$defs/" + billing},
+			"line_items": Schema{"type": "array", "items": Schema{"$ref": "#/$defs/" + items}},
+			"address":    Schema{"$ref": "#/$defs/" + address},
+		}
+	}
+	tests := []struct {
+		name       string
+		naming     DefNaming
+		properties Schema
+		defs       Schema
+	}{
+		{
+			name:       "type names, anonymous structs named after their path",
+			naming:     DefNameType,
+			properties: order("Billing", "LineItems", "Address"),
+			defs:       Schema{"Billing": street, "LineItems": lineItem("Address"), "Address": AddressSchema},
+		},
+		{
+			name:       "path names",
+			naming:     DefNamePath,
+			properties: order("Billing", "LineItems", "LineItemsOrigin"),
+			defs:       Schema{"Billing": street, "LineItems": lineItem("LineItemsOrigin"), "LineItemsOrigin": AddressSchema},
+		},
+		{
+			name:       "qualified names",
+			naming:     DefNameQualified,
+			properties: order("Billing", "LineItems", "InternalAddress"),
+			defs:       Schema{"Billing": street, "LineItems": lineItem("InternalAddress"), "InternalAddress": AddressSchema},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.Defs = true
+			opts.DefNaming = tt.naming
+			result, err := JsonTypeOf(reflect.TypeOf(defsOrder{}), visited, depth, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			schema := result.(Schema)
+			if !reflect.DeepEqual(schema["properties"], tt.properties) {
+				t.Errorf("expected properties %+v, got %+v", tt.properties, schema["properties"])
+			}
+			if !reflect.DeepEqual(schema["$defs"], tt.defs) {
+				t.Errorf("expected $defs %+v, got %+v", tt.defs, schema["$defs"])
+			}
+		})
+	}
+}
+
+func TestDefsNameCollisions(t *testing.T) {
+	type Contact struct {
+		Address struct {
+			Line string `json:"line"`
+		} `json:"address"`
+		Home Address `json:"home"`
+		Next *Node   `json:"next"`
+	}
+	opts, visited, depth := getInputs()
+	opts.Defs = true
+	result, err := JsonTypeOf(reflect.TypeOf(Contact{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defs := result.(Schema)["$defs"].(Schema)
+	if len(defs) != 3 || defs["Address"] == nil || !reflect.DeepEqual(defs["Address2"], AddressSchema) || defs["Node"] == nil {
+		t.Errorf("expected Address, Address2 and Node definitions, got %v", sortedKeys(defs))
+	}
+	next, _ := defs["Node"].(Schema).At("next")
+	if !reflect.DeepEqual(next["anyOf"], []Schema{{"$ref": "#/$defs/Node"}, {"type": "null"}}) {
+		t.Errorf("expected the recursive type to refer to its definition, got %v", next)
+	}
+}
@@ -0,0 +1,416 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// InlineRefs replaces every $ref with a copy of the schema it points to, keywords next to
+// the $ref override the referenced schema's. Refs to other documents are resolved relative
+// to the document containing them, or the root $id, and loaded with opts.RefLoader once per
+// document. Recursive references cannot be inlined and are reported as a CircularRefError.
+// The input is not modified.
+func InlineRefs(schema Schema, opts *Options) (Schema, error) {
+	base, _ := schema["$id"].(string)
+	base, _, _ = strings.Cut(base, "#")
+	r := &refResolver{
+		load:      opts.RefLoader,
+		docs:      map[string]Schema{base: schema},
+		resolving: make(map[string]bool),
+	}
+	inlined, err := r.schema(schema, base)
+	if err != nil {
+		return nil, err
+	}
+	for _, keyword := range defsKeywords {
+		delete(inlined, keyword)
+	}
+	return inlined, nil
+}
+
+// defsKeywords hold the definitions $refs point to, $defs (2019-09) and definitions (draft-07)
+var defsKeywords = []string{"$defs", "definitions"}
+
+type refResolver struct {
+	load func(uri string) (Schema, error)
+	// docs caches the documents by URI, the root document is stored under its base URI
+	docs      map[string]Schema
+	resolving map[string]bool
+	chain     []string
+}
+
+func (r *refResolver) value(v interface{}, base string) (interface{}, error) {
+	switch v := v.(type) {
+	case Schema:
+		return r.schema(v, base)
+	case map[string]interface{}:
+		return r.schema(v, base)
+	case []Schema:
+		out := make([]Schema, len(v))
+		for i, s := range v {
+			inlined, err := r.schema(s, base)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = inlined
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			inlined, err := r.value(item, base)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = inlined
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func (r *refResolver) schema(s Schema, base string) (Schema, error) {
+	out := make(Schema, len(s))
+	ref, isRef := s["$ref"].(string)
+	if isRef {
+		uri, fragment := resolveRef(base, ref)
+		target, err := r.target(uri, fragment)
+		if err != nil {
+			return nil, fmt.Errorf("unresolved $ref %q: %w", ref, err)
+		}
+		key := uri + "#" + fragment
+		if r.resolving[key] {
+			return nil, &CircularRefError{Path: append(append([]string(nil), r.chain...), ref)}
+		}
+		r.resolving[key] = true
+		r.chain = append(r.chain, ref)
+		resolved, err := r.schema(target, uri)
+		r.chain = r.chain[:len(r.chain)-1]
+		delete(r.resolving, key)
+		if err != nil {
+			return nil, err
+		}
+		if fragment == "" {
+			// a whole document is inlined without its definitions and identifiers
+			for _, keyword := range append([]string{"$id", "$schema"}, defsKeywords...) {
+				delete(resolved, keyword)
+			}
+		}
+		out = resolved
+	}
+	for k, v := range s {
+		if k == "$ref" && isRef {
+			continue
+		}
+		inlined, err := r.value(v, base)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = schemaList(k, inlined)
+	}
+	return out, nil
+}
+
+// target returns the schema at the JSON pointer fragment of the document at uri
+func (r *refResolver) target(uri, fragment string) (Schema, error) {
+	doc, ok := r.docs[uri]
+	if !ok {
+		if r.load == nil {
+			return nil, errors.New("no loader for external references")
+		}
+		loaded, err := r.load(uri)
+		if err != nil {
+			return nil, err
+		}
+		doc = loaded
+		r.docs[uri] = doc
+	}
+	var current interface{} = doc
+	if fragment != "" {
+		if !strings.HasPrefix(fragment, "/") {
+			return nil, errors.New("only JSON pointer fragments are supported")
+		}
+		for _, token := range strings.Split(fragment[1:], "/") {
+			token = unescapePointer(token)
+			switch v := current.(type) {
+			case Schema:
+				current, ok = v[token]
+			case map[string]interface{}:
+				current, ok = v[token]
+			case []Schema:
+				i, err := strconv.Atoi(token)
+				ok = err == nil && i >= 0 && i < len(v)
+				if ok {
+					current = v[i]
+				}
+			case []interface{}:
+				i, err := strconv.Atoi(token)
+				ok = err == nil && i >= 0 && i < len(v)
+				if ok {
+					current = v[i]
+				}
+			default:
+				ok = false
+			}
+			if !ok {
+				return nil, fmt.Errorf("%q not found", fragment)
+			}
+		}
+	}
+	target, ok := asSchema(current)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a schema", fragment)
+	}
+	return target, nil
+}
+
+// resolveRef splits a $ref into the URI of its document, resolved against the base URI
+// of the document containing it, and its fragment. Relative file paths are resolved
+// against the directory of the base path.
+func resolveRef(base, ref string) (uri, fragment string) {
+	uri, fragment, _ = strings.Cut(ref, "#")
+	if fragment != "" {
+		if unescaped, err := url.PathUnescape(fragment); err == nil {
+			fragment = unescaped
+		}
+	}
+	switch {
+	case uri == "":
+		return base, fragment
+	case isAbsoluteURI(uri) || path.IsAbs(uri):
+		return uri, fragment
+	case isAbsoluteURI(base):
+		baseURL, _ := url.Parse(base)
+		refURL, err := url.Parse(uri)
+		if err != nil {
+			return uri, fragment
+		}
+		return baseURL.ResolveReference(refURL).String(), fragment
+	}
+	return path.Join(path.Dir(base), uri), fragment
+}
+
+func isAbsoluteURI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && len(u.Scheme) > 1 // a single letter is a Windows drive
+}
+
+// schemaList converts composition keywords decoded from JSON to []Schema like generated schemas
+func schemaList(keyword string, v interface{}) interface{} {
+	items, ok := v.([]interface{})
+	if !ok || (keyword != "anyOf" && keyword != "allOf" && keyword != "oneOf") {
+		return v
+	}
+	variants := make([]Schema, len(items))
+	for i, item := range items {
+		s, ok := item.(Schema)
+		if !ok {
+			return v
+		}
+		variants[i] = s
+	}
+	return variants
+}
+
+// BundleRefs is the inverse of InlineRefs: object schemas occurring more than once are
+// moved to $defs, named after the property they first appear in, and replaced with $refs.
+// Existing $refs are inlined first. The input is not modified.
+func BundleRefs(schema Schema, opts *Options) (Schema, error) {
+	bundled, err := InlineRefs(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+	defs := make(Schema)
+	for {
+		b := &bundler{counts: make(map[string]int), names: make(map[string]string), originals: make(map[string]Schema)}
+		if err := b.count(bundled, "", true); err != nil {
+			return nil, err
+		}
+		for _, name := range sortedKeys(defs) {
+			if err := b.count(defs[name].(Schema), name, true); err != nil {
+				return nil, err
+			}
+		}
+		// the largest repeated schema is extracted first, so that schemas nested in
+		// it are only counted once it has been replaced by a $ref
+		key := ""
+		for k, n := range b.counts {
+			if n > 1 && (len(k) > len(key) || (len(k) == len(key) && k < key)) {
+				key = k
+			}
+		}
+		if key == "" {
+			break
+		}
+		name := uniqueDefName(defs, b.names[key])
+		ref := Schema{"$ref": "#/$defs/" + escapePointer(name)}
+		def := b.originals[key]
+		if err := replaceSchema(bundled, key, ref); err != nil {
+			return nil, err
+		}
+		for _, defName := range sortedKeys(defs) {
+			if err := replaceSchema(defs[defName].(Schema), key, ref); err != nil {
+				return nil, err
+			}
+		}
+		defs[name] = def
+	}
+	if len(defs) > 0 {
+		bundled["$defs"] = defs
+	}
+	return bundled, nil
+}
+
+type bundler struct {
+	// counts the occurrences of object schemas by their JSON encoding
+	counts    map[string]int
+	names     map[string]string
+	originals map[string]Schema
+}
+
+// count records the object schemas below s, root schemas themselves are not candidates
+func (b *bundler) count(s Schema, name string, root bool) error {
+	if !root && s["type"] == "object" {
+		if _, ok := s["properties"].(Schema); ok {
+			key, err := schemaKey(s)
+			if err != nil {
+				return err
+			}
+			if b.counts[key] == 0 {
+				b.names[key] = name
+				b.originals[key] = s
+			}
+			b.counts[key]++
+		}
+	}
+	return forEachSubschema(s, name, func(sub Schema, subName string) error {
+		return b.count(sub, subName, false)
+	})
+}
+
+// forEachSubschema calls fn for the direct subschemas of s in a stable order, with the
+// name a definition extracted from them would get
+func forEachSubschema(s Schema, name string, fn func(sub Schema, name string) error) error {
+	if props, ok := s["properties"].(Schema); ok {
+		for _, prop := range sortedKeys(props) {
+			if sub, ok := props[prop].(Schema); ok {
+				if err := fn(sub, prop); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if items, ok := s["items"].(Schema); ok {
+		if err := fn(items, name+"Item"); err != nil {
+			return err
+		}
+	}
+	if values, ok := s["additionalProperties"].(Schema); ok {
+		if err := fn(values, name+"Value"); err != nil {
+			return err
+		}
+	}
+	for _, keyword := range []string{"anyOf", "allOf", "oneOf"} {
+		variants, _ := s[keyword].([]Schema)
+		for _, variant := range variants {
+			if err := fn(variant, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// replaceSchema replaces the subschemas of s encoded as key with ref, in place
+func replaceSchema(s Schema, key string, ref Schema) error {
+	replace := func(sub Schema) (Schema, error) {
+		subKey, err := schemaKey(sub)
+		if err != nil {
+			return nil, err
+		}
+		if subKey == key {
+			return ref, nil
+		}
+		return sub, replaceSchema(sub, key, ref)
+	}
+	if props, ok := s["properties"].(Schema); ok {
+		for name, prop := range props {
+			if sub, ok := prop.(Schema); ok {
+				replaced, err := replace(sub)
+				if err != nil {
+					return err
+				}
+				props[name] = replaced
+			}
+		}
+	}
+	for _, keyword := range []string{"items", "additionalProperties"} {
+		if sub, ok := s[keyword].(Schema); ok {
+			replaced, err := replace(sub)
+			if err != nil {
+				return err
+			}
+			s[keyword] = replaced
+		}
+	}
+	for _, keyword := range []string{"anyOf", "allOf", "oneOf"} {
+		variants, _ := s[keyword].([]Schema)
+		for i, variant := range variants {
+			replaced, err := replace(variant)
+			if err != nil {
+				return err
+			}
+			variants[i] = replaced
+		}
+	}
+	return nil
+}
+
+// schemaKey encodes a schema deterministically, encoding/json sorts map keys
+func schemaKey(s Schema) (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// uniqueDefName converts a property name to a PascalCase definition name,
+// numbering it when the name is taken
+func uniqueDefName(defs Schema, property string) string {
+	base := PascalCase.Apply(property)
+	if base == "" {
+		base = "Schema"
+	}
+	name := base
+	for i := 2; defs[name] != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	return name
+}
+
+// asSchema accepts generated schemas and schemas decoded from JSON
+func asSchema(v interface{}) (Schema, bool) {
+	switch v := v.(type) {
+	case Schema:
+		return v, true
+	case map[string]interface{}:
+		return v, true
+	}
+	return nil, false
+}
+
+// escapePointer escapes a JSON pointer token (RFC 6901)
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// unescapePointer reverses escapePointer
+func unescapePointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
@@ -0,0 +1,243 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInlineRefs(t *testing.T) {
+	address := Schema{
+		"type":       "object",
+		"properties": Schema{"city": Schema{"type": "string"}},
+	}
+	tests := []struct {
+		name     string
+		schema   Schema
+		expected Schema
+	}{
+		{
+			name: "$defs",
+			schema: Schema{
+				"type": "object",
+				"properties": Schema{
+					"home": Schema{"$ref": "#/$defs/Address"},
+					"work": Schema{"$ref": "#/$defs/Address", "description": "office"},
+				},
+				"$defs": Schema{"Address": address},
+			},
+			expected: Schema{
+				"type": "object",
+				"properties": Schema{
+					"home": address,
+					"work": Schema{"type": "object", "properties": Schema{"city": Schema{"type": "string"}}, "description": "office"},
+				},
+			},
+		},
+		{
+			name: "nested definitions",
+			schema: Schema{
+				"type": "array",
+				"items": Schema{"anyOf": []Schema{
+					{"$ref": "#/definitions/Person"},
+					{"type": "null"},
+				}},
+				"definitions": Schema{
+					"Person":  Schema{"type": "object", "properties": Schema{"address": Schema{"$ref": "#/definitions/Address"}}},
+					"Address": address,
+				},
+			},
+			expected: Schema{
+				"type": "array",
+				"items": Schema{"anyOf": []Schema{
+					{"type": "object", "properties": Schema{"address": address}},
+					{"type": "null"},
+				}},
+			},
+		},
+		{
+			name:     "escaped names",
+			schema:   Schema{"$ref": "#/$defs/a~1b", "$defs": Schema{"a/b": Schema{"type": "string"}}},
+			expected: Schema{"type": "string"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := InlineRefs(tt.schema, DefaultOptions())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestInlineRefs_DecodedJSON(t *testing.T) {
+	data := `{"type":"object","properties":{"pet":{"anyOf":[{"$ref":"#/$defs/Cat"},{"type":"null"}]}},
+		"$defs":{"Cat":{"type":"object","properties":{"name":{"type":"string"}}}}}`
+	var schema Schema
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		t.Fatal(err)
+	}
+	result, err := InlineRefs(schema, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	variants, ok := result["properties"].(Schema)["pet"].(Schema)["anyOf"].([]Schema)
+	if !ok || len(variants) != 2 || variants[0]["type"] != "object" {
+		t.Errorf("expected anyOf to be inlined as []Schema, got %+v", result["properties"])
+	}
+}
+
+func TestInlineRefs_Errors(t *testing.T) {
+	recursive := Schema{
+		"$ref": "#/$defs/Node",
+		"$defs": Schema{
+			"Node": Schema{"type": "object", "properties": Schema{"next": Schema{"$ref": "#/$defs/Node"}}},
+		},
+	}
+	_, err := InlineRefs(recursive, DefaultOptions())
+	var circular *CircularRefError
+	if !errors.As(err, &circular) || !errors.Is(err, ErrCircularRef) {
+		t.Fatalf("expected a CircularRefError, got %v", err)
+	}
+	if expected := []string{"#/$defs/Node", "#/$defs/Node"}; !reflect.DeepEqual(circular.Path, expected) {
+		t.Errorf("expected path %v, got %v", expected, circular.Path)
+	}
+
+	_, err = InlineRefs(Schema{"$ref": "https://example.com/schema.json"}, DefaultOptions())
+	if err == nil || err.Error() != `unresolved $ref "https://example.com/schema.json": no loader for external references` {
+		t.Errorf("expected unresolved $ref error, got %v", err)
+	}
+}
+
+func TestBundleRefs(t *testing.T) {
+	city := Schema{"type": "string"}
+	address := func() Schema {
+		return Schema{"type": "object", "properties": Schema{"city": city}, "additionalProperties": false}
+	}
+	person := func() Schema {
+		return Schema{"type": "object", "properties": Schema{"home_address": address()}}
+	}
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"owner":    person(),
+			"tenants":  Schema{"type": "array", "items": person()},
+			"billing":  address(),
+			"shipping": Schema{"anyOf": []Schema{address(), {"type": "null"}}},
+		},
+	}
+	bundled, err := BundleRefs(schema, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Schema{
+		"type": "object",
+		"properties": Schema{
+			"owner":    Schema{"$ref": "#/$defs/Owner"},
+			"tenants":  Schema{"type": "array", "items": Schema{"$ref": "#/$defs/Owner"}},
+			"billing":  Schema{"$ref": "#/$defs/Billing"},
+			"shipping": Schema{"anyOf": []Schema{{"$ref": "#/$defs/Billing"}, {"type": "null"}}},
+		},
+		"$defs": Schema{
+			"Owner":   Schema{"type": "object", "properties": Schema{"home_address": Schema{"$ref": "#/$defs/Billing"}}},
+			"Billing": address(),
+		},
+	}
+	if !reflect.DeepEqual(bundled, expected) {
+		t.Errorf("expected %+v, got %+v", expected, bundled)
+	}
+	inlined, err := InlineRefs(bundled, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(inlined, schema) {
+		t.Errorf("expected inlining the bundle to restore the schema, got %+v", inlined)
+	}
+}
+
+func TestBundleRefs_NothingRepeated(t *testing.T) {
+	schema := Schema{"type": "object", "properties": Schema{"name": Schema{"type": "string"}}}
+	bundled, err := BundleRefs(schema, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(bundled, schema) {
+		t.Errorf("expected the schema unchanged, got %+v", bundled)
+	}
+}
+
+func TestResolveRef(t *testing.T) {
+	tests := []struct {
+		base, ref     string
+		uri, fragment string
+	}{
+		{base: "", ref: "#/$defs/A", uri: "", fragment: "/$defs/A"},
+		{base: "", ref: "address.json", uri: "address.json"},
+		{base: "schemas/order.json", ref: "address.json#/$defs/A", uri: "schemas/address.json", fragment: "/$defs/A"},
+		{base: "schemas/order.json", ref: "../common/city.json", uri: "common/city.json"},
+		{base: "schemas/order.json", ref: "/abs/city.json", uri: "/abs/city.json"},
+		{base: "https://example.com/s/order.json", ref: "tag.json", uri: "https://example.com/s/tag.json"},
+		{base: "https://example.com/s/order.json", ref: "#/$defs/A%20B", uri: "https://example.com/s/order.json", fragment: "/$defs/A B"},
+		{base: "schemas/order.json", ref: "https://example.com/tag.json", uri: "https://example.com/tag.json"},
+	}
+	for _, tt := range tests {
+		uri, fragment := resolveRef(tt.base, tt.ref)
+		if uri != tt.uri || fragment != tt.fragment {
+			t.Errorf("resolveRef(%q, %q) = %q, %q, expected %q, %q", tt.base, tt.ref, uri, fragment, tt.uri, tt.fragment)
+		}
+	}
+}
+
+func TestInlineRefs_Loader(t *testing.T) {
+	docs := map[string]Schema{
+		"person.json": {
+			"type":       "object",
+			"properties": Schema{"pet": Schema{"$ref": "#/$defs/Pet"}},
+			"$defs":      Schema{"Pet": Schema{"type": "string"}},
+		},
+	}
+	var loaded []string
+	opts := DefaultOptions()
+	opts.RefLoader = func(uri string) (Schema, error) {
+		loaded = append(loaded, uri)
+		doc, ok := docs[uri]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return doc, nil
+	}
+	schema := Schema{
+		"type": "array",
+		"items": Schema{"anyOf": []Schema{
+			{"$ref": "person.json"},
+			{"$ref": "person.json#/properties/pet"},
+		}},
+	}
+	result, err := InlineRefs(schema, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Schema{
+		"type": "array",
+		"items": Schema{"anyOf": []Schema{
+			{"type": "object", "properties": Schema{"pet": Schema{"type": "string"}}},
+			{"type": "string"},
+		}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+	if !reflect.DeepEqual(loaded, []string{"person.json"}) {
+		t.Errorf("expected person.json to be loaded once, got %v", loaded)
+	}
+
+	_, err = InlineRefs(Schema{"$ref": "other.json"}, opts)
+	if err == nil || err.Error() != `unresolved $ref "other.json": not found` {
+		t.Errorf("expected loader error, got %v", err)
+	}
+}
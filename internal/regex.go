@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// regexScalarPatterns maps JSON types to the pattern of their values
+var regexScalarPatterns = map[string]string{
+	"string":  jsonStringPattern,
+	"integer": jsonIntegerPattern,
+	"number":  jsonNumberPattern,
+	"boolean": "(?:true|false)",
+	"null":    "null",
+}
+
+// RenderRegex converts a flat object schema, whose properties are scalars, enums or
+// constants, nullable ones included, into a regular expression matching its compact JSON
+// documents, with the properties in the order of orderedProperties. The expression is not
+// anchored and must match the whole output. Optional properties are left out and
+// constraints on scalars are dropped, both are reported as warnings. Nested objects,
+// arrays, maps and unions are returned as an ErrUnsupportedType error.
+func RenderRegex(schema Schema, opts *Options) (string, error) {
+	if schema["type"] != "object" {
+		return "", fmt.Errorf("%w: (root): only object schemas can be expressed as a regular expression", ErrUnsupportedType)
+	}
+	if _, ok := schema["additionalProperties"].(Schema); ok {
+		return "", fmt.Errorf("%w: (root): maps cannot be expressed as a regular expression", ErrUnsupportedType)
+	}
+	props, _ := schema["properties"].(Schema)
+	required := make(map[string]bool)
+	if names, ok := schema["required"].([]string); ok {
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	var parts []string
+	for _, name := range orderedProperties(schema) {
+		if !required[name] {
+			opts.Warn(name, "optional property left out of the regular expression")
+			continue
+		}
+		prop, _ := props[name].(Schema)
+		pattern, err := regexValue(prop, name, opts)
+		if err != nil {
+			return "", err
+		}
+		key, err := regexLiteral(name)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, key+":"+pattern)
+	}
+	return `\{` + strings.Join(parts, ",") + `\}`, nil
+}
+
+// regexLiteral returns the pattern matching the JSON encoding of v
+func regexLiteral(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return regexp.QuoteMeta(string(data)), nil
+}
+
+// regexValue returns the pattern matching the values of a scalar schema
+func regexValue(s Schema, path string, opts *Options) (string, error) {
+	unwrapped, nullable := unwrapNullable(s)
+	pattern, err := regexNonNullValue(unwrapped, path, opts)
+	if err != nil || !nullable || pattern == "null" {
+		return pattern, err
+	}
+	return "(?:" + pattern + "|null)", nil
+}
+
+func regexNonNullValue(s Schema, path string, opts *Options) (string, error) {
+	if value, ok := s["const"]; ok {
+		return regexLiteral(value)
+	}
+	if values, ok := s["enum"].([]interface{}); ok {
+		literals := make([]string, len(values))
+		for i, value := range values {
+			literal, err := regexLiteral(value)
+			if err != nil {
+				return "", err
+			}
+			literals[i] = literal
+		}
+		return "(?:" + strings.Join(literals, "|") + ")", nil
+	}
+	for _, keyword := range []string{"anyOf", "oneOf", "allOf", "$ref"} {
+		if _, ok := s[keyword]; ok {
+			return "", fmt.Errorf("%w: %s: %s cannot be expressed as a regular expression", ErrUnsupportedType, path, keyword)
+		}
+	}
+	t, _ := s["type"].(string)
+	pattern, ok := regexScalarPatterns[t]
+	if !ok {
+		return "", fmt.Errorf("%w: %s: only scalars can be expressed as a regular expression, got type %v", ErrUnsupportedType, path, s["type"])
+	}
+	for _, keyword := range larkDroppedKeywords {
+		if _, ok := s[keyword]; ok {
+			opts.Warn(path, "keyword %q is not enforced by the regular expression", keyword)
+		}
+	}
+	return pattern, nil
+}
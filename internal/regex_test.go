@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestRenderRegex(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"kind":   Schema{"const": "verdict"},
+			"label":  Schema{"type": "string", "enum": []interface{}{"spam", "ham (maybe)"}},
+			"score":  Schema{"type": "number", "minimum": 0},
+			"count":  Schema{"type": []string{"integer", "null"}},
+			"reason": Schema{"type": "string"},
+			"final":  Schema{"type": "boolean"},
+			"note":   Schema{"type": "string"},
+		},
+		"required": []string{"kind", "label", "score", "count", "reason", "final"},
+	}
+	var warnings []string
+	opts := DefaultOptions()
+	opts.WarningHandler = func(w Warning) { warnings = append(warnings, w.Path) }
+	pattern, err := RenderRegex(schema, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(warnings, []string{"score", "note"}) {
+		t.Errorf("expected warnings for the minimum and the optional property, got %v", warnings)
+	}
+	matcher := regexp.MustCompile(`^(?:` + pattern + `)$`)
+	tests := []struct {
+		document string
+		expected bool
+	}{
+		{`{"kind":"verdict","label":"spam","score":0.5,"count":3,"reason":"a \"quoted\" link\n","final":true}`, true},
+		{`{"kind":"verdict","label":"ham (maybe)","score":-1e3,"count":null,"reason":"","final":false}`, true},
+		{`{"kind":"verdict","label":"eggs","score":0.5,"count":3,"reason":"","final":true}`, false},
+		{`{"kind":"verdict","label":"spam","score":0.5,"count":3.5,"reason":"","final":true}`, false},
+		{`{"kind":"verdict","label":"spam","score":0.5,"count":3,"reason":"line
+break","final":true}`, false},
+		{`{"label":"spam","kind":"verdict","score":0.5,"count":3,"reason":"","final":true}`, false},
+		{`{"kind":"verdict","label":"spam","score":01,"count":3,"reason":"","final":true}`, false},
+	}
+	for _, tt := range tests {
+		if got := matcher.MatchString(tt.document); got != tt.expected {
+			t.Errorf("match %s: expected %v, got %v", tt.document, tt.expected, got)
+		}
+	}
+}
+
+func TestRenderRegex_Unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		prop Schema
+	}{
+		{"nested object", Schema{"type": "object", "properties": Schema{}}},
+		{"array", Schema{"type": "array", "items": Schema{"type": "string"}}},
+		{"union", Schema{"anyOf": []Schema{{"type": "string"}, {"type": "integer"}}}},
+		{"reference", Schema{"$ref": "#/$defs/Owner"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := Schema{"type": "object", "properties": Schema{"value": tt.prop}, "required": []string{"value"}}
+			if _, err := RenderRegex(schema, DefaultOptions()); !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("expected ErrUnsupportedType, got %v", err)
+			}
+		})
+	}
+	if _, err := RenderRegex(Schema{"type": "string"}, DefaultOptions()); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType for a non-object root, got %v", err)
+	}
+}
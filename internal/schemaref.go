@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"fmt"
+	"sync"
+)
+
+// curated schemas registered by name, used by the fields tagged schemaRef:"<name>"
+var (
+	namedSchemasMu sync.RWMutex
+	namedSchemas   = make(map[string]Schema)
+)
+
+// RegisterNamedSchema records a hand-written schema under name, registering the name
+// again replaces the schema
+func RegisterNamedSchema(name string, schema Schema) {
+	namedSchemasMu.Lock()
+	defer namedSchemasMu.Unlock()
+	namedSchemas[name] = schema.Clone()
+}
+
+// lookupNamedSchema returns a copy of the schema registered under name
+func lookupNamedSchema(name string) (Schema, bool) {
+	namedSchemasMu.RLock()
+	defer namedSchemasMu.RUnlock()
+	schema, ok := namedSchemas[name]
+	if !ok {
+		return nil, false
+	}
+	return schema.Clone(), true
+}
+
+// schemaRefSchema returns the subschema of a field tagged schemaRef: the schema registered
+// under the name, or else the document the reference points to, loaded with RefLoader and
+// with its own $refs inlined, e.g. "file://address.schema.json#/$defs/Address"
+func schemaRefSchema(fieldName, ref string, opts *Options) (Schema, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("%w: empty schemaRef tag on field %q", ErrInvalidTag, fieldName)
+	}
+	if schema, ok := lookupNamedSchema(ref); ok {
+		return schema, nil
+	}
+	r := &refResolver{
+		load:      opts.RefLoader,
+		docs:      make(map[string]Schema),
+		resolving: make(map[string]bool),
+	}
+	schema, err := r.schema(Schema{"$ref": ref}, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: schemaRef tag on field %q: %v", ErrInvalidTag, fieldName, err)
+	}
+	return schema, nil
+}
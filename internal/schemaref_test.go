@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaRefTag(t *testing.T) {
+	RegisterNamedSchema("money", Schema{"type": "string", "pattern": `^\d+\.\d{2}$`})
+	docs := map[string]Schema{
+		"address.schema.json": {
+			"$id":   "address.schema.json",
+			"type":  "object",
+			"$defs": Schema{"country": Schema{"type": "string", "enum": []interface{}{"FR", "JP"}}},
+			"properties": Schema{
+				"line":    Schema{"type": "string"},
+				"country": Schema{"$ref": "#/$defs/country"},
+			},
+			"required":             []interface{}{"line", "country"},
+			"additionalProperties": false,
+		},
+	}
+	loader := func(uri string) (Schema, error) {
+		if doc, ok := docs[uri]; ok {
+			return doc, nil
+		}
+		return nil, fmt.Errorf("%s not found", uri)
+	}
+	type Invoice struct {
+		Total     string  `json:"total" schemaRef:"money"`
+		Tip       string  `json:"tip,omitempty" schemaRef:"money"`
+		Address   Curated `json:"address" schemaRef:"address.schema.json"`
+		Country   string  `json:"country" schemaRef:"address.schema.json#/$defs/country"`
+		Reflected int     `json:"reflected"`
+	}
+	opts, visited, depth := getInputs()
+	opts.RefLoader = loader
+	result, err := JsonTypeOf(reflect.TypeOf(Invoice{}), visited, depth, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	country := Schema{"type": "string", "enum": []interface{}{"FR", "JP"}}
+	expected := Schema{
+		"total": Schema{"type": "string", "pattern": `^\d+\.\d{2}$`},
+		"tip":   Schema{"anyOf": []Schema{{"type": "string", "pattern": `^\d+\.\d{2}$`}, {"type": "null"}}},
+		"address": Schema{
+			"type":                 "object",
+			"properties":           Schema{"line": Schema{"type": "string"}, "country": country},
+			"required":             []interface{}{"line", "country"},
+			"additionalProperties": false,
+		},
+		"country":   country,
+		"reflected": Schema{"type": "integer"},
+	}
+	if props := result.(Schema)["properties"]; !reflect.DeepEqual(props, expected) {
+		t.Errorf("expected %+v, got %+v", expected, props)
+	}
+	if _, ok := docs["address.schema.json"]["$defs"]; !ok {
+		t.Errorf("expected the loaded document to stay unchanged")
+	}
+}
+
+// Curated is described by a hand-written schema only
+type Curated struct{}
+
+func TestSchemaRefTagErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{name: "empty reference", input: struct {
+			A string `json:"a" schemaRef:""`
+		}{}},
+		{name: "unknown name without loader", input: struct {
+			A string `json:"a" schemaRef:"unregistered"`
+		}{}},
+		{name: "combined with types", input: struct {
+			A interface{} `json:"a" schemaRef:"money" types:"string,integer"`
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			_, err := JsonTypeOf(reflect.TypeOf(tt.input), visited, depth, opts)
+			if !errors.Is(err, ErrInvalidTag) {
+				t.Fatalf("expected ErrInvalidTag, got %v", err)
+			}
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) || schemaErr.Code != TagSyntax || schemaErr.Path != "a" {
+				t.Errorf("expected a TagSyntax error at a, got %v", err)
+			}
+		})
+	}
+}
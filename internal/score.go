@@ -0,0 +1,197 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FieldScore is the conformance of a field across a batch of responses
+type FieldScore struct {
+	// Path of the field as in FlattenFields, e.g. "items[].price", empty for the response
+	Path string
+	// Seen is the number of responses where the field is present or reported missing
+	Seen int
+	// Valid is the number of those responses without a violation at the field
+	Valid int
+	// Violations counts the violations at the field by keyword, e.g. "enum" or "required"
+	Violations map[string]int
+}
+
+// Rate returns the share of the responses where the field was seen that it validated in,
+// 1 for a field never seen
+func (f FieldScore) Rate() float64 {
+	if f.Seen == 0 {
+		return 1
+	}
+	return float64(f.Valid) / float64(f.Seen)
+}
+
+// ViolationCount is the number of violations of a keyword
+type ViolationCount struct {
+	Keyword string
+	Count   int
+}
+
+// CommonViolations returns the violations of the field, the most frequent first
+func (f FieldScore) CommonViolations() []ViolationCount {
+	counts := make([]ViolationCount, 0, len(f.Violations))
+	for keyword, count := range f.Violations {
+		counts = append(counts, ViolationCount{Keyword: keyword, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Keyword < counts[j].Keyword
+	})
+	return counts
+}
+
+// ScoreReport is the conformance of a batch of responses to a schema
+type ScoreReport struct {
+	// Responses is the number of responses scored
+	Responses int
+	// Valid is the number of responses without any violation
+	Valid int
+	// Unparsable is the number of responses that are not JSON, counted in Responses but
+	// not in the field scores
+	Unparsable int
+	// Fields are the scores of the schema fields in FlattenFields order, followed by the
+	// other paths with violations, such as the response itself
+	Fields []FieldScore
+}
+
+// Field returns the score of a field path
+func (r ScoreReport) Field(path string) (FieldScore, bool) {
+	for _, field := range r.Fields {
+		if field.Path == path {
+			return field, true
+		}
+	}
+	return FieldScore{}, false
+}
+
+// String renders the report as one line per field, e.g.
+// "status: 8/10 valid (80%), enum 2"
+func (r ScoreReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d/%d responses valid", r.Valid, r.Responses)
+	if r.Unparsable > 0 {
+		fmt.Fprintf(&b, ", %d not JSON", r.Unparsable)
+	}
+	b.WriteString("\n")
+	for _, field := range r.Fields {
+		fmt.Fprintf(&b, "%s: %d/%d valid (%.0f%%)", pathLabel(field.Path), field.Valid, field.Seen, field.Rate()*100)
+		for _, violation := range field.CommonViolations() {
+			fmt.Fprintf(&b, ", %s %d", violation.Keyword, violation.Count)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ScoreDocuments validates responses decoded with UseNumber against a schema and gathers
+// per field how often it validated and which keywords it violated
+func ScoreDocuments(schema Schema, documents []interface{}, opts *Options) (ScoreReport, error) {
+	report := ScoreReport{Responses: len(documents)}
+	index := make(map[string]int)
+	var paths []string
+	FlattenFields(schema, func(field FieldInfo) {
+		index[field.Path] = len(report.Fields)
+		paths = append(paths, field.Path)
+		report.Fields = append(report.Fields, FieldScore{Path: field.Path, Violations: map[string]int{}})
+	})
+	extra := len(report.Fields)
+	for _, document := range documents {
+		mismatches, err := ValidateValue(schema, document, opts)
+		if err != nil {
+			return ScoreReport{}, err
+		}
+		if len(mismatches) == 0 {
+			report.Valid++
+		}
+		seen := make(map[int]bool)
+		failed := make(map[int]bool)
+		for _, path := range paths {
+			if len(valuesAt(document, path)) > 0 {
+				seen[index[path]] = true
+			}
+		}
+		for _, m := range mismatches {
+			path := schema.fieldPath(m.Path)
+			i, ok := index[path]
+			if !ok {
+				i = len(report.Fields)
+				index[path] = i
+				report.Fields = append(report.Fields, FieldScore{Path: path, Violations: map[string]int{}})
+			}
+			report.Fields[i].Violations[m.Keyword]++
+			seen[i] = true
+			failed[i] = true
+		}
+		for i := range seen {
+			report.Fields[i].Seen++
+			if !failed[i] {
+				report.Fields[i].Valid++
+			}
+		}
+	}
+	sort.SliceStable(report.Fields[extra:], func(i, j int) bool {
+		return report.Fields[extra+i].Path < report.Fields[extra+j].Path
+	})
+	return report, nil
+}
+
+// arrayIndex matches the item indexes of validation paths
+var arrayIndex = regexp.MustCompile(`\[\d+\]`)
+
+// fieldPath converts the path of a value, e.g. "items[2].price" or "labels.team", into
+// the path of its field, "items[].price" and "labels{}". A property the schema does not
+// declare, such as an unexpected one, is attributed to its object.
+func (s Schema) fieldPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	current := s
+	field := ""
+	for _, token := range strings.Split(path, ".") {
+		name := strings.TrimRight(arrayIndex.ReplaceAllString(token, ""), "[]")
+		indexes := len(arrayIndex.FindAllString(token, -1))
+		parent := s.descend(current)
+		props, _ := parent["properties"].(Schema)
+		if prop, ok := props[name].(Schema); ok {
+			field = joinPath(field, name)
+			current = prop
+		} else if values, ok := mapValues(parent); ok {
+			field += "{}"
+			current = values
+		} else {
+			return field
+		}
+		for ; indexes > 0; indexes-- {
+			items, ok := s.descend(current)["items"].(Schema)
+			if !ok {
+				return field
+			}
+			field += "[]"
+			current = items
+		}
+	}
+	return field
+}
+
+// mapValues returns the schema of the values of a map schema
+func mapValues(s Schema) (Schema, bool) {
+	if values, ok := s["additionalProperties"].(Schema); ok {
+		return values, true
+	}
+	patterns, _ := s["patternProperties"].(Schema)
+	for _, pattern := range sortedKeys(patterns) {
+		if values, ok := patterns[pattern].(Schema); ok {
+			return values, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScoreDocuments(t *testing.T) {
+	var documents []interface{}
+	for _, response := range []string{
+		`{"status":"open","priority":3,"assignee":null,"items":[{"price":1}],"labels":{"team":"web"}}`,
+		`{"status":"pending","priority":3,"assignee":"ana","items":[{"price":1},{"price":-1}]}`,
+		`{"status":"done","priority":9,"items":[]}`,
+		`{"status":"open","priority":1,"assignee":null,"items":[],"labels":{"team":1}}`,
+	} {
+		decoder := json.NewDecoder(strings.NewReader(response))
+		decoder.UseNumber()
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			t.Fatal(err)
+		}
+		documents = append(documents, document)
+	}
+	report, err := ScoreDocuments(assertionSchema, documents, &Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Responses != 4 || report.Valid != 1 {
+		t.Errorf("expected 1/4 valid responses, got %d/%d", report.Valid, report.Responses)
+	}
+	tests := []struct {
+		path       string
+		seen       int
+		valid      int
+		violations map[string]int
+	}{
+		{path: "status", seen: 4, valid: 2, violations: map[string]int{"enum": 2}},
+		{path: "priority", seen: 4, valid: 3, violations: map[string]int{"maximum": 1}},
+		{path: "assignee", seen: 4, valid: 3, violations: map[string]int{"required": 1}},
+		{path: "items[].price", seen: 2, valid: 1, violations: map[string]int{"exclusiveMinimum": 1}},
+		{path: "kind", seen: 0, valid: 0, violations: map[string]int{}},
+		{path: "labels{}", seen: 1, valid: 0, violations: map[string]int{"type": 1}},
+	}
+	for _, tt := range tests {
+		field, ok := report.Field(tt.path)
+		if !ok {
+			t.Errorf("%s: missing field score", tt.path)
+			continue
+		}
+		if field.Seen != tt.seen || field.Valid != tt.valid || !reflect.DeepEqual(field.Violations, tt.violations) {
+			t.Errorf("%s: expected %d/%d %v, got %d/%d %v", tt.path, tt.valid, tt.seen, tt.violations,
+				field.Valid, field.Seen, field.Violations)
+		}
+	}
+	if rate := report.Fields[0].Rate(); rate != 0.5 {
+		t.Errorf("expected a rate of 0.5 for status, got %v", rate)
+	}
+	if !strings.Contains(report.String(), "status: 2/4 valid (50%), enum 2\n") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
+
+func TestFieldPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "", expected: ""},
+		{path: "status", expected: "status"},
+		{path: "items[3].price", expected: "items[].price"},
+		{path: "labels.team", expected: "labels{}"},
+		{path: "unknown", expected: ""},
+		{path: "items[0].extra", expected: "items[]"},
+	}
+	for _, tt := range tests {
+		if got := assertionSchema.fieldPath(tt.path); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestCommonViolations(t *testing.T) {
+	field := FieldScore{Violations: map[string]int{"type": 1, "enum": 3, "const": 1}}
+	expected := []ViolationCount{{"enum", 3}, {"const", 1}, {"type", 1}}
+	if got := field.CommonViolations(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,234 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SelectPaths returns a copy of a schema keeping only the properties at paths and their
+// subschemas, e.g. "title", "lines[].qty" or "address". The objects leading to them are
+// kept with their keywords, and their required lists and dependentRequired only name the
+// kept properties, so required and nullable properties stay so. Definitions reached
+// through local $refs are pruned by every path reaching them, and definitions no kept
+// property reaches are removed. Paths matching no property are reported as an error.
+func SelectPaths(s Schema, paths []string) (Schema, error) {
+	for _, path := range paths {
+		if path == "" {
+			return nil, fmt.Errorf("empty path, select the properties of the root instead")
+		}
+		if _, ok := s.At(path); !ok {
+			return nil, fmt.Errorf("no property at %s", path)
+		}
+	}
+	sel := &selector{root: s, targets: make(map[string]map[string]bool)}
+	sel.record("#", toSet(paths))
+	// the paths reaching a definition grow until every reference is accounted for, they
+	// are suffixes of the selected paths so this ends
+	for sel.changed {
+		sel.changed = false
+		refs := make([]string, 0, len(sel.targets))
+		for ref := range sel.targets {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+		for _, ref := range refs {
+			if target, ok := sel.target(ref); ok {
+				sel.prune(target, sel.targets[ref])
+			}
+		}
+	}
+	result := sel.prune(s, sel.targets["#"])
+	for _, keyword := range []string{"$defs", "definitions"} {
+		defs, ok := s[keyword].(Schema)
+		if !ok {
+			continue
+		}
+		kept := make(Schema)
+		for name, def := range defs {
+			ref := "#/" + keyword + "/" + name
+			if def, ok := def.(Schema); ok && sel.targets[ref] != nil {
+				kept[name] = sel.prune(def, sel.targets[ref])
+			}
+		}
+		if len(kept) > 0 {
+			result[keyword] = kept
+		} else {
+			delete(result, keyword)
+		}
+	}
+	return result, nil
+}
+
+// selector prunes a schema and the definitions it references
+type selector struct {
+	root Schema
+	// targets holds the paths selected in the root, "#", and in each definition reached
+	// by a local $ref, relative to it; "" keeps the whole target
+	targets map[string]map[string]bool
+	changed bool
+}
+
+// record adds the paths selected through a reference to its target
+func (sel *selector) record(ref string, paths map[string]bool) {
+	if ref != "#" && !strings.HasPrefix(ref, "#/$defs/") && !strings.HasPrefix(ref, "#/definitions/") {
+		return
+	}
+	selected := sel.targets[ref]
+	if selected == nil {
+		selected = make(map[string]bool)
+		sel.targets[ref] = selected
+	}
+	for path := range paths {
+		if !selected[path] {
+			selected[path] = true
+			sel.changed = true
+		}
+	}
+}
+
+// target returns the schema a recorded reference points to
+func (sel *selector) target(ref string) (Schema, bool) {
+	if ref == "#" {
+		return sel.root, true
+	}
+	keyword, name, _ := strings.Cut(strings.TrimPrefix(ref, "#/"), "/")
+	defs, _ := sel.root[keyword].(Schema)
+	def, ok := defs[name].(Schema)
+	return def, ok
+}
+
+// prune returns a copy of s keeping the properties at paths, relative to s
+func (sel *selector) prune(s Schema, paths map[string]bool) Schema {
+	if paths[""] {
+		// the whole subschema is kept, and so are the definitions it references
+		transformSchema(s, "", func(sub Schema, _ string) Schema {
+			if ref, ok := sub["$ref"].(string); ok {
+				sel.record(ref, map[string]bool{"": true})
+			}
+			return sub
+		})
+		return s
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		sel.record(ref, paths)
+		return s
+	}
+	out := make(Schema, len(s))
+	for k, v := range s {
+		out[k] = v
+	}
+	for _, keyword := range []string{"anyOf", "oneOf", "allOf"} {
+		if variants, ok := s[keyword].([]Schema); ok {
+			pruned := make([]Schema, len(variants))
+			for i, variant := range variants {
+				pruned[i] = sel.prune(variant, paths)
+			}
+			out[keyword] = pruned
+		}
+	}
+	if props, ok := s["properties"].(Schema); ok {
+		kept := make(Schema, len(props))
+		for name, prop := range props {
+			below := pathsBelow(paths, name)
+			if len(below) == 0 {
+				continue
+			}
+			if sub, ok := prop.(Schema); ok {
+				kept[name] = sel.prune(sub, below)
+			} else {
+				kept[name] = prop
+			}
+		}
+		out["properties"] = kept
+		keepSelectedRequired(out, kept)
+	}
+	if items, ok := s["items"].(Schema); ok {
+		if below := pathsBelow(paths, "[]"); len(below) > 0 {
+			out["items"] = sel.prune(items, below)
+		}
+	}
+	if values, ok := s["additionalProperties"].(Schema); ok {
+		if below := pathsBelow(paths, "{}"); len(below) > 0 {
+			out["additionalProperties"] = sel.prune(values, below)
+		}
+	}
+	if patterns, ok := s["patternProperties"].(Schema); ok {
+		if below := pathsBelow(paths, "{}"); len(below) > 0 {
+			pruned := make(Schema, len(patterns))
+			for pattern, v := range patterns {
+				if sub, ok := v.(Schema); ok {
+					pruned[pattern] = sel.prune(sub, below)
+				} else {
+					pruned[pattern] = v
+				}
+			}
+			out["patternProperties"] = pruned
+		}
+	}
+	return out
+}
+
+// pathsBelow returns the paths under a property name, "[]" or "{}", relative to it
+func pathsBelow(paths map[string]bool, segment string) map[string]bool {
+	below := make(map[string]bool)
+	for path := range paths {
+		switch {
+		case path == segment:
+			below[""] = true
+		case strings.HasPrefix(path, segment+"."):
+			below[path[len(segment)+1:]] = true
+		case strings.HasPrefix(path, segment+"[]"), strings.HasPrefix(path, segment+"{}"):
+			below[path[len(segment):]] = true
+		}
+	}
+	return below
+}
+
+// keepSelectedRequired restricts required and dependentRequired to the kept properties
+func keepSelectedRequired(s, kept Schema) {
+	if required, ok := s["required"].([]string); ok {
+		var names []string
+		for _, name := range required {
+			if _, ok := kept[name]; ok {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			s["required"] = names
+		} else {
+			delete(s, "required")
+		}
+	}
+	if dependents, ok := s["dependentRequired"].(Schema); ok {
+		selected := make(Schema, len(dependents))
+		for name, v := range dependents {
+			names, _ := v.([]string)
+			if _, ok := kept[name]; !ok {
+				continue
+			}
+			var keptNames []string
+			for _, dependent := range names {
+				if _, ok := kept[dependent]; ok {
+					keptNames = append(keptNames, dependent)
+				}
+			}
+			if len(keptNames) > 0 {
+				selected[name] = keptNames
+			}
+		}
+		if len(selected) > 0 {
+			s["dependentRequired"] = selected
+		} else {
+			delete(s, "dependentRequired")
+		}
+	}
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
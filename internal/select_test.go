@@ -0,0 +1,245 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectPaths(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"number": Schema{"type": "string"},
+			"notes":  Schema{"type": []string{"string", "null"}},
+			"address": Schema{"anyOf": []Schema{
+				{
+					"type": "object",
+					"properties": Schema{
+						"city":   Schema{"type": "string"},
+						"street": Schema{"type": "string"},
+					},
+					"required":             []string{"city", "street"},
+					"additionalProperties": false,
+				},
+				{"type": "null"},
+			}},
+			"lines": Schema{"type": "array", "items": Schema{
+				"type": "object",
+				"properties": Schema{
+					"qty":   Schema{"type": "integer"},
+					"price": Schema{"type": "number"},
+				},
+				"required":          []string{"qty", "price"},
+				"dependentRequired": Schema{"qty": []string{"price"}},
+			}},
+		},
+		"required":             []string{"number", "notes", "address", "lines"},
+		"additionalProperties": false,
+	}
+	tests := []struct {
+		name     string
+		paths    []string
+		expected Schema
+	}{
+		{
+			name:  "top-level properties",
+			paths: []string{"notes", "number"},
+			expected: Schema{
+				"type": "object",
+				"properties": Schema{
+					"number": Schema{"type": "string"},
+					"notes":  Schema{"type": []string{"string", "null"}},
+				},
+				"required":             []string{"number", "notes"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name:  "nested property of a nullable object",
+			paths: []string{"address.city"},
+			expected: Schema{
+				"type": "object",
+				"properties": Schema{
+					"address": Schema{"anyOf": []Schema{
+						{
+							"type":                 "object",
+							"properties":           Schema{"city": Schema{"type": "string"}},
+							"required":             []string{"city"},
+							"additionalProperties": false,
+						},
+						{"type": "null"},
+					}},
+				},
+				"required":             []string{"address"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name:  "array items",
+			paths: []string{"lines[].qty"},
+			expected: Schema{
+				"type": "object",
+				"properties": Schema{
+					"lines": Schema{"type": "array", "items": Schema{
+						"type":       "object",
+						"properties": Schema{"qty": Schema{"type": "integer"}},
+						"required":   []string{"qty"},
+					}},
+				},
+				"required":             []string{"lines"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name:  "whole subtree",
+			paths: []string{"lines", "lines[].qty"},
+			expected: Schema{
+				"type":                 "object",
+				"properties":           Schema{"lines": schema["properties"].(Schema)["lines"]},
+				"required":             []string{"lines"},
+				"additionalProperties": false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SelectPaths(schema, tt.paths)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+	if len(schema["properties"].(Schema)) != 4 {
+		t.Errorf("expected the input schema to be left unchanged")
+	}
+}
+
+func TestSelectPaths_Invalid(t *testing.T) {
+	schema := Schema{
+		"type":       "object",
+		"properties": Schema{"name": Schema{"type": "string"}},
+	}
+	for _, path := range []string{"", "missing", "name.first", "name[]"} {
+		if _, err := SelectPaths(schema, []string{path}); err == nil {
+			t.Errorf("expected an error for path %q", path)
+		}
+	}
+}
+
+func TestSelectPaths_Definitions(t *testing.T) {
+	line := Schema{
+		"type": "object",
+		"properties": Schema{
+			"sku": Schema{"type": "string"},
+			"qty": Schema{"type": "integer"},
+		},
+		"required":             []string{"sku", "qty"},
+		"additionalProperties": false,
+	}
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"lines":   Schema{"type": "array", "items": Schema{"$ref": "#/$defs/Line"}},
+			"returns": Schema{"type": "array", "items": Schema{"$ref": "#/$defs/Line"}},
+			"note":    Schema{"type": "string"},
+		},
+		"required":             []string{"lines", "returns", "note"},
+		"additionalProperties": false,
+		"$defs":                Schema{"Line": line},
+	}
+	tests := []struct {
+		name     string
+		paths    []string
+		expected Schema
+	}{
+		{
+			name:  "definition pruned by the paths reaching it",
+			paths: []string{"lines[].qty"},
+			expected: Schema{
+				"type": "object",
+				"properties": Schema{
+					"lines": Schema{"type": "array", "items": Schema{"$ref": "#/$defs/Line"}},
+				},
+				"required":             []string{"lines"},
+				"additionalProperties": false,
+				"$defs": Schema{"Line": Schema{
+					"type":                 "object",
+					"properties":           Schema{"qty": Schema{"type": "integer"}},
+					"required":             []string{"qty"},
+					"additionalProperties": false,
+				}},
+			},
+		},
+		{
+			name:  "definition also reached by a whole selected property",
+			paths: []string{"lines[].qty", "returns"},
+			expected: Schema{
+				"type": "object",
+				"properties": Schema{
+					"lines":   Schema{"type": "array", "items": Schema{"$ref": "#/$defs/Line"}},
+					"returns": Schema{"type": "array", "items": Schema{"$ref": "#/$defs/Line"}},
+				},
+				"required":             []string{"lines", "returns"},
+				"additionalProperties": false,
+				"$defs":                Schema{"Line": line},
+			},
+		},
+		{
+			name:  "unreached definitions are removed",
+			paths: []string{"note"},
+			expected: Schema{
+				"type":                 "object",
+				"properties":           Schema{"note": Schema{"type": "string"}},
+				"required":             []string{"note"},
+				"additionalProperties": false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SelectPaths(schema, tt.paths)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+	if len(line["properties"].(Schema)) != 2 {
+		t.Errorf("expected the input definition to be left unchanged")
+	}
+}
+
+func TestSelectPaths_RecursiveRoot(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"value": Schema{"type": "string"},
+			"label": Schema{"type": "string"},
+			"next":  Schema{"anyOf": []Schema{{"$ref": "#"}, {"type": "null"}}},
+		},
+		"required":             []string{"value", "label", "next"},
+		"additionalProperties": false,
+	}
+	result, err := SelectPaths(schema, []string{"next.value"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the root is also the node reached through next, it keeps value for it
+	expected := Schema{
+		"type": "object",
+		"properties": Schema{
+			"value": Schema{"type": "string"},
+			"next":  Schema{"anyOf": []Schema{{"$ref": "#"}, {"type": "null"}}},
+		},
+		"required":             []string{"value", "next"},
+		"additionalProperties": false,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+}
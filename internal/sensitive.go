@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DefaultSensitiveDescription is appended to the description of fields tagged sensitive:"true"
+const DefaultSensitiveDescription = "Sensitive: do not copy raw personal data from the input, mask or summarize it instead."
+
+// Redactor rewrites a string of a sensitive field while decoding, path is the path of the
+// value in the response, e.g. "customer.email"
+type Redactor func(path, value string) string
+
+// parseSensitiveTag parses the sensitive tag, which only accepts true and false
+func parseSensitiveTag(fieldName string, field reflect.StructField) (bool, error) {
+	tag, ok := field.Tag.Lookup("sensitive")
+	if !ok {
+		return false, nil
+	}
+	switch tag {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: sensitive tag on field %q must be true or false, got %q", ErrInvalidTag, fieldName, tag)
+	}
+}
+
+// withSensitiveNote appends the handling instruction of sensitive fields to a property description
+func withSensitiveNote(prop interface{}, note string) interface{} {
+	s, ok := prop.(Schema)
+	if !ok || note == "" {
+		return prop
+	}
+	if description, ok := s["description"].(string); ok && description != "" {
+		note = description + " " + note
+	}
+	return withDescription(s, note)
+}
+
+// redactValue passes the strings of a sensitive value to the redactor, descending into
+// arrays and objects, and replaces its other scalars with null so they decode to zero values
+func redactValue(value interface{}, path string, redact Redactor) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return redact(path, v)
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item, fmt.Sprintf("%s[%d]", path, i), redact)
+		}
+		return v
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = redactValue(item, joinPath(path, key), redact)
+		}
+		return v
+	}
+	return nil
+}
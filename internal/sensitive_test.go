@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSensitiveTag(t *testing.T) {
+	t.Run("descriptions carry the instruction", func(t *testing.T) {
+		result, err := runJsonTypeOf(reflect.TypeOf(SupportTicket{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, SupportTicketSchema) {
+			t.Errorf("expected %+v, got %+v", SupportTicketSchema, result)
+		}
+	})
+
+	t.Run("custom instruction", func(t *testing.T) {
+		opts, visited, depth := getInputs()
+		opts.SensitiveDescription = "PII, write [PII] instead."
+		result, err := JsonTypeOf(reflect.TypeOf(SupportTicket{}), visited, depth, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		email := result.(Schema)["properties"].(Schema)["email"].(Schema)
+		if email["description"] != "Customer email PII, write [PII] instead." {
+			t.Errorf("unexpected description %q", email["description"])
+		}
+	})
+
+	t.Run("invalid tag value", func(t *testing.T) {
+		type BadSensitive struct {
+			Email string `json:"email" sensitive:"yes"`
+		}
+		_, err := runJsonTypeOf(reflect.TypeOf(BadSensitive{}))
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("expected ErrInvalidTag, got %v", err)
+		}
+	})
+}
+
+func TestCoerceValueRedaction(t *testing.T) {
+	value := func() map[string]interface{} {
+		return map[string]interface{}{
+			"summary":  "Refund request",
+			"email":    "ada@example.com",
+			"phones":   []interface{}{"+44 20 7946 0000"},
+			"customer": map[string]interface{}{"name": "Ada", "age": json.Number("36")},
+		}
+	}
+	var paths []string
+	opts := DefaultOptions()
+	opts.Redactor = func(path, value string) string {
+		paths = append(paths, path)
+		return strings.Repeat("*", len(value))
+	}
+	result := CoerceValue(reflect.TypeOf(SupportTicket{}), value(), opts)
+	expected := map[string]interface{}{
+		"summary":  "Refund request",
+		"email":    "***************",
+		"phones":   []interface{}{"****************"},
+		"customer": map[string]interface{}{"name": "***", "age": nil},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+	if len(paths) != 3 || !containsString(paths, "phones[0]") || !containsString(paths, "customer.name") {
+		t.Errorf("unexpected redacted paths %v", paths)
+	}
+
+	if result := CoerceValue(reflect.TypeOf(SupportTicket{}), value(), DefaultOptions()); !reflect.DeepEqual(result, value()) {
+		t.Errorf("expected values to be unchanged without a redactor, got %+v", result)
+	}
+}
@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseDescriptionsFile decodes a sidecar file mapping field paths to descriptions, in
+// JSON (.json) or YAML (.yaml, .yml). YAML files are limited to a flat mapping of
+// scalars: plain, quoted, or literal (|) and folded (>) blocks, with # comments.
+func ParseDescriptionsFile(name string, data []byte) (map[string]string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		var descriptions map[string]string
+		if err := json.Unmarshal(data, &descriptions); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", name, err)
+		}
+		return descriptions, nil
+	case ".yaml", ".yml":
+		descriptions, err := parseFlatYAML(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", name, err)
+		}
+		return descriptions, nil
+	}
+	return nil, fmt.Errorf("descriptions file %s must be .json, .yaml or .yml", name)
+}
+
+// parseFlatYAML parses a YAML mapping of keys to strings, the keys at the start of lines
+func parseFlatYAML(text string) (map[string]string, error) {
+	descriptions := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			return nil, fmt.Errorf("line %d: unexpected indentation, nested mappings are not supported, use dotted paths as keys", i+1)
+		}
+		key, rest, err := splitYAMLKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if _, ok := descriptions[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %q", i+1, key)
+		}
+		var value string
+		switch {
+		case strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+			var block []string
+			for i+1 < len(lines) && (strings.TrimSpace(lines[i+1]) == "" || lines[i+1][0] == ' ' || lines[i+1][0] == '\t') {
+				i++
+				block = append(block, strings.TrimSpace(lines[i]))
+			}
+			value = joinYAMLBlock(block, rest[0] == '>')
+		case rest == "":
+			if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && (lines[i+1][0] == ' ' || lines[i+1][0] == '\t') {
+				return nil, fmt.Errorf("line %d: key %q holds a nested mapping, use dotted paths as keys", i+1, key)
+			}
+		default:
+			value, err = parseYAMLScalar(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+		descriptions[key] = value
+	}
+	return descriptions, nil
+}
+
+// splitYAMLKey splits a line into its key and the text following the colon
+func splitYAMLKey(line string) (string, string, error) {
+	if line[0] == '"' || line[0] == '\'' {
+		end := closingQuote(line, line[0])
+		if end < 0 || !strings.HasPrefix(line[end+1:], ":") {
+			return "", "", fmt.Errorf("expected a quoted key followed by a colon")
+		}
+		key, err := parseYAMLScalar(line[:end+1])
+		return key, strings.TrimSpace(line[end+2:]), err
+	}
+	for i := 0; i < len(line); i++ {
+		if line[i] == ':' && (i+1 == len(line) || line[i+1] == ' ' || line[i+1] == '\t') {
+			return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:]), nil
+		}
+	}
+	return "", "", fmt.Errorf("expected a key followed by a colon")
+}
+
+// parseYAMLScalar parses a quoted or plain scalar, dropping trailing comments
+func parseYAMLScalar(text string) (string, error) {
+	switch text[0] {
+	case '"':
+		end := closingQuote(text, '"')
+		if end < 0 || !isYAMLComment(text[end+1:]) {
+			return "", fmt.Errorf("malformed double-quoted string %s", text)
+		}
+		value, err := strconv.Unquote(text[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("malformed double-quoted string %s", text)
+		}
+		return value, nil
+	case '\'':
+		end := closingQuote(text, '\'')
+		if end < 0 || !isYAMLComment(text[end+1:]) {
+			return "", fmt.Errorf("malformed single-quoted string %s", text)
+		}
+		return strings.ReplaceAll(text[1:end], "''", "'"), nil
+	}
+	if i := strings.Index(text, " #"); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// closingQuote returns the index of the quote closing the string opening text, escaped
+// with a backslash in double-quoted strings and doubled in single-quoted ones
+func closingQuote(text string, quote byte) int {
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote && quote == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// isYAMLComment reports whether the text following a scalar is blank or a comment
+func isYAMLComment(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "" || strings.HasPrefix(text, "#")
+}
+
+// joinYAMLBlock joins the lines of a block scalar, folded blocks join them with spaces
+// and keep blank lines as line breaks
+func joinYAMLBlock(lines []string, folded bool) string {
+	if !folded {
+		return strings.TrimSpace(strings.Join(lines, "\n"))
+	}
+	var b strings.Builder
+	for i, line := range lines {
+		switch {
+		case line == "":
+			b.WriteString("\n")
+		case i > 0 && lines[i-1] != "":
+			b.WriteString(" " + line)
+		default:
+			b.WriteString(line)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// MergeDescriptions sets the descriptions of a sidecar file on the fields of a schema.
+// Fields already described differently, e.g. by a description tag, take the description
+// of the file and are reported as warnings, as are the paths matching no field.
+func MergeDescriptions(schema Schema, descriptions map[string]string, opts *Options) Schema {
+	used := make(map[string]bool)
+	merged := replaceDescriptions(schema, "", true, descriptions, func(path string, previous interface{}) {
+		used[path] = true
+		if previous, ok := previous.(string); ok && previous != "" && previous != descriptions[path] {
+			opts.Warn(path, "description %q is replaced by the descriptions file: %q", previous, descriptions[path])
+		}
+	})
+	for _, path := range unusedPaths(descriptions, used) {
+		opts.Warn(path, "description from the descriptions file matches no field of the schema, it is ignored")
+	}
+	return merged
+}
+
+// unusedPaths returns the sorted paths of descriptions never applied to a field
+func unusedPaths(descriptions map[string]string, used map[string]bool) []string {
+	var unused []string
+	for path := range descriptions {
+		if !used[path] {
+			unused = append(unused, path)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDescriptionsFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		data     string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{
+			name: "json",
+			file: "descriptions.json",
+			data: `{"title": "Title", "items[].price": "Unit price"}`,
+			expected: map[string]string{
+				"title":         "Title",
+				"items[].price": "Unit price",
+			},
+		},
+		{
+			name: "yaml scalars",
+			file: "descriptions.yaml",
+			data: "# maintained by the prompt team\n---\ntitle: Title: short # a comment\n" +
+				"items[].price: \"Unit price, in \\\"cents\\\"\"\n" +
+				"labels{}: 'It''s a label'\n" +
+				"\"\": Article\n" +
+				"summary:\n",
+			expected: map[string]string{
+				"title":         "Title: short",
+				"items[].price": `Unit price, in "cents"`,
+				"labels{}":      "It's a label",
+				"":              "Article",
+				"summary":       "",
+			},
+		},
+		{
+			name: "yaml blocks",
+			file: "descriptions.yml",
+			data: "body: >\n  Body of the article,\n  in Markdown.\n\n  No HTML.\ntags: |-\n  One tag per topic\n  lowercase\nauthor: Author\n",
+			expected: map[string]string{
+				"body":   "Body of the article, in Markdown.\nNo HTML.",
+				"tags":   "One tag per topic\nlowercase",
+				"author": "Author",
+			},
+		},
+		{name: "nested mapping", file: "descriptions.yaml", data: "customer:\n  name: Name\n", wantErr: true},
+		{name: "duplicate key", file: "descriptions.yaml", data: "title: A\ntitle: B\n", wantErr: true},
+		{name: "missing colon", file: "descriptions.yaml", data: "title\n", wantErr: true},
+		{name: "unterminated quote", file: "descriptions.yaml", data: "title: \"Title\n", wantErr: true},
+		{name: "invalid json", file: "descriptions.json", data: `{"title": 1}`, wantErr: true},
+		{name: "unknown extension", file: "descriptions.toml", data: `title = "Title"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			descriptions, err := ParseDescriptionsFile(tt.file, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(descriptions, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, descriptions)
+			}
+		})
+	}
+}
+
+func TestMergeDescriptions(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"title": Schema{"type": "string", "description": "Title of the article"},
+			"body":  Schema{"type": "string", "description": "Body"},
+			"tags":  Schema{"type": "array", "items": Schema{"type": "string"}},
+		},
+	}
+	descriptions := map[string]string{
+		"title":  "Title, at most 10 words",
+		"body":   "Body",
+		"tags[]": "A topic",
+		"author": "Author",
+	}
+	var warnings []string
+	opts := DefaultOptions()
+	opts.WarningHandler = func(w Warning) { warnings = append(warnings, w.String()) }
+	merged := MergeDescriptions(schema, descriptions, opts)
+
+	for path, expected := range map[string]string{"title": "Title, at most 10 words", "body": "Body", "tags[]": "A topic"} {
+		field, _ := merged.At(path)
+		if field["description"] != expected {
+			t.Errorf("%s: expected %q, got %v", path, expected, field["description"])
+		}
+	}
+	expectedWarnings := []string{
+		`title: description "Title of the article" is replaced by the descriptions file: "Title, at most 10 words"`,
+		"author: description from the descriptions file matches no field of the schema, it is ignored",
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("expected warnings %q, got %q", expectedWarnings, warnings)
+	}
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxSizeContributors is the number of properties reported by SchemaSizeError
+const maxSizeContributors = 5
+
+// SizeContributor is a top-level property and the size of its marshaled schema
+type SizeContributor struct {
+	Path string
+	Size int
+}
+
+// SchemaSizeError reports a schema larger than the size budget. It wraps ErrSchemaTooLarge.
+type SchemaSizeError struct {
+	// Size of the marshaled schema in bytes
+	Size int
+	// Limit is the size budget in bytes
+	Limit int
+	// Contributors lists the largest top-level properties, largest first
+	Contributors []SizeContributor
+}
+
+func (e *SchemaSizeError) Error() string {
+	msg := fmt.Sprintf("%s: %d bytes, the limit is %d", ErrSchemaTooLarge, e.Size, e.Limit)
+	if len(e.Contributors) == 0 {
+		return msg
+	}
+	parts := make([]string, len(e.Contributors))
+	for i, c := range e.Contributors {
+		parts[i] = fmt.Sprintf("%s %d bytes", c.Path, c.Size)
+	}
+	return msg + " (largest properties: " + strings.Join(parts, ", ") + ")"
+}
+
+func (e *SchemaSizeError) Unwrap() error {
+	return ErrSchemaTooLarge
+}
+
+// CheckSchemaSize returns a *SchemaSizeError when the marshaled schema exceeds limit bytes
+func CheckSchemaSize(schema Schema, limit int) error {
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema to JSON: %w", err)
+	}
+	if len(encoded) <= limit {
+		return nil
+	}
+	sizeErr := &SchemaSizeError{Size: len(encoded), Limit: limit}
+	props, _ := schema["properties"].(Schema)
+	for _, name := range sortedKeys(props) {
+		encoded, err := json.Marshal(props[name])
+		if err != nil {
+			return fmt.Errorf("failed to marshal schema to JSON: %w", err)
+		}
+		sizeErr.Contributors = append(sizeErr.Contributors, SizeContributor{Path: name, Size: len(encoded)})
+	}
+	// sorting is stable so properties of the same size stay in name order
+	sort.SliceStable(sizeErr.Contributors, func(i, j int) bool {
+		return sizeErr.Contributors[i].Size > sizeErr.Contributors[j].Size
+	})
+	if len(sizeErr.Contributors) > maxSizeContributors {
+		sizeErr.Contributors = sizeErr.Contributors[:maxSizeContributors]
+	}
+	return sizeErr
+}
@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckSchemaSize(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"a": Schema{"type": "string"},
+			"b": Schema{"type": "array", "items": Schema{"type": "string"}},
+			"c": Schema{"type": "string"},
+			"d": Schema{"type": "integer"},
+			"e": Schema{"type": "boolean"},
+			"f": Schema{"type": "number"},
+		},
+	}
+	if err := CheckSchemaSize(schema, 1000); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := CheckSchemaSize(schema, 50)
+	var sizeErr *SchemaSizeError
+	if !errors.As(err, &sizeErr) || !errors.Is(err, ErrSchemaTooLarge) {
+		t.Fatalf("expected *SchemaSizeError, got %v", err)
+	}
+	expected := "schema exceeds the size budget: 191 bytes, the limit is 50 " +
+		"(largest properties: b 42 bytes, d 18 bytes, e 18 bytes, a 17 bytes, c 17 bytes)"
+	if err.Error() != expected {
+		t.Errorf("expected %s, got %s", expected, err)
+	}
+}
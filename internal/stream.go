@@ -0,0 +1,366 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrIncompleteJSON is returned when a stream ends before its JSON value is complete
+var ErrIncompleteJSON = errors.New("incomplete JSON value")
+
+// streamState is what a StreamParser expects next
+type streamState int
+
+const (
+	expectValue streamState = iota
+	// after "{", a key or the end of the object
+	expectKeyOrEnd
+	// after "," in an object
+	expectKey
+	expectColon
+	// after a value inside a container
+	expectCommaOrEnd
+	inString
+	inKey
+	// numbers and the true, false and null literals, complete at the next delimiter
+	inLiteral
+	// the root value is complete, only whitespace may follow
+	expectEOF
+)
+
+// streamFrame is an object or array being parsed
+type streamFrame struct {
+	array bool
+	path  string
+	// key of the current property of an object, index of the current item of an array
+	key   string
+	index int
+	// start is the offset of the opening bracket
+	start int
+}
+
+// StreamParser parses a JSON document written in chunks, e.g. a streamed model response,
+// and reports every value as soon as it is complete with its path in the syntax of
+// ValidationError ("lines[1].qty"). Values completing inside a value are reported first,
+// the root value last.
+type StreamParser struct {
+	complete func(path string, raw []byte)
+	buf      []byte
+	// pos is the offset of the next byte to parse
+	pos     int
+	state   streamState
+	stack   []streamFrame
+	escaped bool
+	// start is the offset of the string, key or literal being parsed
+	start int
+	err   error
+}
+
+// NewStreamParser creates a parser calling complete with the path and raw JSON of every
+// complete value, complete must not retain raw
+func NewStreamParser(complete func(path string, raw []byte)) *StreamParser {
+	return &StreamParser{complete: complete}
+}
+
+// Write parses the next chunk of the document, syntax errors are returned by this and
+// every later call
+func (p *StreamParser) Write(chunk []byte) (int, error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+	p.buf = append(p.buf, chunk...)
+	for ; p.pos < len(p.buf); p.pos++ {
+		if err := p.step(p.buf[p.pos]); err != nil {
+			p.err = err
+			return len(chunk), err
+		}
+	}
+	return len(chunk), nil
+}
+
+// Close ends the document, a root literal such as a number is only complete here
+func (p *StreamParser) Close() error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.state == inLiteral && len(p.stack) == 0 {
+		if err := p.endLiteral(); err != nil {
+			p.err = err
+			return err
+		}
+	}
+	if p.state != expectEOF {
+		p.err = fmt.Errorf("%w after %d bytes", ErrIncompleteJSON, len(p.buf))
+	}
+	return p.err
+}
+
+// Bytes returns the document written so far
+func (p *StreamParser) Bytes() []byte {
+	return p.buf
+}
+
+// path returns the path of the value starting at the current position
+func (p *StreamParser) path() string {
+	if len(p.stack) == 0 {
+		return ""
+	}
+	top := &p.stack[len(p.stack)-1]
+	if top.array {
+		return top.path + "[" + strconv.Itoa(top.index) + "]"
+	}
+	return joinPath(top.path, top.key)
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func (p *StreamParser) syntaxError(c byte) error {
+	return fmt.Errorf("invalid character %q at offset %d", c, p.pos)
+}
+
+func (p *StreamParser) step(c byte) error {
+	switch p.state {
+	case inString, inKey:
+		switch {
+		case p.escaped:
+			p.escaped = false
+		case c == '\\':
+			p.escaped = true
+		case c == '"':
+			return p.endString()
+		case c < 0x20:
+			return p.syntaxError(c)
+		}
+		return nil
+	case inLiteral:
+		if isJSONSpace(c) || c == ',' || c == '}' || c == ']' {
+			if err := p.endLiteral(); err != nil {
+				return err
+			}
+			return p.step(c)
+		}
+		return nil
+	}
+	if isJSONSpace(c) {
+		return nil
+	}
+	switch p.state {
+	case expectValue:
+		return p.beginValue(c)
+	case expectKeyOrEnd, expectKey:
+		if c == '}' && p.state == expectKeyOrEnd {
+			return p.endContainer()
+		}
+		if c != '"' {
+			return p.syntaxError(c)
+		}
+		p.state, p.start = inKey, p.pos
+	case expectColon:
+		if c != ':' {
+			return p.syntaxError(c)
+		}
+		p.state = expectValue
+	case expectCommaOrEnd:
+		top := &p.stack[len(p.stack)-1]
+		switch {
+		case c == ',' && top.array:
+			top.index++
+			p.state = expectValue
+		case c == ',':
+			p.state = expectKey
+		case c == ']' && top.array, c == '}' && !top.array:
+			return p.endContainer()
+		default:
+			return p.syntaxError(c)
+		}
+	default:
+		return p.syntaxError(c)
+	}
+	return nil
+}
+
+func (p *StreamParser) beginValue(c byte) error {
+	switch {
+	case c == '{' || c == '[':
+		p.stack = append(p.stack, streamFrame{array: c == '[', path: p.path(), start: p.pos})
+		p.state = expectKeyOrEnd
+		if c == '[' {
+			p.state = expectValue
+		}
+	case c == ']' && p.emptyArray():
+		return p.endContainer()
+	case c == '"':
+		p.state, p.start = inString, p.pos
+	case c == '-' || (c >= '0' && c <= '9') || c == 't' || c == 'f' || c == 'n':
+		p.state, p.start = inLiteral, p.pos
+	default:
+		return p.syntaxError(c)
+	}
+	return nil
+}
+
+// emptyArray reports whether only whitespace follows the opening bracket of the current array
+func (p *StreamParser) emptyArray() bool {
+	if len(p.stack) == 0 || !p.stack[len(p.stack)-1].array {
+		return false
+	}
+	top := p.stack[len(p.stack)-1]
+	return top.index == 0 && strings.TrimLeft(string(p.buf[top.start+1:p.pos]), " \t\n\r") == ""
+}
+
+func (p *StreamParser) endString() error {
+	raw := p.buf[p.start : p.pos+1]
+	if p.state == inKey {
+		var key string
+		if err := json.Unmarshal(raw, &key); err != nil {
+			return fmt.Errorf("invalid key at offset %d: %w", p.start, err)
+		}
+		p.stack[len(p.stack)-1].key = key
+		p.state = expectColon
+		return nil
+	}
+	if !json.Valid(raw) {
+		return fmt.Errorf("invalid string at offset %d", p.start)
+	}
+	return p.endValue(raw)
+}
+
+func (p *StreamParser) endLiteral() error {
+	raw := p.buf[p.start:p.pos]
+	if !json.Valid(raw) {
+		return fmt.Errorf("invalid literal %q at offset %d", raw, p.start)
+	}
+	return p.endValue(raw)
+}
+
+func (p *StreamParser) endContainer() error {
+	top := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	return p.endValue(p.buf[top.start : p.pos+1])
+}
+
+// location returns the keys and indexes leading to the value being completed, the
+// containers enclosing it are still open
+func (p *StreamParser) location() []interface{} {
+	location := make([]interface{}, len(p.stack))
+	for i, frame := range p.stack {
+		if frame.array {
+			location[i] = frame.index
+		} else {
+			location[i] = frame.key
+		}
+	}
+	return location
+}
+
+// endValue reports a complete value and moves on to what follows it
+func (p *StreamParser) endValue(raw []byte) error {
+	p.complete(p.path(), raw)
+	if len(p.stack) == 0 {
+		p.state = expectEOF
+	} else {
+		p.state = expectCommaOrEnd
+	}
+	return nil
+}
+
+// MatchStreamPath reports whether the path of a value matches a subscribed path, "[]" in
+// the pattern matching any index, e.g. "lines[].qty" matches "lines[1].qty"
+func MatchStreamPath(pattern, path string) bool {
+	for pattern != "" && path != "" {
+		if strings.HasPrefix(pattern, "[]") && path[0] == '[' {
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return false
+			}
+			pattern, path = pattern[2:], path[end+1:]
+			continue
+		}
+		if pattern[0] != path[0] {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return pattern == path
+}
+
+// PartialDocument rebuilds the values of a streamed document as they complete, e.g. to
+// render a response progressively. Values still streaming are left out, the objects and
+// arrays enclosing completed values are present with what they hold so far.
+type PartialDocument struct {
+	*StreamParser
+	root     interface{}
+	complete map[string]bool
+}
+
+// NewPartialDocument creates an empty document, complete is called for every completed
+// value like with a StreamParser and may be nil
+func NewPartialDocument(complete func(path string, raw []byte)) *PartialDocument {
+	d := &PartialDocument{complete: make(map[string]bool)}
+	d.StreamParser = NewStreamParser(func(path string, raw []byte) {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		var value interface{}
+		// raw was checked by the parser
+		_ = decoder.Decode(&value)
+		d.root = setAt(d.root, d.location(), value)
+		d.complete[path] = true
+		if complete != nil {
+			complete(path, raw)
+		}
+	})
+	return d
+}
+
+// Value returns the document completed so far, decoded with UseNumber, nil before the
+// first value completes
+func (d *PartialDocument) Value() interface{} {
+	return d.root
+}
+
+// Complete reports whether the value at path is complete, "[]" matching any index like
+// in MatchStreamPath, e.g. "items[]" once one item is complete
+func (d *PartialDocument) Complete(path string) bool {
+	if d.complete[path] {
+		return true
+	}
+	if !strings.Contains(path, "[]") {
+		return false
+	}
+	for completed := range d.complete {
+		if MatchStreamPath(path, completed) {
+			return true
+		}
+	}
+	return false
+}
+
+// setAt stores value at location in node, creating the enclosing objects and arrays
+func setAt(node interface{}, location []interface{}, value interface{}) interface{} {
+	if len(location) == 0 {
+		return value
+	}
+	switch segment := location[0].(type) {
+	case string:
+		object, _ := node.(map[string]interface{})
+		if object == nil {
+			object = make(map[string]interface{})
+		}
+		object[segment] = setAt(object[segment], location[1:], value)
+		return object
+	case int:
+		array, _ := node.([]interface{})
+		for len(array) <= segment {
+			array = append(array, nil)
+		}
+		array[segment] = setAt(array[segment], location[1:], value)
+		return array
+	}
+	return node
+}
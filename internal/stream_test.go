@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStreamParser(t *testing.T) {
+	document := `{"title": "Tides", "lines": [{"qty": 2, "tags": []}, {"qty": -1.5e2, "tags": ["a\"b"]}], "meta": {}, "ok": true, "note": null}`
+	expected := []string{
+		`title="Tides"`,
+		`lines[0].qty=2`,
+		`lines[0].tags=[]`,
+		`lines[0]={"qty": 2, "tags": []}`,
+		`lines[1].qty=-1.5e2`,
+		`lines[1].tags[0]="a\"b"`,
+		`lines[1].tags=["a\"b"]`,
+		`lines[1]={"qty": -1.5e2, "tags": ["a\"b"]}`,
+		`lines=[{"qty": 2, "tags": []}, {"qty": -1.5e2, "tags": ["a\"b"]}]`,
+		`meta={}`,
+		`ok=true`,
+		`note=null`,
+		`=` + document,
+	}
+	for _, chunkSize := range []int{1, 7, len(document)} {
+		var completed []string
+		p := NewStreamParser(func(path string, raw []byte) {
+			completed = append(completed, path+"="+string(raw))
+		})
+		for i := 0; i < len(document); i += chunkSize {
+			if _, err := p.Write([]byte(document[i:min(i+chunkSize, len(document))])); err != nil {
+				t.Fatalf("chunks of %d: unexpected error: %v", chunkSize, err)
+			}
+		}
+		if err := p.Close(); err != nil {
+			t.Fatalf("chunks of %d: unexpected error: %v", chunkSize, err)
+		}
+		if !reflect.DeepEqual(completed, expected) {
+			t.Errorf("chunks of %d: expected %q, got %q", chunkSize, expected, completed)
+		}
+	}
+}
+
+func TestStreamParserRootLiteral(t *testing.T) {
+	var completed []string
+	p := NewStreamParser(func(path string, raw []byte) { completed = append(completed, string(raw)) })
+	_, _ = p.Write([]byte(" 42"))
+	if len(completed) != 0 {
+		t.Errorf("expected the number to be incomplete before Close, got %q", completed)
+	}
+	if err := p.Close(); err != nil || !reflect.DeepEqual(completed, []string{"42"}) {
+		t.Errorf("expected 42 at Close, got %q (%v)", completed, err)
+	}
+}
+
+func TestStreamParserErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		document   string
+		incomplete bool
+	}{
+		{name: "trailing comma", document: `[1,]`},
+		{name: "missing colon", document: `{"a" 1}`},
+		{name: "mismatched bracket", document: `{"a": [1}`},
+		{name: "invalid literal", document: `{"a": tru}`},
+		{name: "second root value", document: `{} {}`},
+		{name: "unterminated object", document: `{"a": 1`, incomplete: true},
+		{name: "empty document", document: ``, incomplete: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewStreamParser(func(string, []byte) {})
+			_, err := p.Write([]byte(tt.document))
+			if err == nil {
+				err = p.Close()
+			}
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if errors.Is(err, ErrIncompleteJSON) != tt.incomplete {
+				t.Errorf("unexpected error %v", err)
+			}
+			if _, again := p.Write([]byte("{}")); again == nil {
+				t.Errorf("expected the error to be returned again")
+			}
+		})
+	}
+}
+
+func TestMatchStreamPath(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		expected      bool
+	}{
+		{"title", "title", true},
+		{"title", "titles", false},
+		{"lines[].qty", "lines[12].qty", true},
+		{"lines[1].qty", "lines[1].qty", true},
+		{"lines[1].qty", "lines[0].qty", false},
+		{"lines[]", "lines[3]", true},
+		{"lines[]", "lines", false},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		if got := MatchStreamPath(tt.pattern, tt.path); got != tt.expected {
+			t.Errorf("MatchStreamPath(%q, %q) = %v, expected %v", tt.pattern, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestPartialDocument(t *testing.T) {
+	d := NewPartialDocument(nil)
+	steps := []struct {
+		chunk    string
+		expected interface{}
+	}{
+		{`{"title": "Ti`, nil},
+		{`des", "sections": [{"heading": "Why", "body": "Bec`, map[string]interface{}{
+			"title":    "Tides",
+			"sections": []interface{}{map[string]interface{}{"heading": "Why"}},
+		}},
+		{`ause"}, {"heading": "How"`, map[string]interface{}{
+			"title": "Tides",
+			"sections": []interface{}{
+				map[string]interface{}{"heading": "Why", "body": "Because"},
+				map[string]interface{}{"heading": "How"},
+			},
+		}},
+		{`}], "words": 12}`, map[string]interface{}{
+			"title": "Tides",
+			"sections": []interface{}{
+				map[string]interface{}{"heading": "Why", "body": "Because"},
+				map[string]interface{}{"heading": "How"},
+			},
+			"words": json.Number("12"),
+		}},
+	}
+	for i, step := range steps {
+		if _, err := d.Write([]byte(step.chunk)); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(d.Value(), step.expected) {
+			t.Errorf("step %d: expected %v, got %v", i, step.expected, d.Value())
+		}
+	}
+	for path, expected := range map[string]bool{"title": true, "sections[1].heading": true, "sections[]": true, "sections[].body": true, "missing": false, "": true} {
+		if got := d.Complete(path); got != expected {
+			t.Errorf("Complete(%q) = %v, expected %v", path, got, expected)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AddSubtree scopes options to the property at path and everything below it, e.g.
+// "vendor" or "items[].extra". The configuration errors of the scoped options are
+// recorded on o.
+func (o *Options) AddSubtree(path string, apply ...func(*Options)) {
+	if path == "" {
+		o.AddError("subtree path must not be empty")
+		return
+	}
+	// the options are applied during conversion, check them up front
+	scratch := DefaultOptions()
+	for _, fn := range apply {
+		fn(scratch)
+	}
+	for _, err := range scratch.errs {
+		o.errs = append(o.errs, fmt.Errorf("subtree %q: %w", path, err))
+	}
+	if o.Subtrees == nil {
+		o.Subtrees = make(map[string][]func(*Options))
+	}
+	o.Subtrees[path] = append(o.Subtrees[path], apply...)
+}
+
+// scope returns the options of the property at path: a copy of o with the options scoped
+// to the path applied, or o itself when there are none
+func (o *Options) scope(path string) *Options {
+	apply, ok := o.Subtrees[path]
+	if !ok {
+		return o
+	}
+	scoped := *o
+	// the scoped options may add subtrees of their own, the map of o stays untouched
+	scoped.Subtrees = make(map[string][]func(*Options), len(o.Subtrees))
+	for p, fns := range o.Subtrees {
+		if p != path {
+			scoped.Subtrees[p] = fns
+		}
+	}
+	scoped.errs = nil
+	for _, fn := range apply {
+		fn(&scoped)
+	}
+	return &scoped
+}
+
+// descend records the property converted next, "[]" and "{}" standing for array items and
+// map values, and returns the path of the parent to restore afterwards
+func (o *Options) descend(segment string) string {
+	parent := o.path
+	switch {
+	case strings.HasPrefix(segment, "["), strings.HasPrefix(segment, "{"):
+		o.path += segment
+	default:
+		o.path = joinPath(o.path, segment)
+	}
+	return parent
+}
+
+// subtreePaths lists the paths with scoped options, recorded in provenance comments
+func subtreePaths(o *Options) string {
+	paths := make([]string, 0, len(o.Subtrees))
+	for path := range o.Subtrees {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
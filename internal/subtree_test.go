@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type subtreeOrder struct {
+	Home   Address            `json:"home"`
+	Work   *Address           `json:"work,omitempty"`
+	Extras []Address          `json:"extras"`
+	Labels map[string]Address `json:"labels"`
+}
+
+func TestSubtreeOptions(t *testing.T) {
+	allow := func(o *Options) { o.AllowAdditionalProperty = true }
+	anyOf := func(o *Options) { o.NullableStyle = NullableAnyOf }
+	tests := []struct {
+		name     string
+		subtrees map[string][]func(*Options)
+		// additionalProperties of home, work, extras[] and labels{}
+		expected []bool
+		zipCode  Schema
+	}{
+		{name: "no subtrees", expected: []bool{false, false, false, false}, zipCode: Schema{"type": []string{"string", "null"}}},
+		{
+			name:     "property",
+			subtrees: map[string][]func(*Options){"work": {allow, anyOf}},
+			expected: []bool{false, true, false, false},
+			zipCode:  Schema{"anyOf": []Schema{{"type": "string"}, {"type": "null"}}},
+		},
+		{
+			name:     "array items and map values",
+			subtrees: map[string][]func(*Options){"extras[]": {allow}, "labels{}": {allow}},
+			expected: []bool{false, false, true, true},
+			zipCode:  Schema{"type": []string{"string", "null"}},
+		},
+		{
+			name:     "nested property",
+			subtrees: map[string][]func(*Options){"work": {allow}, "work.zip_code": {anyOf}},
+			expected: []bool{false, true, false, false},
+			zipCode:  Schema{"anyOf": []Schema{{"type": "string"}, {"type": "null"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.Strict = false
+			for path, apply := range tt.subtrees {
+				opts.AddSubtree(path, apply...)
+			}
+			result, err := JsonTypeOf(reflect.TypeOf(subtreeOrder{}), visited, depth, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			schema := result.(Schema)
+			for i, path := range []string{"home", "work", "extras[]", "labels{}"} {
+				object, ok := schema.At(path)
+				if !ok {
+					t.Fatalf("no schema at %s", path)
+				}
+				object, _ = unwrapNullable(object)
+				if object["additionalProperties"] != tt.expected[i] {
+					t.Errorf("expected additionalProperties %v at %s, got %v", tt.expected[i], path, object["additionalProperties"])
+				}
+			}
+			if zipCode, _ := schema.At("work.zip_code"); !reflect.DeepEqual(zipCode, tt.zipCode) {
+				t.Errorf("expected zip_code %+v, got %+v", tt.zipCode, zipCode)
+			}
+			if zipCode, _ := schema.At("home.zip_code"); !reflect.DeepEqual(zipCode, Schema{"type": []string{"string", "null"}}) {
+				t.Errorf("expected the options of home to stay unchanged, got %+v", zipCode)
+			}
+			if opts.path != "" {
+				t.Errorf("expected the path to be restored, got %q", opts.path)
+			}
+		})
+	}
+}
+
+func TestAddSubtreeErrors(t *testing.T) {
+	opts := DefaultOptions()
+	opts.AddSubtree("")
+	opts.AddSubtree("vendor", func(o *Options) { o.AddError("unknown nullable style %q", "short") })
+	err := opts.Validate()
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("expected ErrInvalidOption, got %v", err)
+	}
+	expected := "invalid option: subtree path must not be empty\n" +
+		`subtree "vendor": invalid option: unknown nullable style "short"`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultArraySeparator joins the values of array columns in flattened tables
+const DefaultArraySeparator = "; "
+
+// Table is a batch of responses flattened into rows, one column per leaf of the schema
+type Table struct {
+	// Columns are the leaf paths of the schema in property order, e.g. "lines[].sku"
+	Columns []string
+	Rows    [][]string
+}
+
+// WriteCSV writes the table as comma-separated values, the columns as header
+func (t *Table) WriteCSV(w io.Writer) error {
+	return t.write(w, ',')
+}
+
+// WriteTSV writes the table as tab-separated values, the columns as header
+func (t *Table) WriteTSV(w io.Writer) error {
+	return t.write(w, '\t')
+}
+
+func (t *Table) write(w io.Writer, comma rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	if err := writer.Write(t.Columns); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// FlattenDocuments flattens responses decoded with UseNumber into a table. The values of
+// arrays and maps are joined with the array separator, except the items of the array at
+// ExplodeArray, which get one row each, the other columns repeated on every row.
+func FlattenDocuments(schema Schema, documents []interface{}, opts *Options) (*Table, error) {
+	table := &Table{}
+	for _, leaf := range schema.Paths() {
+		table.Columns = append(table.Columns, leaf.Path)
+	}
+	explode := opts.ExplodeArray
+	if explode != "" {
+		array, ok := schema.At(explode)
+		if ok {
+			array, _ = unwrapNullable(schema.descend(array))
+		}
+		if !ok || array["type"] != "array" {
+			return nil, fmt.Errorf("%w: exploded path %q is not an array of the schema", ErrInvalidOption, explode)
+		}
+	}
+	separator := opts.ArraySeparator
+	for _, document := range documents {
+		if explode == "" {
+			table.Rows = append(table.Rows, flattenRow(document, nil, table.Columns, "", separator))
+			continue
+		}
+		var items []interface{}
+		for _, array := range valuesAt(document, explode) {
+			elements, _ := array.value.([]interface{})
+			items = append(items, elements...)
+		}
+		if len(items) == 0 {
+			// responses without items keep a row for their other columns
+			items = []interface{}{nil}
+		}
+		for _, item := range items {
+			table.Rows = append(table.Rows, flattenRow(document, item, table.Columns, explode+"[]", separator))
+		}
+	}
+	return table, nil
+}
+
+// flattenRow returns the cells of a row, the columns under the exploded prefix are read from item
+func flattenRow(document, item interface{}, columns []string, prefix, separator string) []string {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		var values []locatedValue
+		if rest, ok := strings.CutPrefix(column, prefix); ok && prefix != "" {
+			if item != nil {
+				values = valuesAt(item, strings.TrimPrefix(rest, "."))
+			}
+		} else {
+			values = valuesAt(document, column)
+		}
+		cells := make([]string, 0, len(values))
+		for _, located := range values {
+			if located.value != nil {
+				cells = append(cells, cellValue(located.value))
+			}
+		}
+		row[i] = strings.Join(cells, separator)
+	}
+	return row
+}
+
+// cellValue renders a scalar as text, other values as JSON
+func cellValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	}
+	return jsonLiteral(value)
+}
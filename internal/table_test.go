@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var tableSchema = Schema{
+	"type": "object",
+	"properties": Schema{
+		"id":   Schema{"type": "string"},
+		"paid": Schema{"type": "boolean"},
+		"note": Schema{"type": []string{"string", "null"}},
+		"tags": Schema{"type": "array", "items": Schema{"type": "string"}},
+		"lines": Schema{
+			"type": "array",
+			"items": Schema{
+				"type": "object",
+				"properties": Schema{
+					"sku": Schema{"type": "string"},
+					"qty": Schema{"type": "integer"},
+				},
+				"required": []string{"sku", "qty"},
+			},
+		},
+	},
+	"required": []string{"id", "paid", "note", "tags", "lines"},
+}
+
+func decodeDocuments(t *testing.T, responses ...string) []interface{} {
+	t.Helper()
+	documents := make([]interface{}, len(responses))
+	for i, response := range responses {
+		decoder := json.NewDecoder(strings.NewReader(response))
+		decoder.UseNumber()
+		if err := decoder.Decode(&documents[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return documents
+}
+
+func TestFlattenDocuments(t *testing.T) {
+	documents := decodeDocuments(t,
+		`{"id":"INV-1","paid":true,"note":null,"tags":["a","b"],"lines":[{"sku":"A-1","qty":2},{"sku":"B-7","qty":1}]}`,
+		`{"id":"INV-2","paid":false,"note":"late","tags":[],"lines":[]}`,
+	)
+	columns := []string{"id", "paid", "note", "tags[]", "lines[].sku", "lines[].qty"}
+	tests := []struct {
+		name     string
+		explode  string
+		expected [][]string
+	}{
+		{
+			name: "arrays joined",
+			expected: [][]string{
+				{"INV-1", "true", "", "a; b", "A-1; B-7", "2; 1"},
+				{"INV-2", "false", "late", "", "", ""},
+			},
+		},
+		{
+			name:    "array exploded",
+			explode: "lines",
+			expected: [][]string{
+				{"INV-1", "true", "", "a; b", "A-1", "2"},
+				{"INV-1", "true", "", "a; b", "B-7", "1"},
+				{"INV-2", "false", "late", "", "", ""},
+			},
+		},
+		{
+			name:    "scalar array exploded",
+			explode: "tags",
+			expected: [][]string{
+				{"INV-1", "true", "", "a", "A-1; B-7", "2; 1"},
+				{"INV-1", "true", "", "b", "A-1; B-7", "2; 1"},
+				{"INV-2", "false", "late", "", "", ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.ExplodeArray = tt.explode
+			table, err := FlattenDocuments(tableSchema, documents, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(table.Columns, columns) {
+				t.Errorf("expected columns %v, got %v", columns, table.Columns)
+			}
+			if !reflect.DeepEqual(table.Rows, tt.expected) {
+				t.Errorf("expected rows %q, got %q", tt.expected, table.Rows)
+			}
+		})
+	}
+
+	opts := DefaultOptions()
+	opts.ExplodeArray = "id"
+	if _, err := FlattenDocuments(tableSchema, documents, opts); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestTableWrite(t *testing.T) {
+	table := &Table{Columns: []string{"id", "note"}, Rows: [][]string{{"1", "a, \"b\""}, {"2", ""}}}
+	var csvOut, tsvOut bytes.Buffer
+	if err := table.WriteCSV(&csvOut); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.WriteTSV(&tsvOut); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "id,note\n1,\"a, \"\"b\"\"\"\n2,\n"; csvOut.String() != expected {
+		t.Errorf("expected %q, got %q", expected, csvOut.String())
+	}
+	if expected := "id\tnote\n1\t\"a, \"\"b\"\"\"\n2\t\n"; tsvOut.String() != expected {
+		t.Errorf("expected %q, got %q", expected, tsvOut.String())
+	}
+}
@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// TimeFormat describes how time.Time values are serialized by the application
+type TimeFormat string
+
+const (
+	// TimeRFC3339 is the encoding/json representation of time.Time
+	TimeRFC3339 TimeFormat = "rfc3339"
+	// TimeDate is a date-only string such as 2006-01-02
+	TimeDate TimeFormat = "date"
+	// TimeUnixSeconds is an integer number of seconds since the Unix epoch
+	TimeUnixSeconds TimeFormat = "unix"
+	// TimeUnixMillis is an integer number of milliseconds since the Unix epoch
+	TimeUnixMillis TimeFormat = "unix_millis"
+)
+
+// dateLayout is the layout of TimeDate values
+const dateLayout = "2006-01-02"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Valid reports whether f is one of the supported time formats
+func (f TimeFormat) Valid() bool {
+	switch f {
+	case TimeRFC3339, TimeDate, TimeUnixSeconds, TimeUnixMillis:
+		return true
+	}
+	return false
+}
+
+// timeSchema describes time.Time according to the configured format,
+// epoch values state their unit in the description since integers carry no format
+func timeSchema(format TimeFormat) Schema {
+	switch format {
+	case TimeDate:
+		return Schema{"type": "string", "format": "date"}
+	case TimeUnixSeconds:
+		return Schema{"type": "integer", "description": "Unix timestamp in seconds"}
+	case TimeUnixMillis:
+		return Schema{"type": "integer", "description": "Unix timestamp in milliseconds"}
+	default:
+		return Schema{"type": "string", "format": "date-time"}
+	}
+}
+
+// coerceTime rewrites a value described by timeSchema into the RFC 3339 string
+// time.Time unmarshals from, values that don't match the format are left unchanged
+func coerceTime(value interface{}, format TimeFormat) interface{} {
+	switch v := value.(type) {
+	case string:
+		if format != TimeDate {
+			return value
+		}
+		if parsed, err := time.Parse(dateLayout, v); err == nil {
+			return parsed.Format(time.RFC3339)
+		}
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return value
+		}
+		switch format {
+		case TimeUnixSeconds:
+			return time.Unix(n, 0).UTC().Format(time.RFC3339)
+		case TimeUnixMillis:
+			return time.UnixMilli(n).UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return value
+}
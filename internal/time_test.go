@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimeConversion(t *testing.T) {
+	tests := []struct {
+		format   TimeFormat
+		expected Schema
+	}{
+		{format: TimeRFC3339, expected: Schema{"type": "string", "format": "date-time"}},
+		{format: TimeDate, expected: Schema{"type": "string", "format": "date"}},
+		{format: TimeUnixSeconds, expected: Schema{"type": "integer", "description": "Unix timestamp in seconds"}},
+		{format: TimeUnixMillis, expected: Schema{"type": "integer", "description": "Unix timestamp in milliseconds"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			opts, visited, depth := getInputs()
+			opts.TimeFormat = tt.format
+			result, err := JsonTypeOf(reflect.TypeOf(Event{}), visited, depth, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := Schema{
+				"type": "object",
+				"properties": Schema{
+					"name":     Schema{"type": "string"},
+					"startsAt": tt.expected,
+					"endsAt":   Schema{"anyOf": []Schema{tt.expected, {"type": "null"}}},
+				},
+				"required":             []string{"name", "startsAt", "endsAt"},
+				"additionalProperties": false,
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("expected %+v, got %+v", expected, result)
+			}
+		})
+	}
+}
+
+func TestTimeFormatValid(t *testing.T) {
+	for _, format := range []TimeFormat{TimeRFC3339, TimeDate, TimeUnixSeconds, TimeUnixMillis} {
+		if !format.Valid() {
+			t.Errorf("expected %q to be valid", format)
+		}
+	}
+	if TimeFormat("iso").Valid() {
+		t.Errorf("expected unknown format to be invalid")
+	}
+}
+
+func TestCoerceTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   TimeFormat
+		value    interface{}
+		expected interface{}
+	}{
+		{name: "date", format: TimeDate, value: "2024-03-01", expected: "2024-03-01T00:00:00Z"},
+		{name: "invalid date", format: TimeDate, value: "March 1st", expected: "March 1st"},
+		{name: "seconds", format: TimeUnixSeconds, value: json.Number("1709251200"), expected: "2024-03-01T00:00:00Z"},
+		{name: "millis", format: TimeUnixMillis, value: json.Number("1709251200500"), expected: "2024-03-01T00:00:00.5Z"},
+		{name: "rfc3339", format: TimeRFC3339, value: "2024-03-01T00:00:00Z", expected: "2024-03-01T00:00:00Z"},
+		{name: "fractional epoch", format: TimeUnixSeconds, value: json.Number("1.5"), expected: json.Number("1.5")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.TimeFormat = tt.format
+			result := CoerceValue(reflect.TypeOf(time.Time{}), tt.value, opts)
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
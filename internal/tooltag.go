@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ToolMeta is the tool definition declared in Go source with a `tool` struct tag
+type ToolMeta struct {
+	Name        string
+	Description string
+}
+
+// toolTagKeys are the keys accepted by the tool tag
+var toolTagKeys = []string{"name", "description"}
+
+// toolTagKey matches the key at the start of a tool tag segment
+var toolTagKey = regexp.MustCompile(`^\s*([a-z_]+)=`)
+
+// ParseToolTag reads the `tool:"name=lookup_weather,description=Look up weather by city"`
+// tag of a tool argument struct, usually placed on a blank `_ struct{}` field so it stays out
+// of the schema. Values may contain commas, a segment only starts a new key when it begins
+// with a lowercase key followed by "=". A struct without a tool tag returns an empty ToolMeta.
+func ParseToolTag(t reflect.Type) (ToolMeta, error) {
+	var meta ToolMeta
+	t = deref(t)
+	if t.Kind() != reflect.Struct {
+		return meta, nil
+	}
+	found := ""
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("tool")
+		if !ok {
+			continue
+		}
+		if found != "" {
+			return meta, fmt.Errorf("%w: tool tag declared on both %s.%s and %s.%s", ErrInvalidTag, t.Name(), found, t.Name(), field.Name)
+		}
+		found = field.Name
+		values, err := parseToolTag(tag)
+		if err != nil {
+			return meta, fmt.Errorf("%w: tool tag on %s.%s: %w", ErrInvalidTag, t.Name(), field.Name, err)
+		}
+		meta = ToolMeta{Name: values["name"], Description: values["description"]}
+	}
+	return meta, nil
+}
+
+// parseToolTag splits a tool tag into its key=value pairs
+func parseToolTag(tag string) (map[string]string, error) {
+	values := make(map[string]string)
+	key := ""
+	for _, segment := range strings.Split(tag, ",") {
+		if match := toolTagKey.FindStringSubmatch(segment); match != nil {
+			key = match[1]
+			if !isToolTagKey(key) {
+				return nil, fmt.Errorf("unknown key %q, expected one of %s", key, strings.Join(toolTagKeys, ", "))
+			}
+			if _, duplicate := values[key]; duplicate {
+				return nil, fmt.Errorf("duplicate key %q", key)
+			}
+			values[key] = strings.TrimSpace(segment[len(match[0]):])
+			continue
+		}
+		if key == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", segment)
+		}
+		// a comma inside the previous value
+		values[key] += "," + segment
+	}
+	return values, nil
+}
+
+func isToolTagKey(key string) bool {
+	for _, k := range toolTagKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseToolTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   interface{}
+		want    ToolMeta
+		wantErr bool
+	}{
+		{
+			name:  "name and description with a comma",
+			input: WeatherToolArgs{},
+			want:  ToolMeta{Name: "lookup_weather", Description: "Look up the weather, by city"},
+		},
+		{
+			name:  "pointer",
+			input: &WeatherToolArgs{},
+			want:  ToolMeta{Name: "lookup_weather", Description: "Look up the weather, by city"},
+		},
+		{
+			name: "description only",
+			input: struct {
+				_ struct{} `tool:"description=Book a flight"`
+			}{},
+			want: ToolMeta{Description: "Book a flight"},
+		},
+		{name: "no tag", input: PostalAddress{}},
+		{name: "not a struct", input: ""},
+		{
+			name: "unknown key",
+			input: struct {
+				_ struct{} `tool:"name=book,strict=true"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "duplicate key",
+			input: struct {
+				_ struct{} `tool:"name=book,name=reserve"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "missing key",
+			input: struct {
+				_ struct{} `tool:"book"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "two tags",
+			input: struct {
+				A struct{} `tool:"name=a"`
+				B struct{} `tool:"name=b"`
+			}{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseToolTag(reflect.TypeOf(tt.input))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTag) {
+					t.Errorf("expected ErrInvalidTag, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseToolTag() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("tag field stays out of the schema", func(t *testing.T) {
+		result, err := runJsonTypeOf(reflect.TypeOf(WeatherToolArgs{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, WeatherToolArgsSchema) {
+			t.Errorf("unexpected schema %+v", result)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package internal
+
+// transformSchema rebuilds a schema bottom-up: fn receives a copy of every subschema,
+// with its children already transformed, and the path of the subschema. Paths follow
+// FlattenFields, with "[]" for array items and "{}" for map values, definitions use
+// "$defs.Name". The input is not modified.
+func transformSchema(s Schema, path string, fn func(s Schema, path string) Schema) Schema {
+	out := make(Schema, len(s))
+	for k, v := range s {
+		out[k] = v
+	}
+	if props, ok := s["properties"].(Schema); ok {
+		transformed := make(Schema, len(props))
+		for name, prop := range props {
+			if sub, ok := prop.(Schema); ok {
+				transformed[name] = transformSchema(sub, joinPath(path, name), fn)
+			} else {
+				transformed[name] = prop
+			}
+		}
+		out["properties"] = transformed
+	}
+	for _, keyword := range []string{"items", "additionalItems", "contains"} {
+		if items, ok := s[keyword].(Schema); ok {
+			out[keyword] = transformSchema(items, path+"[]", fn)
+		}
+	}
+	if values, ok := s["additionalProperties"].(Schema); ok {
+		out["additionalProperties"] = transformSchema(values, path+"{}", fn)
+	}
+	if patterns, ok := s["patternProperties"].(Schema); ok {
+		transformed := make(Schema, len(patterns))
+		for pattern, v := range patterns {
+			if sub, ok := v.(Schema); ok {
+				transformed[pattern] = transformSchema(sub, path+"{}", fn)
+			} else {
+				transformed[pattern] = v
+			}
+		}
+		out["patternProperties"] = transformed
+	}
+	for _, keyword := range []string{"$defs", "definitions"} {
+		if defs, ok := s[keyword].(Schema); ok {
+			transformed := make(Schema, len(defs))
+			for name, v := range defs {
+				if sub, ok := v.(Schema); ok {
+					transformed[name] = transformSchema(sub, joinPath(keyword, name), fn)
+				} else {
+					transformed[name] = v
+				}
+			}
+			out[keyword] = transformed
+		}
+	}
+	for _, keyword := range []string{"anyOf", "allOf", "oneOf"} {
+		if variants, ok := s[keyword].([]Schema); ok {
+			transformed := make([]Schema, len(variants))
+			for i, variant := range variants {
+				transformed[i] = transformSchema(variant, path, fn)
+			}
+			out[keyword] = transformed
+		}
+	}
+	if not, ok := s["not"].(Schema); ok {
+		out["not"] = transformSchema(not, path, fn)
+	}
+	// tuples, prefixItems (2020-12) or an items array (draft-07)
+	for _, keyword := range []string{"prefixItems", "items"} {
+		if entries, ok := s[keyword].([]Schema); ok {
+			transformed := make([]Schema, len(entries))
+			for i, entry := range entries {
+				transformed[i] = transformSchema(entry, path+"[]", fn)
+			}
+			out[keyword] = transformed
+		}
+	}
+	return fn(out, path)
+}
+
+// schemaKeywordLists hold lists of subschemas
+var schemaKeywordLists = map[string]bool{"anyOf": true, "allOf": true, "oneOf": true, "prefixItems": true, "items": true}
+
+// withSchemaTypes converts the maps and lists of a schema decoded from JSON to
+// Schema and []Schema, the types of generated schemas, so that it can be transformed
+func withSchemaTypes(s Schema) Schema {
+	out := make(Schema, len(s))
+	for k, v := range s {
+		out[k] = withSchemaType(k, v)
+	}
+	return out
+}
+
+func withSchemaType(keyword string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return withSchemaTypes(v)
+	case Schema:
+		return withSchemaTypes(v)
+	case []Schema:
+		out := make([]Schema, len(v))
+		for i, s := range v {
+			out[i] = withSchemaTypes(s)
+		}
+		return out
+	case []interface{}:
+		if keyword == "type" || keyword == "required" {
+			if names, ok := stringList(v); ok {
+				return names
+			}
+			return v
+		}
+		if !schemaKeywordLists[keyword] {
+			return v
+		}
+		out := make([]Schema, len(v))
+		for i, item := range v {
+			s, ok := withSchemaType("", item).(Schema)
+			if !ok {
+				return v
+			}
+			out[i] = s
+		}
+		return out
+	}
+	return v
+}
+
+func stringList(values []interface{}) ([]string, bool) {
+	out := make([]string, len(values))
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		out[i] = s
+	}
+	return out, true
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTransformSchema(t *testing.T) {
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"name": Schema{"type": "string"},
+			"tags": Schema{"type": "array", "items": Schema{"type": "string"}},
+			"home": Schema{"anyOf": []Schema{
+				{"type": "object", "properties": Schema{"city": Schema{"type": "string"}}},
+				{"type": "null"},
+			}},
+			"scores":  Schema{"type": "object", "additionalProperties": Schema{"type": "integer"}},
+			"labels":  Schema{"type": "object", "patternProperties": Schema{"^[a-z]+$": Schema{"type": "string"}}},
+			"ignored": "not a schema",
+		},
+	}
+	var paths []string
+	result := transformSchema(schema, "", func(s Schema, path string) Schema {
+		paths = append(paths, path)
+		s["visited"] = true
+		return s
+	})
+	sort.Strings(paths)
+	expected := []string{"", "home", "home", "home", "home.city", "labels", "labels{}", "name", "scores", "scores{}", "tags", "tags[]"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected paths %v, got %v", expected, paths)
+	}
+	if _, ok := schema["visited"]; ok {
+		t.Errorf("expected the input schema to be left unchanged")
+	}
+	city := result["properties"].(Schema)["home"].(Schema)["anyOf"].([]Schema)[0]["properties"].(Schema)["city"].(Schema)
+	if city["visited"] != true {
+		t.Errorf("expected nested schemas to be transformed, got %+v", city)
+	}
+}
+
+func TestTransformSchema_DefsAndTuples(t *testing.T) {
+	schema := Schema{
+		"type":        "array",
+		"prefixItems": []Schema{{"type": "string"}},
+		"items":       Schema{"type": "integer"},
+		"$defs":       Schema{"Name": Schema{"type": "string"}},
+	}
+	var paths []string
+	transformSchema(schema, "", func(s Schema, path string) Schema {
+		paths = append(paths, path)
+		return s
+	})
+	sort.Strings(paths)
+	expected := []string{"", "$defs.Name", "[]", "[]"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected paths %v, got %v", expected, paths)
+	}
+}
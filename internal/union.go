@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// UnionSchema combines the schemas of the variants of a union into a root anyOf. The
+// $defs and definitions of the variants are hoisted to the root, where their $refs point;
+// identical definitions are shared, and a definition differing from one of the same name
+// is renamed with a number. A variant referencing itself with "#" is moved to the
+// definitions under its name, since "#" would point at the union. The inputs are not
+// modified.
+func UnionSchema(variants []Schema, names []string) Schema {
+	root := make(Schema)
+	anyOf := make([]Schema, len(variants))
+	for i, variant := range variants {
+		anyOf[i] = hoistDefs(root, variant, names[i])
+	}
+	out := Schema{"anyOf": anyOf}
+	for _, keyword := range defsKeywords {
+		if defs, ok := root[keyword].(Schema); ok {
+			out[keyword] = defs
+		}
+	}
+	return out
+}
+
+// hoistDefs moves the definitions of a variant into the root ones and returns the
+// variant with its $refs rewritten
+func hoistDefs(root, variant Schema, name string) Schema {
+	// renames maps the $refs of the variant to their target in the root
+	renames := make(map[string]string)
+	selfRef := referencesRoot(variant)
+	keyword := "$defs"
+	for _, kw := range defsKeywords {
+		if _, ok := variant[kw].(Schema); ok {
+			keyword = kw
+		}
+	}
+	defs, _ := root[keyword].(Schema)
+	if defs == nil {
+		defs = make(Schema)
+	}
+	own, _ := variant[keyword].(Schema)
+	if selfRef {
+		renames["#"] = "#/" + keyword + "/" + escapePointer(reserveName(defs, name))
+	}
+	// a definition is shared until it differs from the root one once its own $refs are
+	// rewritten, which may rename other definitions in turn
+	for changed := true; changed; {
+		changed = false
+		for _, def := range sortedKeys(own) {
+			ref := "#/" + keyword + "/" + escapePointer(def)
+			if _, renamed := renames[ref]; renamed {
+				continue
+			}
+			existing, taken := defs[def]
+			sub, _ := own[def].(Schema)
+			if taken && !reflect.DeepEqual(existing, rewriteRefs(sub, renames)) {
+				renames[ref] = "#/" + keyword + "/" + escapePointer(reserveName(defs, def))
+				changed = true
+			}
+		}
+	}
+	for _, def := range sortedKeys(own) {
+		sub, _ := own[def].(Schema)
+		target := "#/" + keyword + "/" + escapePointer(def)
+		if renamed, ok := renames[target]; ok {
+			target = renamed
+		}
+		defs[unescapePointer(strings.TrimPrefix(target, "#/"+keyword+"/"))] = rewriteRefs(sub, renames)
+	}
+	body := make(Schema, len(variant))
+	for k, v := range variant {
+		if k != keyword {
+			body[k] = v
+		}
+	}
+	body = rewriteRefs(body, renames)
+	if selfRef {
+		defs[unescapePointer(strings.TrimPrefix(renames["#"], "#/"+keyword+"/"))] = body
+		body = Schema{"$ref": renames["#"]}
+	}
+	if len(defs) > 0 {
+		root[keyword] = defs
+	}
+	return body
+}
+
+// referencesRoot reports whether a schema or its definitions hold a "#" $ref
+func referencesRoot(s Schema) bool {
+	found := false
+	transformSchema(s, "", func(sub Schema, _ string) Schema {
+		found = found || sub["$ref"] == "#"
+		return sub
+	})
+	return found
+}
+
+// rewriteRefs replaces the local $refs found in renames
+func rewriteRefs(s Schema, renames map[string]string) Schema {
+	if len(renames) == 0 {
+		return s
+	}
+	return transformSchema(s, "", func(sub Schema, _ string) Schema {
+		if ref, ok := sub["$ref"].(string); ok {
+			if target, renamed := renames[ref]; renamed {
+				sub["$ref"] = target
+			}
+		}
+		return sub
+	})
+}
+
+// reserveName returns name, or name numbered from 2 when a definition already uses it,
+// and holds it in defs until the definition is stored
+func reserveName(defs Schema, name string) string {
+	unique := name
+	for i := 2; defs[unique] != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	defs[unique] = Schema{}
+	return unique
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnionSchema(t *testing.T) {
+	node := Schema{
+		"type": "object",
+		"properties": Schema{
+			"item": Schema{"$ref": "#/$defs/Item"},
+			"next": Schema{"anyOf": []Schema{{"$ref": "#"}, {"type": "null"}}},
+		},
+		"$defs": Schema{"Item": Schema{"type": "string"}},
+	}
+	list := Schema{
+		"type":       "object",
+		"properties": Schema{"items": Schema{"type": "array", "items": Schema{"$ref": "#/$defs/Item"}}},
+		"$defs":      Schema{"Item": Schema{"type": "integer"}},
+	}
+	result := UnionSchema([]Schema{node, list}, []string{"Node", "List"})
+	expected := Schema{
+		"anyOf": []Schema{
+			{"$ref": "#/$defs/Node"},
+			{
+				"type":       "object",
+				"properties": Schema{"items": Schema{"type": "array", "items": Schema{"$ref": "#/$defs/Item2"}}},
+			},
+		},
+		"$defs": Schema{
+			"Item":  Schema{"type": "string"},
+			"Item2": Schema{"type": "integer"},
+			"Node": Schema{
+				"type": "object",
+				"properties": Schema{
+					"item": Schema{"$ref": "#/$defs/Item"},
+					"next": Schema{"anyOf": []Schema{{"$ref": "#/$defs/Node"}, {"type": "null"}}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+	if _, ok := node["$defs"]; !ok {
+		t.Errorf("expected the variants to be unmodified")
+	}
+}
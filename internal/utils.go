@@ -5,9 +5,9 @@ import (
 )
 
 // Test Utils
-func getInputs() (*Options, map[reflect.Type]bool, int) {
+func getInputs() (*Options, map[reflect.Type]int, int) {
 	opts := DefaultOptions()
-	visited := make(map[reflect.Type]bool)
+	visited := make(map[reflect.Type]int)
 	depth := 0
 	return opts, visited, depth
 }
@@ -0,0 +1,486 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSnippetLength bounds the value snippets of validation errors
+const maxSnippetLength = 80
+
+// ValidationError describes a value that does not match its schema
+type ValidationError struct {
+	// Path of the value, e.g. "lines[1].qty", empty for the root
+	Path string
+	// Keyword is the schema keyword that failed, e.g. "type" or "required"
+	Keyword string
+	// Message explains the mismatch
+	Message string
+	// Schema is the schema fragment the value was checked against
+	Schema Schema
+	// Value is a JSON snippet of the offending value, truncated to a few dozen characters,
+	// empty for missing properties
+	Value string
+}
+
+func (e ValidationError) Error() string {
+	msg := displayPath(e.Path) + ": " + e.Message
+	if e.Value != "" {
+		msg += " (" + e.Value + ")"
+	}
+	return msg
+}
+
+// ValidateValue checks a JSON value decoded with UseNumber against a schema and returns
+// every mismatch sorted by path. $refs are resolved like InlineRefs. An error is only
+// returned for schemas that cannot be evaluated, such as unresolved $refs.
+func ValidateValue(schema Schema, value interface{}, opts *Options) ([]ValidationError, error) {
+	schema = withSchemaTypes(schema)
+	v, base := newValidator(schema, opts)
+	v.validate(schema, value, "", base)
+	if v.err != nil {
+		return nil, v.err
+	}
+	sort.SliceStable(v.errs, func(i, j int) bool { return v.errs[i].Path < v.errs[j].Path })
+	return v.errs, nil
+}
+
+// newValidator returns a validator resolving $refs against a root schema, and the base URI of the root
+func newValidator(root Schema, opts *Options) (*validator, string) {
+	base, _ := root["$id"].(string)
+	base, _, _ = strings.Cut(base, "#")
+	return &validator{
+		refs: &refResolver{
+			load:      opts.RefLoader,
+			docs:      map[string]Schema{base: root},
+			resolving: make(map[string]bool),
+		},
+		patterns: make(map[string]*regexp.Regexp),
+	}, base
+}
+
+type validator struct {
+	refs     *refResolver
+	patterns map[string]*regexp.Regexp
+	errs     []ValidationError
+	// err reports a schema that cannot be evaluated
+	err error
+}
+
+func (v *validator) report(s Schema, path, keyword string, value interface{}, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{
+		Path:    path,
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, args...),
+		Schema:  s,
+		Value:   valueSnippet(value),
+	})
+}
+
+// check returns the errors of value against s without recording them
+func (v *validator) check(s Schema, value interface{}, path, base string) []ValidationError {
+	saved := v.errs
+	v.errs = nil
+	v.validate(s, value, path, base)
+	found := v.errs
+	v.errs = saved
+	return found
+}
+
+func (v *validator) validate(s Schema, value interface{}, path, base string) {
+	if v.err != nil {
+		return
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		uri, fragment := resolveRef(base, ref)
+		target, err := v.refs.target(uri, fragment)
+		if err != nil {
+			v.err = fmt.Errorf("unresolved $ref %q: %w", ref, err)
+			return
+		}
+		v.validate(withSchemaTypes(target), value, path, uri)
+	}
+	if !v.validateType(s, value, path) {
+		// the other keywords assume the type is right
+		return
+	}
+	if values, ok := s["enum"].([]interface{}); ok && !containsJSON(values, value) {
+		literals := make([]string, len(values))
+		for i, allowed := range values {
+			literals[i] = jsonLiteral(allowed)
+		}
+		v.report(s, path, "enum", value, "expected one of %s", strings.Join(literals, ", "))
+	}
+	if expected, ok := s["const"]; ok && !jsonEqual(expected, value) {
+		v.report(s, path, "const", value, "expected %s", jsonLiteral(expected))
+	}
+	switch value := value.(type) {
+	case string:
+		v.validateString(s, value, path)
+	case json.Number:
+		v.validateNumber(s, value, path)
+	case []interface{}:
+		v.validateArray(s, value, path, base)
+	case map[string]interface{}:
+		v.validateObject(s, value, path, base)
+	}
+	v.validateComposition(s, value, path, base)
+}
+
+// validateType reports whether the value has one of the types of s
+func (v *validator) validateType(s Schema, value interface{}, path string) bool {
+	var types []string
+	switch t := s["type"].(type) {
+	case string:
+		types = []string{t}
+	case []string:
+		types = t
+	default:
+		return true
+	}
+	actual := jsonTypeOfValue(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	v.report(s, path, "type", value, "expected %s, got %s", strings.Join(types, " or "), actual)
+	return false
+}
+
+func (v *validator) validateString(s Schema, value, path string) {
+	length := utf8.RuneCountInString(value)
+	if min, ok := numberKeyword(s, "minLength"); ok && float64(length) < min {
+		v.report(s, path, "minLength", value, "expected at least %v characters, got %d", min, length)
+	}
+	if max, ok := numberKeyword(s, "maxLength"); ok && float64(length) > max {
+		v.report(s, path, "maxLength", value, "expected at most %v characters, got %d", max, length)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		if re := v.compile(pattern); re != nil && !re.MatchString(value) {
+			v.report(s, path, "pattern", value, "expected a string matching %q", pattern)
+		}
+	}
+}
+
+// compile returns the cached regular expression, or nil after recording an invalid pattern
+func (v *validator) compile(pattern string) *regexp.Regexp {
+	re, cached := v.patterns[pattern]
+	if !cached {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			v.err = fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			return nil
+		}
+		v.patterns[pattern] = re
+	}
+	return re
+}
+
+func (v *validator) validateNumber(s Schema, value json.Number, path string) {
+	n, ok := toRat(value)
+	if !ok {
+		return
+	}
+	compare := func(keyword string, fails func(cmp int) bool, format string) {
+		if bound, ok := toRat(s[keyword]); ok && fails(n.Cmp(bound)) {
+			v.report(s, path, keyword, value, format, jsonLiteral(s[keyword]))
+		}
+	}
+	compare("minimum", func(cmp int) bool { return cmp < 0 }, "expected a number >= %s")
+	compare("maximum", func(cmp int) bool { return cmp > 0 }, "expected a number <= %s")
+	compare("exclusiveMinimum", func(cmp int) bool { return cmp <= 0 }, "expected a number > %s")
+	compare("exclusiveMaximum", func(cmp int) bool { return cmp >= 0 }, "expected a number < %s")
+	if divisor, ok := toRat(s["multipleOf"]); ok && divisor.Sign() != 0 {
+		if !new(big.Rat).Quo(n, divisor).IsInt() {
+			v.report(s, path, "multipleOf", value, "expected a multiple of %s", jsonLiteral(s["multipleOf"]))
+		}
+	}
+}
+
+func (v *validator) validateArray(s Schema, value []interface{}, path, base string) {
+	if min, ok := numberKeyword(s, "minItems"); ok && float64(len(value)) < min {
+		v.report(s, path, "minItems", value, "expected at least %v items, got %d", min, len(value))
+	}
+	if max, ok := numberKeyword(s, "maxItems"); ok && float64(len(value)) > max {
+		v.report(s, path, "maxItems", value, "expected at most %v items, got %d", max, len(value))
+	}
+	if unique, _ := s["uniqueItems"].(bool); unique {
+		for i := 1; i < len(value); i++ {
+			if containsJSON(value[:i], value[i]) {
+				v.report(s, fmt.Sprintf("%s[%d]", path, i), "uniqueItems", value[i], "expected unique items")
+			}
+		}
+	}
+	if contains, ok := s["contains"].(Schema); ok {
+		matches := 0
+		for i, item := range value {
+			if len(v.check(contains, item, fmt.Sprintf("%s[%d]", path, i), base)) == 0 {
+				matches++
+			}
+		}
+		min, hasMin := numberKeyword(s, "minContains")
+		if !hasMin {
+			min = 1
+		}
+		if float64(matches) < min {
+			v.report(s, path, "contains", value, "expected at least %v items matching %s, got %d", min, jsonLiteral(contains), matches)
+		}
+		if max, ok := numberKeyword(s, "maxContains"); ok && float64(matches) > max {
+			v.report(s, path, "maxContains", value, "expected at most %v items matching %s, got %d", max, jsonLiteral(contains), matches)
+		}
+	}
+	prefix, _ := s["prefixItems"].([]Schema)
+	for i, item := range value {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if i < len(prefix) {
+			v.validate(prefix[i], item, itemPath, base)
+			continue
+		}
+		switch items := s["items"].(type) {
+		case Schema:
+			v.validate(items, item, itemPath, base)
+		case bool:
+			if !items {
+				v.report(s, itemPath, "items", item, "unexpected item, at most %d items are allowed", len(prefix))
+			}
+		}
+	}
+}
+
+func (v *validator) validateObject(s Schema, value map[string]interface{}, path, base string) {
+	props, _ := s["properties"].(Schema)
+	if required, ok := s["required"].([]string); ok {
+		for _, name := range required {
+			if _, ok := value[name]; !ok {
+				fragment, _ := props[name].(Schema)
+				v.errs = append(v.errs, ValidationError{
+					Path:    joinPath(path, name),
+					Keyword: "required",
+					Message: "missing required property",
+					Schema:  fragment,
+				})
+			}
+		}
+	}
+	// dependencies is the draft-07 form of dependentRequired, its schema values are not checked
+	for _, keyword := range []string{"dependentRequired", "dependencies"} {
+		dependents, _ := s[keyword].(Schema)
+		for _, name := range sortedKeys(dependents) {
+			if _, present := value[name]; !present {
+				continue
+			}
+			names, _ := dependentNames(dependents[name])
+			for _, dependent := range names {
+				if _, ok := value[dependent]; !ok {
+					fragment, _ := props[dependent].(Schema)
+					v.errs = append(v.errs, ValidationError{
+						Path:    joinPath(path, dependent),
+						Keyword: keyword,
+						Message: fmt.Sprintf("missing property required by %q", name),
+						Schema:  fragment,
+					})
+				}
+			}
+		}
+	}
+	patterns, _ := s["patternProperties"].(Schema)
+	for _, name := range sortedMapKeys(value) {
+		item := value[name]
+		propPath := joinPath(path, name)
+		matched := false
+		if prop, ok := props[name].(Schema); ok {
+			v.validate(prop, item, propPath, base)
+			matched = true
+		}
+		for _, pattern := range sortedKeys(patterns) {
+			re := v.compile(pattern)
+			if re == nil {
+				return
+			}
+			if sub, ok := patterns[pattern].(Schema); ok && re.MatchString(name) {
+				v.validate(sub, item, propPath, base)
+				matched = true
+			}
+		}
+		if matched {
+			continue
+		}
+		switch additional := s["additionalProperties"].(type) {
+		case Schema:
+			v.validate(additional, item, propPath, base)
+		case bool:
+			if !additional {
+				v.report(s, propPath, "additionalProperties", item, "unexpected property")
+			}
+		}
+	}
+}
+
+func (v *validator) validateComposition(s Schema, value interface{}, path, base string) {
+	if members, ok := s["allOf"].([]Schema); ok {
+		for _, member := range members {
+			v.validate(member, value, path, base)
+		}
+	}
+	if variants, ok := s["anyOf"].([]Schema); ok {
+		v.validateVariants(s, "anyOf", variants, value, path, base)
+	}
+	if variants, ok := s["oneOf"].([]Schema); ok {
+		v.validateVariants(s, "oneOf", variants, value, path, base)
+	}
+	if not, ok := s["not"].(Schema); ok && len(v.check(not, value, path, base)) == 0 {
+		v.report(s, path, "not", value, "expected a value not matching %s", jsonLiteral(not))
+	}
+}
+
+// validateVariants checks anyOf and oneOf. When no variant matches and exactly one of them
+// accepts the type of the value, its errors are reported since they are the most precise,
+// e.g. for the nullable objects of strict mode.
+func (v *validator) validateVariants(s Schema, keyword string, variants []Schema, value interface{}, path, base string) {
+	matches := 0
+	var typed [][]ValidationError
+	for _, variant := range variants {
+		errs := v.check(variant, value, path, base)
+		if len(errs) == 0 {
+			matches++
+			continue
+		}
+		if !(len(errs) == 1 && errs[0].Keyword == "type" && errs[0].Path == path) {
+			typed = append(typed, errs)
+		}
+	}
+	switch {
+	case matches == 0 && len(typed) == 1:
+		v.errs = append(v.errs, typed[0]...)
+	case matches == 0:
+		v.report(s, path, keyword, value, "expected a value matching one of the %s schemas", keyword)
+	case keyword == "oneOf" && matches > 1:
+		v.report(s, path, keyword, value, "expected a value matching exactly one of the oneOf schemas, matched %d", matches)
+	}
+}
+
+// jsonTypeOfValue names the JSON Schema type of a decoded value
+func jsonTypeOfValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if n, ok := toRat(value); ok && n.IsInt() {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// toRat converts the numbers of schemas and decoded values to exact rationals
+func toRat(v interface{}) (*big.Rat, bool) {
+	var literal string
+	switch v := v.(type) {
+	case json.Number:
+		literal = v.String()
+	case float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		literal = fmt.Sprint(v)
+	default:
+		return nil, false
+	}
+	n, ok := new(big.Rat).SetString(literal)
+	return n, ok
+}
+
+func numberKeyword(s Schema, keyword string) (float64, bool) {
+	n, ok := toRat(s[keyword])
+	if !ok {
+		return 0, false
+	}
+	f, _ := n.Float64()
+	return f, true
+}
+
+// jsonEqual compares values by their JSON meaning, 1 and 1.0 are equal
+func jsonEqual(a, b interface{}) bool {
+	if x, ok := toRat(a); ok {
+		y, ok := toRat(b)
+		return ok && x.Cmp(y) == 0
+	}
+	switch a := a.(type) {
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !jsonEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	case string, bool, nil:
+		return a == b
+	}
+	return false
+}
+
+// dependentNames reads a list of property names, generated or decoded from JSON
+func dependentNames(v interface{}) ([]string, bool) {
+	switch names := v.(type) {
+	case []string:
+		return names, true
+	case []interface{}:
+		return stringList(names)
+	}
+	return nil, false
+}
+
+func containsJSON(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if jsonEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// valueSnippet encodes a value as JSON, truncated for logs
+func valueSnippet(value interface{}) string {
+	snippet := jsonLiteral(value)
+	if utf8.RuneCountInString(snippet) > maxSnippetLength {
+		snippet = string([]rune(snippet)[:maxSnippetLength-1]) + ellipsis
+	}
+	return snippet
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, data string) interface{} {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestValidateValue(t *testing.T) {
+	line := Schema{
+		"type": "object",
+		"properties": Schema{
+			"qty":    Schema{"type": "integer", "minimum": 1},
+			"sku":    Schema{"type": "string", "pattern": "^[A-Z][0-9]+$"},
+			"status": Schema{"type": []string{"string", "null"}, "enum": []interface{}{"open", "closed", nil}},
+		},
+		"required":             []string{"qty", "sku", "status"},
+		"additionalProperties": false,
+	}
+	schema := Schema{
+		"type": "object",
+		"properties": Schema{
+			"lines": Schema{"type": "array", "items": line, "maxItems": 3},
+			"note": Schema{"anyOf": []Schema{
+				{"type": "object", "properties": Schema{"text": Schema{"type": "string", "maxLength": 5}}, "required": []string{"text"}},
+				{"type": "null"},
+			}},
+			"score": Schema{"type": "number", "multipleOf": 0.5},
+		},
+		"required":             []string{"lines", "note", "score"},
+		"additionalProperties": false,
+	}
+	tests := []struct {
+		name     string
+		data     string
+		expected []string
+	}{
+		{
+			name: "valid",
+			data: `{"lines":[{"qty":2,"sku":"A1","status":null}],"note":null,"score":1.5}`,
+		},
+		{
+			name: "mismatches",
+			data: `{"lines":[{"qty":"3","sku":"a1","status":"lost","extra":true},{"qty":0.0,"sku":"B2","status":"open"}],` +
+				`"note":{"text":"too long"},"score":1.25,"other":1}`,
+			expected: []string{
+				`lines[0].extra: unexpected property (true)`,
+				`lines[0].qty: expected integer, got string ("3")`,
+				`lines[0].sku: expected a string matching "^[A-Z][0-9]+$" ("a1")`,
+				`lines[0].status: expected one of "open", "closed", null ("lost")`,
+				`lines[1].qty: expected a number >= 1 (0.0)`,
+				`note.text: expected at most 5 characters, got 8 ("too long")`,
+				`other: unexpected property (1)`,
+				`score: expected a multiple of 0.5 (1.25)`,
+			},
+		},
+		{
+			name:     "missing properties",
+			data:     `{"lines":[{"qty":1,"sku":"A1"}],"score":1}`,
+			expected: []string{`lines[0].status: missing required property`, `note: missing required property`},
+		},
+		{
+			name:     "wrong root type",
+			data:     `["not","an","object"]`,
+			expected: []string{`(root): expected object, got array (["not","an","object"])`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := ValidateValue(schema, decodeJSON(t, tt.data), DefaultOptions())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateValue_Keywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   Schema
+		data     string
+		expected []string
+	}{
+		{name: "generated schema", schema: ShipmentSchema, data: `{"tags":["fragile","heavy"],"codes":[1,7],"street":"Main","city":"Oslo","zip":"0150"}`},
+		{
+			name:   "mismatches",
+			schema: ShipmentSchema,
+			data:   `{"tags":["heavy"],"codes":[7.0],"street":"Main"}`,
+			expected: []string{
+				`city: missing property required by "street"`,
+				`tags: expected at least 1 items matching {"const":"fragile"}, got 0 (["heavy"])`,
+				`zip: missing property required by "street"`,
+			},
+		},
+		{
+			name:     "min and max contains",
+			schema:   Schema{"type": "array", "contains": Schema{"type": "integer"}, "minContains": 2, "maxContains": 3},
+			data:     `[1,"a"]`,
+			expected: []string{`(root): expected at least 2 items matching {"type":"integer"}, got 1 ([1,"a"])`},
+		},
+		{
+			name:     "not",
+			schema:   Schema{"type": "string", "not": Schema{"enum": []interface{}{"n/a"}}},
+			data:     `"n/a"`,
+			expected: []string{`(root): expected a value not matching {"enum":["n/a"]} ("n/a")`},
+		},
+		{
+			name:     "draft-07 dependencies",
+			schema:   Schema{"type": "object", "dependencies": map[string]interface{}{"street": []interface{}{"city"}, "zip": Schema{"required": []string{"city"}}}},
+			data:     `{"street":"Main","zip":"0150"}`,
+			expected: []string{`city: missing property required by "street"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := ValidateValue(tt.schema, decodeJSON(t, tt.data), DefaultOptions())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateValue_Details(t *testing.T) {
+	qty := Schema{"type": "integer"}
+	schema := Schema{"type": "object", "properties": Schema{"qty": qty}}
+	long := `"` + string(bytes.Repeat([]byte("x"), 100)) + `"`
+	errs, err := ValidateValue(schema, decodeJSON(t, `{"qty":`+long+`}`), DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected one error, got %v", errs)
+	}
+	e := errs[0]
+	if e.Path != "qty" || e.Keyword != "type" || !reflect.DeepEqual(e.Schema, qty) {
+		t.Errorf("unexpected error details %+v", e)
+	}
+	if len([]rune(e.Value)) != maxSnippetLength {
+		t.Errorf("expected the snippet to be truncated to %d characters, got %q", maxSnippetLength, e.Value)
+	}
+}
+
+func TestValidateValue_Refs(t *testing.T) {
+	schema := Schema{
+		"type":       "object",
+		"properties": Schema{"child": Schema{"$ref": "#/$defs/Node"}},
+		"$defs": Schema{"Node": Schema{
+			"type":       "object",
+			"properties": Schema{"name": Schema{"type": "string"}, "child": Schema{"$ref": "#/$defs/Node"}},
+		}},
+	}
+	errs, err := ValidateValue(schema, decodeJSON(t, `{"child":{"name":"a","child":{"name":1}}}`), DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(errs) != 1 || errs[0].Path != "child.child.name" {
+		t.Errorf("expected a recursive mismatch, got %v", errs)
+	}
+	_, err = ValidateValue(Schema{"$ref": "#/$defs/Missing"}, "x", DefaultOptions())
+	if err == nil {
+		t.Errorf("expected an error for an unresolved $ref")
+	}
+}
@@ -0,0 +1,26 @@
+package internal
+
+import "fmt"
+
+// Warning describes an adjustment made to a schema during generation
+type Warning struct {
+	// Path of the affected property, empty for the root
+	Path string
+	// Message explains the adjustment
+	Message string
+}
+
+func (w Warning) String() string {
+	path := w.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + w.Message
+}
+
+// Warn reports a warning to the configured handler, warnings are dropped without one
+func (o *Options) Warn(path, format string, args ...interface{}) {
+	if o.WarningHandler != nil {
+		o.WarningHandler(Warning{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+}
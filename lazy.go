@@ -0,0 +1,74 @@
+package gptschema
+
+import (
+	"sync"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// LazySchema generates the schema of a value the first time it is needed, so schemas
+// embedded in request parameter structs are only computed on the code paths that send
+// them. It implements json.Marshaler and is safe for concurrent use, generation runs once
+// and its result, or error, is reused by every later call.
+//
+// Example:
+//
+//	var reportSchema = NewLazySchema(Report{}, WithStrict(true))
+//
+//	type request struct {
+//	    Model  string      `json:"model"`
+//	    Schema *LazySchema `json:"schema"`
+//	}
+//
+//	body, err := json.Marshal(request{Model: "gpt-4o-mini", Schema: reportSchema}) // generates Report's schema
+type LazySchema struct {
+	value interface{}
+	opts  []Option
+
+	once   sync.Once
+	schema *FrozenSchema
+	err    error
+}
+
+// NewLazySchema returns a LazySchema generating the schema of v with the options,
+// see GenerateSchema. Nothing is generated until Schema or MarshalJSON is called.
+func NewLazySchema(v interface{}, opts ...Option) *LazySchema {
+	return &LazySchema{value: v, opts: opts}
+}
+
+// Schema generates the schema on the first call and returns a copy of it, so callers
+// modifying it do not affect the other users of the LazySchema.
+func (l *LazySchema) Schema() (*internal.Schema, error) {
+	schema, err := l.Frozen()
+	if err != nil {
+		return nil, err
+	}
+	clone := schema.Clone()
+	return &clone, nil
+}
+
+// Frozen generates the schema on the first call and returns it, shared without copying.
+func (l *LazySchema) Frozen() (*FrozenSchema, error) {
+	l.once.Do(func() {
+		schema, err := GenerateSchema(l.value, l.opts...)
+		if err != nil {
+			l.err = err
+			return
+		}
+		l.schema = &FrozenSchema{schema: *schema}
+	})
+	return l.schema, l.err
+}
+
+// MarshalJSON generates the schema on the first call and marshals it, a nil LazySchema
+// marshals to null.
+func (l *LazySchema) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("null"), nil
+	}
+	schema, err := l.Frozen()
+	if err != nil {
+		return nil, err
+	}
+	return schema.MarshalJSON()
+}
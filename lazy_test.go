@@ -0,0 +1,68 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestLazySchema(t *testing.T) {
+	lazy := NewLazySchema(internal.Employee{})
+	if lazy.schema != nil {
+		t.Fatalf("expected no schema before the first marshal")
+	}
+	type request struct {
+		Model  string      `json:"model"`
+		Schema *LazySchema `json:"schema"`
+		Extra  *LazySchema `json:"extra"`
+	}
+	var wg sync.WaitGroup
+	bodies := make([][]byte, 8)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body, err := json.Marshal(request{Model: "gpt-4o-mini", Schema: lazy})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			bodies[i] = body
+		}(i)
+	}
+	wg.Wait()
+
+	expected, err := GenerateSchemaJSON(internal.Employee{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"model":"gpt-4o-mini","schema":` + expected + `,"extra":null}`
+	for _, body := range bodies {
+		if string(body) != want {
+			t.Errorf("expected %s, got %s", want, body)
+		}
+	}
+	first, _ := lazy.Frozen()
+	second, _ := lazy.Frozen()
+	if first != second {
+		t.Errorf("expected the schema to be generated once")
+	}
+	// callers get copies of the shared schema
+	copied, _ := lazy.Schema()
+	(*copied)["description"] = "modified"
+	if body, _ := json.Marshal(lazy); string(body) != expected {
+		t.Errorf("expected the shared schema to be unchanged, got %s", body)
+	}
+}
+
+func TestLazySchema_Error(t *testing.T) {
+	lazy := NewLazySchema(internal.Employee{}, WithMaxDepth(0))
+	if _, err := json.Marshal(lazy); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+	if _, err := lazy.Schema(); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected the error to be kept, got %v", err)
+	}
+}
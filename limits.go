@@ -0,0 +1,55 @@
+package gptschema
+
+import "github.com/akane9506/gptschema/internal"
+
+// Limits are the numeric limits a provider enforces on schemas: object nesting, total
+// properties, total enum values, the combined length of property names and enum strings,
+// the length of large enums and the size of the marshaled schema. Lint and WithLimitPolicy
+// check them, and MaxSchemaBytes is the size budget of GenerateSchema when
+// WithMaxSchemaBytes is not set. A zero limit is not checked.
+type Limits = internal.Limits
+
+// ProviderLimits returns the limits of a provider profile, the OpenAI limits for an empty
+// provider. Every profile starts from the limits published for OpenAI structured outputs.
+//
+// Example:
+//
+//	limits := ProviderLimits(ProviderOpenAI)
+//	fmt.Println(limits.MaxProperties) // 5000
+func ProviderLimits(provider Provider) Limits {
+	return internal.ProviderLimits(provider)
+}
+
+// SetProviderLimits replaces the limits of a provider profile for every later call, so
+// applications can follow a provider raising or lowering its limits without waiting for a
+// release. Unknown providers and negative limits are rejected with ErrInvalidOption.
+// Call it during initialization, the profile applies to concurrent calls as soon as it is set.
+//
+// Example:
+//
+//	limits := ProviderLimits(ProviderOpenAI)
+//	limits.MaxProperties = 10000
+//	if err := SetProviderLimits(ProviderOpenAI, limits); err != nil {
+//	    log.Fatal(err)
+//	}
+func SetProviderLimits(provider Provider, limits Limits) error {
+	return internal.SetProviderLimits(provider, limits)
+}
+
+// WithLimits overrides the limits of the selected provider for a single call, for Lint,
+// WithLimitPolicy and the schema size budget. Negative limits are rejected with ErrInvalidOption.
+//
+// Example:
+//
+//	limits := ProviderLimits(ProviderOpenAI)
+//	limits.MaxNesting = 5
+//	issues, err := Lint(schema, WithLimits(limits))
+func WithLimits(limits Limits) Option {
+	return func(opts *internal.Options) {
+		if err := limits.Validate(); err != nil {
+			opts.AddError("%v", err)
+			return
+		}
+		opts.Limits = &limits
+	}
+}
@@ -0,0 +1,75 @@
+package gptschema
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithLimits(t *testing.T) {
+	type Inner struct {
+		Name string `json:"name"`
+	}
+	type Outer struct {
+		Inner Inner `json:"inner"`
+	}
+	schema, err := GenerateSchema(Outer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issues, _ := Lint(schema); len(issues) != 0 {
+		t.Fatalf("expected no issues with the default limits, got %+v", issues)
+	}
+
+	limits := ProviderLimits(ProviderOpenAI)
+	limits.MaxNesting = 1
+	issues, err := Lint(schema, WithLimits(limits))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Rule != "max-nesting" || issues[0].Path != "inner" {
+		t.Errorf("expected a max-nesting issue at inner, got %+v", issues)
+	}
+
+	limits.MaxSchemaBytes = 32
+	var sizeErr *SchemaSizeError
+	if _, err := GenerateSchema(Outer{}, WithLimits(limits)); !errors.As(err, &sizeErr) || sizeErr.Limit != 32 {
+		t.Errorf("expected a SchemaSizeError for the limit budget, got %v", err)
+	}
+	if _, err := GenerateSchema(Outer{}, WithLimits(limits), WithMaxSchemaBytes(4096)); err != nil {
+		t.Errorf("expected WithMaxSchemaBytes to take precedence, got %v", err)
+	}
+
+	limits.MaxProperties = -1
+	if _, err := Lint(schema, WithLimits(limits)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption for a negative limit, got %v", err)
+	}
+}
+
+func TestSetProviderLimits(t *testing.T) {
+	original := ProviderLimits(ProviderAnthropic)
+	defer SetProviderLimits(ProviderAnthropic, original)
+
+	type Item struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+	schema, err := GenerateSchema(Item{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lowered := original
+	lowered.MaxProperties = 1
+	if err := SetProviderLimits(ProviderAnthropic, lowered); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	issues, _ := Lint(schema, WithProvider(ProviderAnthropic))
+	if len(issues) != 1 || issues[0].Rule != "max-properties" {
+		t.Errorf("expected a max-properties issue, got %+v", issues)
+	}
+	if issues, _ := Lint(schema); len(issues) != 0 {
+		t.Errorf("expected the OpenAI profile to keep its limits, got %+v", issues)
+	}
+	if err := SetProviderLimits("mistral", lowered); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption for an unknown provider, got %v", err)
+	}
+}
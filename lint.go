@@ -0,0 +1,177 @@
+package gptschema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// LintIssue describes a part of a schema a provider would reject.
+type LintIssue = internal.LintIssue
+
+// ErrLintFailed is wrapped by LintError.
+var ErrLintFailed = errors.New("schema failed lint checks")
+
+// LintError reports the issues found by Lint. It wraps ErrLintFailed.
+type LintError struct {
+	Issues []LintIssue
+}
+
+func (e *LintError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.String()
+	}
+	return ErrLintFailed.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *LintError) Unwrap() error {
+	return ErrLintFailed
+}
+
+// Lint checks a schema against the requirements of OpenAI's structured outputs, so problems
+// surface before the request is sent instead of as a provider error at request time.
+// In strict mode (the default, see WithStrict) it checks the rules of strict structured outputs:
+// the root is an object, every object sets additionalProperties to false and requires all of its
+// properties, and no unsupported keyword is used. In every mode it checks the limits on object
+// nesting, total properties, total enum values, the combined length of property names and
+// enum strings, and the length of large enums, see Limits. With WithProvider
+// it also checks the property names against the characters, length and reserved names the
+// provider accepts, each issue carrying a valid name in Suggestion. The rules added with
+// RegisterLintRule run in every mode.
+//
+// Schemas produced by GenerateSchema pass in strict mode, so Lint is mostly useful for schemas
+// that were modified or assembled by hand. An error is only returned for invalid options
+// and, with ErrInvalidSchema, for a nil schema. Definitions in $defs are checked once each.
+//
+// Example:
+//
+//	issues, err := Lint(schema)
+//	for _, issue := range issues {
+//	    log.Println(issue)
+//	}
+func Lint(schema *internal.Schema, opts ...Option) ([]LintIssue, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("%w: schema is nil", ErrInvalidSchema)
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return lintSchema(*schema, options), nil
+}
+
+// lintSchema runs the checks of Lint with the options already built
+func lintSchema(schema internal.Schema, options *internal.Options) []LintIssue {
+	issues := internal.Lint(schema, options.Strict, options.EffectiveLimits())
+	if options.Provider != "" {
+		issues = append(issues, internal.LintPropertyNames(schema, options.Provider)...)
+	}
+	return issues
+}
+
+// StripUnsupported returns a copy of a schema without the keywords OpenAI strict mode rejects,
+// such as not, contains and dependentRequired, with a warning for each removed keyword.
+// Transmit the stripped copy and keep validating responses against the original, so the
+// constraints the provider cannot enforce are still checked locally.
+//
+// Example:
+//
+//	schema, _ := Object().Prop("code", String().Exclude("n/a")).Required("code").Build()
+//	transmitted, warnings := StripUnsupported(schema) // code: keyword "not" removed, ...
+//	// send transmitted, then check the response against the full schema
+//	mismatches, err := Validate(schema, []byte(content))
+func StripUnsupported(schema *internal.Schema) (*internal.Schema, []Warning) {
+	stripped, warnings := internal.StripUnsupported(*schema)
+	return &stripped, warnings
+}
+
+// LimitPolicy selects how schema generation handles exceeded size limits.
+type LimitPolicy = internal.LimitPolicy
+
+// Limit policies accepted by WithLimitPolicy.
+const (
+	// LimitsIgnore leaves the limits to Lint
+	LimitsIgnore = internal.LimitsIgnore
+	// LimitsWarn reports exceeded limits to the warning handler
+	LimitsWarn = internal.LimitsWarn
+	// LimitsFail fails generation with a *LintError when a limit is exceeded
+	LimitsFail = internal.LimitsFail
+)
+
+// WithLimitPolicy checks the size limits of the selected provider (see Limits) while generating
+// schemas: object nesting, total properties, total enum values, the combined length of
+// property names and enum strings, and the length of large enums. With LimitsWarn each
+// exceeded limit is passed to the handler set with WithWarningHandler, with LimitsFail
+// generation returns a *LintError. By default (LimitsIgnore) only Lint checks the limits.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Catalog{}, WithLimitPolicy(LimitsFail))
+//	if errors.Is(err, ErrLintFailed) {
+//	    log.Fatal(err)
+//	}
+func WithLimitPolicy(policy LimitPolicy) Option {
+	return func(opts *internal.Options) {
+		if !policy.Valid() {
+			opts.AddError("unknown limit policy %q", policy)
+			return
+		}
+		opts.LimitPolicy = policy
+	}
+}
+
+// WithAllowedKeywords fails generation with a *LintError when the final schema, after tags,
+// provenance and every other option, uses a keyword outside the given set, as a guardrail
+// for teams targeting the strictest providers. Every keyword must be listed, including
+// type, properties, required and additionalProperties. An empty set is rejected with
+// ErrInvalidOption.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Order{}, WithAllowedKeywords(
+//	    "type", "properties", "required", "additionalProperties", "items", "anyOf", "enum", "description"))
+//	// total: keyword "pattern" is not allowed (disallowed-keyword)
+func WithAllowedKeywords(keywords ...string) Option {
+	return func(opts *internal.Options) {
+		if len(keywords) == 0 {
+			opts.AddError("allowed keywords must not be empty")
+			return
+		}
+		opts.AllowedKeywords = make(map[string]bool, len(keywords))
+		for _, keyword := range keywords {
+			opts.AllowedKeywords[keyword] = true
+		}
+	}
+}
+
+// checkKeywords enforces the set of WithAllowedKeywords
+func checkKeywords(schema internal.Schema, options *internal.Options) error {
+	if options.AllowedKeywords == nil {
+		return nil
+	}
+	if issues := internal.LintKeywords(schema, options.AllowedKeywords); len(issues) > 0 {
+		return &LintError{Issues: issues}
+	}
+	return nil
+}
+
+// checkLimits applies the limit policy to a generated schema
+func checkLimits(schema internal.Schema, options *internal.Options) error {
+	if options.LimitPolicy != LimitsWarn && options.LimitPolicy != LimitsFail {
+		return nil
+	}
+	issues := internal.LintLimits(schema, options.EffectiveLimits())
+	if len(issues) == 0 {
+		return nil
+	}
+	if options.LimitPolicy == LimitsFail {
+		return &LintError{Issues: issues}
+	}
+	for _, issue := range issues {
+		options.Warn(issue.Path, "%s (%s)", issue.Message, issue.Rule)
+	}
+	return nil
+}
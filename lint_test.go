@@ -0,0 +1,202 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestLint(t *testing.T) {
+	schema, err := GenerateSchema(internal.Employee{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	issues, err := Lint(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected generated schema to pass, got %+v", issues)
+	}
+
+	// a schema modified by hand
+	(*schema)["additionalProperties"] = true
+	issues, err = Lint(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Rule != "additional-properties" {
+		t.Errorf("expected an additional-properties issue, got %+v", issues)
+	}
+	if issues, _ := Lint(schema, WithStrict(false)); len(issues) != 0 {
+		t.Errorf("expected no issues in non-strict mode, got %+v", issues)
+	}
+	if _, err := Lint(schema, WithMaxDepth(0)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+	if _, err := Lint(nil); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for a nil schema, got %v", err)
+	}
+}
+
+func TestLint_Defs(t *testing.T) {
+	schema, err := GenerateSchema(internal.Employee{}, WithDefs(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issues, _ := Lint(schema); len(issues) != 0 {
+		t.Errorf("expected generated schema to pass, got %+v", issues)
+	}
+	defs := (*schema)["$defs"].(internal.Schema)
+	if len(defs) == 0 {
+		t.Fatalf("expected definitions, got %+v", *schema)
+	}
+	expected := []string{""}
+	(*schema)["additionalProperties"] = true
+	for name, def := range defs {
+		def.(internal.Schema)["additionalProperties"] = true
+		expected = append(expected, "$defs."+name)
+	}
+	issues, err := Lint(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var paths []string
+	for _, issue := range issues {
+		if issue.Rule == "additional-properties" {
+			paths = append(paths, issue.Path)
+		}
+	}
+	sort.Strings(expected)
+	sort.Strings(paths)
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected additional-properties issues at %q, got %+v", expected, issues)
+	}
+}
+
+func TestLint_PropertyNames(t *testing.T) {
+	type Row struct {
+		Price float64 `json:"unit.price"`
+	}
+	schema, err := GenerateSchema(Row{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issues, _ := Lint(schema); len(issues) != 0 {
+		t.Errorf("expected property names to be unchecked without a provider, got %+v", issues)
+	}
+	issues, err := Lint(schema, WithProvider(ProviderGemini))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Rule != "property-name" || issues[0].Suggestion != "unit_price" {
+		t.Errorf("expected a property-name issue suggesting unit_price, got %+v", issues)
+	}
+	if issues, _ := Lint(schema, WithProvider(ProviderAnthropic)); len(issues) != 0 {
+		t.Errorf("expected anthropic to accept dotted names, got %+v", issues)
+	}
+}
+
+func TestStripUnsupported(t *testing.T) {
+	schema, err := Object().
+		Prop("code", String().Exclude("n/a")).
+		Prop("tags", Array(String().Not(String().MaxLength(0)))).
+		Required("code", "tags").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transmitted, warnings := StripUnsupported(schema)
+	expected := internal.Schema{
+		"type": "object",
+		"properties": internal.Schema{
+			"code": internal.Schema{"type": "string"},
+			"tags": internal.Schema{"type": "array", "items": internal.Schema{"type": "string"}},
+		},
+		"required":             []string{"code", "tags"},
+		"additionalProperties": false,
+	}
+	if !reflect.DeepEqual(*transmitted, expected) {
+		t.Errorf("expected %+v, got %+v", expected, *transmitted)
+	}
+	var messages []string
+	for _, w := range warnings {
+		messages = append(messages, w.String())
+	}
+	expectedWarnings := []string{
+		`code: keyword "not" removed, it is not supported in strict mode`,
+		`tags[]: keyword "not" removed, it is not supported in strict mode`,
+	}
+	if !reflect.DeepEqual(messages, expectedWarnings) {
+		t.Errorf("expected warnings %v, got %v", expectedWarnings, messages)
+	}
+	if issues, _ := Lint(transmitted); len(issues) != 0 {
+		t.Errorf("expected the transmitted schema to pass lint, got %v", issues)
+	}
+	if _, ok := (*schema)["properties"].(internal.Schema)["code"].(internal.Schema)["not"]; !ok {
+		t.Errorf("expected the original schema to keep not")
+	}
+	if mismatches, _ := Validate(schema, []byte(`{"code":"n/a","tags":[]}`)); len(mismatches) != 1 || mismatches[0].Keyword != "not" {
+		t.Errorf("expected the original schema to reject the excluded value, got %v", mismatches)
+	}
+}
+
+func TestWithLimitPolicy(t *testing.T) {
+	type Level struct {
+		Name string `json:"name"`
+	}
+	// 11 levels of nested objects exceed the nesting limit of 10
+	levels := reflect.TypeOf(Level{})
+	for i := 0; i < 10; i++ {
+		levels = reflect.StructOf([]reflect.StructField{{Name: "Child", Type: levels, Tag: `json:"child"`}})
+	}
+	v := reflect.New(levels).Elem().Interface()
+
+	if _, err := GenerateSchema(v); err != nil {
+		t.Fatalf("unexpected error with limits ignored: %v", err)
+	}
+
+	var warnings []Warning
+	_, err := GenerateSchema(v, WithLimitPolicy(LimitsWarn), WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error with limits as warnings: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Message, "(max-nesting)") {
+		t.Errorf("expected a max-nesting warning, got %+v", warnings)
+	}
+
+	_, err = GenerateSchema(v, WithLimitPolicy(LimitsFail))
+	var lintErr *LintError
+	if !errors.As(err, &lintErr) || len(lintErr.Issues) != 1 || lintErr.Issues[0].Rule != "max-nesting" {
+		t.Errorf("expected a max-nesting LintError, got %v", err)
+	}
+}
+
+func TestWithAllowedKeywords(t *testing.T) {
+	type Order struct {
+		ID    string  `json:"id"`
+		Total float64 `json:"total" description:"Order total in euros"`
+	}
+	if _, err := GenerateSchema(Order{}, WithAllowedKeywords("type", "properties", "required", "additionalProperties", "description")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := GenerateSchema(Order{}, WithAllowedKeywords("type", "properties", "required", "additionalProperties"))
+	if !errors.Is(err, ErrLintFailed) {
+		t.Fatalf("expected ErrLintFailed, got %v", err)
+	}
+	var lintErr *LintError
+	if !errors.As(err, &lintErr) || len(lintErr.Issues) != 1 || lintErr.Issues[0].Path != "total" || lintErr.Issues[0].Rule != "disallowed-keyword" {
+		t.Errorf("expected a disallowed-keyword issue on total, got %v", err)
+	}
+
+	if _, err := GenerateSchema(Order{}, WithAllowedKeywords()); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption for an empty set, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package gptschema
+
+import "github.com/akane9506/gptschema/internal"
+
+// LintRule is a house rule checked by Lint, see RegisterLintRule.
+type LintRule = internal.LintRule
+
+// NewLintRule returns a LintRule named name calling check on the schema and each of its
+// subschemas. check returns a message for each problem found at path, reported by Lint as
+// a LintIssue whose Rule is name.
+//
+// Example:
+//
+//	rule := NewLintRule("no-data-field", func(s internal.Schema, path string) []string {
+//	    if props, ok := s["properties"].(internal.Schema); ok && props["data"] != nil {
+//	        return []string{`property "data" is too vague, use a descriptive name`}
+//	    }
+//	    return nil
+//	})
+func NewLintRule(name string, check func(schema internal.Schema, path string) []string) LintRule {
+	return internal.NewLintRule(name, check)
+}
+
+// RegisterLintRule adds an organization's house rule, such as "every property must have a
+// description", to every Lint call. Registered rules run in the same pass as the built-in
+// checks, so their issues are also reported by CheckLive and the "gptschema check" command
+// when the package registering them is imported. Registering a rule with the name of a
+// registered rule replaces it. Registration is global and safe for concurrent use.
+//
+// Example:
+//
+//	func init() {
+//	    gptschema.RegisterLintRule(gptschema.NewLintRule("property-description",
+//	        func(s internal.Schema, path string) []string {
+//	            if path != "" && s["description"] == nil {
+//	                return []string{"every property must have a description"}
+//	            }
+//	            return nil
+//	        }))
+//	}
+func RegisterLintRule(rule LintRule) {
+	internal.RegisterLintRule(rule)
+}
@@ -0,0 +1,31 @@
+package gptschema
+
+import (
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestRegisterLintRule(t *testing.T) {
+	// the rule only matches a property name used by this test, registration is global
+	RegisterLintRule(NewLintRule("no-blob-field", func(s internal.Schema, path string) []string {
+		if props, ok := s["properties"].(internal.Schema); ok && props["lintRuleBlob"] != nil {
+			return []string{`property "lintRuleBlob" is too vague`}
+		}
+		return nil
+	}))
+	type Payload struct {
+		Blob string `json:"lintRuleBlob"`
+	}
+	schema, err := GenerateSchema(Payload{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	issues, err := Lint(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Rule != "no-blob-field" || issues[0].String() != `(root): property "lintRuleBlob" is too vague (no-blob-field)` {
+		t.Errorf("expected a no-blob-field issue, got %+v", issues)
+	}
+}
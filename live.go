@@ -0,0 +1,141 @@
+package gptschema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// LiveCheckConfig configures the OpenAI-compatible endpoint used by CheckLive.
+type LiveCheckConfig struct {
+	// BaseURL of the API, defaults to https://api.openai.com/v1
+	BaseURL string
+	// APIKey sent as a bearer token, defaults to the OPENAI_API_KEY environment variable
+	APIKey string
+	// Model used for the request, defaults to gpt-4o-mini
+	Model string
+	// HTTPClient used for the request, defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// LiveCheckResult reports whether a provider accepted a schema.
+type LiveCheckResult struct {
+	// Accepted is set when the provider answered the request successfully
+	Accepted bool
+	// StatusCode of the provider response
+	StatusCode int
+	// ProviderMessage is the error message returned by the provider when the schema was rejected
+	ProviderMessage string
+	// LintIssues are the local findings of Lint for the same schema
+	LintIssues []LintIssue
+}
+
+// providerError is the error envelope of OpenAI-compatible APIs
+type providerError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CheckLive submits the schema of v to an OpenAI-compatible Chat Completions endpoint in a
+// minimal request (max_tokens=1) and reports whether the provider accepted it, alongside the
+// local Lint findings. It is a diagnostic helper: each call performs a billable request.
+//
+// A schema rejected by the provider (HTTP 400) is reported through the result with the
+// provider's error message. Other failures, such as authentication errors or rate limits,
+// are returned as errors since they say nothing about the schema.
+//
+// Example:
+//
+//	result, err := CheckLive(ctx, LiveCheckConfig{Model: "gpt-4o-mini"}, "address_item", AddressItem{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !result.Accepted {
+//	    log.Printf("rejected: %s", result.ProviderMessage)
+//	}
+func CheckLive(ctx context.Context, config LiveCheckConfig, name string, v interface{}, opts ...Option) (*LiveCheckResult, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if !responseFormatName.MatchString(name) {
+		return nil, fmt.Errorf("%w: response format name %q must match %s", ErrInvalidOption, name, responseFormatName)
+	}
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	schema, _ = providerSchema(schema, options)
+	issues, err := Lint(schema, opts...)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"model":      defaultString(config.Model, "gpt-4o-mini"),
+		"max_tokens": 1,
+		"messages": []map[string]string{
+			{"role": "user", "content": "ping"},
+		},
+		"response_format": responseFormat{
+			Type: "json_schema",
+			JSONSchema: responseFormatSpec{
+				Name:   name,
+				Schema: schema,
+				Strict: options.Strict,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request to JSON: %w", err)
+	}
+	baseURL := strings.TrimSuffix(defaultString(config.BaseURL, "https://api.openai.com/v1"), "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey := defaultString(config.APIKey, os.Getenv("OPENAI_API_KEY")); apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &LiveCheckResult{StatusCode: resp.StatusCode, LintIssues: issues}
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		result.Accepted = true
+	case resp.StatusCode == http.StatusBadRequest:
+		var perr providerError
+		if json.Unmarshal(respBody, &perr) == nil && perr.Error.Message != "" {
+			result.ProviderMessage = perr.Error.Message
+		} else {
+			result.ProviderMessage = strings.TrimSpace(string(respBody))
+		}
+	default:
+		return nil, fmt.Errorf("provider returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return result, nil
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+package gptschema
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type LiveAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+func TestCheckLive(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		response        string
+		accepted        bool
+		providerMessage string
+	}{
+		{
+			name:     "accepted",
+			status:   http.StatusOK,
+			response: `{"choices":[{"message":{"content":"{"}}]}`,
+			accepted: true,
+		},
+		{
+			name:            "rejected",
+			status:          http.StatusBadRequest,
+			response:        `{"error":{"message":"Invalid schema for response_format 'live_address'"}}`,
+			providerMessage: "Invalid schema for response_format 'live_address'",
+		},
+		{
+			name:            "rejected without envelope",
+			status:          http.StatusBadRequest,
+			response:        "bad request\n",
+			providerMessage: "bad request",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request map[string]interface{}
+			var auth string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/chat/completions" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				auth = r.Header.Get("Authorization")
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+					t.Errorf("invalid request body: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			config := LiveCheckConfig{BaseURL: server.URL + "/v1/", APIKey: "test-key", Model: "test-model"}
+			result, err := CheckLive(context.Background(), config, "live_address", LiveAddress{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Accepted != tt.accepted || result.StatusCode != tt.status || result.ProviderMessage != tt.providerMessage {
+				t.Errorf("unexpected result %+v", result)
+			}
+			if auth != "Bearer test-key" {
+				t.Errorf("expected bearer token, got %q", auth)
+			}
+			if request["model"] != "test-model" || request["max_tokens"] != float64(1) {
+				t.Errorf("unexpected request %v", request)
+			}
+			format, _ := request["response_format"].(map[string]interface{})
+			spec, _ := format["json_schema"].(map[string]interface{})
+			if format["type"] != "json_schema" || spec["name"] != "live_address" || spec["strict"] != true || spec["schema"] == nil {
+				t.Errorf("unexpected response_format %v", request["response_format"])
+			}
+		})
+	}
+}
+
+func TestCheckLive_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"Incorrect API key provided"}}`))
+	}))
+	defer server.Close()
+
+	config := LiveCheckConfig{BaseURL: server.URL, APIKey: "wrong"}
+	if _, err := CheckLive(context.Background(), config, "live_address", LiveAddress{}); err == nil {
+		t.Errorf("expected error for an unauthorized request")
+	}
+	if _, err := CheckLive(context.Background(), config, "live address", LiveAddress{}); err == nil {
+		t.Errorf("expected error for an invalid name")
+	}
+}
@@ -0,0 +1,162 @@
+package gptschema
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Loader loads the schema documents referenced by external $refs, such as
+// "address.json#/$defs/Address" or "https://example.com/schemas/order.json".
+// uri is the reference without its fragment, resolved against the document containing it.
+type Loader interface {
+	Load(uri string) (internal.Schema, error)
+}
+
+// LoaderFunc adapts a function to the Loader interface.
+type LoaderFunc func(uri string) (internal.Schema, error)
+
+// Load calls f(uri).
+func (f LoaderFunc) Load(uri string) (internal.Schema, error) {
+	return f(uri)
+}
+
+// FileLoader loads schema documents from the file system, relative paths and file://
+// URIs are resolved against Dir.
+type FileLoader struct {
+	Dir string
+}
+
+// Load reads and decodes the JSON schema file at uri.
+func (l FileLoader) Load(uri string) (internal.Schema, error) {
+	name := filepath.FromSlash(strings.TrimPrefix(uri, "file://"))
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(l.Dir, name)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSchemaDocument(uri, data)
+}
+
+// HTTPLoader loads schema documents over HTTP(S) with Client, or http.DefaultClient when nil.
+type HTTPLoader struct {
+	Client *http.Client
+}
+
+// Load fetches and decodes the JSON schema document at uri.
+func (l HTTPLoader) Load(uri string) (internal.Schema, error) {
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", uri, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSchemaDocument(uri, data)
+}
+
+// decodeSchemaDocument parses a loaded document like the other inbound schemas, so
+// keywords such as required hold []string
+func decodeSchemaDocument(uri string, data []byte) (internal.Schema, error) {
+	schema, err := internal.ParseSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", uri, err)
+	}
+	return schema, nil
+}
+
+// CachedLoader returns a Loader loading every document once and sharing it across calls,
+// which avoids fetching the same documents again for every schema. It is safe for
+// concurrent use: different documents load in parallel, and concurrent loads of the same
+// document wait for a single call of loader. Failed loads are not cached. Within a single
+// Inline call each document is loaded once even without a cache.
+func CachedLoader(loader Loader) Loader {
+	return &cachedLoader{
+		loader:  loader,
+		docs:    make(map[string]internal.Schema),
+		loading: make(map[string]*pendingLoad),
+	}
+}
+
+type cachedLoader struct {
+	loader  Loader
+	mu      sync.Mutex
+	docs    map[string]internal.Schema
+	loading map[string]*pendingLoad
+}
+
+// pendingLoad is a load in progress, done is closed once doc and err are set
+type pendingLoad struct {
+	done chan struct{}
+	doc  internal.Schema
+	err  error
+}
+
+func (c *cachedLoader) Load(uri string) (internal.Schema, error) {
+	c.mu.Lock()
+	if doc, ok := c.docs[uri]; ok {
+		c.mu.Unlock()
+		return doc, nil
+	}
+	if pending, ok := c.loading[uri]; ok {
+		c.mu.Unlock()
+		<-pending.done
+		return pending.doc, pending.err
+	}
+	pending := &pendingLoad{done: make(chan struct{}), err: fmt.Errorf("loading %s did not complete", uri)}
+	c.loading[uri] = pending
+	c.mu.Unlock()
+	// the I/O runs without the lock, a panicking loader still releases the waiters
+	defer func() {
+		c.mu.Lock()
+		delete(c.loading, uri)
+		if pending.err == nil {
+			c.docs[uri] = pending.doc
+		}
+		c.mu.Unlock()
+		close(pending.done)
+	}()
+	pending.doc, pending.err = c.loader.Load(uri)
+	return pending.doc, pending.err
+}
+
+// WithRefLoader resolves external $refs with loader in Inline and Bundle, and the references
+// of schemaRef tags in GenerateSchema (see RegisterSchemaRef). Without a loader only
+// references within the schema can be resolved. Relative references are resolved
+// against the root $id when present, and against the document containing them.
+//
+// Example:
+//
+//	loader := CachedLoader(LoaderFunc(func(uri string) (internal.Schema, error) {
+//	    if strings.HasPrefix(uri, "https://") {
+//	        return HTTPLoader{}.Load(uri)
+//	    }
+//	    return FileLoader{Dir: "schemas"}.Load(uri)
+//	}))
+//	inlined, err := Inline(schema, WithRefLoader(loader))
+func WithRefLoader(loader Loader) Option {
+	return func(opts *internal.Options) {
+		if loader == nil {
+			opts.AddError("ref loader must not be nil")
+			return
+		}
+		opts.RefLoader = loader.Load
+	}
+}
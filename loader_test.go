@@ -0,0 +1,160 @@
+package gptschema
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestInline_FileLoader(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"order.json":          `{"type":"object","properties":{"shipping":{"$ref":"common/address.json#/$defs/Address"}}}`,
+		"common/address.json": `{"$defs":{"Address":{"type":"object","properties":{"city":{"$ref":"city.json"}}}}}`,
+		"common/city.json":    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"string"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	loader := FileLoader{Dir: dir}
+	order, err := loader.Load("order.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inlined, err := Inline(&order, WithRefLoader(loader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := internal.Schema{
+		"type": "object",
+		"properties": internal.Schema{
+			"shipping": internal.Schema{
+				"type":       "object",
+				"properties": internal.Schema{"city": internal.Schema{"type": "string"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(*inlined, expected) {
+		t.Errorf("expected %+v, got %+v", expected, *inlined)
+	}
+
+	if _, err := Inline(&order); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema without a loader, got %v", err)
+	}
+	if _, err := Inline(&order, WithRefLoader(nil)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption for a nil loader, got %v", err)
+	}
+}
+
+func TestInline_HTTPLoader(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/schemas/tag.json":
+			_, _ = w.Write([]byte(`{"type":"string","maxLength":20}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	schema := internal.Schema{
+		"$id":  server.URL + "/schemas/post.json",
+		"type": "object",
+		"properties": internal.Schema{
+			"tags":  internal.Schema{"type": "array", "items": internal.Schema{"$ref": "tag.json"}},
+			"topic": internal.Schema{"$ref": "tag.json"},
+		},
+	}
+	loader := CachedLoader(HTTPLoader{Client: server.Client()})
+	for i := 0; i < 2; i++ {
+		inlined, err := Inline(&schema, WithRefLoader(loader))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		topic := (*inlined)["properties"].(internal.Schema)["topic"]
+		if expected := (internal.Schema{"type": "string", "maxLength": 20}); !reflect.DeepEqual(topic, expected) {
+			t.Errorf("expected %+v, got %+v", expected, topic)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected the cached loader to fetch once, got %d requests", requests)
+	}
+
+	missing := internal.Schema{"$ref": server.URL + "/missing.json"}
+	if _, err := Inline(&missing, WithRefLoader(HTTPLoader{Client: server.Client()})); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for a missing document, got %v", err)
+	}
+}
+
+func TestCachedLoader_Concurrent(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	loader := CachedLoader(LoaderFunc(func(uri string) (internal.Schema, error) {
+		mu.Lock()
+		calls[uri]++
+		mu.Unlock()
+		switch uri {
+		case "slow.json":
+			<-release
+		case "broken.json":
+			return nil, errors.New("unreachable")
+		}
+		return internal.Schema{"type": "string"}, nil
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := loader.Load("slow.json"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	// another document loads while the slow one is in progress
+	fast := make(chan error, 1)
+	go func() {
+		_, err := loader.Load("fast.json")
+		fast <- err
+	}()
+	select {
+	case err := <-fast:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the fast document to load while the slow one is pending")
+	}
+	close(release)
+	wg.Wait()
+	if _, err := loader.Load("slow.json"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := loader.Load("broken.json"); err == nil {
+			t.Errorf("expected the failed load to be reported")
+		}
+	}
+	expected := map[string]int{"slow.json": 1, "fast.json": 1, "broken.json": 2}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected loads %v, got %v", expected, calls)
+	}
+}
@@ -0,0 +1,59 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Translations holds localized descriptions by locale, then by field path as listed by
+// Paths, e.g. {"fr": {"title": "Titre de l'article", "items[].price": "Prix unitaire"}}.
+// The empty path describes the schema itself.
+type Translations = internal.Translations
+
+// LoadTranslations reads translations from a JSON file mapping locales to field paths to
+// descriptions. Translations kept in YAML can be decoded with any YAML package into a
+// Translations value, which has the same shape.
+//
+// Example:
+//
+//	// descriptions.json: {"fr": {"title": "Titre de l'article"}, "ja": {"title": "記事のタイトル"}}
+//	translations, err := LoadTranslations("descriptions.json")
+func LoadTranslations(name string) (Translations, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var translations Translations
+	if err := json.Unmarshal(data, &translations); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", name, err)
+	}
+	return translations, nil
+}
+
+// WithLocale describes fields with their translations for locale, so the same structs can
+// prompt models in several languages. A translation replaces the whole description of its
+// field, including the length hints and sensitive notes derived from tags; fields without a
+// translation keep their description. Regional locales such as "pt-BR" fall back to their
+// language, "pt", for the paths they do not translate. Paths matching no field are reported
+// to the warning handler, and a locale missing from translations is rejected with
+// ErrInvalidOption.
+//
+// Example:
+//
+//	translations, err := LoadTranslations("descriptions.json")
+//	schema, err := GenerateSchema(Article{}, WithLocale(translations, "fr"))
+//	// {"title": {"type": "string", "description": "Titre de l'article"}, ...}
+func WithLocale(translations Translations, locale string) Option {
+	return func(opts *internal.Options) {
+		descriptions, ok := translations.Lookup(locale)
+		if !ok {
+			opts.AddError("no translations for locale %q", locale)
+			return
+		}
+		opts.Locale = locale
+		opts.LocalizedDescriptions = descriptions
+	}
+}
@@ -0,0 +1,55 @@
+package gptschema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithLocale(t *testing.T) {
+	type Article struct {
+		Title string   `json:"title" description:"Title of the article"`
+		Tags  []string `json:"tags" description:"Topics"`
+	}
+	name := filepath.Join(t.TempDir(), "descriptions.json")
+	data := `{"fr": {"title": "Titre de l'article", "tags[]": "Un sujet"}, "pt": {"title": "Título"}}`
+	if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	translations, err := LoadTranslations(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		locale string
+		title  interface{}
+		tags   interface{}
+		items  interface{}
+	}{
+		{"fr", "Titre de l'article", "Topics", "Un sujet"},
+		{"pt-BR", "Título", "Topics", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			schema, err := GenerateSchema(Article{}, WithLocale(translations, tt.locale))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for path, expected := range map[string]interface{}{"title": tt.title, "tags": tt.tags, "tags[]": tt.items} {
+				field, _ := schema.At(path)
+				if field["description"] != expected {
+					t.Errorf("%s: expected description %v, got %v", path, expected, field["description"])
+				}
+			}
+		})
+	}
+
+	if _, err := GenerateSchema(Article{}, WithLocale(translations, "de")); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption for a missing locale, got %v", err)
+	}
+	if _, err := LoadTranslations(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,99 @@
+package gptschema
+
+import "github.com/akane9506/gptschema/internal"
+
+// Partial is a typed view of a response of type T while it streams, for progressive
+// rendering: the fields whose value is complete are populated, the others are left zero or
+// nil. Objects and arrays still streaming hold what is complete so far, e.g. the finished
+// items of a list. Completed reports which fields are complete, since a zero value does not
+// tell a missing field from an empty one. A Partial decodes a single response and is not
+// safe for concurrent use.
+type Partial[T any] struct {
+	options *internal.Options
+	doc     *internal.PartialDocument
+	fields  []string
+}
+
+// NewPartial creates the view of a response generated from the schema of T with the same
+// options, which are also used to decode it like Unmarshal.
+//
+// Example:
+//
+//	partial, err := NewPartial[Article]()
+//	for stream.Next() {
+//	    _, _ = partial.Write([]byte(stream.Current().Choices[0].Delta.Content))
+//	    article, _ := partial.Value()
+//	    ui.Render(article, partial.Completed()) // the title shows while the body streams
+//	}
+//	err = partial.Close()
+func NewPartial[T any](opts ...Option) (*Partial[T], error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	var zero T
+	schema, err := GenerateSchema(zero, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Partial[T]{
+		options: options,
+		doc:     internal.NewPartialDocument(nil),
+		fields:  schema.PropertyNames(),
+	}, nil
+}
+
+// Write parses the next chunk of the response. A syntax error is returned by this and every
+// later call.
+func (p *Partial[T]) Write(chunk []byte) (int, error) {
+	return p.doc.Write(chunk)
+}
+
+// Close ends the response, a response that is not complete JSON returns ErrIncompleteJSON.
+func (p *Partial[T]) Close() error {
+	return p.doc.Close()
+}
+
+// Value returns a new T holding the values completed so far. An error is returned for a
+// completed value that cannot be decoded into its field, as by Unmarshal.
+func (p *Partial[T]) Value() (T, error) {
+	var value T
+	if p.doc.Value() == nil {
+		return value, nil
+	}
+	err := decodeValue(p.doc.Value(), &value, p.options)
+	return value, err
+}
+
+// Fields returns the top-level properties of T, the required ones first in declaration
+// order, indexing the result of Completed.
+func (p *Partial[T]) Fields() []string {
+	return append([]string(nil), p.fields...)
+}
+
+// Completed returns the completeness of each top-level property, in the order of Fields.
+//
+// Example:
+//
+//	for i, done := range partial.Completed() {
+//	    if !done {
+//	        ui.ShowSpinner(partial.Fields()[i])
+//	    }
+//	}
+func (p *Partial[T]) Completed() []bool {
+	completed := make([]bool, len(p.fields))
+	for i, name := range p.fields {
+		completed[i] = p.doc.Complete(name)
+	}
+	return completed
+}
+
+// Complete reports whether the value at path is complete, in the path syntax of
+// ValidationError; "[]" matches any index.
+//
+// Example:
+//
+//	if partial.Complete("sections[2]") { ... }
+func (p *Partial[T]) Complete(path string) bool {
+	return p.doc.Complete(path)
+}
@@ -0,0 +1,78 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type partialSection struct {
+	Heading string `json:"heading"`
+	Body    string `json:"body"`
+}
+
+type partialArticle struct {
+	Title    string           `json:"title"`
+	Sections []partialSection `json:"sections"`
+	Words    int64            `json:"words"`
+}
+
+func TestPartial(t *testing.T) {
+	partial, err := NewPartial[partialArticle](WithInt64AsString(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields := partial.Fields(); !reflect.DeepEqual(fields, []string{"title", "sections", "words"}) {
+		t.Errorf("unexpected fields %v", fields)
+	}
+	steps := []struct {
+		chunk     string
+		expected  partialArticle
+		completed []bool
+	}{
+		{`{"title": "Tid`, partialArticle{}, []bool{false, false, false}},
+		{`es", "sections": [{"heading": "Why", "body": "The mo`, partialArticle{
+			Title:    "Tides",
+			Sections: []partialSection{{Heading: "Why"}},
+		}, []bool{true, false, false}},
+		{`on"}, {"heading": "How"}], "words": "1`, partialArticle{
+			Title:    "Tides",
+			Sections: []partialSection{{Heading: "Why", Body: "The moon"}, {Heading: "How"}},
+		}, []bool{true, true, false}},
+		{`2"}`, partialArticle{
+			Title:    "Tides",
+			Sections: []partialSection{{Heading: "Why", Body: "The moon"}, {Heading: "How"}},
+			Words:    12,
+		}, []bool{true, true, true}},
+	}
+	for i, step := range steps {
+		if _, err := partial.Write([]byte(step.chunk)); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		value, err := partial.Value()
+		if err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(value, step.expected) {
+			t.Errorf("step %d: expected %+v, got %+v", i, step.expected, value)
+		}
+		if completed := partial.Completed(); !reflect.DeepEqual(completed, step.completed) {
+			t.Errorf("step %d: expected completeness %v, got %v", i, step.completed, completed)
+		}
+	}
+	if !partial.Complete("sections[1].heading") || partial.Complete("sections[1].body") {
+		t.Errorf("expected the second heading to be complete and its body to be missing")
+	}
+	if err := partial.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	truncated, _ := NewPartial[partialArticle]()
+	_, _ = truncated.Write([]byte(`{"title": "Tides"`))
+	if err := truncated.Close(); !errors.Is(err, ErrIncompleteJSON) {
+		t.Errorf("expected ErrIncompleteJSON, got %v", err)
+	}
+	if _, err := NewPartial[chan int](); err == nil {
+		t.Errorf("expected an error for a type without a schema")
+	}
+}
@@ -0,0 +1,22 @@
+package gptschema
+
+import "github.com/akane9506/gptschema/internal"
+
+// LeafPath describes a scalar value of the documents matching a schema, as returned by the
+// Paths and RequiredPaths methods of generated schemas. Column mappings, CSV exporters and
+// eval matchers can be built from them, so they follow the same contract as the model.
+//
+// Paths lists every leaf, descending into nested objects, array items ("[]") and map
+// values ("{}"), with its JSON type and nullability. RequiredPaths keeps the leaves whose
+// property and enclosing properties are all required. The At method returns the schema
+// declared at one of these paths, or at an intermediate one such as "companies[].address",
+// to validate or document part of a response.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Employee{})
+//	for _, path := range schema.Paths() {
+//	    fmt.Println(path.Path, path.Type, path.Nullable) // companies[].address.city string false
+//	}
+//	address, ok := schema.At("companies[].address")
+type LeafPath = internal.LeafPath
@@ -0,0 +1,63 @@
+package gptschema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestSchemaPaths(t *testing.T) {
+	type Row struct {
+		ID    int      `json:"id"`
+		Note  *string  `json:"note,omitempty" required:"false"`
+		Tags  []string `json:"tags"`
+		Price float64  `json:"price"`
+	}
+	schema, err := GenerateSchema(Row{}, WithStrict(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var paths []string
+	for _, path := range schema.Paths() {
+		paths = append(paths, path.Path+":"+path.Type)
+	}
+	expected := "id:integer tags[]:string price:number note:string"
+	if got := strings.Join(paths, " "); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	required := schema.RequiredPaths()
+	if len(required) != 3 || required[2].Path != "price" {
+		t.Errorf("expected the required leaves, got %+v", required)
+	}
+
+	employee, _ := GenerateSchema(internal.Employee{})
+	var zip LeafPath
+	for _, path := range employee.Paths() {
+		if path.Path == "companies[].address.zip_code" {
+			zip = path
+		}
+	}
+	if zip.Type != "string" || !zip.Nullable || !zip.Required {
+		t.Errorf("unexpected zip code leaf %+v", zip)
+	}
+}
+
+func TestSchemaAt(t *testing.T) {
+	schema, err := GenerateSchema(internal.Employee{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	address, ok := schema.At("companies[].address")
+	if !ok || !reflect.DeepEqual(address, internal.AddressSchema) {
+		t.Errorf("expected %+v, got %+v", internal.AddressSchema, address)
+	}
+	mismatches, err := Validate(&address, []byte(`{"street":"Main","city":"Oslo","zip_code":null}`))
+	if err != nil || len(mismatches) != 0 {
+		t.Errorf("expected the subschema to validate part of a response, got %v %v", mismatches, err)
+	}
+	if _, ok := schema.At("companies[].phone"); ok {
+		t.Errorf("expected an unknown path not to be found")
+	}
+}
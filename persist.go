@@ -0,0 +1,180 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// RegistryBundle holds the schemas of a registry persisted with Registry.Save, so services
+// can ship precomputed schemas and check at startup that their structs still match them.
+type RegistryBundle struct {
+	Entries []BundleEntry `json:"schemas"`
+}
+
+// BundleEntry is a schema persisted in a RegistryBundle.
+type BundleEntry struct {
+	Name string `json:"name"`
+	// Version is set for entries registered with RegisterVersion
+	Version int `json:"version,omitempty"`
+	// Type is the Go type the schema was generated from, e.g. "models.Invoice"
+	Type        string           `json:"type"`
+	Fingerprint string           `json:"fingerprint"`
+	Schema      *internal.Schema `json:"schema"`
+}
+
+// Save writes every schema of the registry to a JSON file with its name, version, Go type
+// and fingerprint, in registration order with the versions of a name in ascending order.
+// Load the file with LoadRegistryBundle.
+//
+// Example:
+//
+//	if err := Contracts().Save("schemas.bundle.json"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (r *Registry) Save(name string) error {
+	var bundle RegistryBundle
+	for _, entry := range r.contracts() {
+		fingerprint, err := Fingerprint(entry.Schema)
+		if err != nil {
+			return fmt.Errorf("save %q: %w", contractKey(entry), err)
+		}
+		bundle.Entries = append(bundle.Entries, BundleEntry{
+			Name:        entry.Name,
+			Version:     entry.Version,
+			Type:        entry.Type.String(),
+			Fingerprint: fingerprint,
+			Schema:      entry.Schema,
+		})
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal registry bundle to JSON: %w", err)
+	}
+	return os.WriteFile(name, append(data, '\n'), 0o644)
+}
+
+// LoadRegistryBundle reads a bundle written by Registry.Save. A schema that no longer
+// matches its recorded fingerprint, e.g. after an edit by hand, is rejected with
+// ErrInvalidSchema.
+//
+// Example:
+//
+//	bundle, err := LoadRegistryBundle("schemas.bundle.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if err := bundle.Verify(Contracts()); err != nil {
+//	    log.Fatal(err) // the structs changed since the bundle was built
+//	}
+func LoadRegistryBundle(name string) (*RegistryBundle, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var wire struct {
+		Entries []struct {
+			BundleEntry
+			Schema json.RawMessage `json:"schema"`
+		} `json:"schemas"`
+	}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", name, err)
+	}
+	bundle := &RegistryBundle{Entries: make([]BundleEntry, len(wire.Entries))}
+	for i, decoded := range wire.Entries {
+		entry := decoded.BundleEntry
+		key := contractKey(&RegistryEntry{Name: entry.Name, Version: entry.Version})
+		schema, err := internal.ParseSchema(decoded.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %q: %v", ErrInvalidSchema, name, key, err)
+		}
+		entry.Schema = &schema
+		if fingerprint, err := Fingerprint(entry.Schema); err != nil || fingerprint != entry.Fingerprint {
+			return nil, fmt.Errorf("%w: %s: %q does not match its fingerprint", ErrInvalidSchema, name, key)
+		}
+		bundle.Entries[i] = entry
+	}
+	return bundle, nil
+}
+
+// Get returns the entry persisted under name, the latest version for versioned names.
+func (b *RegistryBundle) Get(name string) (*BundleEntry, bool) {
+	var found *BundleEntry
+	for i, entry := range b.Entries {
+		if entry.Name == name && (found == nil || entry.Version > found.Version) {
+			found = &b.Entries[i]
+		}
+	}
+	return found, found != nil
+}
+
+// Fingerprints returns the fingerprint of every persisted schema, keyed like
+// Registry.Fingerprints.
+func (b *RegistryBundle) Fingerprints() map[string]string {
+	fingerprints := make(map[string]string, len(b.Entries))
+	for _, entry := range b.Entries {
+		fingerprints[contractKey(&RegistryEntry{Name: entry.Name, Version: entry.Version})] = entry.Fingerprint
+	}
+	return fingerprints
+}
+
+// Verify compares the schemas generated from the running structs with the bundle and
+// returns a *ContractError listing the changed, removed and unpersisted schemas, like
+// VerifyFingerprints.
+func (b *RegistryBundle) Verify(registry *Registry) error {
+	return VerifyFingerprints(registry, b.Fingerprints())
+}
+
+// ContractBundleName is the bundle VerifyContracts reads in its directory.
+const ContractBundleName = "contracts.bundle.json"
+
+// VerifyContracts regenerates the schemas of the registry, as registering them did, and
+// compares them with the bundle persisted in dir under ContractBundleName with
+// Registry.Save. It fails fast with a *ContractError when a persisted contract changed
+// without a version bump, or is no longer registered; the error lists the differences of
+// each changed schema. Contracts not persisted yet, such as a new version, are accepted.
+// Call it at startup, or from a test, once the registry is populated.
+//
+// Example:
+//
+//	// at release time: Contracts().Save("contracts/" + ContractBundleName)
+//	if err := VerifyContracts(Contracts(), "contracts"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	// schema contract changed (changed: invoice@v2), bump the contract version or save the registry again if the change is intended
+//	//   invoice@v2: currency: added required property (string)
+func VerifyContracts(registry *Registry, dir string) error {
+	bundle, err := LoadRegistryBundle(filepath.Join(dir, ContractBundleName))
+	if err != nil {
+		return err
+	}
+	var contractErr *ContractError
+	if err := bundle.Verify(registry); !errors.As(err, &contractErr) {
+		return err
+	}
+	contractErr.Added = nil
+	if len(contractErr.Changed)+len(contractErr.Missing) == 0 {
+		return nil
+	}
+	contractErr.hint = "bump the contract version or save the registry again if the change is intended"
+	persisted := make(map[string]*internal.Schema, len(bundle.Entries))
+	for _, entry := range bundle.Entries {
+		persisted[contractKey(&RegistryEntry{Name: entry.Name, Version: entry.Version})] = entry.Schema
+	}
+	changed := make(map[string]bool, len(contractErr.Changed))
+	for _, key := range contractErr.Changed {
+		changed[key] = true
+	}
+	contractErr.Changes = make(map[string][]SchemaChange, len(changed))
+	for _, entry := range registry.contracts() {
+		if key := contractKey(entry); changed[key] {
+			contractErr.Changes[key] = Diff(persisted[key], entry.Schema)
+		}
+	}
+	return contractErr
+}
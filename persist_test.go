@@ -0,0 +1,124 @@
+package gptschema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestRegistry_SaveAndLoad(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("employee", internal.Employee{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for version, v := range map[int]interface{}{2: InvoiceV2{}, 1: InvoiceV1{}} {
+		if err := registry.RegisterVersion("invoice", version, v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	name := filepath.Join(t.TempDir(), "schemas.bundle.json")
+	if err := registry.Save(name); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bundle, err := LoadRegistryBundle(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var keys []string
+	for _, entry := range bundle.Entries {
+		keys = append(keys, entry.Type+" "+contractKey(&RegistryEntry{Name: entry.Name, Version: entry.Version}))
+	}
+	expected := []string{"internal.Employee employee", "gptschema.InvoiceV1 invoice@v1", "gptschema.InvoiceV2 invoice@v2"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected entries %v, got %v", expected, keys)
+	}
+	fingerprints, _ := registry.Fingerprints()
+	if !reflect.DeepEqual(bundle.Fingerprints(), fingerprints) {
+		t.Errorf("expected fingerprints %v, got %v", fingerprints, bundle.Fingerprints())
+	}
+	if entry, ok := bundle.Get("invoice"); !ok || entry.Version != 2 {
+		t.Errorf("expected the latest invoice version, got %+v", entry)
+	}
+	if employee, _ := bundle.Get("employee"); !reflect.DeepEqual(employee.Schema.Paths(), internal.EmployeeSchema.Paths()) {
+		t.Errorf("expected the employee schema, got %+v", employee.Schema)
+	}
+	if err := bundle.Verify(registry); err != nil {
+		t.Errorf("expected the registry to match its bundle, got %v", err)
+	}
+
+	// the running structs changed since the bundle was saved
+	changed := NewRegistry()
+	if err := changed.Register("employee", internal.DescribedContact{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var contractErr *ContractError
+	if err := bundle.Verify(changed); !errors.As(err, &contractErr) ||
+		!reflect.DeepEqual(contractErr.Changed, []string{"employee"}) ||
+		!reflect.DeepEqual(contractErr.Missing, []string{"invoice@v1", "invoice@v2"}) {
+		t.Errorf("expected employee to change and invoice to be missing, got %v", err)
+	}
+
+	// a schema edited by hand no longer matches its fingerprint
+	data, _ := os.ReadFile(name)
+	edited := strings.Replace(string(data), `"currency"`, `"currency_code"`, 1)
+	if err := os.WriteFile(name, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRegistryBundle(name); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, got %v", err)
+	}
+}
+
+func TestVerifyContracts(t *testing.T) {
+	dir := t.TempDir()
+	persisted := NewRegistry()
+	if err := persisted.Register("employee", internal.Employee{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := persisted.RegisterVersion("invoice", 1, InvoiceV1{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := persisted.Save(filepath.Join(dir, ContractBundleName)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := VerifyContracts(persisted, dir); err != nil {
+		t.Errorf("expected the contracts to match, got %v", err)
+	}
+
+	// a new version is a contract bump, not a change
+	bumped := NewRegistry()
+	_ = bumped.Register("employee", internal.Employee{})
+	_ = bumped.RegisterVersion("invoice", 1, InvoiceV1{})
+	_ = bumped.RegisterVersion("invoice", 2, InvoiceV2{})
+	if err := VerifyContracts(bumped, dir); err != nil {
+		t.Errorf("expected a new version to be accepted, got %v", err)
+	}
+
+	// version 1 changed in place
+	changed := NewRegistry()
+	_ = changed.Register("employee", internal.Employee{})
+	_ = changed.RegisterVersion("invoice", 1, InvoiceV2{})
+	err := VerifyContracts(changed, dir)
+	var contractErr *ContractError
+	if !errors.As(err, &contractErr) {
+		t.Fatalf("expected a ContractError, got %v", err)
+	}
+	if !reflect.DeepEqual(contractErr.Changed, []string{"invoice@v1"}) || len(contractErr.Missing)+len(contractErr.Added) != 0 {
+		t.Errorf("expected invoice@v1 to change, got %+v", contractErr)
+	}
+	expected := "schema contract changed (changed: invoice@v1), bump the contract version or save the registry again if the change is intended\n" +
+		"  invoice@v1: currency: added required property (string)"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	if err := VerifyContracts(persisted, t.TempDir()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a missing bundle to be reported, got %v", err)
+	}
+}
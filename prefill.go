@@ -0,0 +1,30 @@
+package gptschema
+
+import (
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Prefill returns the opening of every valid response to a schema, e.g. `{"name": "`, to
+// send as the final assistant turn with Anthropic models, which continue from it and keep
+// to JSON more reliably than when starting from scratch. It follows the required properties
+// in order while their value is known, such as a discriminator constant, and stops at the
+// first value that can take several forms. Trailing whitespace is trimmed, as Anthropic
+// rejects prefills ending with it. The response does not repeat the prefill; decode and
+// validate the prefill followed by the response.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Contact{})
+//	prefill, err := Prefill(schema) // {"name": "
+//	messages = append(messages, anthropic.NewAssistantMessage(anthropic.NewTextBlock(prefill)))
+//	// ... send the messages ...
+//	var contact Contact
+//	err = Unmarshal([]byte(prefill+content), &contact)
+func Prefill(schema *internal.Schema) (string, error) {
+	if schema == nil {
+		return "", fmt.Errorf("%w: cannot prefill a nil schema", ErrInvalidSchema)
+	}
+	return internal.RenderPrefill(*schema), nil
+}
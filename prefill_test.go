@@ -0,0 +1,36 @@
+package gptschema
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrefill(t *testing.T) {
+	type Contact struct {
+		Name  string   `json:"name"`
+		Email string   `json:"email"`
+		Tags  []string `json:"tags"`
+	}
+	schema, err := GenerateSchema(Contact{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prefill, err := Prefill(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prefill != `{"name": "` {
+		t.Errorf("expected the opening of the name, got %q", prefill)
+	}
+	response := []byte(prefill + `Ada", "email": "ada@example.com", "tags": []}`)
+	if mismatches, err := Validate(schema, response); err != nil || len(mismatches) != 0 {
+		t.Fatalf("expected the prefilled response to be valid, got %v %v", mismatches, err)
+	}
+	var contact Contact
+	if err := Unmarshal(response, &contact); err != nil || contact.Name != "Ada" {
+		t.Errorf("expected the prefilled response to decode, got %+v %v", contact, err)
+	}
+	if _, err := Prefill(nil); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for a nil schema, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package gptschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Projection pairs the schema of T pruned with Select with the decoding of its responses
+// into a full T, so one struct backs every extraction request while the model is only
+// asked for some of its fields. A Projection is safe for concurrent use.
+type Projection[T any] struct {
+	schema  *internal.Schema
+	options *internal.Options
+}
+
+// NewProjection generates the schema of T with the options, which are also used to decode
+// responses like Unmarshal, and keeps only the properties at paths as by Select.
+//
+// Example:
+//
+//	totals, err := NewProjection[Invoice]([]string{"number", "lines[].amount"})
+//	// ... send totals.Schema() to the model ...
+//	var invoice Invoice
+//	populated, err := totals.Unmarshal([]byte(content), &invoice)
+//	if populated.Has("lines[].amount") { ... }
+func NewProjection[T any](paths []string, opts ...Option) (*Projection[T], error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	var zero T
+	schema, err := GenerateSchema(zero, opts...)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := Select(schema, paths...)
+	if err != nil {
+		return nil, err
+	}
+	return &Projection[T]{schema: selected, options: options}, nil
+}
+
+// Schema returns the pruned schema to send to the model.
+func (p *Projection[T]) Schema() *internal.Schema {
+	return p.schema
+}
+
+// Unmarshal resets v and decodes a response to the pruned schema into it, like Unmarshal.
+// The fields the response does not hold are left at their zero value, and the paths of the
+// values it holds are returned.
+func (p *Projection[T]) Unmarshal(data []byte, v *T) (Populated, error) {
+	var zero T
+	*v = zero
+	var populated Populated
+	parser := internal.NewStreamParser(func(path string, _ []byte) {
+		if path != "" {
+			populated = append(populated, path)
+		}
+	})
+	if _, err := parser.Write(data); err != nil {
+		return nil, err
+	}
+	if err := parser.Close(); err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if err := decodeValue(raw, v, p.options); err != nil {
+		return nil, err
+	}
+	sort.Strings(populated)
+	return populated, nil
+}
+
+// Populated lists the paths of the values a response held, sorted, in the path syntax of
+// ValidationError, e.g. "lines[1].amount". A null value is populated.
+type Populated []string
+
+// Has reports whether the response held a value at path, "[]" matching any index, e.g.
+// "lines[].amount" once one line holds an amount.
+func (p Populated) Has(path string) bool {
+	for _, populated := range p {
+		if internal.MatchStreamPath(path, populated) {
+			return true
+		}
+	}
+	return false
+}
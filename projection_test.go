@@ -0,0 +1,78 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type projectionLine struct {
+	SKU    string  `json:"sku"`
+	Amount float64 `json:"amount"`
+}
+
+type projectionInvoice struct {
+	Number string           `json:"number"`
+	Notes  *string          `json:"notes"`
+	Lines  []projectionLine `json:"lines"`
+	Total  int64            `json:"total"`
+}
+
+func TestProjection(t *testing.T) {
+	projection, err := NewProjection[projectionInvoice]([]string{"notes", "lines[].amount", "total"}, WithInt64AsString(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema := projection.Schema()
+	if _, ok := schema.At("number"); ok {
+		t.Errorf("expected number to be pruned from the schema")
+	}
+	if _, ok := schema.At("lines[].sku"); ok {
+		t.Errorf("expected lines[].sku to be pruned from the schema")
+	}
+	response := []byte(`{"notes": "paid", "lines": [{"amount": 2.5}, {"amount": 4}], "total": "7"}`)
+	if mismatches, err := Validate(schema, response); err != nil || len(mismatches) != 0 {
+		t.Fatalf("expected the response to match the pruned schema, got %v %v", mismatches, err)
+	}
+	invoice := projectionInvoice{Number: "stale", Lines: []projectionLine{{SKU: "stale"}}}
+	populated, err := projection.Unmarshal(response, &invoice)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notes := "paid"
+	expected := projectionInvoice{Notes: &notes, Lines: []projectionLine{{Amount: 2.5}, {Amount: 4}}, Total: 7}
+	if !reflect.DeepEqual(invoice, expected) {
+		t.Errorf("expected %+v, got %+v", expected, invoice)
+	}
+	expectedPaths := Populated{"lines", "lines[0]", "lines[0].amount", "lines[1]", "lines[1].amount", "notes", "total"}
+	if !reflect.DeepEqual(populated, expectedPaths) {
+		t.Errorf("expected populated paths %v, got %v", expectedPaths, populated)
+	}
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"notes", true},
+		{"lines[].amount", true},
+		{"lines[1].amount", true},
+		{"lines[2].amount", false},
+		{"lines[].sku", false},
+		{"number", false},
+	}
+	for _, tt := range tests {
+		if got := populated.Has(tt.path); got != tt.expected {
+			t.Errorf("Has(%q): expected %v, got %v", tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestProjection_Invalid(t *testing.T) {
+	if _, err := NewProjection[projectionInvoice]([]string{"missing"}); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for an unknown path, got %v", err)
+	}
+	projection, _ := NewProjection[projectionInvoice]([]string{"total"})
+	var invoice projectionInvoice
+	if _, err := projection.Unmarshal([]byte(`{"total": 1`), &invoice); !errors.Is(err, ErrIncompleteJSON) {
+		t.Errorf("expected ErrIncompleteJSON for a truncated response, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package gptschema
+
+import (
+	"fmt"
+	"reflect"
+	"text/template"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// CheckPromptTemplate checks a text/template prompt against the schema of v, to catch drift
+// between prompts and response contracts at test time. The template is walked as if
+// executed with a value of v's type, following range, with, variables and invoked
+// templates. Fields are matched by Go name, as text/template does for structs, or by
+// property name, as it does for decoded JSON. It reports:
+//
+//   - unknown-field: a reference to a field v does not have, or excluded with json:"-",
+//     with the template location, e.g. "prompt:3:14: Ticket has no field Sumary"; the
+//     suggestion is the field differing only by case, if any
+//   - unreferenced-field: a field of the schema the template never references, neither
+//     directly, through a nested field, nor by printing an enclosing value as a whole
+//
+// References through method calls, functions other than piped values, and variables of
+// unknown type are not checked. An error is returned for a template without content.
+//
+// Example:
+//
+//	prompt := template.Must(template.New("prompt").Parse(promptText))
+//	issues, err := CheckPromptTemplate(Ticket{}, prompt)
+//	for _, issue := range issues {
+//	    t.Error(issue) // priority: field is not referenced by the template (unreferenced-field)
+//	}
+func CheckPromptTemplate(v interface{}, tmpl *template.Template, opts ...Option) ([]LintIssue, error) {
+	if tmpl == nil || tmpl.Tree == nil {
+		return nil, fmt.Errorf("prompt template has no content, parse it before checking it")
+	}
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return internal.CheckPromptTemplate(reflect.TypeOf(v), *schema, tmpl, options), nil
+}
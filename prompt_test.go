@@ -0,0 +1,37 @@
+package gptschema
+
+import (
+	"testing"
+	"text/template"
+)
+
+func TestCheckPromptTemplate(t *testing.T) {
+	type Ticket struct {
+		Summary  string `json:"summary"`
+		Priority int    `json:"priority"`
+	}
+	prompt := template.Must(template.New("prompt").Parse("Summarize {{.Sumary}} in one sentence."))
+	issues, err := CheckPromptTemplate(Ticket{}, prompt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %v", issues)
+	}
+	unknown := issues[0]
+	if unknown.Rule != "unknown-field" || unknown.Path != "Sumary" || unknown.Message != "prompt:1:12: Ticket has no field Sumary" {
+		t.Errorf("unexpected issue %+v", unknown)
+	}
+	for i, path := range []string{"summary", "priority"} {
+		if issues[i+1].Rule != "unreferenced-field" || issues[i+1].Path != path {
+			t.Errorf("unexpected issue %+v", issues[i+1])
+		}
+	}
+
+	if _, err := CheckPromptTemplate(Ticket{}, template.New("empty")); err == nil {
+		t.Errorf("expected an error for a template without content")
+	}
+	if _, err := CheckPromptTemplate(nil, prompt); err == nil {
+		t.Errorf("expected an error for a nil value")
+	}
+}
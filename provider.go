@@ -0,0 +1,75 @@
+package gptschema
+
+import (
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Provider is a model provider whose structured outputs accept a subset of JSON Schema.
+type Provider = internal.Provider
+
+// Providers accepted by WithProvider.
+const (
+	// ProviderOpenAI is OpenAI structured outputs in strict mode
+	ProviderOpenAI = internal.ProviderOpenAI
+	// ProviderAnthropic is Anthropic structured outputs
+	ProviderAnthropic = internal.ProviderAnthropic
+	// ProviderGemini is the response schema of the Gemini API
+	ProviderGemini = internal.ProviderGemini
+)
+
+// WithProvider selects the profile of the provider schemas are sent to. The keywords it
+// rejects, such as format values it does not know, default, examples or $comment, are
+// removed from the transmitted copy of the schema while the full schema is kept locally
+// to validate responses. Each removal is reported to the handler set with
+// WithWarningHandler. The profile applies to ProviderSchema, ToolSet and CheckLive,
+// GenerateSchema always returns the full schema.
+//
+// Example:
+//
+//	tools, _ := NewToolSet(WithProvider(ProviderAnthropic), WithWarningHandler(func(w Warning) {
+//	    log.Printf("gptschema: %s", w) // qty: keyword "minimum" removed, it is not supported by anthropic
+//	}))
+func WithProvider(provider Provider) Option {
+	return func(opts *internal.Options) {
+		if !provider.Valid() {
+			opts.AddError("unknown provider %q", provider)
+			return
+		}
+		opts.Provider = provider
+	}
+}
+
+// ProviderSchema returns the copy of a schema to transmit to the provider selected with
+// WithProvider, without the keywords the provider rejects, and the removed keywords as
+// warnings, also passed to the handler set with WithWarningHandler. Without a provider the
+// schema is returned as is. Validate responses against the full schema.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Order{})
+//	transmitted, stripped, err := ProviderSchema(schema, WithProvider(ProviderGemini))
+//	// send transmitted, then
+//	mismatches, err := Validate(schema, []byte(content))
+func ProviderSchema(schema *internal.Schema, opts ...Option) (*internal.Schema, []Warning, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if schema == nil {
+		return nil, nil, ErrInvalidSchema
+	}
+	transmitted, warnings := providerSchema(schema, options)
+	return transmitted, warnings, nil
+}
+
+// providerSchema strips the keywords rejected by the selected provider and reports them
+func providerSchema(schema *internal.Schema, options *internal.Options) (*internal.Schema, []Warning) {
+	if options.Provider == "" {
+		return schema, nil
+	}
+	stripped, warnings := internal.StripForProvider(*schema, options.Provider)
+	for _, w := range warnings {
+		options.Warn(w.Path, "%s", w.Message)
+	}
+	return &stripped, warnings
+}
@@ -0,0 +1,83 @@
+package gptschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestProviderSchema(t *testing.T) {
+	schema, err := Object().
+		Prop("qty", Integer().Minimum(1)).
+		Prop("email", String().Format("email")).
+		Required("qty", "email").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var reported []string
+	transmitted, warnings, err := ProviderSchema(schema,
+		WithProvider(ProviderAnthropic),
+		WithWarningHandler(func(w Warning) { reported = append(reported, w.String()) }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{`qty: keyword "minimum" removed, it is not supported by anthropic`}
+	if len(warnings) != 1 || warnings[0].String() != expected[0] || !reflect.DeepEqual(reported, expected) {
+		t.Errorf("expected %v to be returned and reported, got %v and %v", expected, warnings, reported)
+	}
+	if qty := (*transmitted)["properties"].(internal.Schema)["qty"]; !reflect.DeepEqual(qty, internal.Schema{"type": "integer"}) {
+		t.Errorf("unexpected transmitted qty %+v", qty)
+	}
+	if mismatches, _ := Validate(schema, []byte(`{"qty":0,"email":"a@b.c"}`)); len(mismatches) != 1 || mismatches[0].Keyword != "minimum" {
+		t.Errorf("expected the full schema to keep minimum, got %v", mismatches)
+	}
+
+	if same, warnings, _ := ProviderSchema(schema); same != schema || warnings != nil {
+		t.Errorf("expected the schema to be returned as is without a provider")
+	}
+	if _, _, err := ProviderSchema(schema, WithProvider("mistral")); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestToolSet_Provider(t *testing.T) {
+	type Args struct {
+		Qty int `json:"qty"`
+	}
+	tools, err := NewToolSet(WithProvider(ProviderGemini))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tools.Register("order", "", func(args Args) (int, error) { return args.Qty, nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tool, _ := tools.Get("order")
+	(*tool.Parameters)["$comment"] = "internal note"
+	payload, err := tools.ToolsJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var chat []struct {
+		Function struct {
+			Parameters map[string]interface{} `json:"parameters"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(payload, &chat); err != nil {
+		t.Fatalf("invalid JSON payload: %v", err)
+	}
+	if _, ok := chat[0].Function.Parameters["$comment"]; ok {
+		t.Errorf("expected $comment to be stripped, got %s", payload)
+	}
+	if _, ok := (*tool.Parameters)["$comment"]; !ok {
+		t.Errorf("expected the registered parameters to keep $comment")
+	}
+	if result, err := tools.Dispatch(context.Background(), ToolCall{Name: "order", Arguments: `{"qty":2}`}); err != nil || string(result) != "2" {
+		t.Errorf("unexpected dispatch result %s %v", result, err)
+	}
+}
@@ -0,0 +1,57 @@
+package gptschema
+
+import (
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Inline resolves the $refs of a schema to its root $defs or definitions into a fully
+// inlined schema, for providers that don't support references. Keywords next to a $ref,
+// such as a description, override the definition's. Recursive definitions cannot be
+// inlined and are reported with ErrCircularRef, unresolved $refs with ErrInvalidSchema.
+// External $refs to other files or URLs require WithRefLoader. The input is not modified.
+//
+// Example:
+//
+//	var schema internal.Schema
+//	_ = json.Unmarshal(data, &schema) // a schema using "$ref": "#/$defs/Address"
+//	inlined, err := Inline(&schema)
+func Inline(schema *internal.Schema, opts ...Option) (*internal.Schema, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("%w: cannot inline a nil schema", ErrInvalidSchema)
+	}
+	inlined, err := internal.InlineRefs(*schema, options)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	return &inlined, nil
+}
+
+// Bundle is the inverse of Inline: object schemas occurring more than once, such as a
+// struct used by several fields, are moved to $defs and replaced with $refs. Definitions
+// are named after the property they first appear in, in PascalCase. Inline(Bundle(s))
+// returns a schema equal to s. $refs already present are inlined first, see Inline.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Order{}) // Billing and Shipping are both an Address
+//	bundled, err := Bundle(schema)       // {"$defs":{"Billing":{...}}, ...}
+func Bundle(schema *internal.Schema, opts ...Option) (*internal.Schema, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("%w: cannot bundle a nil schema", ErrInvalidSchema)
+	}
+	bundled, err := internal.BundleRefs(*schema, options)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	return &bundled, nil
+}
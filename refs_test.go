@@ -0,0 +1,56 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestBundleAndInline(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Order struct {
+		ID       string  `json:"id"`
+		Billing  Address `json:"billing"`
+		Shipping Address `json:"shipping"`
+	}
+	schema, err := GenerateSchema(Order{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bundled, err := Bundle(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props := (*bundled)["properties"].(internal.Schema)
+	ref := internal.Schema{"$ref": "#/$defs/Billing"}
+	if !reflect.DeepEqual(props["billing"], ref) || !reflect.DeepEqual(props["shipping"], ref) {
+		t.Errorf("expected both addresses to reference Billing, got %+v", props)
+	}
+	inlined, err := Inline(bundled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(inlined, schema) {
+		t.Errorf("expected %+v, got %+v", *schema, *inlined)
+	}
+}
+
+func TestInline_Errors(t *testing.T) {
+	recursive := &internal.Schema{
+		"$ref":  "#/$defs/Node",
+		"$defs": internal.Schema{"Node": internal.Schema{"type": "array", "items": internal.Schema{"$ref": "#/$defs/Node"}}},
+	}
+	if _, err := Inline(recursive); !errors.Is(err, ErrCircularRef) || !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrCircularRef, got %v", err)
+	}
+	if _, err := Inline(&internal.Schema{"$ref": "#/$defs/Missing"}); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, got %v", err)
+	}
+	if _, err := Bundle(nil); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for a nil schema, got %v", err)
+	}
+}
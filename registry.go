@@ -0,0 +1,242 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// SchemaVersionProperty is the property injected by Registry.RegisterVersion,
+// holding the version of the contract as a constant.
+const SchemaVersionProperty = "schema_version"
+
+// ErrUnknownSchemaVersion is returned by Registry.Decode for a response whose
+// schema_version is missing or not registered.
+var ErrUnknownSchemaVersion = errors.New("unknown schema version")
+
+// RegistryEntry is a schema registered under a name.
+type RegistryEntry struct {
+	Name string
+	// Version is set for entries registered with RegisterVersion
+	Version int
+	Type    reflect.Type
+	Schema  *internal.Schema
+}
+
+// Registry holds named schemas generated from Go types, e.g. the response formats of a service.
+// The zero value is not usable, create registries with NewRegistry. A Registry is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]*RegistryEntry
+	// versions holds the entries of versioned names, entries holds their latest version
+	versions map[string]map[int]*RegistryEntry
+	names    []string
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		entries:  make(map[string]*RegistryEntry),
+		versions: make(map[string]map[int]*RegistryEntry),
+	}
+}
+
+// Register generates the schema of v with the given options and stores it under name.
+// Registering a name twice is an error, so two types can't silently claim the same contract.
+//
+// Example:
+//
+//	registry := NewRegistry()
+//	if err := registry.Register("address_item", AddressItem{}); err != nil {
+//	    log.Fatal(err)
+//	}
+func (r *Registry) Register(name string, v interface{}, opts ...Option) error {
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return fmt.Errorf("register %q: %w", name, err)
+	}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.entries[name]; exists {
+		return fmt.Errorf("register %q: name already registered", name)
+	}
+	r.entries[name] = &RegistryEntry{Name: name, Type: t, Schema: schema}
+	r.names = append(r.names, name)
+	return nil
+}
+
+// RegisterVersion generates the schema of v and stores it as the given version of name,
+// for rolling contract upgrades where several versions are accepted at once. A required
+// schema_version property holding the version as a constant is injected into the schema,
+// so Decode can route each response to the Go type of its version. Get returns the latest
+// version, and registering the same version twice or mixing Register and RegisterVersion
+// for a name is an error.
+//
+// Example:
+//
+//	registry := NewRegistry()
+//	_ = registry.RegisterVersion("invoice", 1, InvoiceV1{})
+//	_ = registry.RegisterVersion("invoice", 2, InvoiceV2{})
+//	// {"properties":{"schema_version":{"type":"integer","const":2},...},"required":["schema_version",...]}
+func (r *Registry) RegisterVersion(name string, version int, v interface{}, opts ...Option) error {
+	if version < 1 {
+		return fmt.Errorf("register %q: %w: version must be positive, got %d", name, ErrInvalidOption, version)
+	}
+	schema, err := GenerateSchema(v, opts...)
+	if err != nil {
+		return fmt.Errorf("register %q version %d: %w", name, version, err)
+	}
+	properties, _ := (*schema)["properties"].(internal.Schema)
+	if _, exists := properties[SchemaVersionProperty]; exists {
+		return fmt.Errorf("register %q version %d: %w: property %q is reserved for the version", name, version, ErrInvalidTag, SchemaVersionProperty)
+	}
+	if properties == nil {
+		properties = internal.Schema{}
+		(*schema)["properties"] = properties
+	}
+	properties[SchemaVersionProperty] = internal.Schema{"type": "integer", "const": version}
+	required, _ := (*schema)["required"].([]string)
+	(*schema)["required"] = append([]string{SchemaVersionProperty}, required...)
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	entry := &RegistryEntry{Name: name, Version: version, Type: t, Schema: schema}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions, versioned := r.versions[name]
+	if _, exists := r.entries[name]; exists && !versioned {
+		return fmt.Errorf("register %q: name already registered without a version", name)
+	}
+	if _, exists := versions[version]; exists {
+		return fmt.Errorf("register %q: version %d already registered", name, version)
+	}
+	if !versioned {
+		versions = make(map[int]*RegistryEntry)
+		r.versions[name] = versions
+		r.names = append(r.names, name)
+	}
+	versions[version] = entry
+	if latest := r.entries[name]; latest == nil || version > latest.Version {
+		r.entries[name] = entry
+	}
+	return nil
+}
+
+// GetVersion returns the entry registered under name and version with RegisterVersion.
+func (r *Registry) GetVersion(name string, version int) (*RegistryEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.versions[name][version]
+	return entry, ok
+}
+
+// Versions returns the versions registered under name, in ascending order.
+func (r *Registry) Versions(name string) []int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	versions := make([]int, 0, len(r.versions[name]))
+	for version := range r.versions[name] {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// Decode routes a response to the Go type of its version: the schema_version property
+// selects the entry registered with RegisterVersion, and the response is decoded with
+// Unmarshal into a new value of its type, returned as a pointer. Pass the options used
+// to register the versions. A missing or unregistered version returns ErrUnknownSchemaVersion.
+//
+// Example:
+//
+//	decoded, err := registry.Decode("invoice", []byte(content))
+//	switch invoice := decoded.(type) {
+//	case *InvoiceV1:
+//	    // ...
+//	case *InvoiceV2:
+//	    // ...
+//	}
+func (r *Registry) Decode(name string, data []byte, opts ...Option) (interface{}, error) {
+	var header map[string]json.RawMessage
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	raw, ok := header[SchemaVersionProperty]
+	if !ok {
+		return nil, fmt.Errorf("decode %q: %w: missing %s", name, ErrUnknownSchemaVersion, SchemaVersionProperty)
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return nil, fmt.Errorf("decode %q: %w: %s is %s", name, ErrUnknownSchemaVersion, SchemaVersionProperty, raw)
+	}
+	entry, ok := r.GetVersion(name, version)
+	if !ok {
+		return nil, fmt.Errorf("decode %q: %w: version %d is not registered", name, ErrUnknownSchemaVersion, version)
+	}
+	value := reflect.New(entry.Type)
+	if err := Unmarshal(data, value.Interface(), opts...); err != nil {
+		return nil, fmt.Errorf("decode %q version %d: %w", name, version, err)
+	}
+	return value.Interface(), nil
+}
+
+// Get returns the entry registered under name, the latest version for versioned names.
+func (r *Registry) Get(name string) (*RegistryEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[name]
+	return entry, ok
+}
+
+// Names returns the registered names in registration order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.names...)
+}
+
+// Entries returns the registered entries in registration order.
+func (r *Registry) Entries() []*RegistryEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entries := make([]*RegistryEntry, len(r.names))
+	for i, name := range r.names {
+		entries[i] = r.entries[name]
+	}
+	return entries
+}
+
+// contracts returns every registered entry in registration order, each version of a
+// versioned name in ascending order
+func (r *Registry) contracts() []*RegistryEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var entries []*RegistryEntry
+	for _, name := range r.names {
+		versions, ok := r.versions[name]
+		if !ok {
+			entries = append(entries, r.entries[name])
+			continue
+		}
+		numbers := make([]int, 0, len(versions))
+		for version := range versions {
+			numbers = append(numbers, version)
+		}
+		sort.Ints(numbers)
+		for _, version := range numbers {
+			entries = append(entries, versions[version])
+		}
+	}
+	return entries
+}
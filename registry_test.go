@@ -0,0 +1,157 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("employee", &internal.Employee{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Register("contact", internal.DescribedContact{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Register("employee", internal.Employee{}); err == nil {
+		t.Errorf("expected error for a duplicate name")
+	}
+	if err := registry.Register("node", internal.Node{}); !errors.Is(err, ErrCircularRef) {
+		t.Errorf("expected ErrCircularRef, got %v", err)
+	}
+	if names := registry.Names(); !reflect.DeepEqual(names, []string{"employee", "contact"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	entry, ok := registry.Get("employee")
+	if !ok {
+		t.Fatalf("expected employee to be registered")
+	}
+	if entry.Type != reflect.TypeOf(internal.Employee{}) {
+		t.Errorf("expected dereferenced type, got %v", entry.Type)
+	}
+	if !reflect.DeepEqual(*entry.Schema, internal.EmployeeSchema) {
+		t.Errorf("unexpected schema %+v", entry.Schema)
+	}
+	if entries := registry.Entries(); len(entries) != 2 || entries[1].Name != "contact" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+	if _, ok := registry.Get("missing"); ok {
+		t.Errorf("expected missing name to be absent")
+	}
+}
+
+type InvoiceV1 struct {
+	Total float64 `json:"total"`
+}
+
+type InvoiceV2 struct {
+	Total    float64 `json:"total"`
+	Currency string  `json:"currency"`
+}
+
+func TestRegistry_RegisterVersion(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.RegisterVersion("invoice", 2, InvoiceV2{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.RegisterVersion("invoice", 1, &InvoiceV1{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if versions := registry.Versions("invoice"); !reflect.DeepEqual(versions, []int{1, 2}) {
+		t.Errorf("unexpected versions %v", versions)
+	}
+	if names := registry.Names(); !reflect.DeepEqual(names, []string{"invoice"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	latest, _ := registry.Get("invoice")
+	if latest.Version != 2 || latest.Type != reflect.TypeOf(InvoiceV2{}) {
+		t.Errorf("expected Get to return the latest version, got %+v", latest)
+	}
+	v1, ok := registry.GetVersion("invoice", 1)
+	if !ok {
+		t.Fatalf("expected version 1 to be registered")
+	}
+	expected := internal.Schema{
+		"type": "object",
+		"properties": internal.Schema{
+			"schema_version": internal.Schema{"type": "integer", "const": 1},
+			"total":          internal.Schema{"type": "number"},
+		},
+		"required":             []string{"schema_version", "total"},
+		"additionalProperties": false,
+	}
+	if !reflect.DeepEqual(*v1.Schema, expected) {
+		t.Errorf("unexpected schema %+v", v1.Schema)
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		v       interface{}
+		wantErr error
+	}{
+		{name: "duplicate version", version: 2, v: InvoiceV2{}},
+		{name: "non-positive version", version: 0, v: InvoiceV2{}, wantErr: ErrInvalidOption},
+		{name: "reserved property", version: 3, v: struct {
+			Version int `json:"schema_version"`
+		}{}, wantErr: ErrInvalidTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.RegisterVersion("invoice", tt.version, tt.v)
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if err := registry.Register("employee", internal.Employee{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.RegisterVersion("employee", 1, internal.Employee{}); err == nil {
+		t.Errorf("expected error for an unversioned name")
+	}
+	if err := registry.Register("invoice", InvoiceV1{}); err == nil {
+		t.Errorf("expected error for a versioned name")
+	}
+}
+
+func TestRegistry_Decode(t *testing.T) {
+	registry := NewRegistry()
+	_ = registry.RegisterVersion("invoice", 1, InvoiceV1{})
+	_ = registry.RegisterVersion("invoice", 2, InvoiceV2{})
+
+	tests := []struct {
+		name    string
+		data    string
+		want    interface{}
+		wantErr error
+	}{
+		{name: "version 1", data: `{"schema_version":1,"total":9.5}`, want: &InvoiceV1{Total: 9.5}},
+		{name: "version 2", data: `{"schema_version":2,"total":9.5,"currency":"EUR"}`, want: &InvoiceV2{Total: 9.5, Currency: "EUR"}},
+		{name: "missing version", data: `{"total":9.5}`, wantErr: ErrUnknownSchemaVersion},
+		{name: "unregistered version", data: `{"schema_version":3}`, wantErr: ErrUnknownSchemaVersion},
+		{name: "non-integer version", data: `{"schema_version":"2"}`, wantErr: ErrUnknownSchemaVersion},
+		{name: "not an object", data: `[1]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := registry.Decode("invoice", []byte(tt.data))
+			if tt.want == nil {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
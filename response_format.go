@@ -0,0 +1,154 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// names accepted by OpenAI for a json_schema response format
+var responseFormatName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// responseFormat mirrors the response_format object of the Chat Completions API
+type responseFormat struct {
+	Type       string             `json:"type"`
+	JSONSchema responseFormatSpec `json:"json_schema"`
+}
+
+type responseFormatSpec struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Schema      *internal.Schema `json:"schema"`
+	Strict      bool             `json:"strict"`
+}
+
+// textConfig mirrors the text object of the Responses API
+type textConfig struct {
+	Format textFormat `json:"format"`
+}
+
+// textFormat is the json_schema format of the Responses API, with the fields of the
+// Chat Completions json_schema object next to the type
+type textFormat struct {
+	Type string `json:"type"`
+	responseFormatSpec
+}
+
+// rawTextFormat is textFormat with a schema that is copied without decoding
+type rawTextFormat struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Schema      json.RawMessage `json:"schema"`
+	Strict      bool            `json:"strict"`
+}
+
+// BuildResponseFormatJSON generates the schema of v and wraps it into the complete
+// response_format object of the Chat Completions API, for users not on the official SDK.
+//
+// The name must match ^[a-zA-Z0-9_-]{1,64}$ as required by OpenAI, and the description is
+// omitted when empty. The schema is generated with WithStrict(strict) and checked with Lint,
+// a *LintError is returned when it would be rejected.
+//
+// Example:
+//
+//	payload, err := BuildResponseFormatJSON("address_item", "mock address", AddressItem{}, true)
+//	// {"type":"json_schema","json_schema":{"name":"address_item","description":"mock address","schema":{...},"strict":true}}
+func BuildResponseFormatJSON(name, description string, v interface{}, strict bool) ([]byte, error) {
+	spec, err := buildFormatSpec(name, description, v, strict)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(responseFormat{Type: "json_schema", JSONSchema: *spec})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response format to JSON: %w", err)
+	}
+	return payload, nil
+}
+
+// BuildTextFormatJSON generates the schema of v and wraps it into the text object of the
+// Responses API, which nests the format under text.format and flattens the name, description,
+// schema and strict fields next to the type instead of nesting them under json_schema.
+// The name and schema are checked as in BuildResponseFormatJSON.
+//
+// Example:
+//
+//	text, err := BuildTextFormatJSON("address_item", "mock address", AddressItem{}, true)
+//	// {"format":{"type":"json_schema","name":"address_item","description":"mock address","schema":{...},"strict":true}}
+func BuildTextFormatJSON(name, description string, v interface{}, strict bool) ([]byte, error) {
+	spec, err := buildFormatSpec(name, description, v, strict)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(textConfig{Format: textFormat{Type: "json_schema", responseFormatSpec: *spec}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal text format to JSON: %w", err)
+	}
+	return payload, nil
+}
+
+// ConvertResponseFormatToTextFormat converts the response_format object of a Chat Completions
+// request, as produced by BuildResponseFormatJSON, into the text object of the Responses API,
+// for callers migrating existing payloads. The schema is copied as is.
+//
+// Example:
+//
+//	text, err := ConvertResponseFormatToTextFormat(responseFormat)
+//	// {"format":{"type":"json_schema","name":"address_item","schema":{...},"strict":true}}
+func ConvertResponseFormatToTextFormat(responseFormatJSON []byte) ([]byte, error) {
+	var format struct {
+		Type       string `json:"type"`
+		JSONSchema *struct {
+			Name        string          `json:"name"`
+			Description string          `json:"description,omitempty"`
+			Schema      json.RawMessage `json:"schema"`
+			Strict      bool            `json:"strict"`
+		} `json:"json_schema"`
+	}
+	if err := json.Unmarshal(responseFormatJSON, &format); err != nil {
+		return nil, fmt.Errorf("failed to parse response format: %w", err)
+	}
+	if format.Type != "json_schema" || format.JSONSchema == nil {
+		return nil, fmt.Errorf("%w: response format of type %q has no json_schema", ErrInvalidSchema, format.Type)
+	}
+	if len(format.JSONSchema.Schema) == 0 {
+		return nil, fmt.Errorf("%w: response format %q has no schema", ErrInvalidSchema, format.JSONSchema.Name)
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"format": rawTextFormat{
+			Type:        format.Type,
+			Name:        format.JSONSchema.Name,
+			Description: format.JSONSchema.Description,
+			Schema:      format.JSONSchema.Schema,
+			Strict:      format.JSONSchema.Strict,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal text format to JSON: %w", err)
+	}
+	return payload, nil
+}
+
+// buildFormatSpec validates the name, generates the schema of v and lints it
+func buildFormatSpec(name, description string, v interface{}, strict bool) (*responseFormatSpec, error) {
+	if !responseFormatName.MatchString(name) {
+		return nil, fmt.Errorf("%w: response format name %q must match %s", ErrInvalidOption, name, responseFormatName)
+	}
+	schema, err := GenerateSchema(v, WithStrict(strict))
+	if err != nil {
+		return nil, err
+	}
+	if issues, err := Lint(schema, WithStrict(strict)); err != nil {
+		return nil, err
+	} else if len(issues) > 0 {
+		return nil, &LintError{Issues: issues}
+	}
+	return &responseFormatSpec{
+		Name:        name,
+		Description: description,
+		Schema:      schema,
+		Strict:      strict,
+	}, nil
+}
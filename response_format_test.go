@@ -0,0 +1,140 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestBuildResponseFormatJSON(t *testing.T) {
+	payload, err := BuildResponseFormatJSON("user_info", "a user", internal.StructWithTags{}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("invalid JSON payload: %v", err)
+	}
+	if decoded["type"] != "json_schema" {
+		t.Errorf("expected type json_schema, got %v", decoded["type"])
+	}
+	spec := decoded["json_schema"].(map[string]interface{})
+	for key, expected := range map[string]interface{}{
+		"name":        "user_info",
+		"description": "a user",
+		"strict":      true,
+	} {
+		if !reflect.DeepEqual(spec[key], expected) {
+			t.Errorf("expected %s=%v, got %v", key, expected, spec[key])
+		}
+	}
+	if spec["schema"].(map[string]interface{})["type"] != "object" {
+		t.Errorf("expected an object schema, got %v", spec["schema"])
+	}
+}
+
+func TestBuildResponseFormatJSON_Errors(t *testing.T) {
+	type Labels struct {
+		Values map[string]string `json:"values" keys:"^[a-z]+$"`
+	}
+	t.Run("empty description is omitted", func(t *testing.T) {
+		payload, err := BuildResponseFormatJSON("labels", "", Labels{}, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var decoded struct {
+			JSONSchema map[string]interface{} `json:"json_schema"`
+		}
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			t.Fatalf("invalid JSON payload: %v", err)
+		}
+		if _, ok := decoded.JSONSchema["description"]; ok {
+			t.Errorf("expected description to be omitted")
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := BuildResponseFormatJSON("user info", "", internal.StructWithTags{}, true)
+		if !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("expected ErrInvalidOption, got %v", err)
+		}
+	})
+
+	t.Run("unsupported type in strict mode", func(t *testing.T) {
+		_, err := BuildResponseFormatJSON("labels", "", Labels{}, true)
+		if !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("expected ErrUnsupportedType, got %v", err)
+		}
+	})
+}
+
+func TestBuildTextFormatJSON(t *testing.T) {
+	payload, err := BuildTextFormatJSON("user_info", "a user", internal.StructWithTags{}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded map[string]map[string]interface{}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("invalid JSON payload: %v", err)
+	}
+	format := decoded["format"]
+	for key, expected := range map[string]interface{}{
+		"type":        "json_schema",
+		"name":        "user_info",
+		"description": "a user",
+		"strict":      true,
+	} {
+		if !reflect.DeepEqual(format[key], expected) {
+			t.Errorf("expected %s=%v, got %v", key, expected, format[key])
+		}
+	}
+	if format["schema"].(map[string]interface{})["type"] != "object" {
+		t.Errorf("expected an object schema, got %v", format["schema"])
+	}
+	if _, ok := format["json_schema"]; ok {
+		t.Errorf("expected the format fields to be flattened, got %v", format)
+	}
+
+	if _, err := BuildTextFormatJSON("user info", "", internal.StructWithTags{}, true); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestConvertResponseFormatToTextFormat(t *testing.T) {
+	responseFormat, err := BuildResponseFormatJSON("user_info", "a user", internal.StructWithTags{}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := BuildTextFormatJSON("user_info", "a user", internal.StructWithTags{}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	converted, err := ConvertResponseFormatToTextFormat(responseFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(converted) != string(expected) {
+		t.Errorf("ConvertResponseFormatToTextFormat() =\n%s\nwant\n%s", converted, expected)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "json_object format", input: `{"type":"json_object"}`, wantErr: ErrInvalidSchema},
+		{name: "missing schema", input: `{"type":"json_schema","json_schema":{"name":"x"}}`, wantErr: ErrInvalidSchema},
+		{name: "invalid JSON", input: `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConvertResponseFormatToTextFormat([]byte(tt.input))
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
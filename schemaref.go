@@ -0,0 +1,37 @@
+package gptschema
+
+import (
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// RegisterSchemaRef records a hand-written schema under name, for the fields tagged
+// `schemaRef:"<name>"`. Tagged fields are described by the curated schema instead of
+// their reflected one, so generated and curated schemas can be mixed type by type. A tag
+// naming no registered schema is a reference loaded with the loader set by WithRefLoader,
+// such as "file://address.schema.json" or "address.schema.json#/$defs/Address", whose own
+// $refs are inlined. Optional fields stay nullable, and the schema is otherwise used as
+// written: Lint checks it against strict mode. Registration is global and safe for
+// concurrent use, registering a name again replaces the schema. An empty name or invalid
+// JSON is rejected with ErrInvalidSchema.
+//
+// Example:
+//
+//	gptschema.RegisterSchemaRef("money", `{"type":"string","pattern":"^\\d+\\.\\d{2}$"}`)
+//	type Invoice struct {
+//	    Total   string  `json:"total" schemaRef:"money"`
+//	    Address Address `json:"address" schemaRef:"file://address.schema.json"`
+//	}
+//	schema, err := GenerateSchema(Invoice{}, WithRefLoader(FileLoader{Dir: "schemas"}))
+func RegisterSchemaRef(name, schemaJSON string) error {
+	if name == "" {
+		return fmt.Errorf("%w: schema name must not be empty", ErrInvalidSchema)
+	}
+	schema, err := internal.ParseSchema([]byte(schemaJSON))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	internal.RegisterNamedSchema(name, schema)
+	return nil
+}
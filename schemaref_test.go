@@ -0,0 +1,74 @@
+package gptschema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestGenerateSchema_SchemaRef(t *testing.T) {
+	if err := RegisterSchemaRef("sku", `{"type":"string","pattern":"^[A-Z]{3}-\\d{4}$"}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := t.TempDir()
+	address := `{"type":"object","properties":{"line":{"type":"string"}},"required":["line"],"additionalProperties":false}`
+	if err := os.WriteFile(filepath.Join(dir, "address.schema.json"), []byte(address), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	type Item struct {
+		SKU     string           `json:"sku" schemaRef:"sku"`
+		Address internal.Address `json:"address" schemaRef:"file://address.schema.json"`
+	}
+	schema, err := GenerateSchema(Item{}, WithRefLoader(FileLoader{Dir: dir}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sku, _ := schema.At("sku")
+	if expected := (internal.Schema{"type": "string", "pattern": `^[A-Z]{3}-\d{4}$`}); !reflect.DeepEqual(sku, expected) {
+		t.Errorf("expected %+v, got %+v", expected, sku)
+	}
+	line, ok := schema.At("address.line")
+	if _, hasCity := schema.At("address.city"); !ok || hasCity {
+		t.Errorf("expected the curated address schema, got %+v", (*schema)["properties"])
+	}
+	if line["type"] != "string" {
+		t.Errorf("expected a string line, got %+v", line)
+	}
+
+	// the file cannot be loaded without a loader
+	if _, err := GenerateSchema(Item{}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+	for _, input := range [][2]string{{"", `{"type":"string"}`}, {"broken", `{"type":`}} {
+		if err := RegisterSchemaRef(input[0], input[1]); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("expected ErrInvalidSchema for %q, got %v", input[0], err)
+		}
+	}
+}
+
+func TestGenerateSchema_SchemaRefLoadedPassesLint(t *testing.T) {
+	dir := t.TempDir()
+	address := `{"type":"object","properties":{"line":{"type":"string"},"city":{"type":"string"}},"required":["line","city"],"additionalProperties":false}`
+	if err := os.WriteFile(filepath.Join(dir, "address.schema.json"), []byte(address), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	type Order struct {
+		Shipping internal.Address `json:"shipping" schemaRef:"file://address.schema.json"`
+	}
+	schema, err := GenerateSchema(Order{}, WithRefLoader(FileLoader{Dir: dir}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shipping, _ := schema.At("shipping")
+	if required, ok := shipping["required"].([]string); !ok || len(required) != 2 {
+		t.Errorf("expected the loaded required list to be []string, got %#v", shipping["required"])
+	}
+	issues, err := Lint(schema)
+	if err != nil || len(issues) != 0 {
+		t.Errorf("expected the loaded schema to pass lint, got %v %v", issues, err)
+	}
+}
@@ -0,0 +1,63 @@
+package gptschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// ScoreReport is the conformance of a batch of responses to a schema, see Score.
+type ScoreReport = internal.ScoreReport
+
+// FieldScore is the conformance of a field across a batch of responses: how many responses
+// it was seen in, how many of them it validated in, and its violations by keyword.
+type FieldScore = internal.FieldScore
+
+// ViolationCount is the number of violations of a keyword, see FieldScore.CommonViolations.
+type ViolationCount = internal.ViolationCount
+
+// Score validates a batch of logged model responses against a schema and reports, per
+// field, how often it validated and its most common violation kinds, to help decide which
+// constraints to tighten or loosen: a field failing "enum" in a third of the responses
+// likely needs another value or a better description. Violations of array items and map
+// values are counted on their field, e.g. "items[].price", unexpected properties on their
+// object. Responses that are not JSON are counted as unparsable. An error is only returned
+// for schemas that cannot be evaluated, such as unresolved $refs (see WithRefLoader).
+//
+// Example:
+//
+//	report, err := Score(schema, responses)
+//	fmt.Print(report)
+//	// 42/50 responses valid
+//	// status: 44/50 valid (88%), enum 6
+//	// ...
+func Score(schema *internal.Schema, responses [][]byte, opts ...Option) (ScoreReport, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return ScoreReport{}, err
+	}
+	if schema == nil {
+		return ScoreReport{}, fmt.Errorf("%w: cannot score against a nil schema", ErrInvalidSchema)
+	}
+	documents := make([]interface{}, 0, len(responses))
+	unparsable := 0
+	for _, response := range responses {
+		decoder := json.NewDecoder(bytes.NewReader(response))
+		decoder.UseNumber()
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			unparsable++
+			continue
+		}
+		documents = append(documents, document)
+	}
+	report, err := internal.ScoreDocuments(*schema, documents, options)
+	if err != nil {
+		return ScoreReport{}, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	report.Responses += unparsable
+	report.Unparsable = unparsable
+	return report, nil
+}
@@ -0,0 +1,44 @@
+package gptschema
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	type Line struct {
+		Qty int `json:"qty"`
+	}
+	type Order struct {
+		ID    string `json:"id"`
+		Lines []Line `json:"lines"`
+	}
+	schema, err := GenerateSchema(Order{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report, err := Score(schema, [][]byte{
+		[]byte(`{"id":"a","lines":[{"qty":1}]}`),
+		[]byte(`{"id":"b","lines":[{"qty":"2"},{"qty":3}]}`),
+		[]byte(`{"lines":[]}`),
+		[]byte(`not json`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Responses != 4 || report.Valid != 1 || report.Unparsable != 1 {
+		t.Errorf("unexpected totals %d/%d, %d unparsable", report.Valid, report.Responses, report.Unparsable)
+	}
+	qty, ok := report.Field("lines[].qty")
+	if !ok || qty.Seen != 2 || qty.Valid != 1 || qty.Violations["type"] != 1 {
+		t.Errorf("unexpected score %+v", qty)
+	}
+	id, _ := report.Field("id")
+	if id.Rate() != 2.0/3 || id.Violations["required"] != 1 {
+		t.Errorf("unexpected score %+v", id)
+	}
+
+	if _, err := Score(nil, nil); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, got %v", err)
+	}
+}
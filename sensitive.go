@@ -0,0 +1,86 @@
+package gptschema
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// DefaultSensitiveDescription is the instruction appended to the description of fields
+// tagged sensitive:"true", see WithSensitiveDescription.
+const DefaultSensitiveDescription = internal.DefaultSensitiveDescription
+
+// Redactor rewrites a string of a field tagged sensitive:"true" while decoding, see
+// WithRedaction. The path is the path of the value in the response, e.g. "customer.email".
+type Redactor = internal.Redactor
+
+// RedactAll is a Redactor replacing every sensitive string with "[REDACTED]".
+func RedactAll(path, value string) string {
+	return "[REDACTED]"
+}
+
+// MaskRedactor returns a Redactor replacing every character of sensitive strings with *
+// except the last visible ones, e.g. "************4242" for a card number with 4 visible.
+//
+// Example:
+//
+//	err := Unmarshal([]byte(content), &payment, WithRedaction(MaskRedactor(4)))
+func MaskRedactor(visible int) Redactor {
+	return func(path, value string) string {
+		n := utf8.RuneCountInString(value)
+		if visible >= n {
+			return value
+		}
+		if visible < 0 {
+			visible = 0
+		}
+		runes := []rune(value)
+		return strings.Repeat("*", n-visible) + string(runes[n-visible:])
+	}
+}
+
+// WithSensitiveDescription replaces the instruction appended to the description of fields
+// tagged sensitive:"true", e.g. to follow the wording of a compliance policy. An empty
+// instruction is rejected with ErrInvalidOption.
+//
+// Example:
+//
+//	schema, err := GenerateSchema(Ticket{},
+//	    WithSensitiveDescription("Personal data: never repeat it, write [PII] instead."))
+func WithSensitiveDescription(instruction string) Option {
+	return func(opts *internal.Options) {
+		if instruction == "" {
+			opts.AddError("sensitive description must not be empty")
+			return
+		}
+		opts.SensitiveDescription = instruction
+	}
+}
+
+// WithRedaction makes Unmarshal pass the strings of fields tagged sensitive:"true" to the
+// redactor before decoding, so responses are masked wherever they are decoded and the
+// sensitive fields are declared in one place, next to the schema instruction asking the
+// model not to echo them. Strings nested in sensitive arrays, maps and structs are
+// redacted too; numbers and booleans decode to their zero value. A nil redactor is
+// rejected with ErrInvalidOption.
+//
+// Example:
+//
+//	type Ticket struct {
+//	    Summary string `json:"summary"`
+//	    Email   string `json:"email" sensitive:"true"`
+//	}
+//
+//	var ticket Ticket
+//	err := Unmarshal([]byte(content), &ticket, WithRedaction(RedactAll))
+//	// ticket.Email == "[REDACTED]"
+func WithRedaction(redactor Redactor) Option {
+	return func(opts *internal.Options) {
+		if redactor == nil {
+			opts.AddError("redactor must not be nil")
+			return
+		}
+		opts.Redactor = redactor
+	}
+}
@@ -0,0 +1,74 @@
+package gptschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+type sensitivePayment struct {
+	Reference string   `json:"reference"`
+	Card      string   `json:"card" sensitive:"true"`
+	Emails    []string `json:"emails" sensitive:"true"`
+	Amount    int      `json:"amount,string" sensitive:"true"`
+}
+
+func TestWithSensitiveDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{name: "default", expected: DefaultSensitiveDescription},
+		{name: "custom", opts: []Option{WithSensitiveDescription("Never repeat it.")}, expected: "Never repeat it."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := GenerateSchema(sensitivePayment{}, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			card := (*schema)["properties"].(internal.Schema)["card"].(internal.Schema)
+			if card["description"] != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, card["description"])
+			}
+		})
+	}
+	if _, err := GenerateSchema(sensitivePayment{}, WithSensitiveDescription("")); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestWithRedaction(t *testing.T) {
+	data := []byte(`{"reference":"R-1","card":"4111111111114242","emails":["ada@example.com"],"amount":"42"}`)
+	tests := []struct {
+		name     string
+		redactor Redactor
+		card     string
+		email    string
+	}{
+		{name: "redact all", redactor: RedactAll, card: "[REDACTED]", email: "[REDACTED]"},
+		{name: "mask", redactor: MaskRedactor(4), card: "************4242", email: "***********.com"},
+		{name: "mask longer than the value", redactor: MaskRedactor(20), card: "4111111111114242", email: "ada@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payment sensitivePayment
+			if err := Unmarshal(data, &payment, WithRedaction(tt.redactor)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if payment.Reference != "R-1" || payment.Card != tt.card || payment.Emails[0] != tt.email || payment.Amount != 0 {
+				t.Errorf("unexpected payment %+v", payment)
+			}
+		})
+	}
+
+	var payment sensitivePayment
+	if err := Unmarshal(data, &payment); err != nil || payment.Card != "4111111111114242" || payment.Amount != 42 {
+		t.Errorf("expected values to be kept without redaction, got %+v %v", payment, err)
+	}
+	if err := Unmarshal(data, &payment, WithRedaction(nil)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// ErrIncompleteJSON is returned by StreamValidator.Close for a response that ended before
+// its JSON value was complete, e.g. a stream cut by a token limit.
+var ErrIncompleteJSON = internal.ErrIncompleteJSON
+
+// StreamValidator validates a model response while it streams: the chunks are parsed as
+// they are written, syntax errors are returned as soon as they appear, and Close checks the
+// complete response against the schema like Validate. Callbacks registered with OnField
+// fire as soon as their field is complete. A StreamValidator validates a single response
+// and is not safe for concurrent use.
+type StreamValidator struct {
+	schema  *internal.Schema
+	options *internal.Options
+	parser  *internal.StreamParser
+	fields  []fieldSubscription
+}
+
+// fieldSubscription is a callback registered with OnField
+type fieldSubscription struct {
+	path string
+	fn   func(value json.RawMessage)
+}
+
+// NewStreamValidator creates a validator for one streamed response. A nil schema is
+// rejected with ErrInvalidSchema.
+//
+// Example:
+//
+//	validator, err := NewStreamValidator(schema)
+//	for stream.Next() {
+//	    if _, err := validator.Write([]byte(stream.Current().Choices[0].Delta.Content)); err != nil {
+//	        break // not JSON, stop early
+//	    }
+//	}
+//	mismatches, err := validator.Close()
+func NewStreamValidator(schema *internal.Schema, opts ...Option) (*StreamValidator, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("%w: cannot validate against a nil schema", ErrInvalidSchema)
+	}
+	v := &StreamValidator{schema: schema, options: options}
+	v.parser = internal.NewStreamParser(v.complete)
+	return v, nil
+}
+
+// OnField calls fn with the raw JSON of the value at path as soon as it is complete in
+// the stream, so a UI can show the "title" while the "body" is still generating. Paths
+// use the syntax of ValidationError; "[]" matches any index, so "items[].name" fires once
+// per item. Objects and arrays are complete once closed, strings at their closing quote,
+// and numbers and literals at the delimiter following them. The value is not validated
+// yet, fn runs during Write.
+//
+// Example:
+//
+//	validator.OnField("title", func(value json.RawMessage) {
+//	    var title string
+//	    _ = json.Unmarshal(value, &title)
+//	    ui.ShowTitle(title)
+//	})
+func (v *StreamValidator) OnField(path string, fn func(value json.RawMessage)) {
+	v.fields = append(v.fields, fieldSubscription{path: path, fn: fn})
+}
+
+func (v *StreamValidator) complete(path string, raw []byte) {
+	for _, field := range v.fields {
+		if internal.MatchStreamPath(field.path, path) {
+			field.fn(append(json.RawMessage(nil), raw...))
+		}
+	}
+}
+
+// Write parses the next chunk of the response. A syntax error is returned by this and every
+// later call.
+func (v *StreamValidator) Write(chunk []byte) (int, error) {
+	return v.parser.Write(chunk)
+}
+
+// Close ends the response and validates it against the schema, returning every mismatch
+// like Validate. A response that is not complete JSON returns ErrIncompleteJSON.
+func (v *StreamValidator) Close() ([]ValidationError, error) {
+	if err := v.parser.Close(); err != nil {
+		return nil, err
+	}
+	return validateJSON(v.schema, v.parser.Bytes(), v.options)
+}
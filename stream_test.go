@@ -0,0 +1,84 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStreamValidator(t *testing.T) {
+	type Section struct {
+		Heading string `json:"heading"`
+	}
+	type Post struct {
+		Title    string    `json:"title"`
+		Body     string    `json:"body"`
+		Sections []Section `json:"sections"`
+		Words    int       `json:"words"`
+	}
+	schema, err := GenerateSchema(Post{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	validator, err := NewStreamValidator(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var events []string
+	for _, path := range []string{"title", "sections[].heading", "words"} {
+		path := path
+		validator.OnField(path, func(value json.RawMessage) {
+			events = append(events, path+" "+string(value))
+		})
+	}
+	chunks := []string{`{"title": "Tid`, `es", "body": "The moon`, ` pulls", "sections": [{"heading": "Why"}, {"heading"`, `: "How"}], "words": 1`, `2}`}
+	for _, chunk := range chunks {
+		events = append(events, "chunk")
+		if _, err := validator.Write([]byte(chunk)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expected := []string{
+		"chunk", "chunk", `title "Tides"`, // the title is shown while the body is still streaming
+		"chunk", `sections[].heading "Why"`,
+		"chunk", `sections[].heading "How"`,
+		"chunk", "words 12",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected %q, got %q", expected, events)
+	}
+	mismatches, err := validator.Close()
+	if err != nil || len(mismatches) != 0 {
+		t.Errorf("expected a valid response, got %v (%v)", mismatches, err)
+	}
+}
+
+func TestStreamValidator_Errors(t *testing.T) {
+	type Post struct {
+		Words int `json:"words"`
+	}
+	schema, _ := GenerateSchema(Post{})
+
+	validator, _ := NewStreamValidator(schema)
+	_, _ = validator.Write([]byte(`{"words": "many"}`))
+	mismatches, err := validator.Close()
+	if err != nil || len(mismatches) != 1 || mismatches[0].Path != "words" {
+		t.Errorf("expected a mismatch at words, got %v (%v)", mismatches, err)
+	}
+
+	validator, _ = NewStreamValidator(schema)
+	if _, err := validator.Write([]byte(`{"words": 3,,`)); err == nil {
+		t.Errorf("expected a syntax error while streaming")
+	}
+
+	validator, _ = NewStreamValidator(schema)
+	_, _ = validator.Write([]byte(`{"words": 3`))
+	if _, err := validator.Close(); !errors.Is(err, ErrIncompleteJSON) {
+		t.Errorf("expected ErrIncompleteJSON, got %v", err)
+	}
+
+	if _, err := NewStreamValidator(nil); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package gptschema
+
+import "github.com/akane9506/gptschema/internal"
+
+// WithSubtreeOptions applies options to the property at path and everything below it, while
+// the rest of the schema keeps the other options, e.g. to let the branch holding a third-party
+// struct allow additionalProperties or use another nullable style. Paths are relative to the
+// root and use the syntax of Schema.At: "vendor", "items[].extra", "labels{}". Options applied
+// to the whole schema after generation, such as WithProvider or WithMaxSchemaBytes, have no
+// effect in a subtree. With WithDefs, a struct type described once is shared by every
+// occurrence, inside the subtree or not. An empty path is rejected with ErrInvalidOption.
+//
+// Example:
+//
+//	type Order struct {
+//	    ID     string         `json:"id"`
+//	    Vendor thirdparty.Ref `json:"vendor,omitempty"`
+//	}
+//	schema, err := GenerateSchema(Order{}, WithSubtreeOptions("vendor",
+//	    WithAdditionalProperties(true), WithNullableStyle(NullableAnyOf)))
+//	// {"properties": {"id": {...}, "vendor": {"anyOf": [{..., "additionalProperties": true}, {"type": "null"}]}}, "additionalProperties": false, ...
+func WithSubtreeOptions(path string, opts ...Option) Option {
+	apply := make([]func(*internal.Options), len(opts))
+	for i, opt := range opts {
+		apply[i] = opt
+	}
+	return func(options *internal.Options) {
+		options.AddSubtree(path, apply...)
+	}
+}
@@ -0,0 +1,47 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestGenerateSchema_WithSubtreeOptions(t *testing.T) {
+	type Vendor struct {
+		Name  string `json:"name"`
+		Notes string `json:"notes,omitempty"`
+	}
+	type Order struct {
+		ID     string `json:"id"`
+		Notes  string `json:"notes,omitempty"`
+		Vendor Vendor `json:"vendor"`
+	}
+	schema, err := GenerateSchema(Order{}, WithSubtreeOptions("vendor",
+		WithAdditionalProperties(true), WithNullableStyle(NullableAnyOf)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if (*schema)["additionalProperties"] != false {
+		t.Errorf("expected the root to stay strict, got %v", (*schema)["additionalProperties"])
+	}
+	vendor, _ := schema.At("vendor")
+	if vendor["additionalProperties"] != true {
+		t.Errorf("expected the vendor to allow additional properties, got %v", vendor["additionalProperties"])
+	}
+	notes, _ := schema.At("notes")
+	if expected := (internal.Schema{"type": []string{"string", "null"}}); !reflect.DeepEqual(notes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, notes)
+	}
+	vendorNotes, _ := schema.At("vendor.notes")
+	if expected := (internal.Schema{"anyOf": []internal.Schema{{"type": "string"}, {"type": "null"}}}); !reflect.DeepEqual(vendorNotes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, vendorNotes)
+	}
+
+	for _, opt := range []Option{WithSubtreeOptions(""), WithSubtreeOptions("vendor", WithNullableStyle("short"))} {
+		if _, err := GenerateSchema(Order{}, opt); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("expected ErrInvalidOption, got %v", err)
+		}
+	}
+}
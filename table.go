@@ -0,0 +1,96 @@
+package gptschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Table is a batch of responses flattened by Flatten, with one column per leaf of the schema
+// named by its path, e.g. "lines[].sku", and WriteCSV and WriteTSV methods.
+type Table = internal.Table
+
+// DefaultArraySeparator joins the values of arrays and maps in the cells of flattened tables.
+const DefaultArraySeparator = internal.DefaultArraySeparator
+
+// Flatten validates a batch of responses against their schema and flattens them into a
+// table, for bulk extraction results landing in spreadsheets or warehouses. Columns follow
+// the property order of the schema, so they are stable across runs. The values of arrays and
+// maps are joined with DefaultArraySeparator, see WithArraySeparator, unless the array is
+// exploded with WithExplodeArray. Nulls are empty cells, objects and arrays without schema
+// are written as JSON. A response that is not JSON or does not match the schema is
+// reported with its index.
+//
+// Example:
+//
+//	schema, _ := GenerateSchema(Invoice{})
+//	table, err := Flatten(schema, responses, WithExplodeArray("lines"))
+//	if err != nil {
+//	    return err
+//	}
+//	err = table.WriteCSV(os.Stdout)
+//	// id,customer,lines[].sku,lines[].qty
+//	// INV-1,Ada,A-1,2
+//	// INV-1,Ada,B-7,1
+func Flatten(schema *internal.Schema, responses [][]byte, opts ...Option) (*Table, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("%w: cannot flatten against a nil schema", ErrInvalidSchema)
+	}
+	documents := make([]interface{}, len(responses))
+	for i, response := range responses {
+		decoder := json.NewDecoder(bytes.NewReader(response))
+		decoder.UseNumber()
+		if err := decoder.Decode(&documents[i]); err != nil {
+			return nil, fmt.Errorf("response %d: %w", i, err)
+		}
+		mismatches, err := internal.ValidateValue(*schema, documents[i], options)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+		}
+		if len(mismatches) > 0 {
+			return nil, fmt.Errorf("response %d does not match the schema: %w", i, mismatches[0])
+		}
+	}
+	return internal.FlattenDocuments(*schema, documents, options)
+}
+
+// WithExplodeArray makes Flatten write one row per item of the array at a path of the schema,
+// e.g. "lines" or "orders[].lines", instead of joining its values. The other columns are
+// repeated on every row, and a response without items keeps a single row. A path that is
+// not an array of the schema is reported by Flatten with ErrInvalidOption.
+//
+// Example:
+//
+//	table, err := Flatten(schema, responses, WithExplodeArray("lines"))
+func WithExplodeArray(path string) Option {
+	return func(opts *internal.Options) {
+		if path == "" {
+			opts.AddError("exploded array path must not be empty")
+			return
+		}
+		opts.ExplodeArray = path
+	}
+}
+
+// WithArraySeparator replaces the separator joining the values of arrays and maps in the
+// cells of flattened tables, DefaultArraySeparator by default. An empty separator is
+// rejected with ErrInvalidOption.
+//
+// Example:
+//
+//	table, err := Flatten(schema, responses, WithArraySeparator("|"))
+func WithArraySeparator(separator string) Option {
+	return func(opts *internal.Options) {
+		if separator == "" {
+			opts.AddError("array separator must not be empty")
+			return
+		}
+		opts.ArraySeparator = separator
+	}
+}
@@ -0,0 +1,88 @@
+package gptschema
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestFlatten(t *testing.T) {
+	type Line struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type Invoice struct {
+		ID    string `json:"id"`
+		Lines []Line `json:"lines"`
+	}
+	schema, err := GenerateSchema(Invoice{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	responses := [][]byte{[]byte(`{"id":"INV-1","lines":[{"sku":"A-1","qty":2},{"sku":"B-7","qty":1}]}`)}
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{name: "joined", expected: "id,lines[].sku,lines[].qty\nINV-1,A-1; B-7,2; 1\n"},
+		{name: "separator", opts: []Option{WithArraySeparator("|")}, expected: "id,lines[].sku,lines[].qty\nINV-1,A-1|B-7,2|1\n"},
+		{name: "exploded", opts: []Option{WithExplodeArray("lines")}, expected: "id,lines[].sku,lines[].qty\nINV-1,A-1,2\nINV-1,B-7,1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := Flatten(schema, responses, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var out bytes.Buffer
+			if err := table.WriteCSV(&out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestFlatten_Invalid(t *testing.T) {
+	type Invoice struct {
+		ID string `json:"id"`
+	}
+	schema, err := GenerateSchema(Invoice{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name      string
+		schema    *internal.Schema
+		responses []string
+		opts      []Option
+		expected  error
+	}{
+		{name: "nil schema", expected: ErrInvalidSchema},
+		{name: "empty separator", schema: schema, opts: []Option{WithArraySeparator("")}, expected: ErrInvalidOption},
+		{name: "empty explode path", schema: schema, opts: []Option{WithExplodeArray("")}, expected: ErrInvalidOption},
+		{name: "explode a scalar", schema: schema, responses: []string{`{"id":"1"}`}, opts: []Option{WithExplodeArray("id")}, expected: ErrInvalidOption},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var responses [][]byte
+			for _, response := range tt.responses {
+				responses = append(responses, []byte(response))
+			}
+			if _, err := Flatten(tt.schema, responses, tt.opts...); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	for _, response := range []string{`{"id":1}`, `not json`} {
+		if _, err := Flatten(schema, [][]byte{[]byte(response)}); err == nil {
+			t.Errorf("%s: expected an error", response)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Package basic covers nested structs, slices and struct tags.
+package basic
+
+// Address is nested in Customer.
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city" description:"City name"`
+}
+
+// Customer is the root of the case.
+//
+//gptschema:generate
+type Customer struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name" description:"Full name"`
+	Tags    []string  `json:"tags"`
+	Score   float64   `json:"score"`
+	Active  bool      `json:"active"`
+	Address Address   `json:"address"`
+	History []Address `json:"history"`
+}
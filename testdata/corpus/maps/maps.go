@@ -0,0 +1,10 @@
+// Package maps covers maps, which are only allowed outside strict mode.
+package maps
+
+// Inventory is the root of the case.
+//
+//gptschema:generate
+type Inventory struct {
+	Counts map[string]int    `json:"counts"`
+	Labels map[string]string `json:"labels" keys:"^[a-z]{2}$"`
+}
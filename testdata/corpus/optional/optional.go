@@ -0,0 +1,17 @@
+// Package optional covers omitempty fields, which become nullable, and pointers, which stay required.
+package optional
+
+// Note is nested in Profile.
+type Note struct {
+	Text string `json:"text"`
+}
+
+// Profile is the root of the case.
+//
+//gptschema:generate
+type Profile struct {
+	Nickname *string `json:"nickname"`
+	Age      int     `json:"age,omitempty"`
+	Note     *Note   `json:"note"`
+	Notes    []Note  `json:"notes,omitempty"`
+}
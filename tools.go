@@ -0,0 +1,366 @@
+package gptschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// Errors returned by ToolSet.Dispatch, match them with errors.Is.
+var (
+	ErrUnknownTool          = errors.New("unknown tool")
+	ErrInvalidToolArguments = errors.New("invalid tool arguments")
+)
+
+// ToolArgumentsError lists the arguments of a tool call that do not match the parameters
+// schema of the tool. It wraps ErrInvalidToolArguments, and its mismatches can be sent back
+// to the model with FeedbackPrompt.
+type ToolArgumentsError struct {
+	Tool       string
+	Mismatches []ValidationError
+}
+
+func (e *ToolArgumentsError) Error() string {
+	lines := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		lines[i] = m.Error()
+	}
+	return fmt.Sprintf("%s for %q: %s", ErrInvalidToolArguments, e.Tool, strings.Join(lines, "; "))
+}
+
+func (e *ToolArgumentsError) Unwrap() error {
+	return ErrInvalidToolArguments
+}
+
+// ToolCall is a function call requested by the model, with its arguments as the JSON
+// string sent by the API.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
+}
+
+// Tool is a function registered in a ToolSet.
+type Tool struct {
+	Name        string
+	Description string
+	// Parameters is the schema of the arguments of the function
+	Parameters *internal.Schema
+	args       reflect.Type
+	result     reflect.Type
+	// template is the registered struct of executor tools, copied before decoding
+	template reflect.Value
+	invoke   func(ctx context.Context, args reflect.Value) (interface{}, error)
+}
+
+// ToolSet dispatches the tool calls of a model to Go functions. Each registered function
+// gets a parameters schema generated from its argument struct, the tools array of the API
+// is emitted with ToolsJSON, and Dispatch validates, decodes and runs the calls.
+// The zero value is not usable, create tool sets with NewToolSet. A ToolSet is safe for concurrent use.
+type ToolSet struct {
+	mu      sync.RWMutex
+	options []Option
+	// settings are the built options, selecting the provider of ToolsJSON
+	settings *internal.Options
+	strict   bool
+	tools    map[string]*Tool
+	names    []string
+}
+
+// NewToolSet creates an empty tool set. The options are used to generate the parameters
+// schemas, and to validate and decode the arguments of the calls. With WithProvider, the
+// parameters emitted by ToolsJSON leave out the keywords the provider rejects.
+func NewToolSet(opts ...Option) (*ToolSet, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolSet{
+		options:  append([]Option(nil), opts...),
+		settings: options,
+		strict:   options.Strict,
+		tools:    make(map[string]*Tool),
+	}, nil
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Register adds a tool under name. fn is either a function taking an argument struct,
+// optionally preceded by a context.Context, and returning a result and an error:
+//
+//	func(ctx context.Context, args WeatherArgs) (Weather, error)
+//	func(args WeatherArgs) (Weather, error)
+//
+// or a struct whose pointer has an Execute(context.Context) (R, error) method, in which case
+// the struct itself holds the arguments. The registered struct is copied for each call before
+// the arguments are decoded into it, so fields excluded with `json:"-"`, such as clients,
+// keep their registered value. Functions taking positional parameters are registered through
+// NamedParams. The name must match ^[a-zA-Z0-9_-]{1,64}$ and
+// registering a name twice is an error.
+//
+// An empty name or description is taken from the `tool` tag of the argument struct, so the
+// whole tool definition can live in Go source:
+//
+//	type WeatherArgs struct {
+//	    _    struct{} `tool:"name=lookup_weather,description=Look up the weather of a city"`
+//	    City string   `json:"city"`
+//	}
+//
+// Example:
+//
+//	tools, _ := NewToolSet()
+//	err := tools.Register("", "", func(ctx context.Context, args WeatherArgs) (Weather, error) {
+//	    return weatherService.Lookup(ctx, args.City)
+//	})
+func (s *ToolSet) Register(name, description string, fn interface{}) error {
+	tool, err := newTool(fn)
+	if err != nil {
+		return fmt.Errorf("register tool %q: %w", name, err)
+	}
+	meta, err := internal.ParseToolTag(tool.args)
+	if err != nil {
+		return fmt.Errorf("register tool %q: %w", name, err)
+	}
+	tool.Name = defaultString(name, meta.Name)
+	tool.Description = defaultString(description, meta.Description)
+	if !responseFormatName.MatchString(tool.Name) {
+		return fmt.Errorf("%w: tool name %q must match %s", ErrInvalidOption, tool.Name, responseFormatName)
+	}
+	name = tool.Name
+	tool.Parameters, err = GenerateSchema(reflect.New(tool.args).Interface(), s.options...)
+	if err != nil {
+		return fmt.Errorf("register tool %q: %w", name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.tools[name]; exists {
+		return fmt.Errorf("register tool %q: name already registered", name)
+	}
+	s.tools[name] = tool
+	s.names = append(s.names, name)
+	return nil
+}
+
+// newTool inspects the signature of a tool function or executor struct
+func newTool(fn interface{}) (*Tool, error) {
+	if named, ok := fn.(*NamedParamsFunc); ok {
+		return named.tool()
+	}
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() {
+		return nil, fmt.Errorf("%w: tool is nil", ErrUnsupportedType)
+	}
+	t := v.Type()
+	if t.Kind() == reflect.Func {
+		if t.NumIn() == 0 || t.NumIn() > 2 || (t.NumIn() == 2 && t.In(0) != contextType) {
+			return nil, fmt.Errorf("%w: tool function %s must take an argument struct, optionally preceded by a context.Context", ErrUnsupportedType, t)
+		}
+		if err := checkResults(t); err != nil {
+			return nil, err
+		}
+		args := t.In(t.NumIn() - 1)
+		pointer := args.Kind() == reflect.Pointer
+		if pointer {
+			args = args.Elem()
+		}
+		if args.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%w: tool function %s must take a struct, got %s", ErrUnsupportedType, t, args)
+		}
+		withContext := t.NumIn() == 2
+		return &Tool{args: args, result: t.Out(0), invoke: func(ctx context.Context, arg reflect.Value) (interface{}, error) {
+			if !pointer {
+				arg = arg.Elem()
+			}
+			in := []reflect.Value{arg}
+			if withContext {
+				in = []reflect.Value{reflect.ValueOf(ctx), arg}
+			}
+			return results(v.Call(in))
+		}}, nil
+	}
+
+	args := t
+	if args.Kind() == reflect.Pointer {
+		args = args.Elem()
+	}
+	method, ok := reflect.PointerTo(args).MethodByName("Execute")
+	if args.Kind() != reflect.Struct || !ok {
+		return nil, fmt.Errorf("%w: tool must be a function or a struct with an Execute method, got %s", ErrUnsupportedType, t)
+	}
+	if method.Type.NumIn() != 2 || method.Type.In(1) != contextType {
+		return nil, fmt.Errorf("%w: Execute method of %s must take a context.Context", ErrUnsupportedType, args)
+	}
+	if err := checkResults(method.Type); err != nil {
+		return nil, err
+	}
+	template := reflect.Indirect(v)
+	if !template.IsValid() {
+		template = reflect.Zero(args)
+	}
+	return &Tool{args: args, result: method.Type.Out(0), template: template, invoke: func(ctx context.Context, arg reflect.Value) (interface{}, error) {
+		return results(method.Func.Call([]reflect.Value{arg, reflect.ValueOf(ctx)}))
+	}}, nil
+}
+
+// checkResults ensures a tool function returns a result and an error
+func checkResults(t reflect.Type) error {
+	if t.NumOut() != 2 || t.Out(1) != errorType {
+		return fmt.Errorf("%w: tool function %s must return a result and an error", ErrUnsupportedType, t)
+	}
+	return nil
+}
+
+func results(out []reflect.Value) (interface{}, error) {
+	err, _ := out[1].Interface().(error)
+	return out[0].Interface(), err
+}
+
+// Get returns the tool registered under name.
+func (s *ToolSet) Get(name string) (*Tool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tool, ok := s.tools[name]
+	return tool, ok
+}
+
+// Names returns the registered tool names in registration order.
+func (s *ToolSet) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.names...)
+}
+
+// chatTool mirrors an entry of the tools array of the Chat Completions API
+type chatTool struct {
+	Type     string       `json:"type"`
+	Function toolFunction `json:"function"`
+}
+
+type toolFunction struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Parameters  *internal.Schema `json:"parameters"`
+	Strict      bool             `json:"strict"`
+}
+
+// responsesTool is chatTool in the Responses API, with the function fields next to the type
+type responsesTool struct {
+	Type string `json:"type"`
+	toolFunction
+}
+
+// ToolsJSON returns the tools array of the Chat Completions API, in registration order.
+//
+// Example:
+//
+//	tools, _ := toolSet.ToolsJSON()
+//	// [{"type":"function","function":{"name":"lookup_weather","description":"...","parameters":{...},"strict":true}}]
+func (s *ToolSet) ToolsJSON() ([]byte, error) {
+	functions := s.functions()
+	tools := make([]chatTool, len(functions))
+	for i, function := range functions {
+		tools[i] = chatTool{Type: "function", Function: function}
+	}
+	payload, err := json.Marshal(tools)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tools to JSON: %w", err)
+	}
+	return payload, nil
+}
+
+// ResponsesToolsJSON returns the tools array of the Responses API, which flattens the
+// function fields next to the type, in registration order.
+//
+// Example:
+//
+//	tools, _ := toolSet.ResponsesToolsJSON()
+//	// [{"type":"function","name":"lookup_weather","description":"...","parameters":{...},"strict":true}]
+func (s *ToolSet) ResponsesToolsJSON() ([]byte, error) {
+	functions := s.functions()
+	tools := make([]responsesTool, len(functions))
+	for i, function := range functions {
+		tools[i] = responsesTool{Type: "function", toolFunction: function}
+	}
+	payload, err := json.Marshal(tools)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tools to JSON: %w", err)
+	}
+	return payload, nil
+}
+
+func (s *ToolSet) functions() []toolFunction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	functions := make([]toolFunction, len(s.names))
+	for i, name := range s.names {
+		tool := s.tools[name]
+		// the parameters are stripped for the provider, Dispatch validates with the full schema
+		parameters, _ := providerSchema(tool.Parameters, s.settings)
+		functions[i] = toolFunction{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  parameters,
+			Strict:      s.strict,
+		}
+	}
+	return functions
+}
+
+// Dispatch runs a tool call: the arguments are validated against the parameters schema of
+// the tool, decoded with Unmarshal into its argument struct and passed to the function,
+// whose result is returned as JSON, ready to be sent back as the tool message.
+//
+// A call of an unregistered tool returns ErrUnknownTool, and arguments that do not match
+// the schema return a *ToolArgumentsError. Errors of the function are returned as is.
+//
+// Example:
+//
+//	for _, call := range completion.Choices[0].Message.ToolCalls {
+//	    result, err := toolSet.Dispatch(ctx, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: call.Function.Arguments})
+//	    if err != nil {
+//	        result = []byte(err.Error())
+//	    }
+//	    messages = append(messages, openai.ToolMessage(string(result), call.ID))
+//	}
+func (s *ToolSet) Dispatch(ctx context.Context, call ToolCall) ([]byte, error) {
+	tool, ok := s.Get(call.Name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTool, call.Name)
+	}
+	arguments := []byte(call.Arguments)
+	if strings.TrimSpace(call.Arguments) == "" {
+		arguments = []byte("{}")
+	}
+	mismatches, err := Validate(tool.Parameters, arguments, s.options...)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %q: %w", ErrInvalidToolArguments, call.Name, err)
+	}
+	if len(mismatches) > 0 {
+		return nil, &ToolArgumentsError{Tool: call.Name, Mismatches: mismatches}
+	}
+	args := reflect.New(tool.args)
+	if tool.template.IsValid() {
+		args.Elem().Set(tool.template)
+	}
+	if err := Unmarshal(arguments, args.Interface(), s.options...); err != nil {
+		return nil, fmt.Errorf("%w for %q: %w", ErrInvalidToolArguments, call.Name, err)
+	}
+	result, err := tool.invoke(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result of tool %q: %w", call.Name, err)
+	}
+	return payload, nil
+}
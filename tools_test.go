@@ -0,0 +1,238 @@
+package gptschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+type WeatherArgs struct {
+	City string `json:"city"`
+	Days int    `json:"days"`
+}
+
+type Weather struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+// ConvertCurrency is an executor tool holding its arguments and a rate table
+type ConvertCurrency struct {
+	Amount float64            `json:"amount"`
+	From   string             `json:"from"`
+	To     string             `json:"to"`
+	Rates  map[string]float64 `json:"-"`
+}
+
+func (c *ConvertCurrency) Execute(ctx context.Context) (float64, error) {
+	rate, ok := c.Rates[c.From+c.To]
+	if !ok {
+		return 0, fmt.Errorf("no rate for %s to %s", c.From, c.To)
+	}
+	return c.Amount * rate, nil
+}
+
+func newTestToolSet(t *testing.T) *ToolSet {
+	tools, err := NewToolSet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tools.Register("lookup_weather", "Look up the weather of a city",
+		func(ctx context.Context, args WeatherArgs) (Weather, error) {
+			return Weather{City: args.City, Temperature: 21.5}, nil
+		}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tools.Register("convert_currency", "", ConvertCurrency{Rates: map[string]float64{"EURUSD": 1.5}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return tools
+}
+
+func TestToolSet_Register(t *testing.T) {
+	tools := newTestToolSet(t)
+	if names := tools.Names(); !reflect.DeepEqual(names, []string{"lookup_weather", "convert_currency"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	tests := []struct {
+		name    string
+		tool    string
+		fn      interface{}
+		wantErr error
+	}{
+		{name: "without context", tool: "no_context", fn: func(args *WeatherArgs) (string, error) { return "", nil }},
+		{name: "duplicate name", tool: "lookup_weather", fn: func(args WeatherArgs) (string, error) { return "", nil }, wantErr: errors.New("")},
+		{name: "invalid name", tool: "lookup weather", fn: func(args WeatherArgs) (string, error) { return "", nil }, wantErr: ErrInvalidOption},
+		{name: "nil", tool: "nil_tool", fn: nil, wantErr: ErrUnsupportedType},
+		{name: "scalar argument", tool: "scalar", fn: func(city string) (string, error) { return "", nil }, wantErr: ErrUnsupportedType},
+		{name: "missing error result", tool: "no_error", fn: func(args WeatherArgs) string { return "" }, wantErr: ErrUnsupportedType},
+		{name: "context not first", tool: "context_last", fn: func(args WeatherArgs, ctx context.Context) (string, error) { return "", nil }, wantErr: ErrUnsupportedType},
+		{name: "struct without Execute", tool: "weather_args", fn: WeatherArgs{}, wantErr: ErrUnsupportedType},
+		{name: "unsupported argument field", tool: "channel", fn: func(args struct{ C chan int }) (string, error) { return "", nil }, wantErr: ErrUnsupportedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tools.Register(tt.tool, "", tt.fn)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != nil && err == nil:
+				t.Errorf("expected an error")
+			case tt.wantErr != nil && tt.wantErr.Error() != "" && !errors.Is(err, tt.wantErr):
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestToolSet_ToolsJSON(t *testing.T) {
+	tools := newTestToolSet(t)
+	weather, _ := tools.Get("lookup_weather")
+	parameters, _ := json.Marshal(weather.Parameters)
+
+	payload, err := tools.ToolsJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var chat []struct {
+		Type     string                 `json:"type"`
+		Function map[string]interface{} `json:"function"`
+	}
+	if err := json.Unmarshal(payload, &chat); err != nil {
+		t.Fatalf("invalid JSON payload: %v", err)
+	}
+	if len(chat) != 2 || chat[0].Type != "function" || chat[0].Function["name"] != "lookup_weather" ||
+		chat[0].Function["description"] != "Look up the weather of a city" || chat[0].Function["strict"] != true {
+		t.Errorf("unexpected tools %s", payload)
+	}
+	if got, _ := json.Marshal(chat[0].Function["parameters"]); string(got) != string(parameters) {
+		t.Errorf("expected parameters %s, got %s", parameters, got)
+	}
+	if _, ok := chat[1].Function["description"]; ok {
+		t.Errorf("expected empty description to be omitted")
+	}
+
+	payload, err = tools.ResponsesToolsJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var responses []map[string]interface{}
+	if err := json.Unmarshal(payload, &responses); err != nil {
+		t.Fatalf("invalid JSON payload: %v", err)
+	}
+	if len(responses) != 2 || responses[0]["type"] != "function" || responses[0]["name"] != "lookup_weather" || responses[0]["parameters"] == nil {
+		t.Errorf("unexpected tools %s", payload)
+	}
+}
+
+func TestToolSet_Dispatch(t *testing.T) {
+	tools := newTestToolSet(t)
+	tests := []struct {
+		name    string
+		call    ToolCall
+		want    string
+		wantErr error
+	}{
+		{
+			name: "function",
+			call: ToolCall{Name: "lookup_weather", Arguments: `{"city":"Oslo","days":3}`},
+			want: `{"city":"Oslo","temperature":21.5}`,
+		},
+		{
+			name: "executor keeps excluded fields",
+			call: ToolCall{Name: "convert_currency", Arguments: `{"amount":10,"from":"EUR","to":"USD"}`},
+			want: `15`,
+		},
+		{
+			name:    "tool error",
+			call:    ToolCall{Name: "convert_currency", Arguments: `{"amount":10,"from":"EUR","to":"JPY"}`},
+			wantErr: errors.New("no rate for EUR to JPY"),
+		},
+		{
+			name:    "unknown tool",
+			call:    ToolCall{Name: "book_flight", Arguments: `{}`},
+			wantErr: ErrUnknownTool,
+		},
+		{
+			name:    "mismatching arguments",
+			call:    ToolCall{Name: "lookup_weather", Arguments: `{"city":"Oslo","days":"3"}`},
+			wantErr: ErrInvalidToolArguments,
+		},
+		{
+			name:    "empty arguments",
+			call:    ToolCall{Name: "lookup_weather"},
+			wantErr: ErrInvalidToolArguments,
+		},
+		{
+			name:    "malformed arguments",
+			call:    ToolCall{Name: "lookup_weather", Arguments: `{"city":`},
+			wantErr: ErrInvalidToolArguments,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tools.Dispatch(context.Background(), tt.call)
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	_, err := tools.Dispatch(context.Background(), ToolCall{Name: "lookup_weather", Arguments: `{"city":"Oslo"}`})
+	var argsErr *ToolArgumentsError
+	if !errors.As(err, &argsErr) || argsErr.Tool != "lookup_weather" || len(argsErr.Mismatches) != 1 || argsErr.Mismatches[0].Keyword != "required" {
+		t.Errorf("expected a ToolArgumentsError for the missing days, got %v", err)
+	}
+}
+
+func TestToolSet_RegisterToolTag(t *testing.T) {
+	tools, _ := NewToolSet()
+	lookup := func(args internal.WeatherToolArgs) (string, error) { return args.City, nil }
+	if err := tools.Register("", "", lookup); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tool, ok := tools.Get("lookup_weather")
+	if !ok {
+		t.Fatalf("expected the tool to be registered under its tag name, got %v", tools.Names())
+	}
+	if tool.Description != "Look up the weather, by city" {
+		t.Errorf("unexpected description %q", tool.Description)
+	}
+	if !reflect.DeepEqual(*tool.Parameters, internal.WeatherToolArgsSchema) {
+		t.Errorf("unexpected parameters %v", tool.Parameters)
+	}
+
+	if err := tools.Register("weather", "Current weather", lookup); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tool, _ := tools.Get("weather"); tool.Description != "Current weather" {
+		t.Errorf("expected explicit arguments to win over the tag, got %q", tool.Description)
+	}
+
+	if err := tools.Register("", "", func(args WeatherArgs) (string, error) { return "", nil }); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption without a name, got %v", err)
+	}
+	invalid := func(args struct {
+		_ struct{} `tool:"name=x,strict=true"`
+	}) (string, error) {
+		return "", nil
+	}
+	if err := tools.Register("", "", invalid); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+}
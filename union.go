@@ -0,0 +1,207 @@
+package gptschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// ErrUnknownVariant is returned by DecodeUnion for a response matching none of the variants
+// of a union, or naming an unknown variant in its discriminator.
+var ErrUnknownVariant = errors.New("unknown union variant")
+
+// unionVariant is a candidate type of a union and its schema
+type unionVariant struct {
+	name   string
+	t      reflect.Type
+	schema *internal.Schema
+}
+
+// WithDiscriminator injects a property identifying the variant into each variant of the
+// schemas built by GenerateUnionSchema, a string constant holding the name of the variant
+// type in snake_case, e.g. "clarifying_question" for ClarifyingQuestion. It is listed first in
+// required, so the model commits to a shape before producing it, and DecodeUnion reads it
+// instead of trying each variant. An empty name is rejected with ErrInvalidOption.
+//
+// Example:
+//
+//	schema, err := GenerateUnionSchema(Answer{}, ClarifyingQuestion{}, WithDiscriminator("kind"))
+//	// {"anyOf":[{"properties":{"kind":{"type":"string","const":"answer"},...}},...]}
+func WithDiscriminator(property string) Option {
+	return func(opts *internal.Options) {
+		if property == "" {
+			opts.AddError("discriminator property must not be empty")
+			return
+		}
+		opts.Discriminator = property
+	}
+}
+
+// GenerateUnionSchema builds a root anyOf schema from several candidate types, for flows
+// where the model may answer with one of several response shapes, e.g. an answer or a
+// clarifying question. Options may be passed among the values and apply to every variant.
+// At least two variants are required, and their type names must differ, otherwise
+// ErrInvalidSchema is returned. With WithDiscriminator each variant also carries a
+// property naming it; a variant defining that property is rejected with ErrInvalidTag.
+// The $defs of the variants, e.g. with WithDefs or WithRecursiveRefs, are moved to the
+// root and shared, a definition differing from another of the same name is numbered, and
+// a variant referencing itself with "#" is moved to $defs under its type name.
+//
+// OpenAI strict mode requires an object root and Lint reports union roots in strict mode,
+// so send union schemas to providers accepting anyOf at the root.
+//
+// Example:
+//
+//	schema, err := GenerateUnionSchema(Answer{}, ClarifyingQuestion{}, WithDiscriminator("kind"))
+//	// ... send the schema to the model ...
+//	value, variant, err := DecodeUnion([]byte(content), Answer{}, ClarifyingQuestion{}, WithDiscriminator("kind"))
+func GenerateUnionSchema(vs ...interface{}) (*internal.Schema, error) {
+	variants, _, err := unionVariants(vs)
+	if err != nil {
+		return nil, err
+	}
+	schemas := make([]internal.Schema, len(variants))
+	names := make([]string, len(variants))
+	for i, variant := range variants {
+		schemas[i] = *variant.schema
+		names[i] = variant.t.Name()
+	}
+	schema := internal.UnionSchema(schemas, names)
+	return &schema, nil
+}
+
+// DecodeUnion decodes a response to a schema built by GenerateUnionSchema into a new value of
+// the matching variant, returned as a pointer with the index of the variant among the
+// values. Pass the same values and options given to GenerateUnionSchema. With
+// WithDiscriminator the variant is selected by the discriminator property, otherwise the
+// response is validated against each variant in order and the first one it matches is
+// used. A response matching no variant returns ErrUnknownVariant.
+//
+// Example:
+//
+//	value, _, err := DecodeUnion([]byte(content), Answer{}, ClarifyingQuestion{}, WithDiscriminator("kind"))
+//	switch v := value.(type) {
+//	case *Answer:
+//	    // ...
+//	case *ClarifyingQuestion:
+//	    // ...
+//	}
+func DecodeUnion(data []byte, vs ...interface{}) (interface{}, int, error) {
+	variants, opts, err := unionVariants(vs)
+	if err != nil {
+		return nil, -1, err
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, -1, err
+	}
+	index, err := matchVariant(data, variants, options.Discriminator, opts)
+	if err != nil {
+		return nil, -1, err
+	}
+	value := reflect.New(variants[index].t)
+	if err := Unmarshal(data, value.Interface(), opts...); err != nil {
+		return nil, -1, fmt.Errorf("decode variant %q: %w", variants[index].name, err)
+	}
+	return value.Interface(), index, nil
+}
+
+// matchVariant returns the index of the variant of a response
+func matchVariant(data []byte, variants []unionVariant, discriminator string, opts []Option) (int, error) {
+	if discriminator != "" {
+		var header map[string]json.RawMessage
+		if err := json.Unmarshal(data, &header); err != nil {
+			return -1, err
+		}
+		var name string
+		if err := json.Unmarshal(header[discriminator], &name); err != nil {
+			return -1, fmt.Errorf("%w: %s is missing or not a string", ErrUnknownVariant, discriminator)
+		}
+		for i, variant := range variants {
+			if variant.name == name {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("%w: %s is %q", ErrUnknownVariant, discriminator, name)
+	}
+	for i, variant := range variants {
+		mismatches, err := Validate(variant.schema, data, opts...)
+		if err != nil {
+			return -1, err
+		}
+		if len(mismatches) == 0 {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: the response matches none of the %d variants", ErrUnknownVariant, len(variants))
+}
+
+// unionVariants separates the values of a union from its options and generates the schema of
+// each variant, with the discriminator property when one is set
+func unionVariants(vs []interface{}) ([]unionVariant, []Option, error) {
+	var values []interface{}
+	var opts []Option
+	for _, v := range vs {
+		if opt, ok := v.(Option); ok {
+			opts = append(opts, opt)
+		} else {
+			values = append(values, v)
+		}
+	}
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(values) < 2 {
+		return nil, nil, fmt.Errorf("%w: a union requires at least two variants, got %d", ErrInvalidSchema, len(values))
+	}
+	variants := make([]unionVariant, len(values))
+	names := make(map[string]bool)
+	for i, v := range values {
+		t := reflect.TypeOf(v)
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil || t.Name() == "" {
+			return nil, nil, fmt.Errorf("%w: union variant %d must be a named type, got %T", ErrInvalidSchema, i, v)
+		}
+		name := SnakeCase.Apply(t.Name())
+		if names[name] {
+			return nil, nil, fmt.Errorf("%w: union variants %q share the name %q", ErrInvalidSchema, t.Name(), name)
+		}
+		names[name] = true
+		schema, err := GenerateSchema(v, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("union variant %s: %w", t.Name(), err)
+		}
+		if options.Discriminator != "" {
+			if err := injectDiscriminator(schema, options.Discriminator, name); err != nil {
+				return nil, nil, fmt.Errorf("union variant %s: %w", t.Name(), err)
+			}
+		}
+		variants[i] = unionVariant{name: name, t: t, schema: schema}
+	}
+	return variants, opts, nil
+}
+
+// injectDiscriminator adds the discriminator property to an object schema, first in required
+func injectDiscriminator(schema *internal.Schema, property, name string) error {
+	if (*schema)["type"] != "object" {
+		return fmt.Errorf("%w: a discriminator requires an object schema", ErrInvalidSchema)
+	}
+	properties, _ := (*schema)["properties"].(internal.Schema)
+	if _, exists := properties[property]; exists {
+		return fmt.Errorf("%w: property %q is reserved for the discriminator", ErrInvalidTag, property)
+	}
+	if properties == nil {
+		properties = internal.Schema{}
+		(*schema)["properties"] = properties
+	}
+	properties[property] = internal.Schema{"type": "string", "const": name}
+	required, _ := (*schema)["required"].([]string)
+	(*schema)["required"] = append([]string{property}, required...)
+	return nil
+}
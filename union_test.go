@@ -0,0 +1,206 @@
+package gptschema
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+type unionAnswer struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+}
+
+type unionStream struct {
+	Events chan string `json:"events"`
+}
+
+type unionClarifyingQuestion struct {
+	Question string   `json:"question"`
+	Options  []string `json:"options"`
+}
+
+func TestGenerateUnionSchema(t *testing.T) {
+	schema, err := GenerateUnionSchema(unionAnswer{}, &unionClarifyingQuestion{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	answer, _ := GenerateSchema(unionAnswer{})
+	question, _ := GenerateSchema(unionClarifyingQuestion{})
+	expected := internal.Schema{"anyOf": []internal.Schema{*answer, *question}}
+	if !reflect.DeepEqual(*schema, expected) {
+		t.Errorf("expected %v, got %v", expected, *schema)
+	}
+
+	schema, err = GenerateUnionSchema(unionAnswer{}, unionClarifyingQuestion{}, WithDiscriminator("kind"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	variants := (*schema)["anyOf"].([]internal.Schema)
+	for i, name := range []string{"union_answer", "union_clarifying_question"} {
+		kind := variants[i]["properties"].(internal.Schema)["kind"]
+		if !reflect.DeepEqual(kind, internal.Schema{"type": "string", "const": name}) {
+			t.Errorf("variant %d: unexpected discriminator %v", i, kind)
+		}
+		if required := variants[i]["required"].([]string); required[0] != "kind" {
+			t.Errorf("variant %d: expected the discriminator first in required, got %v", i, required)
+		}
+	}
+	if _, ok := (*answer)["properties"].(internal.Schema)["kind"]; ok {
+		t.Errorf("expected GenerateSchema to be unaffected by the union")
+	}
+}
+
+type unionLine struct {
+	Text string `json:"text"`
+}
+
+type unionQuote struct {
+	Lines []unionLine `json:"lines"`
+}
+
+type unionSummary struct {
+	Title string      `json:"title"`
+	Lines []unionLine `json:"lines"`
+}
+
+type unionThread struct {
+	Line    unionLine     `json:"line"`
+	Replies []unionThread `json:"replies"`
+}
+
+func TestGenerateUnionSchema_Defs(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []interface{}
+		data     string
+		expected []string
+	}{
+		{
+			name:     "shared definitions",
+			values:   []interface{}{unionQuote{}, unionSummary{}, WithDefs(true)},
+			data:     `{"title":"Notes","lines":[{"text":"first"}]}`,
+			expected: []string{"unionLine"},
+		},
+		{
+			name: "colliding definitions",
+			values: []interface{}{
+				func() interface{} {
+					type Line struct {
+						Text string `json:"text"`
+					}
+					type Plain struct {
+						Line Line `json:"line"`
+					}
+					return Plain{}
+				}(),
+				func() interface{} {
+					type Line struct {
+						Number int `json:"number"`
+					}
+					type Numbered struct {
+						Line Line `json:"line"`
+					}
+					return Numbered{}
+				}(),
+				WithDefs(true),
+			},
+			data:     `{"line":{"number":3}}`,
+			expected: []string{"Line", "Line2"},
+		},
+		{
+			name:     "recursive variant",
+			values:   []interface{}{unionThread{}, unionSummary{}, WithRecursiveRefs(true)},
+			data:     `{"line":{"text":"first"},"replies":[{"line":{"text":"second"},"replies":[]}]}`,
+			expected: []string{"unionThread"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := GenerateUnionSchema(tt.values...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defs, _ := (*schema)["$defs"].(internal.Schema)
+			var names []string
+			for name := range defs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("expected $defs %v, got %v", tt.expected, names)
+			}
+			for _, variant := range (*schema)["anyOf"].([]internal.Schema) {
+				if _, ok := variant["$defs"]; ok {
+					t.Errorf("expected the $defs to be hoisted, got %v", variant)
+				}
+			}
+			mismatches, err := Validate(schema, []byte(tt.data))
+			if err != nil || len(mismatches) > 0 {
+				t.Errorf("expected %s to be valid, got %v %v", tt.data, mismatches, err)
+			}
+		})
+	}
+}
+
+func TestGenerateUnionSchema_Invalid(t *testing.T) {
+	type Kinded struct {
+		Kind string `json:"kind"`
+	}
+	tests := []struct {
+		name     string
+		vs       []interface{}
+		expected error
+	}{
+		{name: "single variant", vs: []interface{}{unionAnswer{}}, expected: ErrInvalidSchema},
+		{name: "duplicate names", vs: []interface{}{unionAnswer{}, &unionAnswer{}}, expected: ErrInvalidSchema},
+		{name: "anonymous type", vs: []interface{}{unionAnswer{}, struct{}{}}, expected: ErrInvalidSchema},
+		{name: "reserved property", vs: []interface{}{unionAnswer{}, Kinded{}, WithDiscriminator("kind")}, expected: ErrInvalidTag},
+		{name: "empty discriminator", vs: []interface{}{unionAnswer{}, unionClarifyingQuestion{}, WithDiscriminator("")}, expected: ErrInvalidOption},
+		{name: "unsupported variant", vs: []interface{}{unionAnswer{}, unionStream{}}, expected: ErrUnsupportedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateUnionSchema(tt.vs...); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestDecodeUnion(t *testing.T) {
+	value, index, err := DecodeUnion([]byte(`{"question":"Which city?","options":["Paris","Lyon"]}`),
+		unionAnswer{}, unionClarifyingQuestion{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	question, ok := value.(*unionClarifyingQuestion)
+	if !ok || index != 1 || question.Question != "Which city?" {
+		t.Errorf("expected the clarifying question, got %d %#v", index, value)
+	}
+
+	value, index, err = DecodeUnion([]byte(`{"kind":"union_answer","text":"Paris","confidence":0.9}`),
+		unionAnswer{}, unionClarifyingQuestion{}, WithDiscriminator("kind"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer, ok := value.(*unionAnswer); !ok || index != 0 || answer.Text != "Paris" {
+		t.Errorf("expected the answer, got %d %#v", index, value)
+	}
+
+	for name, data := range map[string]string{
+		"no match":              `{"text":"Paris"}`,
+		"unknown discriminator": `{"kind":"refusal","text":"no"}`,
+	} {
+		opts := []interface{}{unionAnswer{}, unionClarifyingQuestion{}}
+		if name == "unknown discriminator" {
+			opts = append(opts, WithDiscriminator("kind"))
+		}
+		if _, _, err := DecodeUnion([]byte(data), opts...); !errors.Is(err, ErrUnknownVariant) {
+			t.Errorf("%s: expected ErrUnknownVariant, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package gptschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+// ValidationError describes a value of a model response that does not match its schema:
+// the path of the value, the failing keyword, the schema fragment it was checked against
+// and a snippet of the offending value.
+type ValidationError = internal.ValidationError
+
+// Validate checks a model response against a schema and returns every mismatch, sorted by
+// path, so applications can log precisely which field the model got wrong. An empty result
+// means the response is valid. An error is only returned for data that is not JSON and for
+// schemas that cannot be evaluated, such as unresolved $refs (see WithRefLoader).
+//
+// Example:
+//
+//	mismatches, err := Validate(schema, []byte(content))
+//	for _, m := range mismatches {
+//	    log.Println(m) // lines[1].qty: expected integer, got string ("3")
+//	}
+func Validate(schema *internal.Schema, data []byte, opts ...Option) ([]ValidationError, error) {
+	options, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("%w: cannot validate against a nil schema", ErrInvalidSchema)
+	}
+	return validateJSON(schema, data, options)
+}
+
+// validateJSON decodes data keeping the numbers as written and validates it
+func validateJSON(schema *internal.Schema, data []byte, options *internal.Options) ([]ValidationError, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	mismatches, err := internal.ValidateValue(*schema, value, options)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	return mismatches, nil
+}
+
+// FeedbackPrompt renders validation errors as corrective instructions suitable for appending
+// to a retry message, e.g. "The field 'age' must be an integer, you returned a string "42".",
+// closing the validate and retry loop. Long lists are cut after 20 problems. It returns an
+// empty string when there are no errors.
+//
+// Example:
+//
+//	mismatches, _ := Validate(schema, []byte(content))
+//	if len(mismatches) > 0 {
+//	    messages = append(messages, openai.UserMessage(FeedbackPrompt(mismatches)))
+//	    // ... call the model again ...
+//	}
+func FeedbackPrompt(errs []ValidationError) string {
+	return internal.FeedbackPrompt(errs)
+}
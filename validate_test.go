@@ -0,0 +1,87 @@
+package gptschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akane9506/gptschema/internal"
+)
+
+func TestValidate(t *testing.T) {
+	type Line struct {
+		Qty  int     `json:"qty"`
+		Note *string `json:"note,omitempty"`
+	}
+	type Order struct {
+		Lines []Line `json:"lines"`
+	}
+	schema, err := GenerateSchema(Order{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mismatches, err := Validate(schema, []byte(`{"lines":[{"qty":1,"note":null},{"qty":"3","note":"ok"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mismatches) != 1 {
+		t.Fatalf("expected one mismatch, got %v", mismatches)
+	}
+	m := mismatches[0]
+	if m.Path != "lines[1].qty" || m.Keyword != "type" || m.Value != `"3"` {
+		t.Errorf("unexpected mismatch %+v", m)
+	}
+	if m.Error() != `lines[1].qty: expected integer, got string ("3")` {
+		t.Errorf("unexpected message %q", m.Error())
+	}
+
+	mismatches, err = Validate(schema, []byte(`{"lines":[]}`))
+	if err != nil || len(mismatches) != 0 {
+		t.Errorf("expected a valid response, got %v %v", mismatches, err)
+	}
+}
+
+func TestValidate_ContainsAndDependentRequired(t *testing.T) {
+	schema, err := GenerateSchema(internal.Shipment{}, WithStrict(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mismatches, err := Validate(schema, []byte(`{"tags":["heavy"],"codes":[7],"street":"Main","city":"Oslo"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mismatches) != 2 || mismatches[0].Keyword != "contains" || mismatches[1].Path != "zip" || mismatches[1].Keyword != "dependentRequired" {
+		t.Errorf("unexpected mismatches %v", mismatches)
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	schema := &internal.Schema{"type": "string"}
+	if _, err := Validate(schema, []byte(`{"unterminated"`)); err == nil {
+		t.Errorf("expected an error for invalid JSON")
+	}
+	if _, err := Validate(nil, []byte(`"x"`)); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for a nil schema, got %v", err)
+	}
+	if _, err := Validate(&internal.Schema{"$ref": "other.json"}, []byte(`"x"`)); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for an unresolved $ref, got %v", err)
+	}
+}
+
+func TestFeedbackPrompt(t *testing.T) {
+	schema := &internal.Schema{
+		"type":                 "object",
+		"properties":           internal.Schema{"age": internal.Schema{"type": "integer"}},
+		"required":             []string{"age"},
+		"additionalProperties": false,
+	}
+	mismatches, err := Validate(schema, []byte(`{"age":"42"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Your previous response did not match the required JSON schema:\n" +
+		"- The field 'age' must be an integer, you returned a string \"42\".\n" +
+		"Fix these problems and respond again with the complete JSON."
+	if got := FeedbackPrompt(mismatches); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}